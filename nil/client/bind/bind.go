@@ -0,0 +1,312 @@
+// Package bind is the runtime of the Go contract bindings generated by `nil abigen`.
+//
+// A binding wraps a BoundContract that packs the calls according to the contract ABI, runs the read-only
+// methods with eth_call, sends the transacting methods via a smart account (as async calls)
+// or as external transactions, and decodes the events from the logs of the receipts.
+package bind
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+var (
+	ErrCallFailed      = errors.New("call failed")
+	ErrNoBytecode      = errors.New("contract bytecode is not specified")
+	ErrEventMismatch   = errors.New("log is not the event")
+	ErrValueNotAllowed = errors.New("value and tokens can be sent only via a smart account")
+)
+
+// MetaData holds the ABI and the bytecode of a contract. The ABI is parsed on the first use.
+type MetaData struct {
+	ABI string
+	Bin string
+
+	once sync.Once
+	abi  abi.ABI
+	err  error
+}
+
+func (m *MetaData) GetAbi() (*abi.ABI, error) {
+	m.once.Do(func() {
+		m.abi, m.err = abi.JSON(strings.NewReader(m.ABI))
+	})
+	return &m.abi, m.err
+}
+
+func (m *MetaData) GetBin() ([]byte, error) {
+	if m.Bin == "" {
+		return nil, ErrNoBytecode
+	}
+	return hexutil.DecodeHex(m.Bin)
+}
+
+// CallOpts are the options of a read-only call.
+type CallOpts struct {
+	// BlockId is the block to run the call at ("latest" if not set).
+	BlockId any
+	// From is the sender of the call seen by the contract.
+	From *types.Address
+	// Fee limits the fee of the call. The zero fee means the default limit of the node.
+	Fee types.FeePack
+	// Overrides are the state overrides applied before the call.
+	Overrides *jsonrpc.StateOverrides
+}
+
+// TransactOpts are the options of a transaction.
+type TransactOpts struct {
+	// SmartAccount sends the transaction as an async call of the smart account.
+	// If it is not set, an external transaction is sent directly to the contract.
+	SmartAccount types.Address
+	// PrivateKey signs the external transaction.
+	PrivateKey *ecdsa.PrivateKey
+	// Fee is the fee of the transaction. The zero fee means that it is estimated.
+	Fee types.FeePack
+	// Value is the amount of the base token to send with the transaction.
+	Value types.Value
+	// Tokens are the custom tokens to send with the transaction.
+	Tokens []types.TokenBalance
+}
+
+func (opts *TransactOpts) viaSmartAccount() (bool, error) {
+	if opts.SmartAccount != types.EmptyAddress {
+		return true, nil
+	}
+	if !opts.Value.IsZero() || len(opts.Tokens) > 0 {
+		return false, ErrValueNotAllowed
+	}
+	return false, nil
+}
+
+// DeployOpts are the options of a deployment.
+type DeployOpts struct {
+	TransactOpts
+
+	// ShardId is the shard to deploy the contract to.
+	ShardId types.ShardId
+	// Salt makes the address of the contract differ from the ones of other deployments of the same code.
+	Salt common.Hash
+}
+
+// BoundContract is a contract deployed at the address, which methods and events are described by the ABI.
+type BoundContract struct {
+	address types.Address
+	abi     *abi.ABI
+	client  client.Client
+}
+
+func NewBoundContract(address types.Address, contractAbi *abi.ABI, c client.Client) *BoundContract {
+	return &BoundContract{
+		address: address,
+		abi:     contractAbi,
+		client:  c,
+	}
+}
+
+func (c *BoundContract) Address() types.Address {
+	return c.address
+}
+
+func (c *BoundContract) Abi() *abi.ABI {
+	return c.abi
+}
+
+// Call runs the read-only method and returns its unpacked outputs.
+func (c *BoundContract) Call(ctx context.Context, opts *CallOpts, method string, args ...any) ([]any, error) {
+	if opts == nil {
+		opts = &CallOpts{}
+	}
+	blockId := opts.BlockId
+	if blockId == nil {
+		blockId = "latest"
+	}
+
+	calldata, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+	res, err := c.client.Call(ctx, &jsonrpc.CallArgs{
+		From: opts.From,
+		To:   c.address,
+		Fee:  opts.Fee,
+		Data: (*hexutil.Bytes)(&calldata),
+	}, blockId, opts.Overrides)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("%w: %s", ErrCallFailed, res.Error)
+	}
+
+	outputs, err := c.abi.Unpack(method, res.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s result: %w", method, err)
+	}
+	return outputs, nil
+}
+
+// Transact sends a transaction calling the method and returns its hash.
+func (c *BoundContract) Transact(ctx context.Context, opts *TransactOpts, method string, args ...any) (
+	common.Hash, error,
+) {
+	calldata, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return common.EmptyHash, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+	return c.RawTransact(ctx, opts, calldata)
+}
+
+// RawTransact sends a transaction with the given calldata to the contract.
+func (c *BoundContract) RawTransact(ctx context.Context, opts *TransactOpts, calldata []byte) (common.Hash, error) {
+	viaSmartAccount, err := opts.viaSmartAccount()
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	if viaSmartAccount {
+		return c.client.SendTransactionViaSmartAccount(
+			ctx, opts.SmartAccount, calldata, opts.Fee, opts.Value, opts.Tokens, c.address, opts.PrivateKey)
+	}
+	return c.client.SendExternalTransaction(ctx, calldata, c.address, opts.PrivateKey, opts.Fee)
+}
+
+// DeployPayload returns the payload deploying the bytecode with the packed constructor arguments.
+func DeployPayload(
+	contractAbi *abi.ABI, bytecode []byte, salt common.Hash, args ...any,
+) (types.DeployPayload, error) {
+	ctorArgs, err := contractAbi.Pack("", args...)
+	if err != nil {
+		return types.DeployPayload{}, fmt.Errorf("failed to pack constructor arguments: %w", err)
+	}
+	code := make(types.Code, 0, len(bytecode)+len(ctorArgs))
+	code = append(append(code, bytecode...), ctorArgs...)
+	return types.BuildDeployPayload(code, salt), nil
+}
+
+// ContractAddress returns the address the contract is deployed to with the given options.
+func ContractAddress(
+	contractAbi *abi.ABI, bytecode []byte, shardId types.ShardId, salt common.Hash, args ...any,
+) (types.Address, error) {
+	payload, err := DeployPayload(contractAbi, bytecode, salt, args...)
+	if err != nil {
+		return types.EmptyAddress, err
+	}
+	return types.CreateAddress(shardId, payload), nil
+}
+
+// DeployContract deploys the contract and returns the binding of the contract along with the transaction hash.
+// The contract is deployed once the transaction is processed.
+func DeployContract(
+	ctx context.Context, opts *DeployOpts, contractAbi *abi.ABI, bytecode []byte, c client.Client, args ...any,
+) (*BoundContract, common.Hash, error) {
+	payload, err := DeployPayload(contractAbi, bytecode, opts.Salt, args...)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	viaSmartAccount, err := opts.viaSmartAccount()
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	var hash common.Hash
+	var address types.Address
+	if viaSmartAccount {
+		hash, address, err = c.DeployContract(
+			ctx, opts.ShardId, opts.SmartAccount, payload, opts.Value, opts.Fee, opts.PrivateKey)
+	} else {
+		hash, address, err = c.DeployExternal(ctx, opts.ShardId, payload, opts.Fee)
+	}
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	return NewBoundContract(address, contractAbi, c), hash, nil
+}
+
+// FindLogs returns the logs of the event emitted by the contract in the receipt and in the receipts
+// of the transactions it has produced (e.g., the async calls of a smart account).
+func (c *BoundContract) FindLogs(receipt *jsonrpc.RPCReceipt, event string) ([]*types.Log, error) {
+	ev, ok := c.abi.Events[event]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found in the ABI", event)
+	}
+
+	var logs []*types.Log
+	var walk func(receipt *jsonrpc.RPCReceipt)
+	walk = func(receipt *jsonrpc.RPCReceipt) {
+		if receipt == nil {
+			return
+		}
+		for _, log := range receipt.Logs {
+			if log == nil || log.Log == nil || log.Address != c.address {
+				continue
+			}
+			if !ev.Anonymous && (len(log.Topics) == 0 || log.Topics[0] != ev.ID) {
+				continue
+			}
+			logs = append(logs, log.Log)
+		}
+		for _, out := range receipt.OutReceipts {
+			walk(out)
+		}
+	}
+	walk(receipt)
+	return logs, nil
+}
+
+// UnpackLog returns the arguments of the event in the order of the ABI.
+// The indexed arguments of dynamic types are returned as the hashes stored in the topics.
+func (c *BoundContract) UnpackLog(event string, log *types.Log) ([]any, error) {
+	ev, ok := c.abi.Events[event]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found in the ABI", event)
+	}
+
+	topics := log.Topics
+	if !ev.Anonymous {
+		if len(topics) == 0 || topics[0] != ev.ID {
+			return nil, fmt.Errorf("%w %s", ErrEventMismatch, event)
+		}
+		topics = topics[1:]
+	}
+
+	data, err := ev.Inputs.NonIndexed().UnpackValues(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s data: %w", event, err)
+	}
+
+	values := make([]any, 0, len(ev.Inputs))
+	for _, input := range ev.Inputs {
+		if !input.Indexed {
+			values = append(values, data[0])
+			data = data[1:]
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("%w %s: not enough topics", ErrEventMismatch, event)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+
+		switch input.Type.T {
+		case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			values = append(values, topic)
+		default:
+			value, err := abi.Arguments{{Type: input.Type}}.UnpackValues(topic.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("failed to unpack %s topic %s: %w", event, input.Name, err)
+			}
+			values = append(values, value[0])
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,264 @@
+package bind_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/client/bind"
+	"github.com/NilFoundation/nil/nil/client/bind/internal/testcontract"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SuiteBind struct {
+	suite.Suite
+
+	client  *client.ClientMock
+	address types.Address
+	counter *testcontract.Counter
+}
+
+func (s *SuiteBind) SetupTest() {
+	s.client = &client.ClientMock{}
+	s.address = types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+
+	var err error
+	s.counter, err = testcontract.NewCounter(s.address, s.client)
+	s.Require().NoError(err)
+}
+
+// mockCall makes the client return the packed outputs of the method.
+func (s *SuiteBind) mockCall(method string, outputs ...any) {
+	s.T().Helper()
+
+	contractAbi := s.counter.Contract.Abi()
+	data, err := contractAbi.Methods[method].Outputs.Pack(outputs...)
+	s.Require().NoError(err)
+
+	s.client.CallFunc = func(
+		_ context.Context, args *jsonrpc.CallArgs, blockId any, _ *jsonrpc.StateOverrides,
+	) (*jsonrpc.CallRes, error) {
+		s.Equal(s.address, args.To)
+		s.Equal(contractAbi.Methods[method].ID, []byte(*args.Data)[:4])
+		s.Equal("latest", blockId)
+		return &jsonrpc.CallRes{Data: data}, nil
+	}
+}
+
+func (s *SuiteBind) TestCall() {
+	ctx := s.T().Context()
+
+	s.Run("Single", func() {
+		s.mockCall("get", int32(-5))
+		value, err := s.counter.Get(ctx, nil)
+		s.Require().NoError(err)
+		s.Equal(int32(-5), value)
+	})
+
+	s.Run("Multiple", func() {
+		owner := types.ShardAndHexToAddress(types.BaseShardId, "0x5678")
+		s.mockCall("getState", big.NewInt(100), owner)
+		state, err := s.counter.GetState(ctx, nil)
+		s.Require().NoError(err)
+		s.Equal(big.NewInt(100), state.Total)
+		s.Equal(owner, state.Owner)
+	})
+
+	s.Run("Struct", func() {
+		s.mockCall("getPoint", testcontract.CounterPoint{X: 1, Y: -2})
+		point, err := s.counter.GetPoint(ctx, nil, 3)
+		s.Require().NoError(err)
+		s.Equal(testcontract.CounterPoint{X: 1, Y: -2}, point)
+	})
+
+	s.Run("Error", func() {
+		s.client.CallFunc = func(
+			context.Context, *jsonrpc.CallArgs, any, *jsonrpc.StateOverrides,
+		) (*jsonrpc.CallRes, error) {
+			return &jsonrpc.CallRes{Error: "execution reverted"}, nil
+		}
+		_, err := s.counter.Get(ctx, nil)
+		s.Require().ErrorIs(err, bind.ErrCallFailed)
+	})
+}
+
+func (s *SuiteBind) TestTransact() {
+	ctx := s.T().Context()
+	smartAccount := types.ShardAndHexToAddress(types.BaseShardId, "0x9abc")
+	hash := common.HexToHash("0x01")
+
+	s.Run("SmartAccount", func() {
+		tokens := []types.TokenBalance{{Token: types.TokenId(smartAccount), Balance: types.NewValueFromUint64(7)}}
+		s.client.SendTransactionViaSmartAccountFunc = func(
+			_ context.Context, from types.Address, bytecode types.Code, _ types.FeePack, value types.Value,
+			txnTokens []types.TokenBalance, to types.Address, _ *ecdsa.PrivateKey,
+		) (common.Hash, error) {
+			s.Equal(smartAccount, from)
+			s.Equal(s.address, to)
+			s.Equal(types.NewValueFromUint64(10), value)
+			s.Equal(tokens, txnTokens)
+
+			expected, err := s.counter.Contract.Abi().Pack("add0", int32(2), int32(3))
+			s.Require().NoError(err)
+			s.Equal(types.Code(expected), bytecode)
+			return hash, nil
+		}
+
+		res, err := s.counter.Add0(ctx, &bind.TransactOpts{
+			SmartAccount: smartAccount,
+			Value:        types.NewValueFromUint64(10),
+			Tokens:       tokens,
+		}, 2, 3)
+		s.Require().NoError(err)
+		s.Equal(hash, res)
+	})
+
+	s.Run("External", func() {
+		s.client.SendExternalTransactionFunc = func(
+			_ context.Context, calldata types.Code, to types.Address, _ *ecdsa.PrivateKey, _ types.FeePack,
+		) (common.Hash, error) {
+			s.Equal(s.address, to)
+			s.Equal(s.counter.Contract.Abi().Methods["setPoints"].ID, []byte(calldata[:4]))
+			return hash, nil
+		}
+
+		res, err := s.counter.SetPoints(ctx, &bind.TransactOpts{}, []testcontract.CounterPoint{{X: 1, Y: 2}})
+		s.Require().NoError(err)
+		s.Equal(hash, res)
+
+		_, err = s.counter.Deposit(ctx, &bind.TransactOpts{Value: types.NewValueFromUint64(1)})
+		s.Require().ErrorIs(err, bind.ErrValueNotAllowed)
+	})
+}
+
+func (s *SuiteBind) TestDeploy() {
+	owner := types.ShardAndHexToAddress(types.BaseShardId, "0x5678")
+	salt := common.HexToHash("0x02")
+	const shardId = types.ShardId(2)
+
+	expected, err := testcontract.CounterAddress(shardId, salt, 5, owner)
+	s.Require().NoError(err)
+	s.Equal(shardId, expected.ShardId())
+
+	s.client.DeployExternalFunc = func(
+		_ context.Context, txnShardId types.ShardId, payload types.DeployPayload, _ types.FeePack,
+	) (common.Hash, types.Address, error) {
+		s.Equal(shardId, txnShardId)
+		return common.HexToHash("0x03"), types.CreateAddress(txnShardId, payload), nil
+	}
+
+	counter, hash, err := testcontract.DeployCounter(s.T().Context(), &bind.DeployOpts{
+		ShardId: shardId,
+		Salt:    salt,
+	}, s.client, 5, owner)
+	s.Require().NoError(err)
+	s.Equal(common.HexToHash("0x03"), hash)
+	s.Equal(expected, counter.Contract.Address())
+}
+
+func (s *SuiteBind) TestEvents() {
+	contractAbi := s.counter.Contract.Abi()
+	sender := types.ShardAndHexToAddress(types.BaseShardId, "0x5678")
+
+	addedLog := func(address types.Address, value int32) *jsonrpc.RPCLog {
+		data, err := contractAbi.Events["Added"].Inputs.NonIndexed().Pack(value)
+		s.Require().NoError(err)
+		return &jsonrpc.RPCLog{Log: &types.Log{
+			Address: address,
+			Topics:  []common.Hash{contractAbi.Events["Added"].ID, common.BytesToHash(sender.Bytes())},
+			Data:    data,
+		}}
+	}
+
+	values := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	data, err := contractAbi.Events["Named"].Inputs.NonIndexed().Pack(values)
+	s.Require().NoError(err)
+	namedLog := &jsonrpc.RPCLog{Log: &types.Log{
+		Address: s.address,
+		Topics:  []common.Hash{contractAbi.Events["Named"].ID, common.HexToHash("0xabcd")},
+		Data:    data,
+	}}
+
+	// The events are emitted by the contract called asynchronously by a smart account.
+	receipt := &jsonrpc.RPCReceipt{
+		Logs: []*jsonrpc.RPCLog{addedLog(s.address, 100)},
+		OutReceipts: []*jsonrpc.RPCReceipt{
+			{Logs: []*jsonrpc.RPCLog{addedLog(s.address, 1), namedLog}},
+			{
+				Logs: []*jsonrpc.RPCLog{addedLog(sender, 3)},
+				OutReceipts: []*jsonrpc.RPCReceipt{
+					{Logs: []*jsonrpc.RPCLog{addedLog(s.address, 2)}},
+				},
+			},
+		},
+	}
+
+	added, err := s.counter.FindAdded(receipt)
+	s.Require().NoError(err)
+	s.Require().Len(added, 3)
+	for i, value := range []int32{100, 1, 2} {
+		s.Equal(sender, added[i].Sender)
+		s.Equal(value, added[i].Value)
+		s.Equal(s.address, added[i].Raw.Address)
+	}
+
+	named, err := s.counter.FindNamed(receipt)
+	s.Require().NoError(err)
+	s.Require().Len(named, 1)
+	s.Equal(common.HexToHash("0xabcd"), named[0].Name)
+	s.Equal(values, named[0].Values)
+
+	_, err = s.counter.ParseAdded(namedLog.Log)
+	s.Require().ErrorIs(err, bind.ErrEventMismatch)
+}
+
+func TestSuiteBind(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteBind))
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	abiData, err := os.ReadFile("internal/testcontract/Counter.abi")
+	require.NoError(t, err)
+	binData, err := os.ReadFile("internal/testcontract/Counter.bin")
+	require.NoError(t, err)
+	expected, err := os.ReadFile("internal/testcontract/counter.go")
+	require.NoError(t, err)
+
+	code, err := bind.Generate("testcontract", bind.Contract{
+		Type: "Counter",
+		ABI:  string(abiData),
+		Bin:  string(binData),
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(code), "run `go generate` in internal/testcontract")
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := bind.Generate("testcontract")
+		require.Error(t, err)
+
+		_, err = bind.Generate("test-contract", bind.Contract{Type: "Counter", ABI: string(abiData)})
+		require.Error(t, err)
+
+		_, err = bind.Generate("testcontract", bind.Contract{Type: "counter", ABI: string(abiData)})
+		require.Error(t, err)
+
+		_, err = bind.Generate("testcontract", bind.Contract{Type: "Counter", ABI: "{"})
+		require.Error(t, err)
+
+		_, err = bind.Generate("testcontract", bind.Contract{Type: "Counter", ABI: string(abiData), Bin: "0xzz"})
+		require.Error(t, err)
+	})
+}
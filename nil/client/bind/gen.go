@@ -0,0 +1,365 @@
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+)
+
+// Contract describes a contract to generate the binding of.
+type Contract struct {
+	// Type is the name of the Go type of the binding.
+	Type string
+	// ABI is the JSON ABI of the contract.
+	ABI string
+	// Bin is the hex bytecode of the contract. The deployment helpers are generated only if it is set.
+	Bin string
+}
+
+type tmplArg struct {
+	Name string
+	Type string
+}
+
+type tmplMethod struct {
+	Name      string
+	AbiName   string
+	RawName   string
+	Signature string
+	Inputs    []tmplArg
+	Outputs   []tmplArg
+	// OutputType is the type of the result, a generated struct if the method has several outputs.
+	OutputType string
+}
+
+type tmplEvent struct {
+	Name      string
+	AbiName   string
+	RawName   string
+	Type      string
+	Signature string
+	Fields    []tmplArg
+}
+
+type tmplContract struct {
+	Type        string
+	ABI         string
+	Bin         string
+	Constructor []tmplArg
+	Calls       []*tmplMethod
+	Transacts   []*tmplMethod
+	Events      []*tmplEvent
+}
+
+type tmplStruct struct {
+	Name   string
+	Fields []tmplArg
+}
+
+type tmplData struct {
+	Package   string
+	Contracts []*tmplContract
+	Structs   []*tmplStruct
+}
+
+// generator holds the state shared by the contracts of a file, i.e. the Go types of the ABI structs.
+type generator struct {
+	structs     []*tmplStruct
+	structNames map[string]string
+}
+
+// Generate returns the Go source of the bindings of the contracts in the package.
+func Generate(pkg string, contracts ...Contract) ([]byte, error) {
+	if len(contracts) == 0 {
+		return nil, errors.New("no contracts to generate bindings for")
+	}
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	g := &generator{structNames: make(map[string]string)}
+	data := &tmplData{Package: pkg}
+
+	types := make(map[string]struct{})
+	for _, contract := range contracts {
+		if !token.IsIdentifier(contract.Type) || !token.IsExported(contract.Type) {
+			return nil, fmt.Errorf("invalid contract type name %q", contract.Type)
+		}
+		if _, ok := types[contract.Type]; ok {
+			return nil, fmt.Errorf("duplicate contract type name %q", contract.Type)
+		}
+		types[contract.Type] = struct{}{}
+
+		c, err := g.contract(contract)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %w", contract.Type, err)
+		}
+		data.Contracts = append(data.Contracts, c)
+	}
+	data.Structs = g.structs
+
+	var buf bytes.Buffer
+	if err := bindingTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return code, nil
+}
+
+func (g *generator) contract(contract Contract) (*tmplContract, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(contract.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(contract.ABI)); err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	res := &tmplContract{
+		Type: contract.Type,
+		ABI:  strconv.Quote(compacted.String()),
+	}
+	if contract.Bin != "" {
+		bin := strings.TrimSpace(contract.Bin)
+		if !strings.HasPrefix(bin, "0x") {
+			bin = "0x" + bin
+		}
+		if _, err := hexutil.DecodeHex(bin); err != nil {
+			return nil, fmt.Errorf("invalid bytecode: %w", err)
+		}
+		res.Bin = strconv.Quote(bin)
+		if res.Constructor, err = g.params(contractAbi.Constructor.Inputs); err != nil {
+			return nil, fmt.Errorf("constructor: %w", err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(contractAbi.Methods)) {
+		method := contractAbi.Methods[name]
+		m, err := g.method(contract.Type, method)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", method.Name, err)
+		}
+		if method.IsConstant() {
+			res.Calls = append(res.Calls, m)
+		} else {
+			res.Transacts = append(res.Transacts, m)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(contractAbi.Events)) {
+		event := contractAbi.Events[name]
+		e, err := g.event(contract.Type, event)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.Name, err)
+		}
+		res.Events = append(res.Events, e)
+	}
+	return res, nil
+}
+
+func (g *generator) method(contractType string, method abi.Method) (*tmplMethod, error) {
+	res := &tmplMethod{
+		Name:      methodName(method.Name),
+		AbiName:   method.Name,
+		RawName:   method.RawName,
+		Signature: method.String(),
+	}
+	var err error
+	if res.Inputs, err = g.params(method.Inputs); err != nil {
+		return nil, err
+	}
+	if !method.IsConstant() {
+		return res, nil
+	}
+
+	for i, output := range method.Outputs {
+		typ, err := g.goType(output.Type)
+		if err != nil {
+			return nil, err
+		}
+		res.Outputs = append(res.Outputs, tmplArg{Name: fieldName(output.Name, "Out", i), Type: typ})
+	}
+	switch len(res.Outputs) {
+	case 0:
+	case 1:
+		res.OutputType = res.Outputs[0].Type
+	default:
+		res.OutputType = contractType + res.Name + "Output"
+	}
+	return res, nil
+}
+
+func (g *generator) event(contractType string, event abi.Event) (*tmplEvent, error) {
+	res := &tmplEvent{
+		Name:      methodName(event.Name),
+		AbiName:   event.Name,
+		RawName:   event.RawName,
+		Signature: event.String(),
+	}
+	res.Type = contractType + res.Name
+	for i, input := range event.Inputs {
+		// Only the hash of a dynamic value is stored in the topic.
+		typ := "common.Hash"
+		if !input.Indexed || !isHashedInTopic(input.Type) {
+			var err error
+			if typ, err = g.goType(input.Type); err != nil {
+				return nil, err
+			}
+		}
+		res.Fields = append(res.Fields, tmplArg{Name: fieldName(input.Name, "Arg", i), Type: typ})
+	}
+	return res, nil
+}
+
+func isHashedInTopic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+func (g *generator) params(args abi.Arguments) ([]tmplArg, error) {
+	res := make([]tmplArg, 0, len(args))
+	for i, arg := range args {
+		typ, err := g.goType(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, tmplArg{Name: paramName(arg.Name, i), Type: typ})
+	}
+	return res, nil
+}
+
+// goType returns the Go type the ABI type is packed from and unpacked to.
+func (g *generator) goType(t abi.Type) (string, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		switch t.Size {
+		case 8, 16, 32, 64:
+			if t.T == abi.UintTy {
+				return "uint" + strconv.Itoa(t.Size), nil
+			}
+			return "int" + strconv.Itoa(t.Size), nil
+		}
+		return "*big.Int", nil
+	case abi.BoolTy:
+		return "bool", nil
+	case abi.StringTy:
+		return "string", nil
+	case abi.AddressTy:
+		return "types.Address", nil
+	case abi.BytesTy:
+		return "[]byte", nil
+	case abi.FixedBytesTy:
+		return "[" + strconv.Itoa(t.Size) + "]byte", nil
+	case abi.HashTy, abi.FixedPointTy:
+		return "[32]byte", nil
+	case abi.FunctionTy:
+		return "[24]byte", nil
+	case abi.SliceTy:
+		elem, err := g.goType(*t.Elem)
+		return "[]" + elem, err
+	case abi.ArrayTy:
+		elem, err := g.goType(*t.Elem)
+		return "[" + strconv.Itoa(t.Size) + "]" + elem, err
+	case abi.TupleTy:
+		return g.structType(t)
+	}
+	return "", fmt.Errorf("unsupported ABI type %s", t.String())
+}
+
+func (g *generator) structType(t abi.Type) (string, error) {
+	// The structs without a name in the ABI are identified by their fields.
+	key := t.TupleRawName
+	if key == "" {
+		key = t.String()
+	}
+	if name, ok := g.structNames[key]; ok {
+		return name, nil
+	}
+
+	name := t.TupleRawName
+	if name == "" {
+		name = "Struct" + strconv.Itoa(len(g.structs))
+	}
+	name = capitalize(name)
+	// Register the name before the fields, so that the field types do not take it.
+	g.structNames[key] = name
+	s := &tmplStruct{Name: name}
+	g.structs = append(g.structs, s)
+
+	for i, elem := range t.TupleElems {
+		typ, err := g.goType(*elem)
+		if err != nil {
+			return "", err
+		}
+		// The field names must match the ones the ABI packs the tuples from.
+		s.Fields = append(s.Fields, tmplArg{Name: abi.ToCamelCase(t.TupleRawNames[i]), Type: typ})
+	}
+	return name, nil
+}
+
+// reservedNames are the names used by the generated code, which the parameters are renamed from.
+var reservedNames = map[string]struct{}{
+	"binding":     {},
+	"c":           {},
+	"contract":    {},
+	"contractAbi": {},
+	"ctx":         {},
+	"err":         {},
+	"opts":        {},
+	"out":         {},
+	"salt":        {},
+	"shardId":     {},
+}
+
+func paramName(name string, i int) string {
+	if name == "" {
+		return "arg" + strconv.Itoa(i)
+	}
+	res := abi.ToCamelCase(name)
+	res = string(unicode.ToLower(rune(res[0]))) + res[1:]
+	if _, ok := reservedNames[res]; ok || token.IsKeyword(res) {
+		res += "_"
+	}
+	return res
+}
+
+func fieldName(name string, prefix string, i int) string {
+	if name == "" {
+		return prefix + strconv.Itoa(i)
+	}
+	return capitalize(abi.ToCamelCase(name))
+}
+
+func methodName(name string) string {
+	res := capitalize(abi.ToCamelCase(name))
+	// The binding has the Contract field.
+	if res == "Contract" {
+		res += "_"
+	}
+	return res
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+var bindingTemplate = template.Must(template.New("binding").Parse(bindingTemplateSource))
@@ -0,0 +1,233 @@
+// Code generated by nil abigen. DO NOT EDIT.
+
+package testcontract
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/client/bind"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// Reference the imports that are not used by every binding.
+var (
+	_ = big.NewInt
+	_ = abi.ConvertType
+	_ = common.EmptyHash
+	_ = types.EmptyAddress
+	_ *jsonrpc.RPCReceipt
+)
+
+// CounterPoint is a struct of the ABI.
+type CounterPoint struct {
+	X int32
+	Y int32
+}
+
+// CounterMetaData contains the ABI and the bytecode of the Counter contract.
+var CounterMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"initial\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"}]},{\"type\":\"function\",\"name\":\"get\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"int32\",\"internalType\":\"int32\"}]},{\"type\":\"function\",\"name\":\"getState\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"total\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"}]},{\"type\":\"function\",\"name\":\"getPoint\",\"stateMutability\":\"view\",\"inputs\":[{\"name\":\"index\",\"type\":\"uint64\",\"internalType\":\"uint64\"}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"struct Counter.Point\",\"components\":[{\"name\":\"x\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"y\",\"type\":\"int32\",\"internalType\":\"int32\"}]}]},{\"type\":\"function\",\"name\":\"add\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"value\",\"type\":\"int32\",\"internalType\":\"int32\"}],\"outputs\":[]},{\"type\":\"function\",\"name\":\"add\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"value\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"times\",\"type\":\"int32\",\"internalType\":\"int32\"}],\"outputs\":[]},{\"type\":\"function\",\"name\":\"deposit\",\"stateMutability\":\"payable\",\"inputs\":[],\"outputs\":[]},{\"type\":\"function\",\"name\":\"setPoints\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"points\",\"type\":\"tuple[]\",\"internalType\":\"struct Counter.Point[]\",\"components\":[{\"name\":\"x\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"y\",\"type\":\"int32\",\"internalType\":\"int32\"}]}],\"outputs\":[]},{\"type\":\"event\",\"name\":\"Added\",\"anonymous\":false,\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"value\",\"type\":\"int32\",\"indexed\":false,\"internalType\":\"int32\"}]},{\"type\":\"event\",\"name\":\"Named\",\"anonymous\":false,\"inputs\":[{\"name\":\"name\",\"type\":\"string\",\"indexed\":true,\"internalType\":\"string\"},{\"name\":\"values\",\"type\":\"uint256[]\",\"indexed\":false,\"internalType\":\"uint256[]\"}]}]",
+	Bin: "0x6080604052348015600f57600080fd5b50603f80601d6000396000f3fe6080604052600080fdfea164736f6c6343000815000a",
+}
+
+// Counter is a binding of the Counter contract.
+type Counter struct {
+	Contract *bind.BoundContract
+}
+
+// NewCounter creates a binding of the Counter contract deployed at the address.
+func NewCounter(address types.Address, c client.Client) (*Counter, error) {
+	contractAbi, err := CounterMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return &Counter{Contract: bind.NewBoundContract(address, contractAbi, c)}, nil
+}
+
+// DeployCounter deploys the Counter contract.
+// The contract is deployed once the returned transaction is processed.
+func DeployCounter(
+	ctx context.Context, opts *bind.DeployOpts, c client.Client, initial int32, owner types.Address,
+) (*Counter, common.Hash, error) {
+	contractAbi, err := CounterMetaData.GetAbi()
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	bytecode, err := CounterMetaData.GetBin()
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	contract, hash, err := bind.DeployContract(ctx, opts, contractAbi, bytecode, c, initial, owner)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	return &Counter{Contract: contract}, hash, nil
+}
+
+// CounterAddress returns the address the Counter contract is deployed to with the given arguments.
+func CounterAddress(
+	shardId types.ShardId, salt common.Hash, initial int32, owner types.Address,
+) (types.Address, error) {
+	contractAbi, err := CounterMetaData.GetAbi()
+	if err != nil {
+		return types.EmptyAddress, err
+	}
+	bytecode, err := CounterMetaData.GetBin()
+	if err != nil {
+		return types.EmptyAddress, err
+	}
+	return bind.ContractAddress(contractAbi, bytecode, shardId, salt, initial, owner)
+}
+
+// Get calls the get method.
+//
+// Solidity: function get() view returns(int32)
+func (binding *Counter) Get(ctx context.Context, opts *bind.CallOpts) (int32, error) {
+	out, err := binding.Contract.Call(ctx, opts, "get")
+	if err != nil {
+		return *new(int32), err
+	}
+	return *abi.ConvertType(out[0], new(int32)).(*int32), nil
+}
+
+// GetPoint calls the getPoint method.
+//
+// Solidity: function getPoint(uint64 index) view returns((int32,int32))
+func (binding *Counter) GetPoint(ctx context.Context, opts *bind.CallOpts, index uint64) (CounterPoint, error) {
+	out, err := binding.Contract.Call(ctx, opts, "getPoint", index)
+	if err != nil {
+		return *new(CounterPoint), err
+	}
+	return *abi.ConvertType(out[0], new(CounterPoint)).(*CounterPoint), nil
+}
+
+// CounterGetStateOutput is the result of the getState method of the Counter contract.
+type CounterGetStateOutput struct {
+	Total *big.Int
+	Owner types.Address
+}
+
+// GetState calls the getState method.
+//
+// Solidity: function getState() view returns(uint256 total, address owner)
+func (binding *Counter) GetState(ctx context.Context, opts *bind.CallOpts) (CounterGetStateOutput, error) {
+	var res CounterGetStateOutput
+	out, err := binding.Contract.Call(ctx, opts, "getState")
+	if err != nil {
+		return res, err
+	}
+	res.Total = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	res.Owner = *abi.ConvertType(out[1], new(types.Address)).(*types.Address)
+	return res, nil
+}
+
+// Add sends a transaction calling the add method.
+//
+// Solidity: function add(int32 value) returns()
+func (binding *Counter) Add(ctx context.Context, opts *bind.TransactOpts, value int32) (common.Hash, error) {
+	return binding.Contract.Transact(ctx, opts, "add", value)
+}
+
+// Add0 sends a transaction calling the add method.
+//
+// Solidity: function add(int32 value, int32 times) returns()
+func (binding *Counter) Add0(ctx context.Context, opts *bind.TransactOpts, value int32, times int32) (common.Hash, error) {
+	return binding.Contract.Transact(ctx, opts, "add0", value, times)
+}
+
+// Deposit sends a transaction calling the deposit method.
+//
+// Solidity: function deposit() payable returns()
+func (binding *Counter) Deposit(ctx context.Context, opts *bind.TransactOpts) (common.Hash, error) {
+	return binding.Contract.Transact(ctx, opts, "deposit")
+}
+
+// SetPoints sends a transaction calling the setPoints method.
+//
+// Solidity: function setPoints((int32,int32)[] points) returns()
+func (binding *Counter) SetPoints(ctx context.Context, opts *bind.TransactOpts, points []CounterPoint) (common.Hash, error) {
+	return binding.Contract.Transact(ctx, opts, "setPoints", points)
+}
+
+// CounterAdded is the Added event of the Counter contract.
+type CounterAdded struct {
+	Sender types.Address
+	Value  int32
+	Raw    *types.Log
+}
+
+// ParseAdded decodes the Added event from the log.
+//
+// Solidity: event Added(address indexed sender, int32 value)
+func (binding *Counter) ParseAdded(log *types.Log) (*CounterAdded, error) {
+	values, err := binding.Contract.UnpackLog("Added", log)
+	if err != nil {
+		return nil, err
+	}
+	event := &CounterAdded{Raw: log}
+	event.Sender = *abi.ConvertType(values[0], new(types.Address)).(*types.Address)
+	event.Value = *abi.ConvertType(values[1], new(int32)).(*int32)
+	return event, nil
+}
+
+// FindAdded returns the Added events emitted by the contract
+// in the receipt and in the receipts of the transactions it has produced.
+func (binding *Counter) FindAdded(receipt *jsonrpc.RPCReceipt) ([]*CounterAdded, error) {
+	logs, err := binding.Contract.FindLogs(receipt, "Added")
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*CounterAdded, 0, len(logs))
+	for _, log := range logs {
+		event, err := binding.ParseAdded(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// CounterNamed is the Named event of the Counter contract.
+type CounterNamed struct {
+	Name   common.Hash
+	Values []*big.Int
+	Raw    *types.Log
+}
+
+// ParseNamed decodes the Named event from the log.
+//
+// Solidity: event Named(string indexed name, uint256[] values)
+func (binding *Counter) ParseNamed(log *types.Log) (*CounterNamed, error) {
+	values, err := binding.Contract.UnpackLog("Named", log)
+	if err != nil {
+		return nil, err
+	}
+	event := &CounterNamed{Raw: log}
+	event.Name = *abi.ConvertType(values[0], new(common.Hash)).(*common.Hash)
+	event.Values = *abi.ConvertType(values[1], new([]*big.Int)).(*[]*big.Int)
+	return event, nil
+}
+
+// FindNamed returns the Named events emitted by the contract
+// in the receipt and in the receipts of the transactions it has produced.
+func (binding *Counter) FindNamed(receipt *jsonrpc.RPCReceipt) ([]*CounterNamed, error) {
+	logs, err := binding.Contract.FindLogs(receipt, "Named")
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*CounterNamed, 0, len(logs))
+	for _, log := range logs {
+		event, err := binding.ParseNamed(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
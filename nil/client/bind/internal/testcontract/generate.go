@@ -0,0 +1,4 @@
+// Package testcontract holds the binding of a contract used to test the generated code.
+package testcontract
+
+//go:generate go run ../../../../cmd/nil abigen --abi Counter.abi --bin Counter.bin --pkg testcontract --out counter.go
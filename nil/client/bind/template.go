@@ -0,0 +1,181 @@
+package bind
+
+//nolint:lll
+const bindingTemplateSource = `// Code generated by nil abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/client/bind"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// Reference the imports that are not used by every binding.
+var (
+	_ = big.NewInt
+	_ = abi.ConvertType
+	_ = common.EmptyHash
+	_ = types.EmptyAddress
+	_ *jsonrpc.RPCReceipt
+)
+{{range .Structs}}
+// {{.Name}} is a struct of the ABI.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+{{end}}
+{{- range $contract := .Contracts}}
+{{- $type := .Type}}
+// {{$type}}MetaData contains the ABI{{if .Bin}} and the bytecode{{end}} of the {{$type}} contract.
+var {{$type}}MetaData = &bind.MetaData{
+	ABI: {{.ABI}},
+{{- if .Bin}}
+	Bin: {{.Bin}},
+{{- end}}
+}
+
+// {{$type}} is a binding of the {{$type}} contract.
+type {{$type}} struct {
+	Contract *bind.BoundContract
+}
+
+// New{{$type}} creates a binding of the {{$type}} contract deployed at the address.
+func New{{$type}}(address types.Address, c client.Client) (*{{$type}}, error) {
+	contractAbi, err := {{$type}}MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return &{{$type}}{Contract: bind.NewBoundContract(address, contractAbi, c)}, nil
+}
+{{if .Bin}}
+// Deploy{{$type}} deploys the {{$type}} contract.
+// The contract is deployed once the returned transaction is processed.
+func Deploy{{$type}}(
+	ctx context.Context, opts *bind.DeployOpts, c client.Client{{range .Constructor}}, {{.Name}} {{.Type}}{{end}},
+) (*{{$type}}, common.Hash, error) {
+	contractAbi, err := {{$type}}MetaData.GetAbi()
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	bytecode, err := {{$type}}MetaData.GetBin()
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	contract, hash, err := bind.DeployContract(ctx, opts, contractAbi, bytecode, c{{range .Constructor}}, {{.Name}}{{end}})
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	return &{{$type}}{Contract: contract}, hash, nil
+}
+
+// {{$type}}Address returns the address the {{$type}} contract is deployed to with the given arguments.
+func {{$type}}Address(
+	shardId types.ShardId, salt common.Hash{{range .Constructor}}, {{.Name}} {{.Type}}{{end}},
+) (types.Address, error) {
+	contractAbi, err := {{$type}}MetaData.GetAbi()
+	if err != nil {
+		return types.EmptyAddress, err
+	}
+	bytecode, err := {{$type}}MetaData.GetBin()
+	if err != nil {
+		return types.EmptyAddress, err
+	}
+	return bind.ContractAddress(contractAbi, bytecode, shardId, salt{{range .Constructor}}, {{.Name}}{{end}})
+}
+{{end}}
+{{- range .Calls}}
+{{- if gt (len .Outputs) 1}}
+// {{.OutputType}} is the result of the {{.RawName}} method of the {{$type}} contract.
+type {{.OutputType}} struct {
+{{- range .Outputs}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+{{end}}
+// {{.Name}} calls the {{.RawName}} method.
+//
+// Solidity: {{.Signature}}
+func (binding *{{$type}}) {{.Name}}(ctx context.Context, opts *bind.CallOpts{{range .Inputs}}, {{.Name}} {{.Type}}{{end}}) (
+	{{- if .OutputType}}{{.OutputType}}, {{end}}error) {
+{{- if not .Outputs}}
+	_, err := binding.Contract.Call(ctx, opts, "{{.AbiName}}"{{range .Inputs}}, {{.Name}}{{end}})
+	return err
+{{- else if eq (len .Outputs) 1}}
+	out, err := binding.Contract.Call(ctx, opts, "{{.AbiName}}"{{range .Inputs}}, {{.Name}}{{end}})
+	if err != nil {
+		return *new({{.OutputType}}), err
+	}
+	return *abi.ConvertType(out[0], new({{.OutputType}})).(*{{.OutputType}}), nil
+{{- else}}
+	var res {{.OutputType}}
+	out, err := binding.Contract.Call(ctx, opts, "{{.AbiName}}"{{range .Inputs}}, {{.Name}}{{end}})
+	if err != nil {
+		return res, err
+	}
+{{- range $i, $out := .Outputs}}
+	res.{{.Name}} = *abi.ConvertType(out[{{$i}}], new({{.Type}})).(*{{.Type}})
+{{- end}}
+	return res, nil
+{{- end}}
+}
+{{end}}
+{{- range .Transacts}}
+// {{.Name}} sends a transaction calling the {{.RawName}} method.
+//
+// Solidity: {{.Signature}}
+func (binding *{{$type}}) {{.Name}}(ctx context.Context, opts *bind.TransactOpts{{range .Inputs}}, {{.Name}} {{.Type}}{{end}}) (common.Hash, error) {
+	return binding.Contract.Transact(ctx, opts, "{{.AbiName}}"{{range .Inputs}}, {{.Name}}{{end}})
+}
+{{end}}
+{{- range .Events}}
+// {{.Type}} is the {{.RawName}} event of the {{$type}} contract.
+type {{.Type}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+	Raw *types.Log
+}
+
+// Parse{{.Name}} decodes the {{.RawName}} event from the log.
+//
+// Solidity: {{.Signature}}
+func (binding *{{$type}}) Parse{{.Name}}(log *types.Log) (*{{.Type}}, error) {
+	values, err := binding.Contract.UnpackLog("{{.AbiName}}", log)
+	if err != nil {
+		return nil, err
+	}
+	event := &{{.Type}}{Raw: log}
+{{- range $i, $field := .Fields}}
+	event.{{.Name}} = *abi.ConvertType(values[{{$i}}], new({{.Type}})).(*{{.Type}})
+{{- end}}
+	return event, nil
+}
+
+// Find{{.Name}} returns the {{.RawName}} events emitted by the contract
+// in the receipt and in the receipts of the transactions it has produced.
+func (binding *{{$type}}) Find{{.Name}}(receipt *jsonrpc.RPCReceipt) ([]*{{.Type}}, error) {
+	logs, err := binding.Contract.FindLogs(receipt, "{{.AbiName}}")
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*{{.Type}}, 0, len(logs))
+	for _, log := range logs {
+		event, err := binding.Parse{{.Name}}(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+{{end}}
+{{- end}}`
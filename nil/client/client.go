@@ -56,6 +56,17 @@ type Client interface {
 		blockId any,
 		stateOverride *jsonrpc.StateOverrides,
 	) (*jsonrpc.CallRes, error)
+	CallMany(
+		ctx context.Context,
+		calls []jsonrpc.CallArgs,
+		blockId any,
+		stateOverride *jsonrpc.StateOverrides,
+	) (*jsonrpc.CallManyRes, error)
+	SimulateTransaction(
+		ctx context.Context,
+		txn *types.ExternalTransaction,
+		blockId any,
+	) (*jsonrpc.SimulationRes, error)
 	GetCode(ctx context.Context, addr types.Address, blockId any) (types.Code, error)
 	GetBlock(ctx context.Context, shardId types.ShardId, blockId any, fullTx bool) (*jsonrpc.RPCBlock, error)
 	GetBlocksRange(
@@ -36,7 +36,7 @@ func NewEthClient(
 	localApi rawapi.NodeApi,
 	logger logging.Logger,
 ) (*DirectClient, error) {
-	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, true, false)
+	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, nil, true, false, nil)
 	debugApi := jsonrpc.NewDebugAPI(localApi, logger)
 	dbApi := jsonrpc.NewDbAPI(db, logger)
 	web3Api := jsonrpc.NewWeb3API(localApi)
@@ -321,6 +321,35 @@ func (c *DirectClient) Call(
 	return c.ethApi.Call(ctx, *args, transport.BlockNumberOrHash(blockNrOrHash), stateOverride)
 }
 
+func (c *DirectClient) CallMany(
+	ctx context.Context,
+	calls []jsonrpc.CallArgs,
+	blockId any,
+	stateOverride *jsonrpc.StateOverrides,
+) (*jsonrpc.CallManyRes, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ethApi.CallMany(ctx, calls, transport.BlockNumberOrHash(blockNrOrHash), stateOverride)
+}
+
+func (c *DirectClient) SimulateTransaction(
+	ctx context.Context,
+	txn *types.ExternalTransaction,
+	blockId any,
+) (*jsonrpc.SimulationRes, error) {
+	data, err := txn.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ethApi.SimulateTransaction(ctx, data, transport.BlockNumberOrHash(blockNrOrHash))
+}
+
 func (c *DirectClient) EstimateFee(
 	ctx context.Context,
 	args *jsonrpc.CallArgs,
@@ -432,6 +461,72 @@ func (c *DirectClient) DoPanicOnShard(ctx context.Context, shardId types.ShardId
 	return c.devApi.DoPanicOnShard(ctx, shardId)
 }
 
+func (c *DirectClient) SetBalance(ctx context.Context, address types.Address, balance types.Value) error {
+	return c.devApi.SetBalance(ctx, address, balance)
+}
+
+func (c *DirectClient) SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) error {
+	return c.devApi.SetCode(ctx, address, code)
+}
+
+func (c *DirectClient) SetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	value common.Hash,
+) error {
+	return c.devApi.SetStorageAt(ctx, address, key, value)
+}
+
+func (c *DirectClient) SetTokenBalance(
+	ctx context.Context,
+	address types.Address,
+	token types.TokenId,
+	balance types.Value,
+) error {
+	return c.devApi.SetTokenBalance(ctx, address, token, balance)
+}
+
+func (c *DirectClient) Impersonate(ctx context.Context, address types.Address) error {
+	return c.devApi.Impersonate(ctx, address)
+}
+
+func (c *DirectClient) StopImpersonating(ctx context.Context, address types.Address) error {
+	return c.devApi.StopImpersonating(ctx, address)
+}
+
+func (c *DirectClient) IncreaseTime(
+	ctx context.Context,
+	shardId types.ShardId,
+	seconds hexutil.Uint64,
+) (hexutil.Uint64, error) {
+	return c.devApi.IncreaseTime(ctx, shardId, seconds)
+}
+
+func (c *DirectClient) SetNextBlockTimestamp(
+	ctx context.Context,
+	shardId types.ShardId,
+	timestamp hexutil.Uint64,
+) error {
+	return c.devApi.SetNextBlockTimestamp(ctx, shardId, timestamp)
+}
+
+func (c *DirectClient) Mine(
+	ctx context.Context,
+	shardId types.ShardId,
+	count hexutil.Uint64,
+) (hexutil.Uint64, error) {
+	return c.devApi.Mine(ctx, shardId, count)
+}
+
+func (c *DirectClient) Snapshot(ctx context.Context) (hexutil.Uint64, error) {
+	return c.devApi.Snapshot(ctx)
+}
+
+func (c *DirectClient) Revert(ctx context.Context, id hexutil.Uint64) error {
+	return c.devApi.Revert(ctx, id)
+}
+
 func (c *DirectClient) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (jsonrpc.TxPoolStatus, error) {
 	return c.txPoolApi.GetTxpoolStatus(ctx, shardId)
 }
@@ -61,6 +61,8 @@ var (
 
 const (
 	Eth_call                             = "eth_call"
+	Eth_callMany                         = "eth_callMany"
+	Eth_simulateTransaction              = "eth_simulateTransaction"
 	Eth_estimateFee                      = "eth_estimateFee"
 	Eth_getCode                          = "eth_getCode"
 	Eth_getBlockByHash                   = "eth_getBlockByHash"
@@ -84,6 +86,17 @@ const (
 	Debug_getBootstrapConfig             = "debug_getBootstrapConfig"
 	Web3_clientVersion                   = "web3_clientVersion"
 	Dev_doPanicOnShard                   = "dev_doPanicOnShard"
+	Dev_setBalance                       = "dev_setBalance"
+	Dev_setCode                          = "dev_setCode"
+	Dev_setStorageAt                     = "dev_setStorageAt"
+	Dev_setTokenBalance                  = "dev_setTokenBalance"
+	Dev_impersonate                      = "dev_impersonate"
+	Dev_stopImpersonating                = "dev_stopImpersonating"
+	Dev_increaseTime                     = "dev_increaseTime"
+	Dev_setNextBlockTimestamp            = "dev_setNextBlockTimestamp"
+	Dev_mine                             = "dev_mine"
+	Dev_snapshot                         = "dev_snapshot"
+	Dev_revert                           = "dev_revert"
 	Txpool_getTxpoolStatus               = "txpool_getTxpoolStatus"
 	Txpool_getTxpoolContent              = "txpool_getTxpoolContent"
 )
@@ -698,6 +711,35 @@ func (c *Client) Call(
 	return simpleCall[*jsonrpc.CallRes](ctx, c, Eth_call, args, blockNrOrHash, stateOverride)
 }
 
+func (c *Client) CallMany(
+	ctx context.Context,
+	calls []jsonrpc.CallArgs,
+	blockId any,
+	stateOverride *jsonrpc.StateOverrides,
+) (*jsonrpc.CallManyRes, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return simpleCall[*jsonrpc.CallManyRes](ctx, c, Eth_callMany, calls, blockNrOrHash, stateOverride)
+}
+
+func (c *Client) SimulateTransaction(
+	ctx context.Context,
+	txn *types.ExternalTransaction,
+	blockId any,
+) (*jsonrpc.SimulationRes, error) {
+	data, err := txn.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return simpleCall[*jsonrpc.SimulationRes](ctx, c, Eth_simulateTransaction, hexutil.Bytes(data), blockNrOrHash)
+}
+
 func (c *Client) EstimateFee(
 	ctx context.Context,
 	args *jsonrpc.CallArgs,
@@ -954,6 +996,67 @@ func (c *Client) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uin
 	return 0, err
 }
 
+func (c *Client) SetBalance(ctx context.Context, address types.Address, balance types.Value) error {
+	_, err := c.call(ctx, Dev_setBalance, address, balance)
+	return err
+}
+
+func (c *Client) SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) error {
+	_, err := c.call(ctx, Dev_setCode, address, code)
+	return err
+}
+
+func (c *Client) SetStorageAt(ctx context.Context, address types.Address, key common.Hash, value common.Hash) error {
+	_, err := c.call(ctx, Dev_setStorageAt, address, key, value)
+	return err
+}
+
+func (c *Client) SetTokenBalance(
+	ctx context.Context,
+	address types.Address,
+	token types.TokenId,
+	balance types.Value,
+) error {
+	_, err := c.call(ctx, Dev_setTokenBalance, address, token, balance)
+	return err
+}
+
+func (c *Client) Impersonate(ctx context.Context, address types.Address) error {
+	_, err := c.call(ctx, Dev_impersonate, address)
+	return err
+}
+
+func (c *Client) StopImpersonating(ctx context.Context, address types.Address) error {
+	_, err := c.call(ctx, Dev_stopImpersonating, address)
+	return err
+}
+
+func (c *Client) IncreaseTime(
+	ctx context.Context,
+	shardId types.ShardId,
+	seconds hexutil.Uint64,
+) (hexutil.Uint64, error) {
+	return simpleCall[hexutil.Uint64](ctx, c, Dev_increaseTime, shardId, seconds)
+}
+
+func (c *Client) SetNextBlockTimestamp(ctx context.Context, shardId types.ShardId, timestamp hexutil.Uint64) error {
+	_, err := c.call(ctx, Dev_setNextBlockTimestamp, shardId, timestamp)
+	return err
+}
+
+func (c *Client) Mine(ctx context.Context, shardId types.ShardId, count hexutil.Uint64) (hexutil.Uint64, error) {
+	return simpleCall[hexutil.Uint64](ctx, c, Dev_mine, shardId, count)
+}
+
+func (c *Client) Snapshot(ctx context.Context) (hexutil.Uint64, error) {
+	return simpleCall[hexutil.Uint64](ctx, c, Dev_snapshot)
+}
+
+func (c *Client) Revert(ctx context.Context, id hexutil.Uint64) error {
+	_, err := c.call(ctx, Dev_revert, id)
+	return err
+}
+
 func (c *Client) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (jsonrpc.TxPoolStatus, error) {
 	return simpleCall[jsonrpc.TxPoolStatus](ctx, c, Txpool_getTxpoolStatus, shardId)
 }
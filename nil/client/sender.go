@@ -0,0 +1,435 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+var (
+	// ErrTransactionReplaced is returned when the seqno of the transaction is used by another transaction.
+	ErrTransactionReplaced = errors.New("seqno is used by another transaction")
+	// ErrTransactionStuck is returned when the transaction is not processed in time.
+	ErrTransactionStuck = errors.New("transaction is not processed")
+)
+
+type SenderConfig struct {
+	// PollInterval is the interval of polling the receipts.
+	PollInterval time.Duration
+	// StuckTimeout is the time without a receipt after which the transaction is considered stuck in the pool.
+	StuckTimeout time.Duration
+	// MaxRebroadcasts is the number of times a stuck transaction that is missing from the pool is rebroadcast.
+	MaxRebroadcasts int
+	// MaxFeeBumps is the number of times the fee of a stuck transaction is bumped.
+	MaxFeeBumps int
+	// MaxSendAttempts is the number of seqnos tried to send a transaction
+	// when the seqnos turn out to be used by other senders.
+	MaxSendAttempts int
+}
+
+func NewDefaultSenderConfig() SenderConfig {
+	return SenderConfig{
+		PollInterval:    500 * time.Millisecond,
+		StuckTimeout:    10 * time.Second,
+		MaxRebroadcasts: 2,
+		MaxFeeBumps:     3,
+		MaxSendAttempts: 5,
+	}
+}
+
+// PendingTransaction is a transaction sent by the Sender that has not been processed yet.
+type PendingTransaction struct {
+	to    types.Address
+	seqno types.Seqno
+
+	mu sync.Mutex
+	// txn is the last version of the transaction, its fee might have been bumped.
+	txn *types.ExternalTransaction
+	key *ecdsa.PrivateKey
+	// hashes are the hashes of all versions of the transaction, any of them can be processed.
+	hashes []common.Hash
+
+	rebroadcasts int
+	feeBumps     int
+	lastSent     time.Time
+	// gaps are the unused seqnos below the one of the transaction found when it got stuck.
+	gaps []types.Seqno
+}
+
+// Hash returns the hash of the last version of the transaction.
+func (p *PendingTransaction) Hash() common.Hash {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.hashes[len(p.hashes)-1]
+}
+
+func (p *PendingTransaction) Seqno() types.Seqno {
+	return p.seqno
+}
+
+func (p *PendingTransaction) To() types.Address {
+	return p.to
+}
+
+// Sender sends external transactions concurrently, reserving the seqnos with the SeqnoManager.
+// The transactions that are waited for are tracked until they are processed,
+// so that they are rebroadcast or their fees are bumped if they are stuck in the pool.
+type Sender struct {
+	client Client
+	seqnos *SeqnoManager
+	config SenderConfig
+	logger logging.Logger
+
+	mu       sync.Mutex
+	inFlight map[types.Address]map[types.Seqno]*PendingTransaction
+}
+
+func NewSender(c Client, config SenderConfig, logger logging.Logger) *Sender {
+	return &Sender{
+		client:   c,
+		seqnos:   NewSeqnoManager(c),
+		config:   config,
+		logger:   logger,
+		inFlight: make(map[types.Address]map[types.Seqno]*PendingTransaction),
+	}
+}
+
+func (s *Sender) Seqnos() *SeqnoManager {
+	return s.seqnos
+}
+
+func isSeqnoTaken(err error) bool {
+	return strings.Contains(err.Error(), txnpool.SeqnoTooLow.String()) ||
+		strings.Contains(err.Error(), txnpool.NotReplaced.String())
+}
+
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(err.Error(), txnpool.AlreadyKnown.String()) ||
+		strings.Contains(err.Error(), txnpool.DuplicateHash.String())
+}
+
+// Send sends an external transaction to the account. The transaction is signed with the key if it is set.
+// If the fee credit is zero, the fee is estimated.
+func (s *Sender) Send(
+	ctx context.Context, to types.Address, calldata types.Code, key *ecdsa.PrivateKey, fee types.FeePack,
+) (*PendingTransaction, error) {
+	txn := &types.ExternalTransaction{
+		To:                   to,
+		Data:                 calldata,
+		Kind:                 types.ExecutionTransactionKind,
+		FeeCredit:            fee.FeeCredit,
+		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
+		MaxFeePerGas:         fee.MaxFeePerGas,
+	}
+
+	var err error
+	for range max(s.config.MaxSendAttempts, 1) {
+		if txn.Seqno, err = s.seqnos.Reserve(ctx, to); err != nil {
+			return nil, err
+		}
+		if fee.FeeCredit.IsZero() {
+			if err = s.estimateFee(ctx, txn); err != nil {
+				s.seqnos.Release(to, txn.Seqno)
+				return nil, err
+			}
+		}
+
+		var hash common.Hash
+		if hash, err = s.sign(ctx, txn, key); err == nil {
+			return &PendingTransaction{
+				to:       to,
+				seqno:    txn.Seqno,
+				txn:      txn,
+				key:      key,
+				hashes:   []common.Hash{hash},
+				lastSent: time.Now(),
+			}, nil
+		}
+		if !isSeqnoTaken(err) {
+			s.seqnos.Release(to, txn.Seqno)
+			return nil, err
+		}
+
+		s.logger.Debug().Err(err).
+			Stringer(logging.FieldShardId, to.ShardId()).
+			Uint64("seqno", uint64(txn.Seqno)).
+			Msg("Seqno is used by another sender, resyncing")
+		if err := s.seqnos.Resync(ctx, to); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("failed to send transaction in %d attempts: %w", s.config.MaxSendAttempts, err)
+}
+
+// SendViaSmartAccount sends a transaction to the contract as an async call of the smart account.
+func (s *Sender) SendViaSmartAccount(
+	ctx context.Context,
+	smartAccount types.Address,
+	bytecode types.Code,
+	fee types.FeePack,
+	value types.Value,
+	tokens []types.TokenBalance,
+	contract types.Address,
+	key *ecdsa.PrivateKey,
+) (*PendingTransaction, error) {
+	calldata, err := CreateInternalTransactionPayload(bytecode, value, tokens, contract, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.Send(ctx, smartAccount, calldata, key, fee)
+}
+
+func (s *Sender) estimateFee(ctx context.Context, txn *types.ExternalTransaction) error {
+	res, err := EstimateFeeExternal(ctx, s.client, txn, "latest")
+	if err != nil {
+		return err
+	}
+	txn.FeeCredit = res.FeeCredit
+	txn.MaxFeePerGas = res.MaxBasFee.Add(res.AveragePriorityFee)
+	txn.MaxPriorityFeePerGas = res.AveragePriorityFee
+	return nil
+}
+
+func (s *Sender) sign(ctx context.Context, txn *types.ExternalTransaction, key *ecdsa.PrivateKey) (common.Hash, error) {
+	if key != nil {
+		if err := txn.Sign(key); err != nil {
+			return common.EmptyHash, err
+		}
+	}
+	return s.client.SendTransaction(ctx, txn)
+}
+
+func (s *Sender) track(p *PendingTransaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	txns, ok := s.inFlight[p.To()]
+	if !ok {
+		txns = make(map[types.Seqno]*PendingTransaction)
+		s.inFlight[p.To()] = txns
+	}
+	txns[p.Seqno()] = p
+}
+
+func (s *Sender) untrack(p *PendingTransaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if txns, ok := s.inFlight[p.To()]; ok && txns[p.Seqno()] == p {
+		delete(txns, p.Seqno())
+		if len(txns) == 0 {
+			delete(s.inFlight, p.To())
+		}
+	}
+}
+
+// pendingBelow returns the tracked transactions to the account with the seqnos in [from, to).
+func (s *Sender) pendingBelow(addr types.Address, from, to types.Seqno) map[types.Seqno]*PendingTransaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[types.Seqno]*PendingTransaction)
+	for seqno, p := range s.inFlight[addr] {
+		if seqno >= from && seqno < to {
+			res[seqno] = p
+		}
+	}
+	return res
+}
+
+// Wait waits for the transaction to be processed along with the transactions it has produced.
+// If the transaction gets stuck, the pool is checked for it and for the transactions with the preceding seqnos:
+// the missing ones that are waited for are rebroadcast, and if the transaction is in the pool, its fee is bumped.
+func (s *Sender) Wait(ctx context.Context, p *PendingTransaction, timeout time.Duration) (*jsonrpc.RPCReceipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	s.track(p)
+	defer s.untrack(p)
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := s.receipt(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			// The seqno is used once the transaction is included, only the async calls are awaited further.
+			s.untrack(p)
+			if receipt.IsComplete() {
+				return receipt, nil
+			}
+		} else if err := s.unstick(ctx, p); err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			gaps := p.gaps
+			p.mu.Unlock()
+			if len(gaps) > 0 {
+				return nil, fmt.Errorf("%w: seqnos %v before %d are not used", ErrTransactionStuck, gaps, p.Seqno())
+			}
+			return nil, fmt.Errorf("%w: %w", ErrTransactionStuck, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// receipt returns the receipt of any version of the transaction.
+func (s *Sender) receipt(ctx context.Context, p *PendingTransaction) (*jsonrpc.RPCReceipt, error) {
+	p.mu.Lock()
+	hashes := p.hashes
+	p.mu.Unlock()
+
+	for _, hash := range hashes {
+		receipt, err := s.client.GetInTransactionReceipt(ctx, hash)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The timeout is reported by the caller.
+				return nil, nil
+			}
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Sender) unstick(ctx context.Context, p *PendingTransaction) error {
+	p.mu.Lock()
+	stuck := time.Since(p.lastSent) >= s.config.StuckTimeout
+	p.mu.Unlock()
+	if !stuck {
+		return nil
+	}
+
+	committed, err := s.client.GetTransactionCount(ctx, p.To(), "latest")
+	if err != nil {
+		// Try again on the next poll.
+		s.logger.Debug().Err(err).Msg("Failed to get seqno")
+		return nil
+	}
+	if committed > p.Seqno() {
+		// The transaction might have been processed after its receipt was requested.
+		if receipt, err := s.receipt(ctx, p); err != nil || receipt != nil {
+			return err
+		}
+		// Another transaction with the seqno has been processed.
+		if err := s.seqnos.Resync(ctx, p.To()); err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to resync seqno")
+		}
+		return fmt.Errorf("%w: seqno %d of %s", ErrTransactionReplaced, p.Seqno(), p.To())
+	}
+
+	// The transaction cannot be processed until all the preceding ones are, so the ones missing from the pool
+	// are rebroadcast. The rest of them are the gaps that are left by other senders.
+	inPool, err := s.pooledSeqnos(ctx, p.To())
+	if err != nil {
+		s.logger.Debug().Err(err).Msg("Failed to get txpool content")
+	}
+	tracked := s.pendingBelow(p.To(), committed, p.Seqno())
+	var gaps []types.Seqno
+	if inPool != nil {
+		for seqno := committed; seqno < p.Seqno(); seqno++ {
+			if inPool[seqno] {
+				continue
+			}
+			if prev, ok := tracked[seqno]; ok {
+				s.rebroadcast(ctx, prev)
+			} else {
+				gaps = append(gaps, seqno)
+			}
+		}
+	} else {
+		for _, prev := range tracked {
+			s.rebroadcast(ctx, prev)
+		}
+		gaps = s.seqnos.Gaps(p.To(), p.Seqno())
+	}
+	if len(gaps) > 0 {
+		s.logger.Warn().
+			Stringer(logging.FieldShardId, p.To().ShardId()).
+			Msgf("Transaction %d to %s is blocked by the unused seqnos %v", p.Seqno(), p.To(), gaps)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gaps = gaps
+	switch {
+	case len(gaps) > 0:
+		// Neither rebroadcasting nor the fee bump helps until the gaps are filled.
+	case (inPool == nil || !inPool[p.Seqno()]) && p.rebroadcasts < s.config.MaxRebroadcasts:
+		p.rebroadcasts++
+		s.rebroadcastLocked(ctx, p)
+	case p.feeBumps < s.config.MaxFeeBumps:
+		// The transaction is in the pool but is not included, most likely its fee is too low.
+		p.feeBumps++
+		s.bumpFeeLocked(ctx, p)
+	}
+	p.lastSent = time.Now()
+	return nil
+}
+
+// pooledSeqnos returns the seqnos of the external transactions to the account that are in the pool.
+func (s *Sender) pooledSeqnos(ctx context.Context, addr types.Address) (map[types.Seqno]bool, error) {
+	content, err := s.client.GetTxpoolContent(ctx, addr.ShardId())
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[types.Seqno]bool)
+	for _, txn := range content.Pending[addr.String()] {
+		if !txn.Flags.GetBit(types.TransactionFlagInternal) {
+			res[types.Seqno(txn.Seqno)] = true
+		}
+	}
+	return res, nil
+}
+
+func (s *Sender) rebroadcast(ctx context.Context, p *PendingTransaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s.rebroadcastLocked(ctx, p)
+}
+
+func (s *Sender) rebroadcastLocked(ctx context.Context, p *PendingTransaction) {
+	if _, err := s.client.SendTransaction(ctx, p.txn); err != nil && !isAlreadyKnown(err) {
+		s.logger.Debug().Err(err).
+			Stringer(logging.FieldTransactionHash, p.hashes[len(p.hashes)-1]).
+			Msg("Failed to rebroadcast transaction")
+	}
+}
+
+func bump(v types.Value) types.Value {
+	return v.Mul64(100 + txnpool.FeeBumpPercentage).Div64(100).Add64(1)
+}
+
+// bumpFeeLocked re-signs the transaction with the fee that is high enough to replace it in the pool.
+func (s *Sender) bumpFeeLocked(ctx context.Context, p *PendingTransaction) {
+	txn := *p.txn
+	txn.MaxPriorityFeePerGas = bump(txn.MaxPriorityFeePerGas)
+	txn.MaxFeePerGas = bump(txn.MaxFeePerGas)
+	txn.FeeCredit = bump(txn.FeeCredit)
+
+	hash, err := s.sign(ctx, &txn, p.key)
+	if err != nil {
+		s.logger.Debug().Err(err).
+			Stringer(logging.FieldTransactionHash, p.hashes[len(p.hashes)-1]).
+			Msg("Failed to bump the fee of transaction")
+		return
+	}
+	p.txn = &txn
+	p.hashes = append(p.hashes, hash)
+}
@@ -0,0 +1,334 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type SuiteSender struct {
+	suite.Suite
+
+	client  *client.ClientMock
+	address types.Address
+
+	mu sync.Mutex
+	// pending is the seqno returned for the pending block, latest is the one for the latest block.
+	pending types.Seqno
+	latest  types.Seqno
+	// pool contains the transactions in the pool by their seqnos.
+	pool map[types.Seqno]*types.ExternalTransaction
+	// processed contains the hashes of the processed transactions.
+	processed map[common.Hash]bool
+	sent      []*types.ExternalTransaction
+	sendErr   func(txn *types.ExternalTransaction) error
+}
+
+func (s *SuiteSender) SetupTest() {
+	s.address = types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	s.pending = 5
+	s.latest = 5
+	s.pool = make(map[types.Seqno]*types.ExternalTransaction)
+	s.processed = make(map[common.Hash]bool)
+	s.sent = nil
+	s.sendErr = nil
+
+	s.client = &client.ClientMock{
+		GetTransactionCountFunc: func(_ context.Context, address types.Address, blockId any) (types.Seqno, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.Equal(s.address, address)
+			if blockId == "latest" {
+				return s.latest, nil
+			}
+			return s.pending, nil
+		},
+		SendTransactionFunc: func(_ context.Context, txn *types.ExternalTransaction) (common.Hash, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.sendErr != nil {
+				if err := s.sendErr(txn); err != nil {
+					return common.EmptyHash, err
+				}
+			}
+			s.sent = append(s.sent, txn)
+			s.pool[txn.Seqno] = txn
+			return txn.Hash(), nil
+		},
+		GetTxpoolContentFunc: func(_ context.Context, shardId types.ShardId) (jsonrpc.TxPoolContent, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.Equal(s.address.ShardId(), shardId)
+			txns := make(map[string]*jsonrpc.Transaction)
+			for seqno, txn := range s.pool {
+				txns[seqno.String()] = jsonrpc.NewTransaction(txn.ToTransaction())
+			}
+			return jsonrpc.TxPoolContent{Pending: map[string]map[string]*jsonrpc.Transaction{s.address.String(): txns}}, nil
+		},
+		GetInTransactionReceiptFunc: func(_ context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.processed[hash] {
+				return &jsonrpc.RPCReceipt{TxnHash: hash, Success: true}, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func (s *SuiteSender) newSender() *client.Sender {
+	return client.NewSender(s.client, client.SenderConfig{
+		PollInterval:    time.Millisecond,
+		StuckTimeout:    0,
+		MaxRebroadcasts: 2,
+		MaxFeeBumps:     1,
+		MaxSendAttempts: 3,
+	}, logging.NewLogger("test"))
+}
+
+func (s *SuiteSender) send(sender *client.Sender) *client.PendingTransaction {
+	s.T().Helper()
+
+	txn, err := sender.Send(s.T().Context(), s.address, types.Code{0x01}, nil, types.FeePack{
+		FeeCredit:            types.GasToValue(100_000),
+		MaxFeePerGas:         types.MaxFeePerGasDefault,
+		MaxPriorityFeePerGas: types.NewValueFromUint64(100),
+	})
+	s.Require().NoError(err)
+	return txn
+}
+
+func (s *SuiteSender) TestSeqnoManager() {
+	ctx := s.T().Context()
+	m := client.NewSeqnoManager(s.client)
+
+	s.Run("Concurrent", func() {
+		const n = 50
+		seqnos := make(chan types.Seqno, n)
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				seqno, err := m.Reserve(ctx, s.address)
+				s.NoError(err)
+				seqnos <- seqno
+			}()
+		}
+		wg.Wait()
+		close(seqnos)
+
+		reserved := make(map[types.Seqno]bool)
+		for seqno := range seqnos {
+			s.False(reserved[seqno])
+			reserved[seqno] = true
+		}
+		for seqno := types.Seqno(5); seqno < 5+n; seqno++ {
+			s.True(reserved[seqno])
+		}
+		s.Len(s.client.GetTransactionCountCalls(), 1)
+	})
+
+	s.Run("Release", func() {
+		m.Release(s.address, 10)
+		m.Release(s.address, 7)
+		s.Equal([]types.Seqno{7, 10}, m.Gaps(s.address, 55))
+		s.Equal([]types.Seqno{7}, m.Gaps(s.address, 10))
+
+		// The released seqnos are reserved first.
+		seqno, err := m.Reserve(ctx, s.address)
+		s.Require().NoError(err)
+		s.Equal(types.Seqno(7), seqno)
+
+		// Releasing the highest seqnos shrinks the range.
+		m.Release(s.address, 54)
+		m.Release(s.address, 53)
+		s.Equal([]types.Seqno{10}, m.Gaps(s.address, 100))
+		m.Release(s.address, 10)
+		seqno, err = m.Reserve(ctx, s.address)
+		s.Require().NoError(err)
+		s.Equal(types.Seqno(10), seqno)
+		seqno, err = m.Reserve(ctx, s.address)
+		s.Require().NoError(err)
+		s.Equal(types.Seqno(53), seqno)
+	})
+
+	s.Run("Resync", func() {
+		m.Release(s.address, 20)
+		m.Release(s.address, 40)
+
+		s.pending = 30
+		s.Require().NoError(m.Resync(ctx, s.address))
+		s.Equal([]types.Seqno{40}, m.Gaps(s.address, 100))
+
+		s.pending = 100
+		s.Require().NoError(m.Resync(ctx, s.address))
+		s.Empty(m.Gaps(s.address, 200))
+		seqno, err := m.Reserve(ctx, s.address)
+		s.Require().NoError(err)
+		s.Equal(types.Seqno(100), seqno)
+	})
+
+	s.Run("Forget", func() {
+		m.Release(s.address, 100)
+		calls := len(s.client.GetTransactionCountCalls())
+
+		// The seqno of the node is used even if it is below the local one.
+		s.pending = 90
+		m.Forget(s.address)
+		s.Empty(m.Gaps(s.address, 200))
+		seqno, err := m.Reserve(ctx, s.address)
+		s.Require().NoError(err)
+		s.Equal(types.Seqno(90), seqno)
+		seqno, err = m.Reserve(ctx, s.address)
+		s.Require().NoError(err)
+		s.Equal(types.Seqno(91), seqno)
+		s.Len(s.client.GetTransactionCountCalls(), calls+1)
+	})
+}
+
+func (s *SuiteSender) TestSend() {
+	sender := s.newSender()
+
+	s.Run("Concurrent", func() {
+		const n = 20
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.send(sender)
+			}()
+		}
+		wg.Wait()
+
+		s.Len(s.pool, n)
+		for seqno := types.Seqno(5); seqno < 5+n; seqno++ {
+			s.Contains(s.pool, seqno)
+		}
+	})
+
+	s.Run("SeqnoTaken", func() {
+		// Another sender has used the seqnos.
+		s.pending = 30
+		s.sendErr = func(txn *types.ExternalTransaction) error {
+			if txn.Seqno < 30 {
+				return fmt.Errorf("%w: %s", jsonrpc.ErrTransactionDiscarded, txnpool.SeqnoTooLow)
+			}
+			return nil
+		}
+		txn := s.send(sender)
+		s.Equal(types.Seqno(30), txn.Seqno())
+	})
+
+	s.Run("Error", func() {
+		s.sendErr = func(*types.ExternalTransaction) error {
+			return fmt.Errorf("%w: %s", jsonrpc.ErrTransactionDiscarded, txnpool.Unverified)
+		}
+		_, err := sender.Send(s.T().Context(), s.address, types.Code{0x01}, nil, types.FeePack{
+			FeeCredit: types.GasToValue(100_000),
+		})
+		s.Require().Error(err)
+
+		// The seqno is not lost.
+		s.sendErr = nil
+		s.Equal(types.Seqno(31), s.send(sender).Seqno())
+	})
+}
+
+func (s *SuiteSender) TestWait() {
+	ctx := s.T().Context()
+	sender := s.newSender()
+
+	s.Run("Processed", func() {
+		txn := s.send(sender)
+		s.processed[txn.Hash()] = true
+
+		receipt, err := sender.Wait(ctx, txn, time.Second)
+		s.Require().NoError(err)
+		s.Equal(txn.Hash(), receipt.TxnHash)
+	})
+
+	s.Run("FeeBump", func() {
+		txn := s.send(sender)
+		hash := txn.Hash()
+
+		// The transaction is in the pool, but it is not included until its fee is bumped.
+		_, err := sender.Wait(ctx, txn, 50*time.Millisecond)
+		s.Require().ErrorIs(err, client.ErrTransactionStuck)
+		s.NotEqual(hash, txn.Hash())
+
+		bumped := s.pool[txn.Seqno()]
+		s.Equal(txn.Hash(), bumped.Hash())
+		s.Equal(0, bumped.MaxPriorityFeePerGas.Cmp(types.NewValueFromUint64(106)))
+
+		// Any version of the transaction can be processed.
+		s.processed[hash] = true
+		receipt, err := sender.Wait(ctx, txn, time.Second)
+		s.Require().NoError(err)
+		s.Equal(hash, receipt.TxnHash)
+	})
+
+	s.Run("Rebroadcast", func() {
+		s.latest = 7
+		first := s.send(sender)
+		second := s.send(sender)
+		s.Equal(types.Seqno(7), first.Seqno())
+
+		// The transactions are dropped from the pool.
+		clear(s.pool)
+		sent := len(s.sent)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sender.Wait(ctx, first, 50*time.Millisecond)
+			s.ErrorIs(err, client.ErrTransactionStuck)
+		}()
+		_, err := sender.Wait(ctx, second, 50*time.Millisecond)
+		s.Require().ErrorIs(err, client.ErrTransactionStuck)
+		wg.Wait()
+
+		s.Greater(len(s.sent), sent)
+		s.Contains(s.pool, first.Seqno())
+		s.Contains(s.pool, second.Seqno())
+	})
+
+	s.Run("Gap", func() {
+		s.latest = 9
+		clear(s.pool)
+		s.pending = 11
+		s.Require().NoError(sender.Seqnos().Resync(ctx, s.address))
+		txn := s.send(sender)
+
+		// The seqnos 9 and 10 are used by the transactions of another sender that are lost.
+		_, err := sender.Wait(ctx, txn, 50*time.Millisecond)
+		s.Require().ErrorIs(err, client.ErrTransactionStuck)
+		s.Contains(err.Error(), "[9 10]")
+	})
+
+	s.Run("Replaced", func() {
+		txn := s.send(sender)
+		s.latest = txn.Seqno() + 1
+
+		_, err := sender.Wait(ctx, txn, time.Second)
+		s.Require().ErrorIs(err, client.ErrTransactionReplaced)
+	})
+}
+
+func TestSuiteSender(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteSender))
+}
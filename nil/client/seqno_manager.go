@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// SeqnoManager hands out the seqnos of the external transactions to the accounts to concurrent senders.
+// The seqno of an account is fetched from the node once and then tracked locally,
+// so the senders do not have to be serialized and to re-fetch the seqno before every transaction.
+type SeqnoManager struct {
+	client Client
+
+	mu       sync.Mutex
+	accounts map[types.Address]*accountSeqnos
+}
+
+type accountSeqnos struct {
+	mu sync.Mutex
+
+	loaded bool
+	// next is the seqno after the highest reserved one.
+	next types.Seqno
+	// released are the reserved seqnos below next that have not been used, sorted.
+	// They are reserved again first, so that they do not leave gaps.
+	released []types.Seqno
+}
+
+func NewSeqnoManager(c Client) *SeqnoManager {
+	return &SeqnoManager{
+		client:   c,
+		accounts: make(map[types.Address]*accountSeqnos),
+	}
+}
+
+func (m *SeqnoManager) account(addr types.Address) *accountSeqnos {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[addr]
+	if !ok {
+		acc = &accountSeqnos{}
+		m.accounts[addr] = acc
+	}
+	return acc
+}
+
+func (m *SeqnoManager) fetch(ctx context.Context, addr types.Address) (types.Seqno, error) {
+	return m.client.GetTransactionCount(ctx, addr, "pending")
+}
+
+// Reserve returns the seqno to send the next transaction to the account with.
+// The seqno must be released if the transaction is not sent.
+func (m *SeqnoManager) Reserve(ctx context.Context, addr types.Address) (types.Seqno, error) {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.loaded {
+		seqno, err := m.fetch(ctx, addr)
+		if err != nil {
+			return 0, err
+		}
+		acc.next = seqno
+		acc.loaded = true
+	}
+
+	if len(acc.released) > 0 {
+		seqno := acc.released[0]
+		acc.released = acc.released[1:]
+		return seqno, nil
+	}
+	seqno := acc.next
+	acc.next++
+	return seqno, nil
+}
+
+// Release returns the reserved seqno that has not been used, so that it is reserved again.
+func (m *SeqnoManager) Release(addr types.Address, seqno types.Seqno) {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.loaded || seqno >= acc.next {
+		return
+	}
+	if i, found := slices.BinarySearch(acc.released, seqno); !found {
+		acc.released = slices.Insert(acc.released, i, seqno)
+	}
+	// Shrink the reserved range instead of keeping the released seqnos at its end.
+	for n := len(acc.released); n > 0 && acc.released[n-1] == acc.next-1; n-- {
+		acc.released = acc.released[:n-1]
+		acc.next--
+	}
+}
+
+// Resync fetches the seqno of the account from the node. It is needed when the seqnos are used by other senders.
+// The seqnos that are reserved locally are not reserved again, even if the node does not know about them yet.
+func (m *SeqnoManager) Resync(ctx context.Context, addr types.Address) error {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	seqno, err := m.fetch(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if !acc.loaded || seqno > acc.next {
+		acc.next = seqno
+		acc.loaded = true
+	}
+	// The released seqnos below the one of the node are used by someone else.
+	i, _ := slices.BinarySearch(acc.released, seqno)
+	acc.released = acc.released[i:]
+	return nil
+}
+
+// Gaps returns the released seqnos of the account below the given one.
+// The transactions with higher seqnos are not processed until the gaps are filled by new transactions.
+func (m *SeqnoManager) Gaps(addr types.Address, below types.Seqno) []types.Seqno {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	i, _ := slices.BinarySearch(acc.released, below)
+	return slices.Clone(acc.released[:i])
+}
+
+// Forget drops the seqnos of the account tracked locally, so the seqno is fetched from the node on the next
+// reservation even if it is below the local one (e.g., if the transactions with the reserved seqnos are lost).
+// The account is not removed from the manager, since the concurrent reservations might hold it.
+// The seqnos reserved before may be reserved again if the node doesn't know about them yet,
+// the senders resync in this case.
+func (m *SeqnoManager) Forget(addr types.Address) {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	acc.loaded = false
+	acc.next = 0
+	acc.released = nil
+}
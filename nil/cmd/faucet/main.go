@@ -44,11 +44,12 @@ func processRun(cfg *config) error {
 	addr := fmt.Sprintf("tcp://127.0.0.1:%d", cfg.port)
 	client := rpc_client.NewClient(cfg.endpoint, logging.NewLogger("faucet"))
 
-	serviceFaucet, err := faucet.NewService(client)
+	ctx := context.Background()
+	serviceFaucet, err := faucet.NewService(ctx, client)
 	if err != nil {
 		return err
 	}
-	return serviceFaucet.Run(context.Background(), addr)
+	return serviceFaucet.Run(ctx, addr)
 }
 
 func parseArgs() *config {
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
 )
 
 type Config struct {
@@ -12,4 +13,15 @@ type Config struct {
 	FaucetEndpoint string            `mapstructure:"faucet_endpoint"`
 	PrivateKey     *ecdsa.PrivateKey `mapstructure:"private_key"`
 	Address        types.Address     `mapstructure:"address"`
+	Account        string            `mapstructure:"account"`
+	KeystoreDir    string            `mapstructure:"keystore_dir"`
+
+	// Keys provide the key used to sign transactions: either the key of the account or the private key.
+	Keys cliservice.KeySource `mapstructure:"-"`
+}
+
+// AccountProfile is a named account: a key from the keystore paired with a smart account and an RPC endpoint.
+type AccountProfile struct {
+	Address     types.Address `mapstructure:"address"`
+	RPCEndpoint string        `mapstructure:"rpc_endpoint"`
 }
@@ -12,3 +12,6 @@ type Params struct {
 }
 
 var Quiet = false
+
+// Account is the name of the account selected with the --account flag.
+var Account = ""
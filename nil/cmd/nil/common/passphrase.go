@@ -0,0 +1,76 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	// PassphraseEnv is the environment variable the passphrase of the account keys is taken from.
+	// If it is not set, the passphrase is prompted.
+	PassphraseEnv = "NIL_KEYSTORE_PASSPHRASE"
+	// NewPassphraseEnv is the environment variable the passphrase to encrypt a key with is taken from.
+	// If it is not set, PassphraseEnv is used for the new keys, and the passphrase is prompted on the change.
+	NewPassphraseEnv = "NIL_KEYSTORE_NEW_PASSPHRASE"
+)
+
+// ReadPassphrase returns the passphrase of the key of the account.
+func ReadPassphrase(account string) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+	return promptPassphrase(fmt.Sprintf("Passphrase of account %q: ", account), PassphraseEnv)
+}
+
+// ReadNewPassphrase returns the passphrase to encrypt the key of the account with.
+func ReadNewPassphrase(account string) (string, error) {
+	if passphrase, ok := os.LookupEnv(NewPassphraseEnv); ok {
+		return passphrase, nil
+	}
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+	return promptNewPassphrase(account)
+}
+
+// ReadChangedPassphrase returns the passphrase to re-encrypt the key of the account with.
+// Unlike ReadNewPassphrase, it is not taken from PassphraseEnv, which holds the current passphrase.
+func ReadChangedPassphrase(account string) (string, error) {
+	if passphrase, ok := os.LookupEnv(NewPassphraseEnv); ok {
+		return passphrase, nil
+	}
+	return promptNewPassphrase(account)
+}
+
+func promptNewPassphrase(account string) (string, error) {
+	passphrase, err := promptPassphrase(fmt.Sprintf("New passphrase of account %q: ", account), NewPassphraseEnv)
+	if err != nil {
+		return "", err
+	}
+	repeated, err := promptPassphrase("Repeat the passphrase: ", NewPassphraseEnv)
+	if err != nil {
+		return "", err
+	}
+	if passphrase != repeated {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string, env string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("can't prompt the passphrase without a terminal; set it via %s", env)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
@@ -19,7 +19,7 @@ func RunTopUp(
 	if err != nil {
 		return err
 	}
-	service := cliservice.NewService(ctx, GetRpcClient(), cfg.PrivateKey, faucet)
+	service := cliservice.NewServiceWithKeys(ctx, GetRpcClient(), cfg.Keys, faucet)
 
 	faucetAddress := types.FaucetAddress
 	if len(tokId) == 0 {
@@ -0,0 +1,135 @@
+package abigen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/client/bind"
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var logger = logging.NewLogger("abigenCommand")
+
+const (
+	abiFlag     = "abi"
+	binFlag     = "bin"
+	typeFlag    = "type"
+	pkgFlag     = "pkg"
+	outFlag     = "out"
+	addressFlag = "address"
+)
+
+type params struct {
+	abiPath  string
+	binPath  string
+	typeName string
+	pkg      string
+	out      string
+	address  types.Address
+}
+
+func GetCommand() *cobra.Command {
+	params := &params{}
+
+	cmd := &cobra.Command{
+		Use:   "abigen",
+		Short: "Generate Go bindings of a contract",
+		Long: "Generate Go bindings of a contract from its ABI (and bytecode) " +
+			"or from the contract registered in Cometa at the given address",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runAbigen(cmd, params)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&params.abiPath, abiFlag, "", "The path to the ABI file")
+	cmd.Flags().StringVar(&params.binPath, binFlag, "", "The path to the bytecode file (to generate deployment helpers)")
+	cmd.Flags().StringVar(&params.typeName, typeFlag, "", "The Go type name of the binding")
+	cmd.Flags().StringVar(&params.pkg, pkgFlag, "", "The Go package name of the binding")
+	cmd.Flags().StringVar(&params.out, outFlag, "", "The output file (stdout if not set)")
+	cmd.Flags().Var(&params.address, addressFlag, "The address of the contract registered in Cometa")
+	cmd.MarkFlagsMutuallyExclusive(abiFlag, addressFlag)
+	cmd.MarkFlagsOneRequired(abiFlag, addressFlag)
+	cmd.MarkFlagsMutuallyExclusive(binFlag, addressFlag)
+
+	return cmd
+}
+
+func runAbigen(cmd *cobra.Command, params *params) error {
+	var contract bind.Contract
+	if params.abiPath != "" {
+		data, err := os.ReadFile(params.abiPath)
+		if err != nil {
+			return fmt.Errorf("failed to read the ABI: %w", err)
+		}
+		contract.ABI = string(data)
+
+		if params.binPath != "" {
+			data, err := os.ReadFile(params.binPath)
+			if err != nil {
+				return fmt.Errorf("failed to read the bytecode: %w", err)
+			}
+			contract.Bin = strings.TrimSpace(string(data))
+		}
+		contract.Type = strings.TrimSuffix(filepath.Base(params.abiPath), filepath.Ext(params.abiPath))
+	} else {
+		// The command runs without the config, it is needed only to connect to Cometa.
+		cfg, err := config.LoadConfig(viper.ConfigFileUsed(), logger)
+		if err != nil {
+			return err
+		}
+		common.InitRpcClient(cfg, logger)
+
+		data, err := common.GetCometaRpcClient().GetContract(cmd.Context(), params.address)
+		if err != nil {
+			return fmt.Errorf("failed to fetch the contract from Cometa: %w", err)
+		}
+		if data.Abi == "" {
+			return errors.New("the contract registered in Cometa has no ABI")
+		}
+		contract.ABI = data.Abi
+		if len(data.InitCode) > 0 {
+			contract.Bin = hexutil.Encode(data.InitCode)
+		}
+		contract.Type = data.Name
+	}
+
+	if params.typeName != "" {
+		contract.Type = params.typeName
+	}
+	if contract.Type == "" {
+		return fmt.Errorf("the type name is not specified, set it with --%s", typeFlag)
+	}
+	contract.Type = strings.ToUpper(contract.Type[:1]) + contract.Type[1:]
+	pkg := params.pkg
+	if pkg == "" {
+		pkg = strings.ToLower(contract.Type)
+	}
+
+	code, err := bind.Generate(pkg, contract)
+	if err != nil {
+		return err
+	}
+
+	if params.out == "" {
+		_, err := os.Stdout.Write(code)
+		return err
+	}
+	if err := os.WriteFile(params.out, code, 0o600); err != nil {
+		return fmt.Errorf("failed to write the bindings: %w", err)
+	}
+	if !common.Quiet {
+		fmt.Printf("Bindings of %s are written to %s\n", contract.Type, params.out)
+	}
+	return nil
+}
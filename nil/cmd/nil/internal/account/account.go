@@ -0,0 +1,272 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cliservice/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var logger = logging.NewLogger("accountCommand")
+
+const lightKdfFlag = "light-kdf"
+
+// profileOptions are the options that can be set for an account.
+var profileOptions = map[string]struct{}{
+	config.AddressField:     {},
+	config.RPCEndpointField: {},
+}
+
+func GetCommand() *cobra.Command {
+	var accountCmd *cobra.Command
+
+	accountCmd = &cobra.Command{
+		Use:   "account",
+		Short: "Manage the accounts whose keys are stored encrypted in the keystore",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if parent := accountCmd.Parent(); parent != nil {
+				if parent.PersistentPreRunE != nil {
+					if err := parent.PersistentPreRunE(cmd, args); err != nil {
+						return err
+					}
+				}
+			}
+			// The config is optional, it only provides the keystore directory and the account options.
+			if err := viper.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) &&
+				!errors.As(err, new(viper.ConfigFileNotFoundError)) {
+				return fmt.Errorf("failed to read the config file: %w", err)
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	accountCmd.AddCommand(
+		newCommand(),
+		importCommand(),
+		listCommand(),
+		setCommand(),
+		passwdCommand(),
+	)
+	return accountCmd
+}
+
+func keyStore(cmd *cobra.Command) *keystore.KeyStore {
+	// The flag is defined only for the commands that encrypt keys.
+	light, _ := cmd.Flags().GetBool(lightKdfFlag)
+	return config.KeyStore(viper.GetString("nil."+config.KeystoreDirField), light)
+}
+
+func addLightKdfFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(
+		lightKdfFlag,
+		false,
+		"Encrypt the key faster, making it easier to brute-force (for development networks)",
+	)
+}
+
+func newCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new [name]",
+		Short: "Generate a new key and store it encrypted as a new account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				return err
+			}
+			return Create(keyStore(cmd), args[0], key, nil)
+		},
+		SilenceUsage: true,
+	}
+	addLightKdfFlag(cmd)
+	return cmd
+}
+
+func importCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [name] [hex-private-key]",
+		Short: "Import a private key as a new account",
+		Long: "Import a private key as a new account. If the key is omitted, " +
+			"the private key and the smart account address set in the config file are imported.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := make(map[string]any)
+
+			hexKey := viper.GetString("nil." + config.PrivateKeyField)
+			if len(args) == 2 {
+				hexKey = args[1]
+			} else if address := viper.GetString("nil." + config.AddressField); address != "" {
+				profile[config.AddressField] = address
+			}
+			if hexKey == "" {
+				return errors.New("private key is not specified")
+			}
+			key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+			if err != nil {
+				return fmt.Errorf("invalid private key: %w", err)
+			}
+
+			if err := Create(keyStore(cmd), args[0], key, profile); err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				logger.Info().Msgf(
+					"The private key can now be removed from the config file: %s", viper.ConfigFileUsed())
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	addLightKdfFlag(cmd)
+	return cmd
+}
+
+// Create stores the key encrypted in the keystore and adds the account section with the given options to the config.
+func Create(ks *keystore.KeyStore, name string, key *ecdsa.PrivateKey, options map[string]any) error {
+	if found, err := ks.Has(name); err != nil {
+		return err
+	} else if found {
+		return fmt.Errorf("%w: %s", keystore.ErrKeyExists, name)
+	}
+
+	passphrase, err := common.ReadNewPassphrase(name)
+	if err != nil {
+		return err
+	}
+	info, err := ks.Store(name, key, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if len(options) > 0 {
+		if err := config.PatchConfigSection(config.AccountSection(name), options); err != nil {
+			logger.Error().Err(err).Msg("failed to add the account to the config file")
+		}
+	}
+
+	if common.Quiet {
+		fmt.Println(hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)))
+		return nil
+	}
+	fmt.Printf("Account: %s\n", name)
+	fmt.Printf("Public key: %s\n", hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)))
+	fmt.Printf("Key address: %s\n", info.Address.Hex())
+	fmt.Printf("Key file: %s\n", info.Path)
+	return nil
+}
+
+func listCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the accounts",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keys, err := keyStore(cmd).List()
+			if err != nil {
+				return err
+			}
+
+			defaultAccount := viper.GetString("nil." + config.AccountField)
+			for _, key := range keys {
+				if common.Quiet {
+					fmt.Println(key.Name)
+					continue
+				}
+
+				profile, err := config.LoadAccountProfile(key.Name)
+				if err != nil {
+					return err
+				}
+
+				name := key.Name
+				if name == defaultAccount {
+					name += " (default)"
+				}
+				fmt.Println(name)
+				fmt.Printf("  Key address: %s\n", key.Address.Hex())
+				if profile.Address != types.EmptyAddress {
+					fmt.Printf("  Smart account: %s\n", profile.Address.Hex())
+				}
+				if profile.RPCEndpoint != "" {
+					fmt.Printf("  RPC endpoint: %s\n", profile.RPCEndpoint)
+				}
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func setCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set [name] [key] [value]",
+		Short: "Set an option of the account (address or rpc_endpoint)",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, key, value := args[0], args[1], args[2]
+			if _, supported := profileOptions[key]; !supported {
+				return fmt.Errorf("key %q is not known", key)
+			}
+			if found, err := keyStore(cmd).Has(name); err != nil {
+				return err
+			} else if !found {
+				return fmt.Errorf("%w: %s", keystore.ErrKeyNotFound, name)
+			}
+
+			if err := config.PatchConfigSection(config.AccountSection(name), map[string]any{key: value}); err != nil {
+				return err
+			}
+			logger.Info().Msgf("Set %q of account %q to %q", key, name, value)
+			return nil
+		},
+		SilenceUsage: true,
+	}
+}
+
+func passwdCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "passwd [name]",
+		Short: "Change the passphrase of the account key",
+		Long: "Change the passphrase of the account key. The new passphrase is taken from " +
+			common.NewPassphraseEnv + " or prompted. The current one set via " + common.PassphraseEnv + " is not reused.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ks := keyStore(cmd)
+			if found, err := ks.Has(name); err != nil {
+				return err
+			} else if !found {
+				return fmt.Errorf("%w: %s", keystore.ErrKeyNotFound, name)
+			}
+
+			passphrase, err := common.ReadPassphrase(name)
+			if err != nil {
+				return err
+			}
+			newPassphrase, err := common.ReadChangedPassphrase(name)
+			if err != nil {
+				return err
+			}
+			if err := ks.ChangePassphrase(name, passphrase, newPassphrase); err != nil {
+				return err
+			}
+			logger.Info().Msgf("Changed the passphrase of account %q", name)
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	addLightKdfFlag(cmd)
+	return cmd
+}
@@ -22,6 +22,8 @@ var supportedOptions = map[string]struct{}{
 	"faucet_endpoint": {},
 	"private_key":     {},
 	"address":         {},
+	"account":         {},
+	"keystore_dir":    {},
 }
 
 func GetCommand(configPath *string) *cobra.Command {
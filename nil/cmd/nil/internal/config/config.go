@@ -5,15 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/NilFoundation/nil/nil/services/cliservice/keystore"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/go-viper/encoding/ini"
 	"github.com/go-viper/mapstructure/v2"
@@ -24,8 +28,13 @@ const (
 	AddressField     = "address"
 	PrivateKeyField  = "private_key"
 	RPCEndpointField = "rpc_endpoint"
+	AccountField     = "account"
+	KeystoreDirField = "keystore_dir"
 )
 
+// nilSection is the section of the general options.
+const nilSection = "nil"
+
 const InitConfigTemplate = `; Configuration for interacting with the =nil; cluster
 [nil]
 
@@ -50,6 +59,18 @@ const InitConfigTemplate = `; Configuration for interacting with the =nil; clust
 ; Specify the address of your smart account to be the receiver of your external transactions.
 ; You can deploy a new account and save its address with "nil smart account new".
 ; address = "0xWRITE_YOUR_ADDRESS_HERE"
+
+; Instead of the private key and the address above, you can use named accounts.
+; The key of an account is stored encrypted in the keystore directory ("keystore" next to this file by default).
+; You can create an account with "nil account new <name>" and select it with "--account <name>".
+; Specify the account to use by default.
+; account = "WRITE_YOUR_ACCOUNT_NAME_HERE"
+; keystore_dir = "/path/to/keystore"
+
+; Accounts are configured in their own sections:
+; [account.deployer]
+; address = "0xSMART_ACCOUNT_ADDRESS"
+; rpc_endpoint = "http://127.0.0.1:8529"
 `
 
 var DefaultConfigPath string
@@ -89,6 +110,17 @@ func InitDefaultConfig(configPath string) (string, error) {
 }
 
 func PatchConfig(delta map[string]any, force bool) error {
+	return PatchConfigSection(nilSection, delta)
+}
+
+// AccountSection returns the config section of the account.
+func AccountSection(name string) string {
+	return AccountField + "." + name
+}
+
+// PatchConfigSection sets the values in the given section of the config file.
+// The section is created if it doesn't exist.
+func PatchConfigSection(section string, delta map[string]any) error {
 	configPath := viper.ConfigFileUsed()
 	if configPath == "" {
 		// impossible, since we set the default in SetConfigFile
@@ -108,26 +140,61 @@ func PatchConfig(delta map[string]any, force bool) error {
 		return err
 	}
 
-	result := strings.Builder{}
-	first := true
-	for _, line := range strings.Split(string(cfg), "\n") {
-		if !first {
-			result.WriteByte('\n')
-		} else {
-			first = false
+	lines := strings.Split(string(cfg), "\n")
+
+	// Find the lines of the section: from its header to the next one.
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		name, isHeader := sectionHeader(line)
+		if !isHeader {
+			continue
+		}
+		if start != -1 {
+			end = i
+			break
+		}
+		if name == section {
+			start = i
+		}
+	}
+	if start == -1 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, "", "["+section+"]")
+		start, end = len(lines)-1, len(lines)
+	}
+
+	insertAt := start + 1
+	for i := start + 1; i < end; i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) != "" {
+			insertAt = i + 1
 		}
 		key := strings.TrimSpace(strings.Split(line, "=")[0])
 		if value, ok := delta[key]; ok {
-			result.WriteString(fmt.Sprintf("%s = %v", key, value))
+			lines[i] = fmt.Sprintf("%s = %v", key, value)
 			delete(delta, key)
-		} else {
-			result.WriteString(line)
 		}
 	}
-	for key, value := range delta {
-		result.WriteString(fmt.Sprintf("%s = %v\n", key, value))
+
+	added := make([]string, 0, len(delta))
+	for _, key := range slices.Sorted(maps.Keys(delta)) {
+		added = append(added, fmt.Sprintf("%s = %v", key, delta[key]))
+	}
+	lines = slices.Insert(lines, insertAt, added...)
+	if lines[len(lines)-1] != "" {
+		lines = append(lines, "")
 	}
-	return os.WriteFile(configPath, []byte(result.String()), 0o600)
+	return os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0o600)
+}
+
+func sectionHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
 }
 
 // SetConfigFile sets the config file for the viper
@@ -194,6 +261,10 @@ func LoadConfig(cfgFilePath string, logger logging.Logger) (*common.Config, erro
 		return nil, fmt.Errorf("unable to decode config: %w", err)
 	}
 
+	if err := setKeys(&config); err != nil {
+		return nil, err
+	}
+
 	if err := validateConfig(&config, logger); err != nil {
 		return nil, err
 	}
@@ -210,8 +281,63 @@ func validateConfig(config *common.Config, logger logging.Logger) error {
 	return nil
 }
 
+// setKeys sets the key and the address of the selected account or the ones specified in the config.
+func setKeys(config *common.Config) error {
+	name := config.Account
+	if common.Account != "" {
+		name = common.Account
+	}
+	if name == "" {
+		config.Keys = cliservice.StaticKey(config.PrivateKey)
+		return nil
+	}
+
+	ks := KeyStore(config.KeystoreDir, false)
+	if found, err := ks.Has(name); err != nil {
+		return err
+	} else if !found {
+		return fmt.Errorf("account %q not found in %s; run `%s account new %s` to create it",
+			name, ks.Dir(), os.Args[0], name)
+	}
+
+	profile, err := LoadAccountProfile(name)
+	if err != nil {
+		return err
+	}
+
+	config.Account = name
+	config.PrivateKey = nil
+	config.Keys = ks.Key(name, common.ReadPassphrase)
+	config.Address = profile.Address
+	if profile.RPCEndpoint != "" {
+		config.RPCEndpoint = profile.RPCEndpoint
+	}
+	return nil
+}
+
+// LoadAccountProfile loads the options of the account from the config that has been read.
+func LoadAccountProfile(name string) (*common.AccountProfile, error) {
+	var profile common.AccountProfile
+	if err := viper.UnmarshalKey(AccountSection(name), &profile, updateDecoderConfig); err != nil {
+		return nil, fmt.Errorf("unable to decode account %q: %w", name, err)
+	}
+	return &profile, nil
+}
+
+// KeyStore returns the keystore in the given directory or in the default one next to the config file.
+// A light keystore encrypts the keys faster but makes them easier to brute-force.
+func KeyStore(dir string, light bool) *keystore.KeyStore {
+	if dir == "" {
+		dir = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), "keystore")
+	}
+	if light {
+		return keystore.NewLight(dir)
+	}
+	return keystore.New(dir)
+}
+
 var generateCommands = map[string]string{
-	PrivateKeyField: "keygen",
+	PrivateKeyField: "account new <name>",
 	AddressField:    "smart-account new",
 }
 
@@ -49,7 +49,7 @@ func GetAddressCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runAddress(cmd *cobra.Command, cmdArgs []string, cfg *common.Config, params *contractParams) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var filename string
 	var args []string
@@ -29,7 +29,7 @@ func runBalance(cmd *cobra.Command, args []string, cfg *common.Config) error {
 		return err
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 	balance, err := service.GetBalance(address)
 	if err != nil {
 		return err
@@ -73,7 +73,7 @@ func GetCallReadonlyCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config, params *contractParams) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
@@ -29,7 +29,7 @@ func runCode(cmd *cobra.Command, args []string, cfg *common.Config) error {
 		return err
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 	_, _ = service.GetCode(address)
 	code, _ := service.GetCode(address)
 	if !common.Quiet {
@@ -34,6 +34,7 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 		GetEstimateFeeCommand(cfg),
 		GetTopUpCommand(cfg),
 		GetSeqnoCommand(),
+		GetExportCommand(),
 	)
 
 	return serverCmd
@@ -49,7 +50,7 @@ func runContract(cmd *cobra.Command, args []string, cfg *common.Config, params *
 		return err
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	debugRPCContract, _ := service.GetDebugContract(address, params.blockId)
 
@@ -67,7 +67,7 @@ func setDeployFlags(cmd *cobra.Command, params *contractParams) {
 }
 
 func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config, params *contractParams) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var filename string
 	var args []string
@@ -33,7 +33,7 @@ func GetEstimateFeeCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runEstimateFee(cmd *cobra.Command, args []string, cfg *common.Config, params *contractParams) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
@@ -0,0 +1,69 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/cliservice"
+	"github.com/spf13/cobra"
+)
+
+type exportParams struct {
+	blockId string
+	out     string
+}
+
+func GetExportCommand() *cobra.Command {
+	params := &exportParams{}
+
+	cmd := &cobra.Command{
+		Use:   "export [address]",
+		Short: "Export the code, storage and balances of a smart contract",
+		Long: "Export the state of a smart contract at the given block as a JSON bundle with the proof of the state.\n" +
+			"The bundle can be imported into the zero state of another network " +
+			"by listing it in contractBundles of the zero state config.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(cmd, args, params)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&params.blockId, "block", "latest", "Block number, hash or tag")
+	cmd.Flags().StringVar(&params.out, outFlag, "", "The output file (stdout if not set)")
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string, params *exportParams) error {
+	var address types.Address
+	if err := address.Set(args[0]); err != nil {
+		return err
+	}
+
+	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), nil, nil)
+	bundle, err := service.ExportContract(address, params.blockId)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if params.out == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(params.out, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write the bundle: %w", err)
+	}
+	if !common.Quiet {
+		fmt.Printf("Contract %s at block %d is exported to %s\n", address, bundle.BlockNumber, params.out)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ const (
 	outOverridesFlag = "out-overrides"
 	withDetailsFlag  = "with-details"
 	asJsonFlag       = "json"
+	outFlag          = "out"
 )
 
 type contractParams struct {
@@ -1,11 +1,18 @@
 package keygen
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+
 	"github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/cmd/nil/common"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/account"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/services/cliservice"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var logger = logging.NewLogger("keygenCommand")
@@ -17,6 +24,20 @@ func GetCommand() *cobra.Command {
 		Use:   "keygen",
 		Short: "Generate a new key or generate a key from the provided hex private key",
 		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if common.Account != "" {
+				// The key is stored encrypted instead of being written to the config file.
+				key := keygen.GetPrivateKeyECDSA()
+				if key == nil {
+					return nil
+				}
+				if err := viper.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) &&
+					!errors.As(err, new(viper.ConfigFileNotFoundError)) {
+					return fmt.Errorf("failed to read the config file: %w", err)
+				}
+				ks := config.KeyStore(viper.GetString("nil."+config.KeystoreDirField), false)
+				return account.Create(ks, common.Account, key, nil)
+			}
+
 			privateKey := keygen.GetPrivateKey()
 			logger.Info().Msgf("Private key: %v", privateKey)
 
@@ -25,6 +25,10 @@ func runNew(_ *cobra.Command, _ []string, keygen *cliservice.Service) error {
 	if err := keygen.GenerateNewKey(); err != nil {
 		return err
 	}
+	if common.Account != "" {
+		// The key is not printed, it is stored encrypted in the keystore.
+		return nil
+	}
 	if !common.Quiet {
 		fmt.Printf("Private key: ")
 	}
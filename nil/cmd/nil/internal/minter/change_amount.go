@@ -30,7 +30,7 @@ func ChangeTokenAmountCommand(cfg *common.Config, mint bool) *cobra.Command {
 }
 
 func runChangeTokenAmount(cmd *cobra.Command, args []string, cfg *common.Config, mint bool) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
@@ -24,7 +24,7 @@ func CreateTokenCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runCreateToken(cmd *cobra.Command, args []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
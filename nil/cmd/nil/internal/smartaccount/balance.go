@@ -23,7 +23,7 @@ func BalanceCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runBalance(cmd *cobra.Command, _ []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 	balance, err := service.GetBalance(cfg.Address)
 	if err != nil {
 		return err
@@ -75,7 +75,7 @@ func CallReadonlyCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runCallReadonly(cmd *cobra.Command, args []string, cfg *common.Config, params *smartAccountParams) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
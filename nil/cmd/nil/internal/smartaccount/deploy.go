@@ -90,7 +90,7 @@ func runDeploy(cmd *cobra.Command, cmdArgs []string, cfg *common.Config, params
 		return errors.New("the \"no-wait\" flag cannot be used with the \"token\" flag")
 	}
 
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var cm *cometa.Client
 	if len(params.compileInput) != 0 {
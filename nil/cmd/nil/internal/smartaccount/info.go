@@ -23,7 +23,7 @@ func InfoCommand(cfg *common.Config) *cobra.Command {
 }
 
 func infoBalance(cmd *cobra.Command, _ []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 	addr, pub, err := service.GetInfo(cfg.Address)
 	if err != nil {
 		return err
@@ -72,17 +72,26 @@ func runNew(cmd *cobra.Command, _ []string, cfg *common.Config, params *smartAcc
 	if err != nil {
 		return err
 	}
-	srv := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, faucet)
-	check.PanicIfNotf(cfg.PrivateKey != nil, "A private key is not set in the config file")
+	srv := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, faucet)
+	check.PanicIfNotf(cfg.Keys != nil, "A private key is not set in the config file")
+	privateKey, err := cfg.Keys.PrivateKey()
+	if err != nil {
+		return err
+	}
 	smartAccountAddress, err := srv.CreateSmartAccount(params.shardId, &params.salt, amount,
-		types.NewFeePackFromFeeCredit(params.Fee.FeeCredit), &cfg.PrivateKey.PublicKey)
+		types.NewFeePackFromFeeCredit(params.Fee.FeeCredit), &privateKey.PublicKey)
 	if err != nil {
 		return err
 	}
 
-	if err := config.PatchConfig(map[string]any{
-		config.AddressField: smartAccountAddress.Hex(),
-	}, false); err != nil {
+	// The address of a named account is stored in its section.
+	delta := map[string]any{config.AddressField: smartAccountAddress.Hex()}
+	if cfg.Account != "" {
+		err = config.PatchConfigSection(config.AccountSection(cfg.Account), delta)
+	} else {
+		err = config.PatchConfig(delta, false)
+	}
+	if err != nil {
 		logger.Error().Err(err).Msg("failed to update the smart account address in the config file")
 	}
 
@@ -48,7 +48,7 @@ func SendTokensCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runTransfer(cmd *cobra.Command, args []string, cfg *common.Config, params *smartAccountParams) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 
 	var address types.Address
 	if err := address.Set(args[0]); err != nil {
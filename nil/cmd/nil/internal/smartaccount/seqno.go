@@ -23,7 +23,7 @@ func SeqnoCommand(cfg *common.Config) *cobra.Command {
 }
 
 func runSeqno(cmd *cobra.Command, _ []string, cfg *common.Config) error {
-	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), cfg.PrivateKey, nil)
+	service := cliservice.NewServiceWithKeys(cmd.Context(), common.GetRpcClient(), cfg.Keys, nil)
 	seqno, err := service.GetSeqno(cfg.Address)
 	if err != nil {
 		return err
@@ -6,6 +6,8 @@ import (
 
 	"github.com/NilFoundation/nil/nil/cmd/nil/common"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/abigen"
+	"github.com/NilFoundation/nil/nil/cmd/nil/internal/account"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/block"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/cometa"
 	"github.com/NilFoundation/nil/nil/cmd/nil/internal/config"
@@ -36,6 +38,8 @@ var logger = logging.NewLogger("root")
 
 var noConfigCmd = map[string]struct{}{
 	"abi":              {},
+	"abigen":           {},
+	"account":          {},
 	"config":           {},
 	"help":             {},
 	"keygen":           {},
@@ -101,6 +105,12 @@ func main() {
 		"l",
 		"info",
 		"Log level: trace|debug|info|warn|error|fatal|panic")
+	rootCmd.baseCmd.PersistentFlags().StringVar(
+		&common.Account,
+		"account",
+		"",
+		"The account to use (overrides the one set in the config file)",
+	)
 	rootCmd.baseCmd.PersistentFlags().BoolVarP(
 		&common.Quiet,
 		"quiet",
@@ -124,6 +134,8 @@ func main() {
 func (rc *RootCommand) registerSubCommands() {
 	rc.baseCmd.AddCommand(
 		abi.GetCommand(),
+		abigen.GetCommand(),
+		account.GetCommand(),
 		block.GetCommand(&rc.config),
 		config.GetCommand(&rc.cfgFile),
 		contract.GetCommand(&rc.config),
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	rpc_client "github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/cmd/nild/nildconfig"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/readthroughdb"
+	"github.com/NilFoundation/nil/nil/services/nilservice"
+	"github.com/spf13/cobra"
+)
+
+func ForkCommand(cfg *nildconfig.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fork",
+		Short: "Run a local node over all shards forked from a live network",
+		Long: "Run a local node over all shards forked from a live network at the given main shard block.\n" +
+			"The state is read lazily from the source node (it must expose the db API, i.e. run in normal mode) " +
+			"and all changes are kept in memory. The blocks are produced without consensus " +
+			"and the development API is enabled.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg.RunMode = nilservice.NormalRunMode
+			cfg.ReadThrough.Fork = true
+			cfg.MyShards = nil
+			cfg.SplitShards = false
+			cfg.DisableConsensus = true
+			cfg.EnableDevApi = true
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&cfg.ReadThrough.SourceAddr, "rpc", cfg.ReadThrough.SourceAddr, "rpc endpoint of the node to fork")
+	cmd.Flags().Var(&cfg.ReadThrough.ForkMainAtBlock, "block", "main shard block to fork at; latest block by default")
+	cmd.Flags().Uint32Var(
+		&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
+	cmd.Flags().BoolVar(
+		&cfg.ManualMining, "manual-mining", cfg.ManualMining, "produce blocks only on dev_mine requests")
+	check.PanicIfErr(cmd.MarkFlagRequired("rpc"))
+
+	return cmd
+}
+
+// openForkDb creates the in-memory database that reads the state of the source node at the fork block.
+// The number of shards is taken from the source node.
+func openForkDb(ctx context.Context, cfg *nildconfig.Config, logger logging.Logger) (db.DB, error) {
+	client := rpc_client.NewClient(cfg.ReadThrough.SourceAddr, logging.NewLogger("db_client"))
+
+	shardIds, err := client.GetShardIdList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shards of the source node: %w", err)
+	}
+	cfg.NShards = uint32(len(shardIds)) + 1
+
+	cache, err := db.NewBadgerDbInMemory()
+	if err != nil {
+		return nil, err
+	}
+	database, err := readthroughdb.NewReadThroughDbWithMainShard(ctx, client, cache, cfg.ReadThrough.ForkMainAtBlock)
+	if err != nil {
+		cache.Close()
+		return nil, fmt.Errorf("failed to fork %s: %w", cfg.ReadThrough.SourceAddr, err)
+	}
+
+	logger.Info().
+		Str("source", cfg.ReadThrough.SourceAddr).
+		Stringer("block", cfg.ReadThrough.ForkMainAtBlock).
+		Uint32("nShards", cfg.NShards).
+		Msg("Forked the network")
+	return database, nil
+}
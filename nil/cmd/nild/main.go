@@ -40,16 +40,23 @@ func main() {
 
 	profiling.Start(cfg.PprofPort)
 
-	database, err := openDb(cfg.DB.Path, cfg.AllowDbDrop, logger)
-	check.PanicIfErr(err)
-
-	if len(cfg.ReadThrough.SourceAddr) != 0 {
-		database, err = readthroughdb.NewReadThroughWithEndpoint(
-			context.Background(),
-			cfg.ReadThrough.SourceAddr,
-			database,
-			cfg.ReadThrough.ForkMainAtBlock)
+	var database db.DB
+	var err error
+	if cfg.ReadThrough.Fork {
+		database, err = openForkDb(context.Background(), cfg, logger)
 		check.PanicIfErr(err)
+	} else {
+		database, err = openDb(cfg.DB.Path, cfg.AllowDbDrop, logger)
+		check.PanicIfErr(err)
+
+		if len(cfg.ReadThrough.SourceAddr) != 0 {
+			database, err = readthroughdb.NewReadThroughWithEndpoint(
+				context.Background(),
+				cfg.ReadThrough.SourceAddr,
+				database,
+				cfg.ReadThrough.ForkMainAtBlock)
+			check.PanicIfErr(err)
+		}
 	}
 
 	exitCode := nilservice.Run(
@@ -100,6 +107,12 @@ func addBasicFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	addAllowDbClearFlag(fset, cfg)
 	fset.Uint32Var(
 		&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
+	fset.IntVar(
+		&cfg.NodeCacheSize, "node-cache-size", cfg.NodeCacheSize,
+		"number of trie nodes cached in memory (0 disables the cache)")
+	fset.BoolVar(
+		&cfg.EnableFlatState, "flat-state", cfg.EnableFlatState,
+		"keep the latest state in a flat table to serve the RPC reads without trie traversal")
 }
 
 func doBootstrapRequestAndPatchConfig(
@@ -161,6 +174,8 @@ func parseArgs(logger logging.Logger) *nildconfig.Config {
 	rootCmd.PersistentFlags().DurationVar(
 		&cfg.DB.GcFrequency, "db-gc-interval", cfg.DB.GcFrequency, "frequency for badger GC")
 	rootCmd.PersistentFlags().IntVar(&cfg.RPCPort, "http-port", cfg.RPCPort, "http port for rpc server")
+	rootCmd.PersistentFlags().BoolVar(
+		&cfg.EnableWebSocket, "enable-websocket", cfg.EnableWebSocket, "serve websocket connections on the rpc port")
 	rootCmd.PersistentFlags().Var(
 		&cfg.BootstrapPeers,
 		"bootstrap-peers",
@@ -200,7 +215,18 @@ func parseArgs(logger logging.Logger) *nildconfig.Config {
 	runCmd.Flags().StringVar(
 		&cfg.ValidatorKeysPath, "validator-keys-path", cfg.ValidatorKeysPath, "path to write validator keys")
 	runCmd.Flags().BoolVar(&cfg.EnableDevApi, "dev-api", cfg.EnableDevApi, "enable development API")
+	runCmd.Flags().BoolVar(
+		&cfg.ManualMining, "manual-mining", cfg.ManualMining, "produce blocks only on dev_mine requests")
 	runCmd.Flags().StringVar(&cfg.IndexerConfig, "indexer-config", "", "path to Indexer config")
+	runCmd.Flags().StringVar(
+		(*string)(&cfg.StorageMode), "storage-mode", string(cfg.StorageMode),
+		"storage mode: 'archive' keeps the state of all blocks, 'full' prunes the state of old blocks")
+	runCmd.Flags().Uint64Var(
+		&cfg.Pruner.KeepBlocks, "prune-keep-blocks", cfg.Pruner.KeepBlocks,
+		"number of the latest blocks whose state is kept in full storage mode")
+	runCmd.Flags().Uint64Var(
+		&cfg.Pruner.CheckpointInterval, "prune-checkpoint-interval", cfg.Pruner.CheckpointInterval,
+		"keep the state of every n-th block in full storage mode (0 disables checkpoints)")
 
 	addBasicFlags(runCmd.Flags(), cfg)
 	cmdflags.AddNetwork(runCmd.Flags(), cfg.Network)
@@ -258,8 +284,9 @@ func parseArgs(logger logging.Logger) *nildconfig.Config {
 
 	versionCmd := cobrax.VersionCmd(appTitle)
 	devnetCmd := DevnetCommand()
+	forkCmd := ForkCommand(cfg)
 
-	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, versionCmd)
+	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, forkCmd, versionCmd)
 	cobrax.ExitOnHelp(rootCmd)
 
 	check.PanicIfErr(rootCmd.Execute())
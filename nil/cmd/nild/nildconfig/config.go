@@ -9,6 +9,8 @@ import (
 type ReadThroughOptions struct {
 	SourceAddr      string                `yaml:"sourceAddr"`
 	ForkMainAtBlock transport.BlockNumber `yaml:"forkMainAtBlock"`
+	// Set by the fork command: the node runs all shards without consensus over an in-memory database.
+	Fork bool `yaml:"-"`
 }
 
 type Config struct {
@@ -25,6 +25,29 @@ contract Governance is NilBase {
         );
     }
 
+    // scheduleProtocolVersion activates the protocol version at the main shard height.
+    // The nodes that don't support the version stop at that height,
+    // so it must be scheduled once the nodes of the network are upgraded.
+    function scheduleProtocolVersion(uint32 version, uint64 height) external onlyExternal {
+        require(height > block.number, "scheduleProtocolVersion: activation height must be in the future");
+        Nil.ParamProtocolVersions memory params =
+            abi.decode(Nil.getConfigParam("protocol_versions"), (Nil.ParamProtocolVersions));
+        uint256 n = params.activations.length;
+        if (n > 0) {
+            Nil.ProtocolVersionActivation memory last = params.activations[n - 1];
+            require(
+                version > last.version && height > last.height,
+                "scheduleProtocolVersion: versions must be activated in order");
+        }
+
+        Nil.ProtocolVersionActivation[] memory activations = new Nil.ProtocolVersionActivation[](n + 1);
+        for (uint256 i = 0; i < n; i++) {
+            activations[i] = params.activations[i];
+        }
+        activations[n] = Nil.ProtocolVersionActivation(version, height);
+        Nil.setConfigParam("protocol_versions", abi.encode(Nil.ParamProtocolVersions(activations)));
+    }
+
     bytes pubkey;
 
     constructor(bytes memory _pubkey) payable {
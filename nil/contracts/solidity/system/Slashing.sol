@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.15;
+
+import "../lib/Nil.sol";
+
+// Slashing keeps the evidence of the validators misbehaviour for the later slashing.
+// The main shard collator reports the equivocations detected by its node on behalf of the contract itself.
+// There is no BLS precompile, so the signatures of the messages are not checked here,
+// they must be verified before acting upon the evidence.
+contract Slashing {
+    address public constant SELF_ADDRESS = address(0x444444444444444444444444444444444444);
+
+    // Equivocation is a pair of the conflicting protobuf-encoded IBFT messages
+    // signed by the validator at the same height and round.
+    struct Equivocation {
+        uint32 shardId;
+        uint64 height;
+        uint64 round;
+        uint8 msgType;
+        bytes validator;
+        bytes first;
+        bytes second;
+    }
+
+    // reported is checked by the collator to avoid reporting the same equivocation twice.
+    mapping(bytes32 => bool) public reported;
+
+    bytes32[] private ids;
+    mapping(bytes32 => Equivocation) private equivocations;
+
+    event EquivocationReported(bytes32 indexed id, bytes validator, uint32 shardId, uint64 height, uint64 round);
+
+    function reportEquivocation(bytes32 id, Equivocation calldata equivocation) external {
+        require(
+            msg.sender == SELF_ADDRESS, "reportEquivocation: only Slashing contract can be caller of this function");
+        if (reported[id]) {
+            return;
+        }
+        reported[id] = true;
+        ids.push(id);
+        equivocations[id] = equivocation;
+        emit EquivocationReported(
+            id, equivocation.validator, equivocation.shardId, equivocation.height, equivocation.round);
+    }
+
+    function equivocationsCount() external view returns (uint256) {
+        return ids.length;
+    }
+
+    function getEquivocation(uint256 index) external view returns (bytes32, Equivocation memory) {
+        bytes32 id = ids[index];
+        return (id, equivocations[id]);
+    }
+}
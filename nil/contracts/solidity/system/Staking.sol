@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.15;
+
+import "../lib/Nil.sol";
+
+// Staking collects the requests to change the validator set and applies them at the epoch boundaries.
+// The main shard collator calls applyEpoch on behalf of the contract itself in the first block of every epoch,
+// the new set is written into the "curr_validators" config parameter.
+// A validator is identified by its withdrawal address, i.e. the address that requested to join.
+// The requests are queued and applied in batches of at most MAX_REQUESTS_PER_EPOCH, so that applyEpoch always fits
+// into the gas of the epoch transaction; the rest of the queue is applied in the next epochs.
+// A new key must come with the proof of possession: the BLS signature of possessionHash(owner, pubkey) made by the key.
+// It also proves that the key can be parsed, a key that can't be parsed would halt the chain.
+contract Staking {
+    address public constant SELF_ADDRESS = address(0x333333333333333333333333333333333333);
+
+    uint256 public constant MIN_STAKE = 10 ** 18;
+    uint256 public constant MAX_REQUESTS_PER_EPOCH = 32;
+    uint256 public constant MAX_VALIDATORS_PER_SHARD = 100;
+
+    enum RequestKind {
+        Join,
+        Leave,
+        RotateKey
+    }
+
+    struct Request {
+        RequestKind kind;
+        address owner;
+        uint32 shardId;
+        uint8[128] pubkey;
+        uint256 stake;
+    }
+
+    uint64 public epoch;
+
+    // The queue of the requests: the requests with indexes in [head, tail) are not applied yet.
+    mapping(uint256 => Request) private pending;
+    uint256 private head;
+    uint256 private tail;
+    // Whether the owner has a request for the shard in the queue. An owner may have one request per shard.
+    mapping(address => mapping(uint32 => bool)) private hasPending;
+
+    // Stakes of the validators by their withdrawal address and shard.
+    mapping(address => mapping(uint32 => uint256)) public stakes;
+    // Stakes of the validators that left and of the rejected join requests.
+    mapping(address => uint256) public withdrawable;
+
+    event ValidatorJoined(address indexed owner, uint32 shardId);
+    event ValidatorLeft(address indexed owner, uint32 shardId);
+    event KeyRotated(address indexed owner, uint32 shardId);
+    event RequestRejected(address indexed owner, uint32 shardId, RequestKind kind, string reason);
+    event EpochApplied(uint64 epoch, uint256 requests);
+
+    function join(uint32 shardId, uint8[128] calldata pubkey, bytes calldata possessionProof) external payable {
+        require(msg.value >= MIN_STAKE, "join: stake is too low");
+        require(!isValidator(shardId, msg.sender), "join: already a validator");
+        require(isKeyPossessed(msg.sender, pubkey, possessionProof), "join: invalid proof of possession");
+        enqueue(Request(RequestKind.Join, msg.sender, shardId, pubkey, msg.value));
+    }
+
+    function leave(uint32 shardId) external {
+        require(isValidator(shardId, msg.sender), "leave: not a validator");
+        uint8[128] memory pubkey;
+        enqueue(Request(RequestKind.Leave, msg.sender, shardId, pubkey, 0));
+    }
+
+    function rotateKey(uint32 shardId, uint8[128] calldata pubkey, bytes calldata possessionProof) external {
+        require(isValidator(shardId, msg.sender), "rotateKey: not a validator");
+        require(isKeyPossessed(msg.sender, pubkey, possessionProof), "rotateKey: invalid proof of possession");
+        enqueue(Request(RequestKind.RotateKey, msg.sender, shardId, pubkey, 0));
+    }
+
+    function withdraw() external {
+        uint256 amount = withdrawable[msg.sender];
+        require(amount > 0, "withdraw: nothing to withdraw");
+        withdrawable[msg.sender] = 0;
+        Nil.asyncCall(msg.sender, msg.sender, amount, "");
+    }
+
+    function pendingRequests() external view returns (uint256) {
+        return tail - head;
+    }
+
+    // possessionHash returns the hash that the key of the owner signs to prove the possession.
+    function possessionHash(address owner, uint8[128] memory pubkey) public pure returns (uint256) {
+        return uint256(keccak256(abi.encode(SELF_ADDRESS, owner, pubkey)));
+    }
+
+    function applyEpoch() external {
+        require(msg.sender == SELF_ADDRESS, "applyEpoch: only Staking contract can be caller of this function");
+        epoch++;
+
+        uint256 count = tail - head;
+        if (count == 0) {
+            return;
+        }
+        if (count > MAX_REQUESTS_PER_EPOCH) {
+            count = MAX_REQUESTS_PER_EPOCH;
+        }
+
+        Nil.ParamValidators memory params = Nil.getValidators();
+        for (uint256 i = 0; i < count; i++) {
+            Request memory request = pending[head];
+            delete pending[head];
+            head++;
+            hasPending[request.owner][request.shardId] = false;
+
+            string memory err = applyRequest(params, request);
+            if (bytes(err).length != 0) {
+                withdrawable[request.owner] += request.stake;
+                emit RequestRejected(request.owner, request.shardId, request.kind, err);
+            }
+        }
+
+        Nil.setConfigParam("curr_validators", abi.encode(params));
+        emit EpochApplied(epoch, count);
+    }
+
+    function enqueue(Request memory request) private {
+        require(!hasPending[request.owner][request.shardId], "request for the shard is already pending");
+        hasPending[request.owner][request.shardId] = true;
+        pending[tail] = request;
+        tail++;
+    }
+
+    // isValidator checks whether the owner is in the current validator set of the shard.
+    function isValidator(uint32 shardId, address owner) private returns (bool) {
+        Nil.ParamValidators memory params = Nil.getValidators();
+        if (shardId == 0 || shardId > params.validators.length) {
+            return false;
+        }
+        (bool found, ) = findValidator(params.validators[shardId - 1].list, owner);
+        return found;
+    }
+
+    // applyRequest changes the validator set according to the request.
+    // It returns the reason if the request can't be applied.
+    function applyRequest(Nil.ParamValidators memory params, Request memory request) private returns (string memory) {
+        if (request.shardId == 0 || request.shardId > params.validators.length) {
+            return "unknown shard";
+        }
+        Nil.ValidatorInfo[] memory list = params.validators[request.shardId - 1].list;
+        (bool found, uint256 index) = findValidator(list, request.owner);
+
+        if (request.kind == RequestKind.Join) {
+            if (found) {
+                return "already a validator";
+            }
+            if (list.length >= MAX_VALIDATORS_PER_SHARD) {
+                return "the validator set of the shard is full";
+            }
+            if (isKeyUsed(params, request.pubkey)) {
+                return "public key is already used";
+            }
+            params.validators[request.shardId - 1].list = appendValidator(
+                list, Nil.ValidatorInfo(request.pubkey, request.owner));
+            stakes[request.owner][request.shardId] += request.stake;
+            emit ValidatorJoined(request.owner, request.shardId);
+        } else if (request.kind == RequestKind.Leave) {
+            if (!found) {
+                return "not a validator";
+            }
+            if (list.length == 1) {
+                return "the last validator of the shard can't leave";
+            }
+            params.validators[request.shardId - 1].list = removeValidator(list, index);
+            withdrawable[request.owner] += stakes[request.owner][request.shardId];
+            stakes[request.owner][request.shardId] = 0;
+            emit ValidatorLeft(request.owner, request.shardId);
+        } else {
+            if (!found) {
+                return "not a validator";
+            }
+            if (isKeyUsed(params, request.pubkey)) {
+                return "public key is already used";
+            }
+            list[index].PublicKey = request.pubkey;
+            emit KeyRotated(request.owner, request.shardId);
+        }
+        return "";
+    }
+
+    function isKeyPossessed(
+        address owner,
+        uint8[128] calldata pubkey,
+        bytes calldata possessionProof
+    ) private view returns (bool) {
+        bytes memory key = new bytes(128);
+        for (uint256 i = 0; i < 128; i++) {
+            key[i] = bytes1(pubkey[i]);
+        }
+        return Nil.validateBlsSignature(key, possessionHash(owner, pubkey), possessionProof);
+    }
+
+    function findValidator(Nil.ValidatorInfo[] memory list, address owner) private pure returns (bool, uint256) {
+        for (uint256 i = 0; i < list.length; i++) {
+            if (list[i].WithdrawalAddress == owner) {
+                return (true, i);
+            }
+        }
+        return (false, 0);
+    }
+
+    function isKeyUsed(Nil.ParamValidators memory params, uint8[128] memory pubkey) private pure returns (bool) {
+        bytes32 hash = keccak256(abi.encode(pubkey));
+        for (uint256 i = 0; i < params.validators.length; i++) {
+            Nil.ValidatorInfo[] memory list = params.validators[i].list;
+            for (uint256 j = 0; j < list.length; j++) {
+                if (keccak256(abi.encode(list[j].PublicKey)) == hash) {
+                    return true;
+                }
+            }
+        }
+        return false;
+    }
+
+    function appendValidator(
+        Nil.ValidatorInfo[] memory list,
+        Nil.ValidatorInfo memory validator
+    ) private pure returns (Nil.ValidatorInfo[] memory) {
+        Nil.ValidatorInfo[] memory res = new Nil.ValidatorInfo[](list.length + 1);
+        for (uint256 i = 0; i < list.length; i++) {
+            res[i] = list[i];
+        }
+        res[list.length] = validator;
+        return res;
+    }
+
+    function removeValidator(
+        Nil.ValidatorInfo[] memory list,
+        uint256 index
+    ) private pure returns (Nil.ValidatorInfo[] memory) {
+        Nil.ValidatorInfo[] memory res = new Nil.ValidatorInfo[](list.length - 1);
+        for (uint256 i = 0; i < index; i++) {
+            res[i] = list[i];
+        }
+        for (uint256 i = index + 1; i < list.length; i++) {
+            res[i - 1] = list[i];
+        }
+        return res;
+    }
+}
@@ -0,0 +1,216 @@
+package collate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// devRequest is run by the collator of the shard between the blocks.
+type devRequest struct {
+	run    func(ctx context.Context, s *Scheduler) error
+	result chan error
+}
+
+// DevState keeps the changes requested via the development API until they are included into a block.
+// It is shared by the collator of the shard and the RPC.
+type DevState struct {
+	shardId      types.ShardId
+	manualMining bool
+
+	mu           sync.Mutex
+	overrides    []*execution.AccountOverride // +checklocks:mu
+	impersonated map[types.Address]struct{}   // +checklocks:mu
+	timeOffset   uint64                       // +checklocks:mu
+
+	requests chan *devRequest
+}
+
+// NewDevState creates the development state of the shard.
+// With manual mining the collator produces blocks only on Mine requests.
+func NewDevState(shardId types.ShardId, manualMining bool) *DevState {
+	return &DevState{
+		shardId:      shardId,
+		manualMining: manualMining,
+		impersonated: make(map[types.Address]struct{}),
+		requests:     make(chan *devRequest),
+	}
+}
+
+func (d *DevState) checkAddress(address types.Address) error {
+	if address.ShardId() != d.shardId {
+		return fmt.Errorf("address %s doesn't belong to shard %s", address, d.shardId)
+	}
+	return nil
+}
+
+// Override schedules the change of the account for the next block.
+func (d *DevState) Override(o *execution.AccountOverride) error {
+	if err := d.checkAddress(o.Address); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.overrides = append(d.overrides, o)
+	return nil
+}
+
+// Impersonate enables or disables accepting external transactions to the address without verifyExternal.
+func (d *DevState) Impersonate(address types.Address, enabled bool) error {
+	if err := d.checkAddress(address); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if enabled {
+		d.impersonated[address] = struct{}{}
+	} else {
+		delete(d.impersonated, address)
+	}
+	return nil
+}
+
+// IncreaseTime shifts the time of all the following blocks and returns the total shift.
+func (d *DevState) IncreaseTime(seconds uint64) uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.timeOffset += seconds
+	return d.timeOffset
+}
+
+// SetNextBlockTimestamp shifts the time of the following blocks, so that the block following lastBlockId
+// gets the given timestamp. The time can't go backwards.
+func (d *DevState) SetNextBlockTimestamp(timestamp uint64, lastBlockId types.BlockNumber) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// The time of a block is the number of its previous block, see execution.NewEVMBlockContext.
+	next := lastBlockId.Uint64() + d.timeOffset
+	if timestamp < next {
+		return fmt.Errorf("timestamp %d is lower than the time of the next block %d", timestamp, next)
+	}
+	d.timeOffset = timestamp - lastBlockId.Uint64()
+	return nil
+}
+
+// Mine makes the collator produce count blocks and returns the number of the last block.
+func (d *DevState) Mine(ctx context.Context, count uint64) (types.BlockNumber, error) {
+	var blockId types.BlockNumber
+	err := d.do(ctx, func(ctx context.Context, s *Scheduler) error {
+		var err error
+		blockId, err = s.mine(ctx, count)
+		return err
+	})
+	return blockId, err
+}
+
+// do runs f in the collator loop and waits for the result.
+func (d *DevState) do(ctx context.Context, f func(ctx context.Context, s *Scheduler) error) error {
+	req := &devRequest{run: f, result: make(chan error, 1)}
+	select {
+	case d.requests <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause stops the collator until resume is called.
+func (d *DevState) pause(ctx context.Context) (resume func(), err error) {
+	paused := make(chan struct{})
+	resumed := make(chan struct{})
+	req := &devRequest{
+		run: func(ctx context.Context, _ *Scheduler) error {
+			close(paused)
+			select {
+			case <-resumed:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+		result: make(chan error, 1),
+	}
+
+	select {
+	case d.requests <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	resume = sync.OnceFunc(func() { close(resumed) })
+	select {
+	case <-paused:
+		return resume, nil
+	case <-ctx.Done():
+		resume()
+		return nil, ctx.Err()
+	}
+}
+
+// apply adds the pending changes to the proposal.
+func (d *DevState) apply(proposal *execution.ProposalSSZ) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	proposal.AccountOverrides = slices.Clone(d.overrides)
+	proposal.Impersonated = slices.SortedFunc(maps.Keys(d.impersonated), func(a, b types.Address) int {
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	})
+	proposal.TimeOffset = d.timeOffset
+}
+
+// onCommitted drops the overrides included into the committed block.
+// The overrides of the proposal are always the oldest pending ones, unless it was built by another validator.
+func (d *DevState) onCommitted(proposal *execution.Proposal) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := len(proposal.AccountOverrides)
+	if n == 0 || n > len(d.overrides) {
+		return
+	}
+	if slices.EqualFunc(d.overrides[:n], proposal.AccountOverrides, (*execution.AccountOverride).Equal) {
+		d.overrides = slices.Clone(d.overrides[n:])
+	}
+}
+
+// devStateSnapshot is the pending changes saved along with the state of the shard.
+type devStateSnapshot struct {
+	overrides    []*execution.AccountOverride
+	impersonated map[types.Address]struct{}
+	timeOffset   uint64
+}
+
+func (d *DevState) snapshot() devStateSnapshot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return devStateSnapshot{
+		overrides:    slices.Clone(d.overrides),
+		impersonated: maps.Clone(d.impersonated),
+		timeOffset:   d.timeOffset,
+	}
+}
+
+func (d *DevState) revert(s devStateSnapshot) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.overrides = slices.Clone(s.overrides)
+	d.impersonated = maps.Clone(s.impersonated)
+	d.timeOffset = s.timeOffset
+}
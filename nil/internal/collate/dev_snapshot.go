@@ -0,0 +1,262 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+// devTxnPool is implemented by the pools that can be rolled back along with the state of the shard.
+type devTxnPool interface {
+	Snapshot() *txnpool.Snapshot
+	Revert(ctx context.Context, snapshot *txnpool.Snapshot) error
+}
+
+// shardSnapshot is the state of a shard saved by the development API.
+type shardSnapshot struct {
+	block         *types.Block
+	blockHash     common.Hash
+	collatorState types.CollatorState
+	pool          *txnpool.Snapshot
+	dev           devStateSnapshot
+}
+
+// DevSnapshots saves and restores the state of all shards for the development API.
+// A snapshot remembers only the last block of every shard: the blocks and the tries of the older state
+// are kept in the database, so reverting rewinds the last block table and drops the indexes
+// of the later blocks.
+type DevSnapshots struct {
+	db         db.DB
+	validators []*Validator
+
+	mu        sync.Mutex
+	nextId    uint64                      // +checklocks:mu
+	snapshots map[uint64][]*shardSnapshot // +checklocks:mu
+
+	logger logging.Logger
+}
+
+// NewDevSnapshots creates the snapshots over the validators of all shards of the network.
+// All shards must be collated by the node with the development API enabled.
+func NewDevSnapshots(database db.DB, validators []*Validator) (*DevSnapshots, error) {
+	for i, v := range validators {
+		if v == nil || v.DevState() == nil {
+			return nil, fmt.Errorf("shard %d is not collated with the development API enabled", i)
+		}
+	}
+
+	return &DevSnapshots{
+		db:         database,
+		validators: validators,
+		snapshots:  make(map[uint64][]*shardSnapshot),
+		logger:     logging.NewLogger("dev_snapshots"),
+	}, nil
+}
+
+// pauseAll stops the collators of all shards until resume is called.
+func (s *DevSnapshots) pauseAll(ctx context.Context) (resume func(), err error) {
+	resumes := make([]func(), 0, len(s.validators))
+	resume = func() {
+		for _, r := range resumes {
+			r()
+		}
+	}
+
+	for _, v := range s.validators {
+		r, err := v.DevState().pause(ctx)
+		if err != nil {
+			resume()
+			return nil, err
+		}
+		resumes = append(resumes, r)
+	}
+	return resume, nil
+}
+
+func (s *DevSnapshots) pool(v *Validator) devTxnPool {
+	if reflect.ValueOf(v.pool).IsNil() {
+		return nil
+	}
+	pool, ok := v.pool.(devTxnPool)
+	if !ok {
+		return nil
+	}
+	return pool
+}
+
+// Snapshot saves the state of all shards and returns the id of the snapshot.
+func (s *DevSnapshots) Snapshot(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resume, err := s.pauseAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer resume()
+
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	shards := make([]*shardSnapshot, len(s.validators))
+	for i, v := range s.validators {
+		shardId := types.ShardId(i)
+		block, hash, err := db.ReadLastBlock(tx, shardId)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read last block of shard %d: %w", shardId, err)
+		}
+		collatorState, err := db.ReadCollatorState(tx, shardId)
+		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return 0, fmt.Errorf("failed to read collator state of shard %d: %w", shardId, err)
+		}
+
+		shards[i] = &shardSnapshot{
+			block:         block,
+			blockHash:     hash,
+			collatorState: collatorState,
+			dev:           v.DevState().snapshot(),
+		}
+		if pool := s.pool(v); pool != nil {
+			shards[i].pool = pool.Snapshot()
+		}
+	}
+
+	id := s.nextId
+	s.nextId++
+	s.snapshots[id] = shards
+
+	s.logger.Info().
+		Uint64("id", id).
+		Stringer(logging.FieldBlockNumber, shards[types.MainShardId].block.Id).
+		Msg("Saved snapshot")
+	return id, nil
+}
+
+// Revert restores the state of all shards saved by the snapshot.
+// The snapshot and all the later ones are dropped.
+func (s *DevSnapshots) Revert(ctx context.Context, id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shards, ok := s.snapshots[id]
+	if !ok {
+		return fmt.Errorf("snapshot %d not found", id)
+	}
+
+	resume, err := s.pauseAll(ctx)
+	if err != nil {
+		return err
+	}
+	defer resume()
+
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, shard := range shards {
+		if err := rewindShard(tx, types.ShardId(i), shard); err != nil {
+			return fmt.Errorf("failed to rewind shard %d: %w", i, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for i, v := range s.validators {
+		if !v.params.EnableFlatState {
+			continue
+		}
+		if err := execution.RebuildFlatState(ctx, s.db, types.ShardId(i)); err != nil {
+			return fmt.Errorf("failed to rebuild flat state of shard %d: %w", i, err)
+		}
+	}
+
+	for i, shard := range shards {
+		v := s.validators[i]
+		v.resetLastBlock(shard.block, shard.blockHash)
+		v.DevState().revert(shard.dev)
+		if pool := s.pool(v); pool != nil && shard.pool != nil {
+			if err := pool.Revert(ctx, shard.pool); err != nil {
+				return fmt.Errorf("failed to revert txn pool of shard %d: %w", i, err)
+			}
+		}
+	}
+
+	maps.DeleteFunc(s.snapshots, func(k uint64, _ []*shardSnapshot) bool {
+		return k >= id
+	})
+
+	s.logger.Info().
+		Uint64("id", id).
+		Stringer(logging.FieldBlockNumber, shards[types.MainShardId].block.Id).
+		Msg("Reverted to snapshot")
+	return nil
+}
+
+// rewindShard makes the snapshot block the last one of the shard
+// and drops the indexes of the blocks produced after it.
+func rewindShard(tx db.RwTx, shardId types.ShardId, snapshot *shardSnapshot) error {
+	if err := db.CheckStateAvailable(tx, shardId, snapshot.block.Id); err != nil {
+		return err
+	}
+
+	last, _, err := db.ReadLastBlock(tx, shardId)
+	if err != nil {
+		return err
+	}
+
+	for blockId := snapshot.block.Id + 1; blockId <= last.Id; blockId++ {
+		block, err := db.ReadBlockByNumber(tx, shardId, blockId)
+		if err != nil {
+			return fmt.Errorf("failed to read block %d: %w", blockId, err)
+		}
+		if err := deleteTxnIndex(
+			tx, shardId, block.InTransactionsRoot, db.BlockHashAndInTransactionIndexByTransactionHash,
+		); err != nil {
+			return err
+		}
+		if err := deleteTxnIndex(
+			tx, shardId, block.OutTransactionsRoot, db.BlockHashAndOutTransactionIndexByTransactionHash,
+		); err != nil {
+			return err
+		}
+		if err := tx.DeleteFromShard(shardId, db.BlockHashByNumberIndex, blockId.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := db.WriteLastBlockHash(tx, shardId, snapshot.blockHash); err != nil {
+		return err
+	}
+	return db.WriteCollatorState(tx, shardId, snapshot.collatorState)
+}
+
+func deleteTxnIndex(tx db.RwTx, shardId types.ShardId, root common.Hash, table db.ShardedTableName) error {
+	reader := execution.NewDbTransactionTrieReader(tx, shardId)
+	reader.SetRootHash(root)
+	txns, err := reader.Values()
+	if err != nil {
+		return fmt.Errorf("failed to read transactions: %w", err)
+	}
+	for _, txn := range txns {
+		if err := tx.DeleteFromShard(shardId, table, txn.Hash().Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
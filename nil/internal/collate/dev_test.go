@@ -0,0 +1,406 @@
+package collate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevStateOverrides(t *testing.T) {
+	t.Parallel()
+
+	shardId := types.BaseShardId
+	dev := NewDevState(shardId, false)
+
+	addr := types.GenerateRandomAddress(shardId)
+	o1 := &execution.AccountOverride{Kind: execution.OverrideBalance, Address: addr, Balance: types.NewValueFromUint64(1)}
+	o2 := &execution.AccountOverride{Kind: execution.OverrideCode, Address: addr, Code: types.Code("code")}
+	o3 := &execution.AccountOverride{Kind: execution.OverrideStorage, Address: addr, Value: common.HexToHash("0x1")}
+
+	t.Run("WrongShard", func(t *testing.T) {
+		other := types.GenerateRandomAddress(types.MainShardId)
+		require.Error(t, dev.Override(&execution.AccountOverride{Kind: execution.OverrideBalance, Address: other}))
+		require.Error(t, dev.Impersonate(other, true))
+	})
+
+	require.NoError(t, dev.Override(o1))
+	require.NoError(t, dev.Override(o2))
+	require.NoError(t, dev.Impersonate(addr, true))
+
+	proposal := &execution.ProposalSSZ{}
+	dev.apply(proposal)
+	require.Equal(t, []*execution.AccountOverride{o1, o2}, proposal.AccountOverrides)
+	require.Equal(t, []types.Address{addr}, proposal.Impersonated)
+
+	t.Run("Encoding", func(t *testing.T) {
+		data, err := proposal.MarshalSSZ()
+		require.NoError(t, err)
+
+		decoded := &execution.ProposalSSZ{}
+		require.NoError(t, decoded.UnmarshalSSZ(data))
+		require.Len(t, decoded.AccountOverrides, 2)
+		require.True(t, o1.Equal(decoded.AccountOverrides[0]))
+		require.True(t, o2.Equal(decoded.AccountOverrides[1]))
+		require.Equal(t, proposal.Impersonated, decoded.Impersonated)
+	})
+
+	// An override requested while the block was being built stays pending.
+	require.NoError(t, dev.Override(o3))
+
+	t.Run("CommitForeignProposal", func(t *testing.T) {
+		dev.onCommitted(&execution.Proposal{AccountOverrides: []*execution.AccountOverride{o3}})
+
+		next := &execution.ProposalSSZ{}
+		dev.apply(next)
+		require.Len(t, next.AccountOverrides, 3)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		dev.onCommitted(&execution.Proposal{AccountOverrides: proposal.AccountOverrides})
+
+		next := &execution.ProposalSSZ{}
+		dev.apply(next)
+		require.Equal(t, []*execution.AccountOverride{o3}, next.AccountOverrides)
+		require.Equal(t, []types.Address{addr}, next.Impersonated)
+	})
+
+	t.Run("StopImpersonating", func(t *testing.T) {
+		require.NoError(t, dev.Impersonate(addr, false))
+
+		next := &execution.ProposalSSZ{}
+		dev.apply(next)
+		require.Empty(t, next.Impersonated)
+	})
+}
+
+func TestDevStateTime(t *testing.T) {
+	t.Parallel()
+
+	dev := NewDevState(types.BaseShardId, true)
+
+	require.Equal(t, uint64(10), dev.IncreaseTime(10))
+	require.Equal(t, uint64(15), dev.IncreaseTime(5))
+
+	// The next block after block 100 gets time 100 + 15.
+	require.Error(t, dev.SetNextBlockTimestamp(114, 100))
+	require.NoError(t, dev.SetNextBlockTimestamp(115, 100))
+	require.NoError(t, dev.SetNextBlockTimestamp(200, 100))
+
+	proposal := &execution.ProposalSSZ{}
+	dev.apply(proposal)
+	require.Equal(t, uint64(100), proposal.TimeOffset)
+}
+
+func TestApplyDevChanges(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	shardId := types.BaseShardId
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	addr := types.GenerateRandomAddress(shardId)
+	token := types.TokenId(types.GenerateRandomAddress(shardId))
+	key := common.HexToHash("0x01")
+	value := common.HexToHash("0x02")
+	code := types.Code("some code")
+
+	require.NoError(t, es.ApplyDevChanges([]*execution.AccountOverride{
+		{Kind: execution.OverrideBalance, Address: addr, Balance: types.NewValueFromUint64(100)},
+		{Kind: execution.OverrideCode, Address: addr, Code: code},
+		{Kind: execution.OverrideStorage, Address: addr, Key: key, Value: value},
+		{Kind: execution.OverrideTokenBalance, Address: addr, Token: token, Balance: types.NewValueFromUint64(7)},
+	}, []types.Address{addr}, 42))
+
+	balance, err := es.GetBalance(addr)
+	require.NoError(t, err)
+	require.Equal(t, types.NewValueFromUint64(100), balance)
+
+	actualCode, _, err := es.GetCode(addr)
+	require.NoError(t, err)
+	require.Equal(t, []byte(code), actualCode)
+
+	actualValue, err := es.GetState(addr, key)
+	require.NoError(t, err)
+	require.Equal(t, value, actualValue)
+
+	tokens := es.GetTokens(addr)
+	require.Equal(t, types.NewValueFromUint64(7), tokens[token])
+
+	require.Equal(t, uint64(42), es.TimeOffset)
+}
+
+func TestRejectDevChanges(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardId := types.BaseShardId
+	addr := types.GenerateRandomAddress(shardId)
+	genesis := commitTestBlock(t, database, shardId, nil, addr, 100, nil)
+
+	proposal := &execution.ProposalSSZ{
+		PrevBlockId:   genesis.Block.Id,
+		PrevBlockHash: genesis.BlockHash,
+		AccountOverrides: []*execution.AccountOverride{
+			{Kind: execution.OverrideBalance, Address: addr, Balance: types.NewValueFromUint64(1000)},
+		},
+	}
+
+	validator, err := NewValidator(&Params{
+		BlockGeneratorParams: execution.NewBlockGeneratorParams(shardId, 2),
+	}, nil, database, nil, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, validator.IsValidProposal(t.Context(), proposal), execution.ErrDevChangesNotAllowed)
+
+	// The block generator rejects them as well.
+	p, err := execution.ConvertProposal(proposal)
+	require.NoError(t, err)
+	gen, err := execution.NewBlockGenerator(
+		t.Context(), execution.NewBlockGeneratorParams(shardId, 2), database, genesis.Block)
+	require.NoError(t, err)
+	defer gen.Rollback()
+	_, err = gen.BuildBlock(p, nil)
+	require.ErrorIs(t, err, execution.ErrDevChangesNotAllowed)
+}
+
+func TestStoreDevChanges(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardId := types.BaseShardId
+	addr := types.GenerateRandomAddress(shardId)
+	genesis := commitTestBlock(t, database, shardId, nil, addr, 100, nil)
+
+	proposal := &execution.Proposal{
+		PrevBlockId:   genesis.Block.Id,
+		PrevBlockHash: genesis.BlockHash,
+		AccountOverrides: []*execution.AccountOverride{
+			{Kind: execution.OverrideBalance, Address: addr, Balance: types.NewValueFromUint64(1000)},
+		},
+		Impersonated: []types.Address{addr},
+		TimeOffset:   42,
+	}
+
+	params := execution.NewBlockGeneratorParams(shardId, 2)
+	params.AllowDevChanges = true
+	gen, err := execution.NewBlockGenerator(t.Context(), params, database, genesis.Block)
+	require.NoError(t, err)
+	defer gen.Rollback()
+	res, err := gen.GenerateBlock(proposal, &types.ConsensusParams{})
+	require.NoError(t, err)
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	changes, err := execution.ReadDevChanges(tx, shardId, res.BlockHash)
+	require.NoError(t, err)
+	require.NotNil(t, changes)
+	require.Len(t, changes.AccountOverrides, 1)
+	require.True(t, changes.AccountOverrides[0].Equal(proposal.AccountOverrides[0]))
+	require.Equal(t, proposal.Impersonated, changes.Impersonated)
+	require.Equal(t, proposal.TimeOffset, changes.TimeOffset)
+
+	// The blocks without the changes have nothing stored.
+	changes, err = execution.ReadDevChanges(tx, shardId, genesis.BlockHash)
+	require.NoError(t, err)
+	require.Nil(t, changes)
+
+	// Replaying the block on the state of its previous block applies the stored changes.
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          genesis.Block,
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, es.ApplyBlockDevChanges(res.BlockHash))
+
+	balance, err := es.GetBalance(addr)
+	require.NoError(t, err)
+	require.Equal(t, types.NewValueFromUint64(1000), balance)
+	require.Equal(t, uint64(42), es.TimeOffset)
+}
+
+// serveDevRequests runs the requests of the development API instead of the collator.
+func serveDevRequests(ctx context.Context, dev *DevState) {
+	for {
+		select {
+		case req := <-dev.requests:
+			req.result <- req.run(ctx, nil)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// commitTestBlock commits the block with the transaction that sets the balance of the address.
+func commitTestBlock(
+	t *testing.T,
+	database db.DB,
+	shardId types.ShardId,
+	prev *types.Block,
+	addr types.Address,
+	balance uint64,
+	txn *types.Transaction,
+) *execution.BlockGenerationResult {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          prev,
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(balance)))
+	blockId := types.BlockNumber(0)
+	if prev != nil {
+		blockId = prev.Id + 1
+	}
+	if txn != nil {
+		es.AddInTransaction(txn)
+		es.AddReceipt(execution.NewExecutionResult())
+	}
+
+	out, err := es.Commit(blockId, nil)
+	require.NoError(t, err)
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, out, execution.ModeVerify))
+	require.NoError(t, tx.Commit())
+	return out
+}
+
+func newDevTestTransaction(to types.Address, seqno types.Seqno) *types.Transaction {
+	return &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			To:           to,
+			ChainId:      types.DefaultChainId,
+			Seqno:        seqno,
+			MaxFeePerGas: types.NewValueFromUint64(1000),
+		},
+	}
+}
+
+func TestDevSnapshots(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardIds := []types.ShardId{types.MainShardId, types.BaseShardId}
+	validators := make([]*Validator, len(shardIds))
+	pools := make([]*txnpool.TxnPool, len(shardIds))
+	for i, shardId := range shardIds {
+		pools[i], err = txnpool.New(ctx, txnpool.NewConfig(shardId), nil, nil)
+		require.NoError(t, err)
+
+		params := &Params{
+			BlockGeneratorParams: execution.NewBlockGeneratorParams(shardId, uint32(len(shardIds))),
+			DevState:             NewDevState(shardId, true),
+		}
+		validators[i], err = NewValidator(params, validators[0], database, pools[i], nil)
+		require.NoError(t, err)
+		go serveDevRequests(ctx, params.DevState)
+	}
+
+	snapshots, err := NewDevSnapshots(database, validators)
+	require.NoError(t, err)
+
+	addrs := make([]types.Address, len(shardIds))
+	genesis := make([]*execution.BlockGenerationResult, len(shardIds))
+	for i, shardId := range shardIds {
+		addrs[i] = types.GenerateRandomAddress(shardId)
+		genesis[i] = commitTestBlock(t, database, shardId, nil, addrs[i], 100, nil)
+	}
+
+	pending := newDevTestTransaction(addrs[1], 0)
+	_, err = pools[1].Add(ctx, pending)
+	require.NoError(t, err)
+
+	id, err := snapshots.Snapshot(ctx)
+	require.NoError(t, err)
+
+	// Change the state of all shards after the snapshot.
+	committed := newDevTestTransaction(addrs[1], 1)
+	for i, shardId := range shardIds {
+		var txn *types.Transaction
+		if shardId == types.BaseShardId {
+			txn = committed
+		}
+		res := commitTestBlock(t, database, shardId, genesis[i].Block, addrs[i], 200, txn)
+		validators[i].resetLastBlock(res.Block, res.BlockHash)
+	}
+	require.NoError(t, pools[1].OnCommitted(ctx, types.Value{}, []*types.Transaction{pending}))
+	require.NoError(t, validators[1].DevState().Impersonate(addrs[1], true))
+
+	require.NoError(t, snapshots.Revert(ctx, id))
+
+	tx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	for i, shardId := range shardIds {
+		_, hash, err := db.ReadLastBlock(tx, shardId)
+		require.NoError(t, err)
+		require.Equal(t, genesis[i].BlockHash, hash)
+
+		_, err = db.ReadBlockHashByNumber(tx, shardId, 1)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+		_, lastHash, err := validators[i].GetLastBlock(ctx)
+		require.NoError(t, err)
+		require.Equal(t, genesis[i].BlockHash, lastHash)
+
+		es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+			Block:          genesis[i].Block,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		balance, err := es.GetBalance(addrs[i])
+		require.NoError(t, err)
+		require.Equal(t, types.NewValueFromUint64(100), balance)
+	}
+
+	// The transaction of the reverted block can be included again.
+	_, err = tx.GetFromShard(
+		types.BaseShardId, db.BlockHashAndInTransactionIndexByTransactionHash, committed.Hash().Bytes())
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	known, err := pools[1].IdHashKnown(pending.Hash())
+	require.NoError(t, err)
+	require.True(t, known)
+
+	proposal := &execution.ProposalSSZ{}
+	validators[1].DevState().apply(proposal)
+	require.Empty(t, proposal.Impersonated)
+
+	// The snapshot is dropped by the revert.
+	require.Error(t, snapshots.Revert(ctx, id))
+}
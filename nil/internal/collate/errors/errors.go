@@ -7,4 +7,5 @@ var (
 	ErrOutOfOrder          = errors.New("received block is out of order")
 	ErrHashMismatch        = errors.New("block hash mismatch")
 	ErrInvalidProposedHash = errors.New("invalid prposed hash")
+	ErrValidatorsEpoch     = errors.New("invalid validators epoch transaction")
 )
@@ -0,0 +1,172 @@
+package collate
+
+import (
+	"container/heap"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// blockPacker decides how the gas of the proposed block is shared between the incoming internal transactions
+// and the external transactions from the pool, and in which order the candidates are packed into the block.
+//
+// Internal transactions are always executed before the external ones (that is the order validators replay
+// the proposal in), so they can use the whole block. External transactions can't use the reserved share of
+// the block gas even if it is not used by internal transactions, thus the shard loaded with external
+// transactions keeps room for cross-shard traffic. Candidates that don't fit into the remaining gas are
+// skipped instead of stopping the packing, so smaller transactions can still get into the block.
+type blockPacker struct {
+	maxGas      types.Gas
+	reservedGas types.Gas
+}
+
+func newBlockPacker(params *Params) *blockPacker {
+	reserved := params.MaxGasInBlock / 100 * types.Gas(min(params.InternalGasReservePercent, 100))
+	return &blockPacker{
+		maxGas:      params.MaxGasInBlock,
+		reservedGas: reserved,
+	}
+}
+
+// internalGasLimit returns the gas incoming internal transactions can use.
+func (bp *blockPacker) internalGasLimit() types.Gas {
+	return bp.maxGas
+}
+
+// externalGasLimit returns the gas the block can use after adding external transactions,
+// given the gas used by the internal ones.
+func (bp *blockPacker) externalGasLimit(internalGasUsed types.Gas) types.Gas {
+	unavailable := max(internalGasUsed, bp.reservedGas)
+	if unavailable >= bp.maxGas {
+		return internalGasUsed
+	}
+	return internalGasUsed + bp.maxGas - unavailable
+}
+
+// fits reports whether the external transaction fits into the block in the worst case.
+// The first external transaction of the block is admitted while there is gas left: its worst case may exceed
+// the whole external share (e.g., due to a huge fee credit), and it would never get into any block otherwise,
+// blocking the next transactions to its receiver.
+func (bp *blockPacker) fits(es *execution.ExecutionState, limit types.Gas, txn *types.Transaction, first bool) bool {
+	if es.GasUsed >= limit {
+		return false
+	}
+	if first {
+		return true
+	}
+	bound := min(externalGasBound(es, txn), bp.maxGas-min(bp.reservedGas, bp.maxGas))
+	return es.GasUsed.Add(bound) <= limit
+}
+
+// externalGasBound returns the maximum gas the external transaction can use,
+// including the verification of its signature.
+func externalGasBound(es *execution.ExecutionState, txn *types.Transaction) types.Gas {
+	gas := execution.ExternalTransactionVerificationMaxGas
+
+	// Keep in sync with ExecutionState.updateGasPrice.
+	price := es.BaseFee.Add(txn.MaxPriorityFeePerGas)
+	if price.Cmp(txn.MaxFeePerGas) > 0 {
+		price = txn.MaxFeePerGas
+	}
+	if price.IsZero() {
+		return gas
+	}
+	return gas.Add(min(txn.FeeCredit.ToGas(price), es.GasLimit))
+}
+
+// orderExternal returns the external transactions in the order they should be packed into the block:
+// the transaction paying the highest effective priority fee at the given base fee goes first, but
+// the transactions to the same receiver keep their relative order (i.e., the order of seqnos).
+// Transactions that can't pay the base fee go last.
+func orderExternal(txns []*types.TxnWithHash, baseFee types.Value) []*types.TxnWithHash {
+	byReceiver := make(map[types.Address]*receiverQueue)
+	queues := make(receiverHeap, 0)
+	for i, txn := range txns {
+		q, ok := byReceiver[txn.To]
+		if !ok {
+			q = &receiverQueue{firstIndex: i, baseFee: baseFee}
+			byReceiver[txn.To] = q
+			queues = append(queues, q)
+		}
+		q.txns = append(q.txns, txn)
+	}
+	for _, q := range queues {
+		q.updateHead()
+	}
+	heap.Init(&queues)
+
+	res := make([]*types.TxnWithHash, 0, len(txns))
+	for queues.Len() > 0 {
+		q := queues[0]
+		res = append(res, q.txns[0])
+		q.txns = q.txns[1:]
+		if len(q.txns) == 0 {
+			heap.Pop(&queues)
+			continue
+		}
+		q.updateHead()
+		heap.Fix(&queues, 0)
+	}
+	return res
+}
+
+// rotateNeighbors returns the neighbors starting from a different one in every block,
+// so that a busy neighbor doesn't delay the transactions from the others.
+func rotateNeighbors(neighbors []types.ShardId, blockId types.BlockNumber) []types.ShardId {
+	if len(neighbors) == 0 {
+		return neighbors
+	}
+	k := int(uint64(blockId) % uint64(len(neighbors)))
+	res := make([]types.ShardId, 0, len(neighbors))
+	res = append(res, neighbors[k:]...)
+	return append(res, neighbors[:k]...)
+}
+
+type receiverQueue struct {
+	txns       []*types.TxnWithHash
+	firstIndex int
+	baseFee    types.Value
+
+	headFee   types.Value
+	headValid bool
+}
+
+func (q *receiverQueue) updateHead() {
+	q.headFee, q.headValid = execution.GetEffectivePriorityFee(q.baseFee, q.txns[0].Transaction)
+}
+
+type receiverHeap []*receiverQueue
+
+func (h receiverHeap) Len() int {
+	return len(h)
+}
+
+func (h receiverHeap) Less(i, j int) bool {
+	if h[i].headValid != h[j].headValid {
+		return h[i].headValid
+	}
+	if c := h[i].headFee.Cmp(h[j].headFee); c != 0 {
+		return c > 0
+	}
+	return h[i].firstIndex < h[j].firstIndex
+}
+
+func (h receiverHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *receiverHeap) Push(x any) {
+	q, ok := x.(*receiverQueue)
+	check.PanicIfNot(ok)
+	*h = append(*h, q)
+}
+
+func (h *receiverHeap) Pop() any {
+	old := *h
+	n := len(old)
+	q := old[n-1]
+	old[n-1] = nil // avoid memory leak
+	*h = old[:n-1]
+	return q
+}
@@ -0,0 +1,111 @@
+package collate
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderExternal(t *testing.T) {
+	t.Parallel()
+
+	addr1 := types.ShardAndHexToAddress(types.BaseShardId, "11")
+	addr2 := types.ShardAndHexToAddress(types.BaseShardId, "22")
+	addr3 := types.ShardAndHexToAddress(types.BaseShardId, "33")
+
+	newTxn := func(to types.Address, seqno types.Seqno, maxFee, priorityFee uint64) *types.TxnWithHash {
+		return types.NewTxnWithHash(&types.Transaction{
+			TransactionDigest: types.TransactionDigest{
+				To:                   to,
+				Seqno:                seqno,
+				MaxFeePerGas:         types.NewValueFromUint64(maxFee),
+				MaxPriorityFeePerGas: types.NewValueFromUint64(priorityFee),
+			},
+		})
+	}
+
+	const baseFee = 10
+	txn10 := newTxn(addr1, 0, 100, 1)
+	txn11 := newTxn(addr1, 1, 100, 50)
+	txn20 := newTxn(addr2, 0, 100, 5)
+	txn21 := newTxn(addr2, 1, 13, 5) // pays only 3 over the base fee
+	txn30 := newTxn(addr3, 0, 5, 5)  // can't pay the base fee
+
+	res := orderExternal(
+		[]*types.TxnWithHash{txn30, txn10, txn11, txn20, txn21}, types.NewValueFromUint64(baseFee))
+
+	// txn11 pays the most, but it can't go before txn10.
+	require.Equal(t, []*types.TxnWithHash{txn20, txn21, txn10, txn11, txn30}, res)
+
+	require.Empty(t, orderExternal(nil, types.NewValueFromUint64(baseFee)))
+}
+
+func TestBlockPackerGasLimits(t *testing.T) {
+	t.Parallel()
+
+	packer := newBlockPacker(&Params{MaxGasInBlock: 1000})
+	require.Equal(t, types.Gas(1000), packer.internalGasLimit())
+	require.Equal(t, types.Gas(1000), packer.externalGasLimit(0))
+	require.Equal(t, types.Gas(1000), packer.externalGasLimit(400))
+
+	packer = newBlockPacker(&Params{MaxGasInBlock: 1000, InternalGasReservePercent: 30})
+	require.Equal(t, types.Gas(1000), packer.internalGasLimit())
+	// The reserved gas is not available to external transactions even if internal ones don't use it.
+	require.Equal(t, types.Gas(700), packer.externalGasLimit(0))
+	require.Equal(t, types.Gas(900), packer.externalGasLimit(200))
+	require.Equal(t, types.Gas(1000), packer.externalGasLimit(400))
+	require.Equal(t, types.Gas(1200), packer.externalGasLimit(1200))
+
+	packer = newBlockPacker(&Params{MaxGasInBlock: 1000, InternalGasReservePercent: 200})
+	require.Equal(t, types.Gas(0), packer.externalGasLimit(0))
+}
+
+func TestBlockPackerFits(t *testing.T) {
+	t.Parallel()
+
+	packer := newBlockPacker(&Params{MaxGasInBlock: types.DefaultMaxGasInBlock, InternalGasReservePercent: 10})
+	limit := packer.externalGasLimit(0)
+	es := &execution.ExecutionState{
+		BaseFee:  types.NewValueFromUint64(10),
+		GasLimit: types.DefaultMaxGasInBlock,
+	}
+
+	newTxn := func(feeCredit types.Gas) *types.Transaction {
+		return &types.Transaction{
+			TransactionDigest: types.TransactionDigest{
+				MaxFeePerGas: types.NewValueFromUint64(10),
+				FeeCredit:    feeCredit.ToValue(types.NewValueFromUint64(10)),
+			},
+		}
+	}
+
+	// The fee credit is far above the block limit, but the transaction can use at most the external share.
+	huge := newTxn(100 * types.DefaultMaxGasInBlock)
+	require.True(t, packer.fits(es, limit, huge, false))
+
+	// It doesn't fit after the other transactions unless it is the first external one.
+	es.GasUsed = 1000
+	require.False(t, packer.fits(es, limit, huge, false))
+	require.True(t, packer.fits(es, limit, huge, true))
+
+	small := newTxn(1000)
+	require.True(t, packer.fits(es, limit, small, false))
+
+	// Nothing fits into the full block.
+	es.GasUsed = limit
+	require.False(t, packer.fits(es, limit, small, true))
+	require.False(t, packer.fits(es, limit, small, false))
+}
+
+func TestRotateNeighbors(t *testing.T) {
+	t.Parallel()
+
+	neighbors := []types.ShardId{1, 2, 3}
+	require.Equal(t, []types.ShardId{1, 2, 3}, rotateNeighbors(neighbors, 0))
+	require.Equal(t, []types.ShardId{2, 3, 1}, rotateNeighbors(neighbors, 1))
+	require.Equal(t, []types.ShardId{3, 1, 2}, rotateNeighbors(neighbors, 5))
+	require.Equal(t, []types.ShardId{1, 2, 3}, neighbors)
+	require.Empty(t, rotateNeighbors(nil, 1))
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
@@ -24,6 +26,10 @@ const (
 	defaultMaxGasInBlock                 = types.DefaultMaxGasInBlock
 	maxTxnsFromPool                      = 10_000
 	defaultMaxForwardTransactionsInBlock = 200
+	// maxEvidenceInBlock limits the number of equivocations reported in a single main shard block.
+	maxEvidenceInBlock = 4
+	// slashingViewGas is the gas limit of the calls of the Slashing contract getters.
+	slashingViewGas = types.Gas(100_000)
 
 	validatorPatchLevel = 1
 )
@@ -33,6 +39,7 @@ type proposer struct {
 
 	topology ShardTopology
 	pool     TxnPool
+	packer   *blockPacker
 
 	logger logging.Logger
 
@@ -55,6 +62,7 @@ func newProposer(params *Params, topology ShardTopology, pool TxnPool, logger lo
 		params:         params,
 		topology:       topology,
 		pool:           pool,
+		packer:         newBlockPacker(params),
 		logger:         logger,
 		l1BlockFetcher: params.L1Fetcher,
 	}
@@ -96,6 +104,15 @@ func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execu
 		return nil, err
 	}
 
+	if dev := p.params.DevState; dev != nil {
+		dev.apply(p.proposal)
+		if err := p.executionState.ApplyDevChanges(
+			p.proposal.AccountOverrides, p.proposal.Impersonated, p.proposal.TimeOffset,
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	p.logger.Trace().Msg("Collating...")
 
 	if err := p.fetchLastBlockHashes(tx); err != nil {
@@ -107,6 +124,14 @@ func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execu
 		p.logger.Trace().Err(err).Msg("Failed to handle L1 attributes")
 	}
 
+	if err := p.handleValidatorsEpoch(); err != nil {
+		return nil, fmt.Errorf("failed to handle validators epoch: %w", err)
+	}
+
+	if err := p.handleConsensusEvidence(tx); err != nil {
+		return nil, fmt.Errorf("failed to handle consensus evidence: %w", err)
+	}
+
 	if err := p.handleTransactionsFromNeighbors(tx); err != nil {
 		return nil, fmt.Errorf("failed to handle transactions from neighbors: %w", err)
 	}
@@ -208,6 +233,289 @@ func (p *proposer) handleL1Attributes(tx db.RoTx, mainShardHash common.Hash) err
 	return nil
 }
 
+// handleValidatorsEpoch adds the transaction applying the validator set changes requested in the Staking contract
+// to the first main shard block of the epoch. The new set is written to the config of this block, so it is used
+// to verify the blocks starting from the next but one.
+func (p *proposer) handleValidatorsEpoch() error {
+	if !p.params.ShardId.IsMainShard() {
+		return nil
+	}
+	if ok, err := isValidatorsEpochStart(p.executionState, p.proposal.PrevBlockId+1); err != nil || !ok {
+		return err
+	}
+
+	txId := p.executionState.InTxCounts[types.MainShardId]
+	p.executionState.InTxCounts[types.MainShardId] = txId + 1
+	txn, err := CreateValidatorsEpochTransaction(txId)
+	if err != nil {
+		return fmt.Errorf("failed to create validators epoch transaction: %w", err)
+	}
+
+	p.logger.Debug().
+		Stringer(logging.FieldBlockNumber, p.proposal.PrevBlockId+1).
+		Msg("Add validators epoch transaction")
+
+	p.proposal.SpecialTxns = append(p.proposal.SpecialTxns, txn)
+	return nil
+}
+
+// handleConsensusEvidence reports the equivocations detected by the validators of this node
+// to the Slashing contract. The ones already reported by any node are skipped,
+// they are deleted once a block is committed (see deleteReportedEvidence).
+func (p *proposer) handleConsensusEvidence(tx db.RoTx) error {
+	if !p.params.ShardId.IsMainShard() {
+		return nil
+	}
+
+	acc, err := p.executionState.GetAccount(types.SlashingAddress)
+	if err != nil {
+		return err
+	}
+	if acc == nil || len(acc.Code) == 0 {
+		return nil
+	}
+
+	slashingAbi, err := contracts.GetAbi(contracts.NameSlashing)
+	if err != nil {
+		return fmt.Errorf("failed to get Slashing ABI: %w", err)
+	}
+
+	count := 0
+	for shardId := range types.ShardId(p.params.NShards) {
+		records, err := evidence.ReadAll(tx, shardId)
+		if err != nil {
+			return fmt.Errorf("failed to read evidence of shard %d: %w", shardId, err)
+		}
+		for _, ev := range records {
+			done, err := isEquivocationReported(p.executionState, slashingAbi, ev.Id())
+			if err != nil {
+				return err
+			}
+			if done || count == maxEvidenceInBlock {
+				continue
+			}
+
+			txId := p.executionState.InTxCounts[types.MainShardId]
+			p.executionState.InTxCounts[types.MainShardId] = txId + 1
+			txn, err := CreateEquivocationReportTransaction(ev, txId)
+			if err != nil {
+				return fmt.Errorf("failed to create equivocation report transaction: %w", err)
+			}
+
+			p.logger.Debug().
+				Stringer(logging.FieldShardId, shardId).
+				Hex(logging.FieldPublicKey, ev.Validator()).
+				Uint64(logging.FieldHeight, ev.Height()).
+				Uint64(logging.FieldRound, ev.Round()).
+				Msg("Add equivocation report transaction")
+
+			p.proposal.SpecialTxns = append(p.proposal.SpecialTxns, txn)
+			count++
+		}
+	}
+	return nil
+}
+
+// isEquivocationReported calls the `reported` getter of the Slashing contract.
+func isEquivocationReported(es *execution.ExecutionState, slashingAbi *abi.ABI, id common.Hash) (bool, error) {
+	calldata, err := slashingAbi.Pack("reported", [32]byte(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to pack Slashing.reported calldata: %w", err)
+	}
+	ret, err := es.StaticCall(types.SlashingAddress, calldata, slashingViewGas)
+	if err != nil {
+		return false, fmt.Errorf("failed to call Slashing.reported: %w", err)
+	}
+	var reported bool
+	if err := slashingAbi.UnpackIntoInterface(&reported, "reported", ret); err != nil {
+		return false, fmt.Errorf("failed to unpack Slashing.reported result: %w", err)
+	}
+	return reported, nil
+}
+
+// deleteReportedEvidence removes the evidence the Slashing contract holds already in the state of the committed
+// main shard block, so it is not read by the proposer again.
+func deleteReportedEvidence(ctx context.Context, database db.DB, nShards uint32, block *types.Block) error {
+	reported, err := readReportedEvidence(ctx, database, nShards, block)
+	if err != nil || len(reported) == 0 {
+		return err
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, ev := range reported {
+		if err := evidence.Delete(tx, ev.ShardId, ev.Id()); err != nil {
+			return fmt.Errorf("failed to delete reported evidence: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func readReportedEvidence(
+	ctx context.Context, database db.DB, nShards uint32, block *types.Block,
+) ([]*evidence.Evidence, error) {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var records []*evidence.Evidence
+	for shardId := range types.ShardId(nShards) {
+		shardRecords, err := evidence.ReadAll(tx, shardId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read evidence of shard %d: %w", shardId, err)
+		}
+		records = append(records, shardRecords...)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, block, types.MainShardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+	es, err := execution.NewExecutionState(tx, types.MainShardId, execution.StateParams{
+		Block:          block,
+		ConfigAccessor: configAccessor,
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	acc, err := es.GetAccount(types.SlashingAddress)
+	if err != nil || acc == nil || len(acc.Code) == 0 {
+		return nil, err
+	}
+
+	slashingAbi, err := contracts.GetAbi(contracts.NameSlashing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Slashing ABI: %w", err)
+	}
+
+	var reported []*evidence.Evidence
+	for _, ev := range records {
+		done, err := isEquivocationReported(es, slashingAbi, ev.Id())
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			reported = append(reported, ev)
+		}
+	}
+	return reported, nil
+}
+
+// equivocation is the Slashing.Equivocation structure.
+type equivocation struct {
+	ShardId   uint32
+	Height    uint64
+	Round     uint64
+	MsgType   uint8
+	Validator []byte
+	First     []byte
+	Second    []byte
+}
+
+func CreateEquivocationReportTransaction(
+	ev *evidence.Evidence,
+	txId types.TransactionIndex,
+) (*types.Transaction, error) {
+	abi, err := contracts.GetAbi(contracts.NameSlashing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Slashing ABI: %w", err)
+	}
+	first, second, err := ev.MarshalMessages()
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := abi.Pack("reportEquivocation", [32]byte(ev.Id()), equivocation{
+		ShardId:   uint32(ev.ShardId),
+		Height:    ev.Height(),
+		Round:     ev.Round(),
+		MsgType:   uint8(ev.Type()),
+		Validator: ev.Validator(),
+		First:     first,
+		Second:    second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack reportEquivocation calldata: %w", err)
+	}
+
+	txn := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags:                types.NewTransactionFlags(types.TransactionFlagInternal),
+			To:                   types.SlashingAddress,
+			FeeCredit:            types.GasToValue(types.DefaultMaxGasInBlock.Uint64()),
+			MaxFeePerGas:         types.MaxFeePerGasDefault,
+			MaxPriorityFeePerGas: types.Value0,
+			Data:                 calldata,
+		},
+		TxId: txId,
+		From: types.SlashingAddress,
+	}
+
+	return txn, nil
+}
+
+// isValidatorsEpochStart reports whether the main shard block must carry the validators epoch transaction.
+// The state is the one the block is built on.
+func isValidatorsEpochStart(es *execution.ExecutionState, blockId types.BlockNumber) (bool, error) {
+	length, err := config.GetValidatorsEpochLength(es.GetConfigAccessor())
+	if err != nil {
+		return false, fmt.Errorf("failed to get validators epoch length: %w", err)
+	}
+	if blockId%length != 0 {
+		return false, nil
+	}
+
+	// The networks with custom zero state may have no Staking contract.
+	acc, err := es.GetAccount(types.StakingAddress)
+	if err != nil {
+		return false, err
+	}
+	return acc != nil && len(acc.Code) > 0, nil
+}
+
+func createValidatorsEpochCalldata() ([]byte, error) {
+	abi, err := contracts.GetAbi(contracts.NameStaking)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Staking ABI: %w", err)
+	}
+	calldata, err := abi.Pack("applyEpoch")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack applyEpoch calldata: %w", err)
+	}
+	return calldata, nil
+}
+
+func CreateValidatorsEpochTransaction(txId types.TransactionIndex) (*types.Transaction, error) {
+	calldata, err := createValidatorsEpochCalldata()
+	if err != nil {
+		return nil, err
+	}
+
+	txn := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags:                types.NewTransactionFlags(types.TransactionFlagInternal),
+			To:                   types.StakingAddress,
+			FeeCredit:            types.GasToValue(types.DefaultMaxGasInBlock.Uint64()),
+			MaxFeePerGas:         types.MaxFeePerGasDefault,
+			MaxPriorityFeePerGas: types.Value0,
+			Data:                 calldata,
+		},
+		TxId: txId,
+		From: types.StakingAddress,
+	}
+
+	return txn, nil
+}
+
 func CreateRollbackCalldata(params *execution.RollbackParams) ([]byte, error) {
 	abi, err := contracts.GetAbi(contracts.NameGovernance)
 	if err != nil {
@@ -294,6 +602,12 @@ func (p *proposer) handleTransactionsFromPool() error {
 		p.logger.Debug().Int("txNum", len(poolTxns)).Msg("Start handling transactions from the pool")
 	}
 
+	gasLimit := p.packer.externalGasLimit(p.executionState.GasUsed)
+	poolTxns = orderExternal(poolTxns, p.executionState.BaseFee)
+
+	// Receivers with skipped transactions. Their next transactions would fail with a seqno gap.
+	skipped := make(map[types.Address]struct{})
+
 	var unverified []common.Hash
 	handle := func(mt *types.TxnWithHash) (bool, error) {
 		txnHash := mt.Hash()
@@ -323,14 +637,18 @@ func (p *proposer) handleTransactionsFromPool() error {
 	}
 
 	for _, txn := range poolTxns {
+		if _, ok := skipped[txn.To]; ok {
+			continue
+		}
+		if !p.packer.fits(p.executionState, gasLimit, txn.Transaction, len(p.proposal.ExternalTxns) == 0) {
+			skipped[txn.To] = struct{}{}
+			continue
+		}
+
 		if ok, err := handle(txn); err != nil {
 			return err
 		} else if ok {
 			p.proposal.ExternalTxns = append(p.proposal.ExternalTxns, txn.Transaction)
-			if p.executionState.GasUsed > p.params.MaxGasInBlock {
-				unverified = append(unverified, txn.Hash())
-				break
-			}
 		}
 	}
 
@@ -361,13 +679,15 @@ func (p *proposer) handleTransactionsFromNeighbors(tx db.RoTx) error {
 	})
 
 	checkLimits := func() bool {
-		return p.executionState.GasUsed < p.params.MaxGasInBlock &&
+		return p.executionState.GasUsed < p.packer.internalGasLimit() &&
 			len(p.proposal.ForwardTxnRefs) < p.params.MaxForwardTransactionsInBlock
 	}
 
 	var parents []*execution.ParentBlock
 
-	for _, neighborId := range p.topology.GetNeighbors(p.params.ShardId, p.params.NShards, true) {
+	neighbors := rotateNeighbors(
+		p.topology.GetNeighbors(p.params.ShardId, p.params.NShards, true), p.proposal.PrevBlockId+1)
+	for _, neighborId := range neighbors {
 		position, ok := neighborIndexes[neighborId]
 		if !ok {
 			position = len(neighborIndexes)
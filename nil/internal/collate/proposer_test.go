@@ -5,7 +5,10 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
@@ -72,23 +75,185 @@ func (s *ProposerTestSuite) TestBlockGas() {
 
 	params := s.newParams()
 
+	var txnGas types.Gas
 	s.Run("DefaultMaxGasInBlock", func() {
 		p := newTestProposer(params, pool)
 
 		proposal := s.generateProposal(p)
 		s.Equal(pool.Txns, proposal.ExternalTxns)
+
+		txnGas = externalGasBound(p.executionState, m1)
+		s.Equal(txnGas, externalGasBound(p.executionState, m2))
 	})
 
 	s.Run("MaxGasInBlockFor1Txn", func() {
-		params.MaxGasInBlock = 2000
+		// The second transaction doesn't fit since the first one uses some gas.
+		params.MaxGasInBlock = txnGas
+		p := newTestProposer(params, pool)
+
+		proposal := s.generateProposal(p)
+
+		s.Equal(pool.Txns[:1], proposal.ExternalTxns)
+		// The transaction that doesn't fit stays in the pool.
+		s.Empty(pool.LastDiscarded)
+	})
+
+	s.Run("MaxGasInBlockBelowTxnBound", func() {
+		// The first transaction is admitted even if its worst case exceeds the block gas,
+		// otherwise it would never get into a block.
+		params.MaxGasInBlock = txnGas - 1
 		p := newTestProposer(params, pool)
 
 		proposal := s.generateProposal(p)
 
 		s.Equal(pool.Txns[:1], proposal.ExternalTxns)
+		s.Empty(pool.LastDiscarded)
+	})
+
+	s.Run("InternalGasReserve", func() {
+		params.MaxGasInBlock = 2 * txnGas
+		params.InternalGasReservePercent = 50
+		p := newTestProposer(params, pool)
+
+		proposal := s.generateProposal(p)
+
+		s.Equal(pool.Txns[:1], proposal.ExternalTxns)
+	})
+}
+
+func (s *ProposerTestSuite) TestValidatorsEpoch() {
+	// The epoch length is a part of the config, so all validators agree on the blocks starting the epochs.
+	g, err := execution.NewBlockGenerator(s.T().Context(),
+		execution.NewBlockGeneratorParams(types.MainShardId, 2), s.db, nil)
+	s.Require().NoError(err)
+	zerostateCfg, err := execution.CreateDefaultZeroStateConfig(execution.MainPublicKey)
+	s.Require().NoError(err)
+	zerostateCfg.ConfigParams = execution.ConfigParams{
+		GasPrice: config.ParamGasPrice{
+			Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+		},
+		ValidatorsEpoch: config.ParamValidatorsEpoch{Length: 2},
+	}
+	zeroState, err := g.GenerateZeroState(zerostateCfg)
+	s.Require().NoError(err)
+	g.Rollback()
+
+	params := &Params{BlockGeneratorParams: execution.NewBlockGeneratorParams(types.MainShardId, 2)}
+	validator, err := NewValidator(params, nil, s.db, nil, nil)
+	s.Require().NoError(err)
+
+	readBlock := func(hash common.Hash) *types.Block {
+		tx, err := s.db.CreateRoTx(s.T().Context())
+		s.Require().NoError(err)
+		defer tx.Rollback()
+		block, err := db.ReadBlock(tx, types.MainShardId, hash)
+		s.Require().NoError(err)
+		return block
+	}
+
+	s.Run("InsideEpoch", func() {
+		// The zero state is block 0, so block 1 is not the epoch boundary.
+		proposal := s.generateProposal(newTestProposer(params, &MockTxnPool{}))
+		s.Empty(proposal.InternalTxns)
+
+		prevBlock := readBlock(proposal.PrevBlockHash)
+		s.Require().NoError(validator.validateValidatorsEpoch(s.T().Context(), prevBlock, proposal))
+
+		// The epoch transaction is rejected inside the epoch.
+		epochTxn, err := CreateValidatorsEpochTransaction(0)
+		s.Require().NoError(err)
+		proposal.InternalTxns = append(proposal.InternalTxns, epochTxn)
+		s.Require().ErrorIs(
+			validator.validateValidatorsEpoch(s.T().Context(), prevBlock, proposal), cerrors.ErrValidatorsEpoch)
+	})
+
+	s.Run("EpochBoundary", func() {
+		hash := execution.GenerateBlockFromTransactions(
+			s.T(), types.MainShardId, 1, zeroState.Hash(types.MainShardId), s.db, nil)
+
+		proposal := s.generateProposal(newTestProposer(params, &MockTxnPool{}))
+		s.Require().Equal(hash, proposal.PrevBlockHash)
+		// Special transactions come first among the internal ones.
+		s.Require().NotEmpty(proposal.InternalTxns)
+
+		txn := proposal.InternalTxns[0]
+		s.Equal(types.StakingAddress, txn.To)
+		s.Equal(types.StakingAddress, txn.From)
+		s.True(txn.IsInternal())
+
+		prevBlock := readBlock(hash)
+		s.Require().NoError(validator.validateValidatorsEpoch(s.T().Context(), prevBlock, proposal))
+
+		// The proposal without the epoch transaction is rejected.
+		proposal.InternalTxns = proposal.InternalTxns[1:]
+		s.Require().ErrorIs(
+			validator.validateValidatorsEpoch(s.T().Context(), prevBlock, proposal), cerrors.ErrValidatorsEpoch)
 	})
 }
 
+func (s *ProposerTestSuite) TestConsensusEvidence() {
+	execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
+
+	newCommit := func(hash []byte) *protoIBFT.IbftMessage {
+		return &protoIBFT.IbftMessage{
+			View: &protoIBFT.View{Height: 3},
+			From: []byte{1},
+			Type: protoIBFT.MessageType_COMMIT,
+			Payload: &protoIBFT.IbftMessage_CommitData{
+				CommitData: &protoIBFT.CommitMessage{ProposalHash: hash},
+			},
+		}
+	}
+	ev := &evidence.Evidence{ShardId: s.shardId, First: newCommit([]byte{1}), Second: newCommit([]byte{2})}
+
+	tx, err := s.db.CreateRwTx(s.T().Context())
+	s.Require().NoError(err)
+	written, err := evidence.Write(tx, ev)
+	s.Require().NoError(err)
+	s.Require().True(written)
+	s.Require().NoError(tx.Commit())
+
+	params := &Params{BlockGeneratorParams: execution.NewBlockGeneratorParams(types.MainShardId, 2)}
+	p := newTestProposer(params, &MockTxnPool{})
+	proposal := s.generateProposal(p)
+	s.Require().NotEmpty(proposal.InternalTxns)
+
+	txn := proposal.InternalTxns[0]
+	s.Equal(types.SlashingAddress, txn.To)
+	s.Equal(types.SlashingAddress, txn.From)
+
+	readEvidence := func() []*evidence.Evidence {
+		s.T().Helper()
+		roTx, err := s.db.CreateRoTx(s.T().Context())
+		s.Require().NoError(err)
+		defer roTx.Rollback()
+		stored, err := evidence.ReadAll(roTx, s.shardId)
+		s.Require().NoError(err)
+		return stored
+	}
+
+	// Once the report is committed, the evidence is not reported again.
+	roTx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	block, err := db.ReadBlock(roTx, types.MainShardId, proposal.PrevBlockHash)
+	roTx.Rollback()
+	s.Require().NoError(err)
+
+	blockGenerator, err := execution.NewBlockGenerator(s.T().Context(), params.BlockGeneratorParams, s.db, block)
+	s.Require().NoError(err)
+	res, err := blockGenerator.GenerateBlock(proposal, &types.ConsensusParams{})
+	blockGenerator.Rollback()
+	s.Require().NoError(err)
+
+	proposal = s.generateProposal(p)
+	s.Empty(proposal.InternalTxns)
+
+	// The proposer doesn't change the database, the evidence is deleted after the block is committed.
+	s.Len(readEvidence(), 1)
+	s.Require().NoError(deleteReportedEvidence(s.T().Context(), s.db, params.NShards, res.Block))
+	s.Empty(readEvidence())
+}
+
 func (s *ProposerTestSuite) TestCollator() {
 	to := contracts.CounterAddress(s.T(), s.shardId)
 
@@ -2,6 +2,7 @@ package collate
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -29,6 +30,9 @@ type Params struct {
 
 	MaxGasInBlock                 types.Gas
 	MaxForwardTransactionsInBlock int
+	// InternalGasReservePercent is the share of MaxGasInBlock (in percent) that external transactions can't use,
+	// so that incoming internal transactions are never starved by them.
+	InternalGasReservePercent uint32
 
 	CollatorTickPeriod time.Duration
 	Timeout            time.Duration
@@ -36,6 +40,9 @@ type Params struct {
 	Topology ShardTopology
 
 	L1Fetcher rollup.L1BlockFetcher
+
+	// DevState is set if the development API is enabled.
+	DevState *DevState
 }
 
 type Scheduler struct {
@@ -78,19 +85,32 @@ func (s *Scheduler) Validator() *Validator {
 func (s *Scheduler) Run(ctx context.Context) error {
 	s.logger.Info().Msg("Starting collation...")
 
+	var devRequests <-chan *devRequest
+	manualMining := false
+	if dev := s.params.DevState; dev != nil {
+		devRequests = dev.requests
+		manualMining = dev.manualMining
+	}
+
 	tickPeriodMs := s.params.CollatorTickPeriod.Milliseconds()
 	for {
-		var toRoundStartMs int64
-		elapsed := time.Now().UnixMilli() % tickPeriodMs
-		if elapsed > 0 {
-			toRoundStartMs = tickPeriodMs - elapsed
+		var tick <-chan time.Time
+		if !manualMining {
+			var toRoundStartMs int64
+			elapsed := time.Now().UnixMilli() % tickPeriodMs
+			if elapsed > 0 {
+				toRoundStartMs = tickPeriodMs - elapsed
+			}
+			tick = time.After(time.Duration(toRoundStartMs) * time.Millisecond)
 		}
 
 		select {
 		case <-ctx.Done():
 			s.logger.Info().Msg("Stopping collation...")
 			return nil
-		case <-time.After(time.Duration(toRoundStartMs) * time.Millisecond):
+		case req := <-devRequests:
+			req.result <- req.run(ctx, s)
+		case <-tick:
 			if err := s.doCollate(ctx); err != nil {
 				if ctx.Err() != nil {
 					continue
@@ -101,6 +121,21 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	}
 }
 
+// mine produces count blocks on request of the development API.
+func (s *Scheduler) mine(ctx context.Context, count uint64) (types.BlockNumber, error) {
+	for range count {
+		if err := s.doCollate(ctx); err != nil {
+			return 0, fmt.Errorf("failed to collate: %w", err)
+		}
+	}
+
+	block, _, err := s.validator.GetLastBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return block.Id, nil
+}
+
 func (s *Scheduler) doCollate(ctx context.Context) error {
 	if s.params.DisableConsensus {
 		proposal, err := s.validator.BuildProposal(ctx)
@@ -0,0 +1,900 @@
+package collate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Snapshot sync bootstraps a node with the state of all shards at a recent block instead of the whole history.
+//
+// The latest main shard block is requested from a peer and its signature is verified against the validators of
+// its epoch. The validator set is taken from the config of the main shard block two blocks behind, and it changes
+// only at the first block of an epoch. So the sets are followed from the genesis (which is generated locally and
+// must be the same as the network's one): the first block of every epoch is requested by number, verified against
+// the set of the previous epoch, and the config of that block provides the set of the next epoch. The blocks of the
+// other shards are taken from the child blocks of the latest block, so they are verified by their hashes, as well as
+// the main shard blocks they refer to.
+//
+// The tries are transferred in chunks: a chunk holds up to snapshotChunkSize nodes of the subtrie of the requested
+// node in depth-first order, so that every node is verified by the hash stored in its parent before it is written.
+// Since the nodes are addressed by their hashes, an interrupted sync resumes by walking the nodes that are already
+// in the database, and a request that failed or returned an invalid chunk is retried with the next peer.
+
+const (
+	protocolSnapshotBlock network.ProtocolID = "/nil/snap/block"
+	protocolSnapshotNodes network.ProtocolID = "/nil/snap/nodes"
+
+	// snapshotChunkSize is the maximum number of nodes in a single response.
+	snapshotChunkSize = 1024
+
+	// maxSnapshotRetargets is the number of times the sync switches to a newer block
+	// if no peer can serve the state of the current one (e.g., because it was pruned).
+	maxSnapshotRetargets = 3
+
+	// every n-th chunk will be reported to info log (to avoid spamming)
+	chunkReportInterval = 100
+)
+
+var (
+	errSnapshotUnavailable  = errors.New("snapshot is not available")
+	errInvalidSnapshotChunk = errors.New("invalid snapshot chunk")
+
+	errChunkFull = errors.New("chunk is full")
+)
+
+func snapshotTrieTable(table pb.SnapshotTable, blockId types.BlockNumber) (db.ShardedTableName, error) {
+	switch table {
+	case pb.SnapshotTable_ContractTrie:
+		return db.ContractTrieTable, nil
+	case pb.SnapshotTable_StorageTrie:
+		return db.StorageTrieTable, nil
+	case pb.SnapshotTable_TokenTrie:
+		return db.TokenTrieTable, nil
+	case pb.SnapshotTable_AsyncCallContextTrie:
+		return db.AsyncCallContextTable, nil
+	case pb.SnapshotTable_ConfigTrie:
+		return db.ConfigTrieTable, nil
+	case pb.SnapshotTable_TransactionTrie:
+		return db.TransactionTrieTable, nil
+	case pb.SnapshotTable_ReceiptTrie:
+		return db.ReceiptTrieTable, nil
+	case pb.SnapshotTable_ShardBlocksTrie:
+		return db.ShardBlocksTrieTableName(blockId), nil
+	default:
+		return "", fmt.Errorf("unsupported snapshot table %s", table)
+	}
+}
+
+// childRefs returns the references to the children of the node, including the inlined ones.
+func childRefs(node mpt.Node) []mpt.Reference {
+	switch node := node.(type) {
+	case *mpt.BranchNode:
+		refs := make([]mpt.Reference, 0, len(node.Branches))
+		for _, br := range node.Branches {
+			if len(br) > 0 {
+				refs = append(refs, br)
+			}
+		}
+		return refs
+	case *mpt.ExtensionNode:
+		return []mpt.Reference{node.NextRef}
+	}
+	return nil
+}
+
+// SetSnapshotHandlers sets the handlers serving the state snapshots to the syncing peers.
+func SetSnapshotHandlers(ctx context.Context, nm network.Manager, database db.DB) {
+	logger := logging.NewLogger("snapshot").With().
+		Stringer(logging.FieldP2PIdentity, nm.ID()).
+		Logger()
+
+	s := &snapshotServer{db: database}
+	nm.SetRequestHandler(ctx, protocolSnapshotBlock, s.handleBlockRequest)
+	nm.SetRequestHandler(ctx, protocolSnapshotNodes, s.handleNodesRequest)
+
+	logger.Info().Msg("Enabled snapshot endpoints")
+}
+
+type snapshotServer struct {
+	db db.DB
+}
+
+func (s *snapshotServer) handleBlockRequest(ctx context.Context, data []byte) ([]byte, error) {
+	var req pb.SnapshotBlockRequest
+	if err := proto.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+
+	var resp pb.SnapshotBlockResponse
+	resp.PackProtoMessage(s.readBlock(ctx, &req))
+	return proto.Marshal(&resp)
+}
+
+func (s *snapshotServer) readBlock(ctx context.Context, req *pb.SnapshotBlockRequest) (sszx.SSZEncodedData, error) {
+	shardId, hash, blockId, err := req.UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var block *types.Block
+	switch {
+	case !hash.Empty():
+		block, err = db.ReadBlock(tx, shardId, hash)
+	case blockId != 0:
+		block, err = db.ReadBlockByNumber(tx, shardId, blockId)
+	default:
+		block, _, err = db.ReadLastBlock(tx, shardId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return block.MarshalSSZ()
+}
+
+func (s *snapshotServer) handleNodesRequest(ctx context.Context, data []byte) ([]byte, error) {
+	var req pb.SnapshotNodesRequest
+	if err := proto.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+
+	var resp pb.SnapshotNodesResponse
+	resp.PackProtoMessage(s.readNodes(ctx, &req))
+	return proto.Marshal(&resp)
+}
+
+// readNodes returns the code with the requested hash or the nodes of the subtrie of the requested node
+// in depth-first order.
+func (s *snapshotServer) readNodes(ctx context.Context, req *pb.SnapshotNodesRequest) ([][]byte, error) {
+	shardId := types.ShardId(req.GetShardId())
+	key := req.GetKey()
+	if len(key) != common.HashSize {
+		return nil, fmt.Errorf("invalid node key %x", key)
+	}
+
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if req.GetTable() == pb.SnapshotTable_Code {
+		code, err := db.ReadCode(tx, shardId, common.BytesToHash(key))
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{code}, nil
+	}
+
+	table, err := snapshotTrieTable(req.GetTable(), types.BlockNumber(req.GetBlockId()))
+	if err != nil {
+		return nil, err
+	}
+
+	maxNodes := int(min(max(req.GetMaxNodes(), 1), snapshotChunkSize))
+	nodes := make([][]byte, 0, maxNodes)
+
+	// The whole trie is walked, the node cache is bypassed to keep the recently used nodes.
+	reader := mpt.NewReader(mpt.NewDbGetter(tx, shardId, table))
+	reader.SetRootHash(common.BytesToHash(key))
+	err = reader.WalkNodes(func(ref mpt.Reference, _ mpt.Node) (bool, error) {
+		if len(ref) < common.HashSize {
+			// The node is inlined into its parent.
+			return true, nil
+		}
+		if len(nodes) == maxNodes {
+			return false, errChunkFull
+		}
+		data, err := tx.GetFromShard(shardId, table, ref)
+		if err != nil {
+			return false, err
+		}
+		nodes = append(nodes, data)
+		return true, nil
+	})
+	if err != nil && !errors.Is(err, errChunkFull) {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+type snapshotFetcher struct {
+	nm    network.Manager
+	db    db.DB
+	peers []network.PeerID
+	// peer is the index of the peer the requests are sent to. It is switched after a failure.
+	peer int
+	// verify is false if the blocks are not signed.
+	verify bool
+	// trusted is the latest main shard block with verified validators in its config: the genesis or the first block
+	// of an epoch verified against the validators of the previous epoch.
+	trusted     *types.Block
+	trustedHash common.Hash
+	// chunkSize is the maximum number of nodes requested at once.
+	chunkSize uint32
+
+	logger logging.Logger
+
+	chunks int
+	nodes  int
+}
+
+func newSnapshotFetcher(
+	nm network.Manager, database db.DB, peers []network.PeerID, verify bool, logger logging.Logger,
+) *snapshotFetcher {
+	return &snapshotFetcher{
+		nm:        nm,
+		db:        database,
+		peers:     peers,
+		verify:    verify,
+		chunkSize: snapshotChunkSize,
+		logger:    logger,
+	}
+}
+
+// fetch syncs the snapshot, resuming the sync in progress if any.
+func (f *snapshotFetcher) fetch(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		err := f.sync(ctx)
+		if !errors.Is(err, errSnapshotUnavailable) || attempt == maxSnapshotRetargets {
+			return err
+		}
+
+		f.logger.Warn().Err(err).Msg("Switching snapshot sync to the latest block")
+		if err := f.update(ctx, db.DeleteSnapshotTarget); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *snapshotFetcher) sync(ctx context.Context) error {
+	main, mainHash, err := f.target(ctx)
+	if err != nil {
+		return err
+	}
+
+	f.logger.Info().
+		Uint64(logging.FieldBlockNumber, uint64(main.Id)).
+		Stringer(logging.FieldBlockHash, mainHash).
+		Msg("Syncing snapshot")
+
+	heads := map[types.ShardId]*types.Block{types.MainShardId: main}
+	hashes := map[types.ShardId]common.Hash{types.MainShardId: mainHash}
+	if err := f.syncBlockState(ctx, types.MainShardId, main); err != nil {
+		return err
+	}
+
+	// Main shard blocks whose config is synced. The config of the block is needed to verify the next one.
+	configs := map[common.Hash]struct{}{mainHash: {}}
+	if err := f.syncMainShardConfig(ctx, main.GetMainShardHash(types.MainShardId), configs); err != nil {
+		return err
+	}
+
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	childBlocks := execution.NewDbShardBlocksTrieReader(tx, types.MainShardId, main.Id)
+	childBlocks.SetRootHash(main.ChildBlocksRootHash)
+	children, err := childBlocks.Entries()
+	tx.Rollback()
+	if err != nil {
+		return fmt.Errorf("failed to read child blocks: %w", err)
+	}
+
+	for _, child := range children {
+		shardId, hash := child.Key, *child.Val
+		block, err := f.fetchBlock(ctx, shardId, hash)
+		if err != nil {
+			return err
+		}
+		if err := f.syncBlockState(ctx, shardId, block); err != nil {
+			return err
+		}
+		if err := f.syncMainShardConfig(ctx, block.GetMainShardHash(shardId), configs); err != nil {
+			return err
+		}
+		heads[shardId] = block
+		hashes[shardId] = hash
+	}
+
+	if err := f.update(ctx, func(tx db.RwTx) error {
+		for shardId, block := range heads {
+			hash := hashes[shardId]
+			if err := db.WriteLastBlockHash(tx, shardId, hash); err != nil {
+				return err
+			}
+			if err := tx.PutToShard(shardId, db.BlockHashByNumberIndex, block.Id.Bytes(), hash.Bytes()); err != nil {
+				return err
+			}
+			if err := db.WritePrunedState(tx, shardId, db.PrunedState{FirstKeptBlock: block.Id}); err != nil {
+				return err
+			}
+		}
+		return db.DeleteSnapshotTarget(tx)
+	}); err != nil {
+		return fmt.Errorf("failed to finish snapshot sync: %w", err)
+	}
+
+	f.logger.Info().
+		Int("chunks", f.chunks).
+		Int("nodes", f.nodes).
+		Msg("Snapshot sync completed")
+	return nil
+}
+
+// target returns the main shard block the sync in progress is targeted at or requests the latest one.
+func (f *snapshotFetcher) target(ctx context.Context) (*types.Block, common.Hash, error) {
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	defer tx.Rollback()
+
+	hash, err := db.ReadSnapshotTarget(tx)
+	if err == nil {
+		f.logger.Info().Msg("Resuming snapshot sync")
+		block, err := db.ReadBlock(tx, types.MainShardId, hash)
+		return block, hash, err
+	}
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, common.EmptyHash, err
+	}
+
+	for range f.peers {
+		peer := f.peers[f.peer]
+		block, err := f.requestBlock(ctx, peer, types.MainShardId, common.EmptyHash, 0)
+		if err == nil && f.verify {
+			var validators *config.PublicKeyMap
+			if validators, err = f.validatorsOf(ctx, block.Id); err != nil {
+				return nil, common.EmptyHash, err
+			}
+			if err = block.VerifySignature(validators.Keys(), types.MainShardId); err != nil {
+				err = newErrInvalidSignature(err)
+			}
+		}
+		if err != nil {
+			f.peerFailed(peer, err, "Failed to fetch the latest main shard block")
+			continue
+		}
+
+		hash := block.Hash(types.MainShardId)
+		if err := f.update(ctx, func(tx db.RwTx) error {
+			if err := db.WriteBlock(tx, types.MainShardId, hash, block); err != nil {
+				return err
+			}
+			return db.WriteSnapshotTarget(tx, hash)
+		}); err != nil {
+			return nil, common.EmptyHash, err
+		}
+		return block, hash, nil
+	}
+	return nil, common.EmptyHash, fmt.Errorf(
+		"%w: failed to fetch the latest main shard block from all peers", errSnapshotUnavailable)
+}
+
+// fetchBlock returns the block with the given hash, requesting it from the peers if it is not in the database.
+func (f *snapshotFetcher) fetchBlock(
+	ctx context.Context, shardId types.ShardId, hash common.Hash,
+) (*types.Block, error) {
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block, err := db.ReadBlock(tx, shardId, hash)
+	tx.Rollback()
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return block, err
+	}
+
+	for range f.peers {
+		peer := f.peers[f.peer]
+		block, err := f.requestBlock(ctx, peer, shardId, hash, 0)
+		if err == nil && block.Hash(shardId) != hash {
+			err = fmt.Errorf("%w: block hash mismatch", errInvalidSnapshotChunk)
+		}
+		if err != nil {
+			f.peerFailed(peer, err, "Failed to fetch block")
+			continue
+		}
+
+		if err := f.update(ctx, func(tx db.RwTx) error {
+			return db.WriteBlock(tx, shardId, hash, block)
+		}); err != nil {
+			return nil, err
+		}
+		return block, nil
+	}
+	return nil, fmt.Errorf(
+		"%w: failed to fetch block %s of shard %d from all peers", errSnapshotUnavailable, hash, shardId)
+}
+
+// validatorsOf returns the validators of the main shard block with the given number.
+// The first blocks of the epochs up to the one of the block are fetched and verified on the way.
+func (f *snapshotFetcher) validatorsOf(ctx context.Context, blockId types.BlockNumber) (*config.PublicKeyMap, error) {
+	if f.trusted == nil {
+		if err := f.readGenesis(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		length, validators, err := f.trustedValidators(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// The validators of a block are taken from the config of the block two blocks behind,
+		// so the set of the trusted block's epoch applies up to the second block of the next epoch.
+		next := (f.trusted.Id/length + 1) * length
+		if blockId < next+2 {
+			return validators, nil
+		}
+		if err := f.fetchEpochStart(ctx, next, validators); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *snapshotFetcher) readGenesis(ctx context.Context) error {
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	hash, err := db.ReadBlockHashByNumber(tx, types.MainShardId, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read the genesis: %w", err)
+	}
+	block, err := db.ReadBlock(tx, types.MainShardId, hash)
+	if err != nil {
+		return fmt.Errorf("failed to read the genesis: %w", err)
+	}
+	f.trusted, f.trustedHash = block, hash
+	return nil
+}
+
+// trustedValidators returns the epoch length and the validators from the config of the trusted block.
+func (f *snapshotFetcher) trustedValidators(
+	ctx context.Context,
+) (types.BlockNumber, *config.PublicKeyMap, error) {
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigReader(tx, &f.trustedHash)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read config of block %d: %w", f.trusted.Id, err)
+	}
+	length, err := config.GetValidatorsEpochLength(configAccessor)
+	if err != nil {
+		return 0, nil, err
+	}
+	validators, err := config.GetShardValidators(configAccessor, types.MainShardId)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read validators of block %d: %w", f.trusted.Id, err)
+	}
+	keys, err := config.CreateValidatorsPublicKeyMap(validators)
+	if err != nil {
+		return 0, nil, err
+	}
+	return length, keys, nil
+}
+
+// fetchEpochStart fetches the first block of an epoch, verifies it against the validators of the previous epoch,
+// syncs its config and makes it trusted.
+func (f *snapshotFetcher) fetchEpochStart(
+	ctx context.Context, blockId types.BlockNumber, validators *config.PublicKeyMap,
+) error {
+	for range f.peers {
+		peer := f.peers[f.peer]
+		block, err := f.requestBlock(ctx, peer, types.MainShardId, common.EmptyHash, blockId)
+		if err == nil && block.Id != blockId {
+			err = fmt.Errorf("%w: unexpected block %d instead of %d", errInvalidSnapshotChunk, block.Id, blockId)
+		}
+		if err == nil {
+			if err = block.VerifySignature(validators.Keys(), types.MainShardId); err != nil {
+				err = newErrInvalidSignature(err)
+			}
+		}
+		if err != nil {
+			f.peerFailed(peer, err, "Failed to fetch the first block of the validators epoch")
+			continue
+		}
+
+		hash := block.Hash(types.MainShardId)
+		if err := f.update(ctx, func(tx db.RwTx) error {
+			return db.WriteBlock(tx, types.MainShardId, hash, block)
+		}); err != nil {
+			return err
+		}
+		if err := f.syncTrie(ctx, types.MainShardId, pb.SnapshotTable_ConfigTrie, 0, block.ConfigRoot, nil); err != nil {
+			return err
+		}
+
+		f.logger.Debug().
+			Uint64(logging.FieldBlockNumber, uint64(block.Id)).
+			Msg("Verified the first block of the validators epoch")
+		f.trusted, f.trustedHash = block, hash
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: failed to fetch main shard block %d from all peers", errSnapshotUnavailable, blockId)
+}
+
+func (f *snapshotFetcher) requestBlock(
+	ctx context.Context, peer network.PeerID, shardId types.ShardId, hash common.Hash, blockId types.BlockNumber,
+) (*types.Block, error) {
+	var req pb.SnapshotBlockRequest
+	if err := req.PackProtoMessage(shardId, hash, blockId); err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = f.nm.SendRequestAndGetResponse(ctx, peer, protocolSnapshotBlock, data)
+	if err != nil {
+		return nil, err
+	}
+	var resp pb.SnapshotBlockResponse
+	if err := proto.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidSnapshotChunk, err)
+	}
+	raw, err := resp.UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	block := &types.Block{}
+	if err := block.UnmarshalSSZ(raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidSnapshotChunk, err)
+	}
+	return block, nil
+}
+
+// syncMainShardConfig syncs the block of the main shard and its config.
+func (f *snapshotFetcher) syncMainShardConfig(
+	ctx context.Context, hash common.Hash, synced map[common.Hash]struct{},
+) error {
+	if _, ok := synced[hash]; ok || hash.Empty() {
+		return nil
+	}
+
+	block, err := f.fetchBlock(ctx, types.MainShardId, hash)
+	if err != nil {
+		return err
+	}
+	if err := f.syncTrie(ctx, types.MainShardId, pb.SnapshotTable_ConfigTrie, 0, block.ConfigRoot, nil); err != nil {
+		return err
+	}
+	synced[hash] = struct{}{}
+	return nil
+}
+
+func (f *snapshotFetcher) syncBlockState(ctx context.Context, shardId types.ShardId, block *types.Block) error {
+	syncContract := func(data []byte) error {
+		var contract types.SmartContract
+		if err := contract.UnmarshalSSZ(data); err != nil {
+			return err
+		}
+		if err := f.syncTrie(ctx, shardId, pb.SnapshotTable_StorageTrie, 0, contract.StorageRoot, nil); err != nil {
+			return err
+		}
+		if err := f.syncTrie(ctx, shardId, pb.SnapshotTable_TokenTrie, 0, contract.TokenRoot, nil); err != nil {
+			return err
+		}
+		if err := f.syncTrie(
+			ctx, shardId, pb.SnapshotTable_AsyncCallContextTrie, 0, contract.AsyncContextRoot, nil,
+		); err != nil {
+			return err
+		}
+		return f.syncCode(ctx, shardId, contract.CodeHash)
+	}
+
+	type trie struct {
+		table pb.SnapshotTable
+		root  common.Hash
+	}
+	tries := []trie{
+		{pb.SnapshotTable_TransactionTrie, block.InTransactionsRoot},
+		{pb.SnapshotTable_TransactionTrie, block.OutTransactionsRoot},
+		{pb.SnapshotTable_ReceiptTrie, block.ReceiptsRoot},
+	}
+	if shardId.IsMainShard() {
+		tries = append(tries,
+			trie{pb.SnapshotTable_ConfigTrie, block.ConfigRoot},
+			trie{pb.SnapshotTable_ShardBlocksTrie, block.ChildBlocksRootHash})
+	}
+
+	if err := f.syncTrie(
+		ctx, shardId, pb.SnapshotTable_ContractTrie, 0, block.SmartContractsRoot, syncContract,
+	); err != nil {
+		return fmt.Errorf("failed to sync contracts of shard %d: %w", shardId, err)
+	}
+	for _, t := range tries {
+		if err := f.syncTrie(ctx, shardId, t.table, block.Id, t.root, nil); err != nil {
+			return fmt.Errorf("failed to sync %s of shard %d: %w", t.table, shardId, err)
+		}
+	}
+
+	f.logger.Info().
+		Stringer(logging.FieldShardId, shardId).
+		Uint64(logging.FieldBlockNumber, uint64(block.Id)).
+		Msg("Synced shard state")
+	return nil
+}
+
+func (f *snapshotFetcher) syncCode(ctx context.Context, shardId types.ShardId, hash common.Hash) error {
+	if hash.Empty() {
+		return nil
+	}
+
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = db.ReadCode(tx, shardId, hash)
+	tx.Rollback()
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	return f.fetchNodes(ctx, shardId, pb.SnapshotTable_Code, 0, hash.Bytes())
+}
+
+// syncTrie walks the trie depth-first, fetching the nodes missing in the database,
+// and calls onValue for every value stored in the trie.
+func (f *snapshotFetcher) syncTrie(
+	ctx context.Context,
+	shardId types.ShardId,
+	table pb.SnapshotTable,
+	blockId types.BlockNumber,
+	root common.Hash,
+	onValue func(data []byte) error,
+) error {
+	if root.Empty() {
+		return nil
+	}
+	tableName, err := snapshotTrieTable(table, blockId)
+	if err != nil {
+		return err
+	}
+
+	tx, err := f.db.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { tx.Rollback() }()
+
+	readNode := func(ref mpt.Reference) (mpt.Node, error) {
+		if len(ref) < common.HashSize {
+			return mpt.DecodeNode(ref)
+		}
+		data, err := tx.GetFromShard(shardId, tableName, ref)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			if err := f.fetchNodes(ctx, shardId, table, blockId, ref); err != nil {
+				return nil, err
+			}
+			// Renew the transaction to see the fetched nodes.
+			tx.Rollback()
+			if tx, err = f.db.CreateRoTx(ctx); err != nil {
+				return nil, err
+			}
+			data, err = tx.GetFromShard(shardId, tableName, ref)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return mpt.DecodeNode(data)
+	}
+
+	stack := []mpt.Reference{root.Bytes()}
+	for len(stack) > 0 {
+		ref := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node, err := readNode(ref)
+		if err != nil {
+			return err
+		}
+		if data := node.Data(); onValue != nil && len(data) > 0 {
+			if err := onValue(data); err != nil {
+				return err
+			}
+		}
+		stack = append(stack, childRefs(node)...)
+	}
+	return nil
+}
+
+// fetchNodes requests the chunk starting from the given node from the peers and writes it into the database.
+func (f *snapshotFetcher) fetchNodes(
+	ctx context.Context, shardId types.ShardId, table pb.SnapshotTable, blockId types.BlockNumber, ref []byte,
+) error {
+	req, err := proto.Marshal(&pb.SnapshotNodesRequest{
+		ShardId:  uint32(shardId),
+		Table:    table,
+		BlockId:  uint64(blockId),
+		Key:      ref,
+		MaxNodes: f.chunkSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for range f.peers {
+		peer := f.peers[f.peer]
+		nodes, err := f.requestNodes(ctx, peer, req)
+		var keys [][]byte
+		if err == nil {
+			keys, err = verifyChunk(table, ref, nodes)
+		}
+		if err != nil {
+			f.peerFailed(peer, err, "Failed to fetch snapshot chunk")
+			continue
+		}
+
+		if err := f.update(ctx, func(tx db.RwTx) error {
+			return writeChunk(tx, shardId, table, blockId, keys, nodes)
+		}); err != nil {
+			return err
+		}
+
+		f.chunks++
+		f.nodes += len(nodes)
+		if f.chunks%chunkReportInterval == 0 {
+			f.logger.Info().
+				Int("chunks", f.chunks).
+				Int("nodes", f.nodes).
+				Msg("Fetching snapshot")
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: failed to fetch node %x of %s from all peers", errSnapshotUnavailable, ref, table)
+}
+
+func (f *snapshotFetcher) requestNodes(ctx context.Context, peer network.PeerID, req []byte) ([][]byte, error) {
+	data, err := f.nm.SendRequestAndGetResponse(ctx, peer, protocolSnapshotNodes, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp pb.SnapshotNodesResponse
+	if err := proto.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidSnapshotChunk, err)
+	}
+	return resp.UnpackProtoMessage()
+}
+
+// verifyChunk checks that the chunk consists of the requested node and its descendants.
+// Returns the database keys of the nodes.
+func verifyChunk(table pb.SnapshotTable, ref []byte, nodes [][]byte) ([][]byte, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: no nodes", errInvalidSnapshotChunk)
+	}
+
+	if table == pb.SnapshotTable_Code {
+		if len(nodes) != 1 || types.Code(nodes[0]).Hash() != common.BytesToHash(ref) {
+			return nil, fmt.Errorf("%w: code hash mismatch", errInvalidSnapshotChunk)
+		}
+		return [][]byte{ref}, nil
+	}
+
+	// Identical subtries might occur several times in a trie, so the expected references are counted.
+	expected := make(map[string]int)
+	var expectChildren func(node mpt.Node) error
+	expectChildren = func(node mpt.Node) error {
+		for _, child := range childRefs(node) {
+			if len(child) >= common.HashSize {
+				expected[string(child)]++
+				continue
+			}
+			inlined, err := mpt.DecodeNode(child)
+			if err != nil {
+				return err
+			}
+			if err := expectChildren(inlined); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	keys := make([][]byte, len(nodes))
+	for i, data := range nodes {
+		key := common.KeccakHash(data).Bytes()
+		if i == 0 {
+			// A short root node is stored under its reference widened to 32 bytes.
+			isShortRoot := len(data) < common.HashSize && common.BytesToHash(data) == common.BytesToHash(ref)
+			if !bytes.Equal(key, ref) && !isShortRoot {
+				return nil, fmt.Errorf("%w: unexpected first node %x", errInvalidSnapshotChunk, key)
+			}
+			key = ref
+		} else if expected[string(key)] == 0 {
+			return nil, fmt.Errorf("%w: unexpected node %x", errInvalidSnapshotChunk, key)
+		}
+		expected[string(key)]--
+
+		node, err := mpt.DecodeNode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidSnapshotChunk, err)
+		}
+		if err := expectChildren(node); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidSnapshotChunk, err)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+func writeChunk(
+	tx db.RwTx, shardId types.ShardId, table pb.SnapshotTable, blockId types.BlockNumber, keys, nodes [][]byte,
+) error {
+	if table == pb.SnapshotTable_Code {
+		return db.WriteCode(tx, shardId, common.BytesToHash(keys[0]), nodes[0])
+	}
+
+	tableName, err := snapshotTrieTable(table, blockId)
+	if err != nil {
+		return err
+	}
+	for i := range nodes {
+		if err := tx.PutToShard(shardId, tableName, keys[i], nodes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// peerFailed switches the requests to the next peer. Peers that sent invalid data are reported.
+func (f *snapshotFetcher) peerFailed(peer network.PeerID, err error, msg string) {
+	f.logger.Warn().Err(err).Stringer(logging.FieldPeerId, peer).Msg(msg)
+	f.peer = (f.peer + 1) % len(f.peers)
+
+	tracker := network.TryGetPeerReputationTracker(f.nm)
+	if tracker == nil {
+		return
+	}
+	switch {
+	case errors.As(err, new(invalidSignatureError)):
+		tracker.ReportPeer(peer, cm.ReputationChangeInvalidBlockSignature)
+	case errors.Is(err, errInvalidSnapshotChunk):
+		tracker.ReportPeer(peer, cm.ReputationChangeInvalidSnapshotChunk)
+	}
+}
+
+func (f *snapshotFetcher) update(ctx context.Context, fn func(tx db.RwTx) error) error {
+	tx, err := f.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,348 @@
+package collate
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type snapshotTestState struct {
+	block *types.Block
+	addrs []types.Address
+	code  types.Code
+}
+
+func newSnapshotTestState(t *testing.T, database db.DB, shardId types.ShardId) *snapshotTestState {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	res := &snapshotTestState{code: types.Code("snapshot test code")}
+	for i := range 20 {
+		addr := types.GenerateRandomAddress(shardId)
+		require.NoError(t, es.CreateAccount(addr))
+		require.NoError(t, es.SetCode(addr, res.code))
+		for j := range 10 {
+			require.NoError(t, es.SetState(addr, common.IntToHash(j), common.IntToHash(i*j+1)))
+		}
+		res.addrs = append(res.addrs, addr)
+	}
+
+	out, err := es.Commit(0, nil)
+	require.NoError(t, err)
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, out, execution.ModeVerify))
+	require.NoError(t, tx.Commit())
+
+	res.block = out.Block
+	return res
+}
+
+func (s *snapshotTestState) check(t *testing.T, database db.DB, shardId types.ShardId) {
+	t.Helper()
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          s.block,
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	for i, addr := range s.addrs {
+		code, _, err := es.GetCode(addr)
+		require.NoError(t, err)
+		require.Equal(t, []byte(s.code), code)
+
+		for j := range 10 {
+			value, err := es.GetState(addr, common.IntToHash(j))
+			require.NoError(t, err)
+			require.Equal(t, common.IntToHash(i*j+1), value)
+		}
+	}
+}
+
+func TestSnapshotSync(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	const shardId = types.BaseShardId
+
+	serverDb, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer serverDb.Close()
+
+	state := newSnapshotTestState(t, serverDb, shardId)
+
+	nms := network.NewTestManagers(ctx, t, 9300, 3)
+	server, badServer, client := nms[0], nms[1], nms[2]
+	defer server.Close()
+	defer badServer.Close()
+	defer client.Close()
+
+	SetSnapshotHandlers(ctx, server, serverDb)
+	SetSnapshotHandlers(ctx, badServer, serverDb)
+
+	// The bad server returns the requested nodes along with a node that is not their descendant.
+	var badRequests atomic.Int32
+	badServer.SetRequestHandler(ctx, protocolSnapshotNodes, func(ctx context.Context, data []byte) ([]byte, error) {
+		badRequests.Add(1)
+
+		var req pb.SnapshotNodesRequest
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+		nodes, err := (&snapshotServer{db: serverDb}).readNodes(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		var resp pb.SnapshotNodesResponse
+		resp.PackProtoMessage(append(nodes, []byte("garbage")), nil)
+		return proto.Marshal(&resp)
+	})
+
+	_, serverId := network.ConnectManagers(t, client, server)
+	_, badServerId := network.ConnectManagers(t, client, badServer)
+
+	clientDb, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer clientDb.Close()
+
+	f := newSnapshotFetcher(
+		client, clientDb, []network.PeerID{badServerId, serverId}, false, logging.NewLogger("snapshot-test"))
+	f.chunkSize = 8
+
+	block, err := f.fetchBlock(ctx, shardId, state.block.Hash(shardId))
+	require.NoError(t, err)
+	require.NoError(t, f.syncBlockState(ctx, shardId, block))
+	state.check(t, clientDb, shardId)
+
+	require.Equal(t, int32(1), badRequests.Load())
+	require.Greater(t, f.chunks, 1)
+
+	t.Run("Resume", func(t *testing.T) {
+		chunks := f.chunks
+		require.NoError(t, f.syncBlockState(ctx, shardId, state.block))
+		require.Equal(t, chunks, f.chunks)
+	})
+}
+
+func TestVerifyChunk(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	state := newSnapshotTestState(t, database, shardId)
+
+	server := &snapshotServer{db: database}
+	root := state.block.SmartContractsRoot.Bytes()
+	nodes, err := server.readNodes(t.Context(), &pb.SnapshotNodesRequest{
+		ShardId:  uint32(shardId),
+		Table:    pb.SnapshotTable_ContractTrie,
+		Key:      root,
+		MaxNodes: 16,
+	})
+	require.NoError(t, err)
+	require.Len(t, nodes, 16)
+
+	keys, err := verifyChunk(pb.SnapshotTable_ContractTrie, root, nodes)
+	require.NoError(t, err)
+	require.Equal(t, root, keys[0])
+
+	t.Run("WrongRoot", func(t *testing.T) {
+		_, err := verifyChunk(pb.SnapshotTable_ContractTrie, root, nodes[1:])
+		require.ErrorIs(t, err, errInvalidSnapshotChunk)
+	})
+
+	t.Run("ModifiedNode", func(t *testing.T) {
+		modified := append([][]byte{}, nodes...)
+		modified[5] = append([]byte{}, nodes[5]...)
+		modified[5][len(modified[5])-1]++
+		_, err := verifyChunk(pb.SnapshotTable_ContractTrie, root, modified)
+		require.ErrorIs(t, err, errInvalidSnapshotChunk)
+	})
+
+	t.Run("DuplicateNode", func(t *testing.T) {
+		_, err := verifyChunk(pb.SnapshotTable_ContractTrie, root, append(nodes, nodes[1]))
+		require.ErrorIs(t, err, errInvalidSnapshotChunk)
+	})
+
+	t.Run("Code", func(t *testing.T) {
+		hash := state.code.Hash()
+		_, err := verifyChunk(pb.SnapshotTable_Code, hash.Bytes(), [][]byte{state.code})
+		require.NoError(t, err)
+
+		_, err = verifyChunk(pb.SnapshotTable_Code, hash.Bytes(), [][]byte{[]byte("other code")})
+		require.ErrorIs(t, err, errInvalidSnapshotChunk)
+	})
+}
+
+// snapshotTestChain is a main shard chain with no state whose validator changes at every epoch.
+type snapshotTestChain struct {
+	keys   []bls.PrivateKey
+	blocks []*types.Block
+}
+
+const snapshotTestEpochLength = 4
+
+func newSnapshotTestChain(epochs int) *snapshotTestChain {
+	c := &snapshotTestChain{}
+	for range epochs {
+		c.keys = append(c.keys, bls.NewRandomKey())
+	}
+	return c
+}
+
+// write writes the blocks up to the given one to the database. The signer of each block is chosen by signer.
+func (c *snapshotTestChain) write(
+	t *testing.T, database db.DB, last types.BlockNumber, signer func(types.BlockNumber) bls.PrivateKey,
+) {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	c.blocks = nil
+	var prev common.Hash
+	for id := range last + 1 {
+		pubkey, err := c.keys[id/snapshotTestEpochLength].PublicKey().Marshal()
+		require.NoError(t, err)
+
+		configAccessor := config.NewConfigAccessorFromMap(make(map[string][]byte))
+		require.NoError(t, config.SetParamValidators(configAccessor, &config.ParamValidators{
+			Validators: []config.ListValidators{{List: []config.ValidatorInfo{{PublicKey: config.Pubkey(pubkey)}}}},
+		}))
+		require.NoError(t, config.SetParamValidatorsEpoch(configAccessor, &config.ParamValidatorsEpoch{
+			Length: snapshotTestEpochLength,
+		}))
+		configRoot, err := configAccessor.Commit(tx, common.EmptyHash)
+		require.NoError(t, err)
+
+		block := &types.Block{BlockData: types.BlockData{Id: id, PrevBlock: prev, ConfigRoot: configRoot}}
+		hash := block.Hash(types.MainShardId)
+		if id > 0 {
+			key := signer(id)
+			mask, err := bls.NewMask([]bls.PublicKey{key.PublicKey()})
+			require.NoError(t, err)
+			require.NoError(t, mask.SetParticipants([]uint32{0}))
+			sig, err := key.Sign(hash[:])
+			require.NoError(t, err)
+			sig, err = bls.AggregateSignatures([]bls.Signature{sig}, mask)
+			require.NoError(t, err)
+			sigBytes, err := sig.Marshal()
+			require.NoError(t, err)
+			block.Signature = &types.BlsAggregateSignature{Sig: sigBytes, Mask: []byte{1}}
+		}
+
+		require.NoError(t, db.WriteBlock(tx, types.MainShardId, hash, block))
+		require.NoError(t, tx.PutToShard(types.MainShardId, db.BlockHashByNumberIndex, id.Bytes(), hash.Bytes()))
+		require.NoError(t, db.WriteLastBlockHash(tx, types.MainShardId, hash))
+		c.blocks = append(c.blocks, block)
+		prev = hash
+	}
+	require.NoError(t, tx.Commit())
+}
+
+// validator returns the key of the validator of the block, whose set is taken from the config two blocks behind.
+func (c *snapshotTestChain) validator(id types.BlockNumber) bls.PrivateKey {
+	return c.keys[(max(id, 2)-2)/snapshotTestEpochLength]
+}
+
+func TestSnapshotSyncAcrossEpochs(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	const last = 3*snapshotTestEpochLength + 2
+
+	chain := newSnapshotTestChain(4)
+
+	nms := network.NewTestManagers(ctx, t, 9310, 2)
+	server, client := nms[0], nms[1]
+	defer server.Close()
+	defer client.Close()
+	_, serverId := network.ConnectManagers(t, client, server)
+
+	newClientDb := func(t *testing.T) db.DB {
+		t.Helper()
+
+		// The genesis is the trust anchor, so the client has it before the sync.
+		clientDb, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		chain.write(t, clientDb, 0, nil)
+		return clientDb
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		serverDb, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		defer serverDb.Close()
+		chain.write(t, serverDb, last, chain.validator)
+		targetHash := chain.blocks[last].Hash(types.MainShardId)
+		SetSnapshotHandlers(ctx, server, serverDb)
+
+		clientDb := newClientDb(t)
+		defer clientDb.Close()
+
+		f := newSnapshotFetcher(client, clientDb, []network.PeerID{serverId}, true, logging.NewLogger("snapshot-test"))
+		block, hash, err := f.target(ctx)
+		require.NoError(t, err)
+		require.Equal(t, targetHash, hash)
+		require.Equal(t, types.BlockNumber(last), block.Id)
+		require.Equal(t, types.BlockNumber(3*snapshotTestEpochLength), f.trusted.Id)
+
+		// A new fetcher walks the epochs only up to the one of the requested block.
+		for id, key := range map[types.BlockNumber]bls.PrivateKey{
+			snapshotTestEpochLength + 1: chain.keys[0],
+			snapshotTestEpochLength + 2: chain.keys[1],
+			last:                        chain.keys[3],
+		} {
+			f := newSnapshotFetcher(client, clientDb, []network.PeerID{serverId}, true, logging.NewLogger("snapshot-test"))
+			validators, err := f.validatorsOf(ctx, id)
+			require.NoError(t, err)
+			expected, err := key.PublicKey().Marshal()
+			require.NoError(t, err)
+			_, ok := validators.Find(config.Pubkey(expected))
+			require.True(t, ok, "block %d", id)
+		}
+	})
+
+	t.Run("SignedByGenesisValidators", func(t *testing.T) {
+		serverDb, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		defer serverDb.Close()
+		chain.write(t, serverDb, last, func(types.BlockNumber) bls.PrivateKey { return chain.keys[0] })
+		SetSnapshotHandlers(ctx, server, serverDb)
+
+		clientDb := newClientDb(t)
+		defer clientDb.Close()
+
+		f := newSnapshotFetcher(client, clientDb, []network.PeerID{serverId}, true, logging.NewLogger("snapshot-test"))
+		_, _, err = f.target(ctx)
+		require.ErrorIs(t, err, errSnapshotUnavailable)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -39,6 +40,10 @@ type SyncerConfig struct {
 	Timeout         time.Duration // pull blocks if no new blocks appear in the topic for this duration
 	BootstrapPeers  network.AddrInfoSlice
 	ZeroStateConfig *execution.ZeroStateConfig
+
+	// ReplayFromGenesis makes an empty node replay all blocks from the genesis
+	// instead of fetching the snapshot of the recent state.
+	ReplayFromGenesis bool
 }
 
 // every n-th block will be reported to info log (to avoid spamming)
@@ -151,14 +156,49 @@ func (s *Syncer) fetchRemoteVersion(ctx context.Context) (NodeVersion, error) {
 }
 
 func (s *Syncer) fetchSnapshot(ctx context.Context) error {
-	var err error
+	peers := make([]network.PeerID, 0, len(s.config.BootstrapPeers))
 	for _, peer := range s.config.BootstrapPeers {
-		err = fetchSnapshot(ctx, s.networkManager, network.AddrInfo(peer), s.db, s.logger)
-		if err == nil {
-			return nil
+		peerId, err := s.networkManager.Connect(ctx, network.AddrInfo(peer))
+		if err != nil {
+			s.logger.Warn().Err(err).Msgf("Failed to connect to %s", peer)
+			continue
 		}
+		if !slices.Contains(peers, peerId) {
+			peers = append(peers, peerId)
+		}
+	}
+	if len(peers) == 0 {
+		return errors.New("failed to connect to all bootstrap peers")
+	}
+
+	fetcher := newSnapshotFetcher(s.networkManager, s.db, peers, !s.config.DisableConsensus, s.logger)
+	if err := fetcher.fetch(ctx); err != nil {
+		return fmt.Errorf("failed to fetch snapshot: %w", err)
 	}
-	return fmt.Errorf("failed to fetch snapshot from all peers; last error: %w", err)
+	return nil
+}
+
+// initFromNetwork initializes the empty db of the node joining the network.
+func (s *Syncer) initFromNetwork(ctx context.Context, remoteVersion NodeVersion) error {
+	// The genesis is the trust anchor of the snapshot, so it is generated locally.
+	if err := s.GenerateZerostateIfShardIsEmpty(ctx); err != nil {
+		return fmt.Errorf("failed to generate zero-state: %w", err)
+	}
+	version, err := s.getLocalVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version.GenesisBlockHash != remoteVersion.GenesisBlockHash {
+		return fmt.Errorf("zero-state config differs from the network; local genesis: %s, remote genesis: %s",
+			version.GenesisBlockHash, remoteVersion.GenesisBlockHash)
+	}
+
+	if s.config.ReplayFromGenesis {
+		s.logger.Info().Msg("Blocks will be replayed from the genesis. Finished initialization")
+		return nil
+	}
+	s.logger.Info().Msg("Fetching snapshot...")
+	return s.fetchSnapshot(ctx)
 }
 
 func (s *Syncer) Init(ctx context.Context, allowDbDrop bool) error {
@@ -171,6 +211,12 @@ func (s *Syncer) Init(ctx context.Context, allowDbDrop bool) error {
 		return nil
 	}
 
+	if syncing, err := s.snapshotSyncInProgress(ctx); err != nil {
+		return err
+	} else if syncing {
+		return s.fetchSnapshot(ctx)
+	}
+
 	version, err := s.getLocalVersion(ctx)
 	if err != nil {
 		return err
@@ -196,8 +242,8 @@ func (s *Syncer) Init(ctx context.Context, allowDbDrop bool) error {
 	}
 
 	if version.GenesisBlockHash.Empty() {
-		s.logger.Info().Msg("Local version is empty. Fetching snapshot...")
-		return s.fetchSnapshot(ctx)
+		s.logger.Info().Msg("Local version is empty. Initializing from the network...")
+		return s.initFromNetwork(ctx, remoteVersion)
 	}
 
 	if version.GenesisBlockHash == remoteVersion.GenesisBlockHash {
@@ -213,8 +259,22 @@ func (s *Syncer) Init(ctx context.Context, allowDbDrop bool) error {
 	if err := s.db.DropAll(); err != nil {
 		return fmt.Errorf("failed to drop db: %w", err)
 	}
-	s.logger.Info().Msg("DB dropped. Fetching snapshot...")
-	return s.fetchSnapshot(ctx)
+	s.logger.Info().Msg("DB dropped. Initializing from the network...")
+	return s.initFromNetwork(ctx, remoteVersion)
+}
+
+func (s *Syncer) snapshotSyncInProgress(ctx context.Context) (bool, error) {
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	_, err = db.ReadSnapshotTarget(tx)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
 }
 
 // SetHandlers sets the handlers for generic (shard-independent) protocols.
@@ -226,7 +286,7 @@ func (s *Syncer) SetHandlers(ctx context.Context) error {
 		return nil
 	}
 
-	SetBootstrapHandler(ctx, s.networkManager, s.db)
+	SetSnapshotHandlers(ctx, s.networkManager, s.db)
 	return nil
 }
 
@@ -411,6 +471,18 @@ func (s *Syncer) saveBlock(ctx context.Context, block *types.BlockWithExtractedD
 	return nil
 }
 
+// RebuildFlatState fills the flat state from the tries of the last block if it is enabled and left behind,
+// e.g., after the snapshot sync or if it was disabled for a while.
+func (s *Syncer) RebuildFlatState(ctx context.Context) error {
+	if !s.config.EnableFlatState {
+		return nil
+	}
+	if err := execution.RebuildFlatState(ctx, s.db, s.config.ShardId); err != nil {
+		return fmt.Errorf("failed to rebuild flat state: %w", err)
+	}
+	return nil
+}
+
 func (s *Syncer) GenerateZerostateIfShardIsEmpty(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
 	defer cancel()
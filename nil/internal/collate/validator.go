@@ -1,6 +1,7 @@
 package collate
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -146,6 +147,10 @@ func (s *Validator) TxPool() TxnPool {
 	return s.pool
 }
 
+func (s *Validator) DevState() *DevState {
+	return s.params.DevState
+}
+
 func (s *Validator) BuildProposal(ctx context.Context) (*execution.ProposalSSZ, error) {
 	// No lock since it doesn't directly access last block/hash
 	proposer := newProposer(s.params, s.params.Topology, s.pool, s.logger)
@@ -292,6 +297,16 @@ func (s *Validator) onBlockCommitUnlocked(
 		}
 	}
 
+	if s.params.DevState != nil {
+		s.params.DevState.onCommitted(proposal)
+	}
+
+	if s.params.ShardId.IsMainShard() {
+		if err := deleteReportedEvidence(ctx, s.txFabric, s.params.NShards, res.Block); err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to delete reported consensus evidence")
+		}
+	}
+
 	s.notify(&event{evType, res.Block.Id})
 }
 
@@ -364,6 +379,15 @@ func (s *Validator) validateProposalUnlocked(ctx context.Context, proposal *exec
 		return err
 	}
 
+	// The development API changes bypass the transaction checks, so they are accepted only by the nodes running
+	// the development API themselves.
+	if proposal.HasDevChanges() && s.params.DevState == nil {
+		s.logger.Warn().
+			Err(execution.ErrDevChangesNotAllowed).
+			Msg("Rejecting proposal with development API changes")
+		return execution.ErrDevChangesNotAllowed
+	}
+
 	blockId := proposal.PrevBlockId + 1
 	if blockId <= lastBlock.Id {
 		s.logger.Trace().
@@ -392,9 +416,76 @@ func (s *Validator) validateProposalUnlocked(ctx context.Context, proposal *exec
 		return cerrors.ErrHashMismatch
 	}
 
+	if s.params.ShardId.IsMainShard() {
+		return s.validateValidatorsEpoch(ctx, lastBlock, proposal)
+	}
 	return nil
 }
 
+// validateValidatorsEpoch checks that the proposal carries the validators epoch transaction
+// if and only if the block starts the epoch (see proposer.handleValidatorsEpoch).
+func (s *Validator) validateValidatorsEpoch(
+	ctx context.Context, prevBlock *types.Block, proposal *execution.Proposal,
+) error {
+	tx, err := s.txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, s.params.ShardId)
+	if err != nil {
+		return fmt.Errorf("failed to create config accessor: %w", err)
+	}
+	es, err := execution.NewExecutionState(tx, s.params.ShardId, execution.StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+
+	blockId := proposal.PrevBlockId + 1
+	expected, err := isValidatorsEpochStart(es, blockId)
+	if err != nil {
+		return err
+	}
+
+	var epochTxns []*types.Transaction
+	for _, txn := range proposal.InternalTxns {
+		if txn.IsInternal() && txn.From == types.StakingAddress && txn.To == types.StakingAddress {
+			epochTxns = append(epochTxns, txn)
+		}
+	}
+
+	var calldata []byte
+	if expected {
+		if calldata, err = createValidatorsEpochCalldata(); err != nil {
+			return err
+		}
+	}
+	if !isValidEpochTxns(expected, epochTxns, calldata) {
+		s.logger.Error().
+			Stringer(logging.FieldBlockNumber, blockId).
+			Bool("epochStart", expected).
+			Int("count", len(epochTxns)).
+			Err(cerrors.ErrValidatorsEpoch).
+			Msg("Unexpected validators epoch transactions in the proposal")
+		return cerrors.ErrValidatorsEpoch
+	}
+	return nil
+}
+
+// isValidEpochTxns reports whether the epoch transactions of the proposal are the expected ones:
+// the single call with calldata at the epoch start and none inside the epoch.
+func isValidEpochTxns(expected bool, epochTxns []*types.Transaction, calldata []byte) bool {
+	if !expected {
+		return len(epochTxns) == 0
+	}
+	return len(epochTxns) == 1 && bytes.Equal(epochTxns[0].Data, calldata)
+}
+
 func (s *Validator) validateProposal(ctx context.Context, proposal *execution.Proposal) error {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -504,6 +595,13 @@ func (s *Validator) setLastBlockUnlocked(block *types.Block, hash common.Hash) {
 	s.lastBlockHash = hash
 }
 
+// resetLastBlock sets the last block after the database was rewound by the development API.
+func (s *Validator) resetLastBlock(block *types.Block, hash common.Hash) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.setLastBlockUnlocked(block, hash)
+}
+
 func (s *Validator) Subscribe() (uint64, <-chan *event) {
 	s.subsMutex.Lock()
 	defer s.subsMutex.Unlock()
@@ -0,0 +1,28 @@
+package collate
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidEpochTxns(t *testing.T) {
+	t.Parallel()
+
+	calldata := []byte{1, 2, 3, 4}
+	newTxn := func(data []byte) *types.Transaction {
+		return &types.Transaction{TransactionDigest: types.TransactionDigest{Data: data}}
+	}
+	epochTxn := newTxn(calldata)
+
+	// Inside the epoch, no epoch transaction is allowed.
+	require.True(t, isValidEpochTxns(false, nil, nil))
+	require.False(t, isValidEpochTxns(false, []*types.Transaction{epochTxn}, nil))
+
+	// At the epoch start, exactly one transaction with the expected calldata is required.
+	require.True(t, isValidEpochTxns(true, []*types.Transaction{epochTxn}, calldata))
+	require.False(t, isValidEpochTxns(true, nil, calldata))
+	require.False(t, isValidEpochTxns(true, []*types.Transaction{epochTxn, epochTxn}, calldata))
+	require.False(t, isValidEpochTxns(true, []*types.Transaction{newTxn([]byte{5})}, calldata))
+}
@@ -121,18 +121,20 @@ func mergeValidators(input []ListValidators) []ValidatorInfo {
 	return result
 }
 
-func (v *cacheValue) getValidatorsList(configAccessor ConfigAccessor) ([]ValidatorInfo, error) {
+// GetShardValidators returns the validators of the shard from the config.
+// The main shard is validated by the validators of all shards.
+func GetShardValidators(configAccessor ConfigAccessor, shardId types.ShardId) ([]ValidatorInfo, error) {
 	validatorsList, err := getParamImpl[ParamValidators](configAccessor)
 	if err != nil {
 		return nil, err
 	}
-	if v.shardId.IsMainShard() {
+	if shardId.IsMainShard() {
 		return mergeValidators(validatorsList.Validators), nil
 	}
-	if int(v.shardId)-1 >= len(validatorsList.Validators) {
+	if int(shardId)-1 >= len(validatorsList.Validators) {
 		return nil, types.NewError(types.ErrorShardIdIsTooBig)
 	}
-	return validatorsList.Validators[v.shardId-1].List, nil
+	return validatorsList.Validators[shardId-1].List, nil
 }
 
 func (v *cacheValue) initUnsafe(ctx context.Context) error {
@@ -146,11 +148,11 @@ func (v *cacheValue) initUnsafe(ctx context.Context) error {
 		return err
 	}
 	var configAccessor ConfigAccessor
-	configAccessor, err = NewConfigAccessorFromBlockWithTx(tx, block, v.shardId)
+	configAccessor, err = NewStrictConfigAccessorFromBlockWithTx(tx, block, v.shardId)
 	if err != nil {
 		return err
 	}
-	v.ValidatorInfo, err = v.getValidatorsList(configAccessor)
+	v.ValidatorInfo, err = GetShardValidators(configAccessor, v.shardId)
 	if err != nil {
 		return err
 	}
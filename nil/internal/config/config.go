@@ -82,7 +82,8 @@ type ConfigAccessorStub struct{}
 var _ ConfigAccessor = (*ConfigAccessorStub)(nil)
 
 func (c *ConfigAccessorStub) GetParamData(name string) ([]byte, error) {
-	return nil, errors.New("stub config accessor should not be called")
+	// The stub has no params, so the callers that can do without a param (e.g. the protocol versions) still work.
+	return nil, fmt.Errorf("%w: %s (stub config accessor should not be called)", ErrParamNotFound, name)
 }
 
 func (c *ConfigAccessorStub) GetParams() (map[string][]byte, error) {
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -10,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/rs/zerolog/log"
 )
@@ -20,12 +22,20 @@ const (
 	NameValidators = "curr_validators"
 	NameGasPrice   = "gas_price"
 	NameL1Block    = "l1block"
+
+	NameProtocolVersions = "protocol_versions"
+	NameValidatorsEpoch  = "validators_epoch"
 )
 
+// DefaultValidatorsEpochLength is used if the length of the validators epoch is not set in the config.
+const DefaultValidatorsEpochLength = 100
+
 var ParamsList = []IConfigParam{
 	new(ParamValidators),
 	new(ParamGasPrice),
 	new(ParamL1BlockInfo),
+	new(ParamProtocolVersions),
+	new(ParamValidatorsEpoch),
 }
 
 type Pubkey [ValidatorPubkeySize]byte
@@ -111,6 +121,57 @@ func (p *ParamL1BlockInfo) Accessor() *ParamAccessor {
 	return CreateAccessor[ParamL1BlockInfo]()
 }
 
+type ProtocolVersionActivation struct {
+	Version uint32 `json:"version" yaml:"version"`
+	Height  uint64 `json:"height" yaml:"height"`
+}
+
+// ParamProtocolVersions schedules the protocol versions (see params.ProtocolVersion) at the main shard heights.
+// The activations are ordered by height and each one activates a newer version.
+type ParamProtocolVersions struct {
+	Activations []ProtocolVersionActivation `json:"activations" ssz-max:"4096" yaml:"activations"`
+}
+
+var _ IConfigParam = new(ParamProtocolVersions)
+
+func (p *ParamProtocolVersions) Name() string {
+	return NameProtocolVersions
+}
+
+func (p *ParamProtocolVersions) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamProtocolVersions]()
+}
+
+// ParamValidatorsEpoch sets the number of main shard blocks in the validators epoch. The validator set changes
+// requested in the Staking contract are applied in the first block of every epoch. Zero means the default length.
+type ParamValidatorsEpoch struct {
+	Length uint64 `json:"length" yaml:"length"`
+}
+
+var _ IConfigParam = new(ParamValidatorsEpoch)
+
+func (p *ParamValidatorsEpoch) Name() string {
+	return NameValidatorsEpoch
+}
+
+func (p *ParamValidatorsEpoch) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamValidatorsEpoch]()
+}
+
+// Version returns the protocol version active at the main shard height.
+func (p *ParamProtocolVersions) Version(height types.BlockNumber) (params.ProtocolVersion, error) {
+	version := params.ProtocolGenesis
+	for i, a := range p.Activations {
+		if i > 0 && (a.Height <= p.Activations[i-1].Height || a.Version <= p.Activations[i-1].Version) {
+			return 0, fmt.Errorf("protocol version %d activated at %d is out of order", a.Version, a.Height)
+		}
+		if a.Height <= height.Uint64() {
+			version = params.ProtocolVersion(a.Version)
+		}
+	}
+	return version, nil
+}
+
 func CreateAccessor[T any, paramPtr IConfigParamPointer[T]]() *ParamAccessor {
 	return &ParamAccessor{
 		func(c ConfigAccessor) (any, error) {
@@ -151,6 +212,26 @@ func NewConfigAccessorFromBlock(
 }
 
 func NewConfigAccessorFromBlockWithTx(tx db.RoTx, block *types.Block, shardId types.ShardId) (ConfigAccessor, error) {
+	return newConfigAccessorFromBlock(tx, block, shardId, false)
+}
+
+// NewStrictConfigAccessorFromBlockWithTx is the same as NewConfigAccessorFromBlockWithTx,
+// but it fails if the main shard block referenced by the block is not available yet.
+// The validator set is changed at the epoch boundaries, so it can't be taken from the latest config.
+func NewStrictConfigAccessorFromBlockWithTx(
+	tx db.RoTx,
+	block *types.Block,
+	shardId types.ShardId,
+) (ConfigAccessor, error) {
+	return newConfigAccessorFromBlock(tx, block, shardId, true)
+}
+
+func newConfigAccessorFromBlock(
+	tx db.RoTx,
+	block *types.Block,
+	shardId types.ShardId,
+	strict bool,
+) (ConfigAccessor, error) {
 	var mainShardHash *common.Hash
 	if block != nil {
 		h := block.GetMainShardHash(shardId)
@@ -171,8 +252,11 @@ func NewConfigAccessorFromBlockWithTx(tx db.RoTx, block *types.Block, shardId ty
 		if _, err := db.ReadBlock(tx, types.MainShardId, *mainShardHash); errors.Is(err, db.ErrKeyNotFound) {
 			// It is possible that the needed main chain block has not arrived yet,
 			// or that this one is some byzantine block.
-			// Because right now the config is actually constant, we can use whatever version we like in this case,
-			// so we use the latest accessible config.
+			if strict {
+				return nil, fmt.Errorf("main chain block %s not found: %w", mainShardHash, err)
+			}
+			// The parameters other than the validator set are changed rarely,
+			// so we use the latest accessible config in this case.
 			// TODO(@isergeyam): create some subscription mechanism that will handle this correctly.
 			log.Warn().
 				Stringer(logging.FieldBlockNumber, block.Id).
@@ -227,6 +311,16 @@ func CreateValidatorsPublicKeyMap(validators []ValidatorInfo) (*PublicKeyMap, er
 	return m, nil
 }
 
+// CheckPublicKeys returns an error if any of the validator keys is not a valid BLS public key.
+func (p *ParamValidators) CheckPublicKeys() error {
+	for i, list := range p.Validators {
+		if _, err := CreateValidatorsPublicKeyMap(list.List); err != nil {
+			return fmt.Errorf("invalid public key in the validators list %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 func SetParamValidators(c ConfigAccessor, params *ParamValidators) error {
 	return setParamImpl(c, params)
 }
@@ -247,6 +341,40 @@ func SetParamL1Block(c ConfigAccessor, params *ParamL1BlockInfo) error {
 	return setParamImpl(c, params)
 }
 
+// GetParamProtocolVersions returns the schedule of the protocol versions.
+// The networks created before the versions were introduced have no schedule and run the genesis version.
+func GetParamProtocolVersions(c ConfigAccessor) (*ParamProtocolVersions, error) {
+	param, err := getParamImpl[ParamProtocolVersions](c)
+	if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+		return &ParamProtocolVersions{}, nil
+	}
+	return param, err
+}
+
+func SetParamProtocolVersions(c ConfigAccessor, params *ParamProtocolVersions) error {
+	return setParamImpl(c, params)
+}
+
+// GetValidatorsEpochLength returns the number of main shard blocks in the validators epoch.
+// The networks created before the param was introduced use the default length.
+func GetValidatorsEpochLength(c ConfigAccessor) (types.BlockNumber, error) {
+	param, err := getParamImpl[ParamValidatorsEpoch](c)
+	if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+		return DefaultValidatorsEpochLength, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if param.Length == 0 {
+		return DefaultValidatorsEpochLength, nil
+	}
+	return types.BlockNumber(param.Length), nil
+}
+
+func SetParamValidatorsEpoch(c ConfigAccessor, params *ParamValidatorsEpoch) error {
+	return setParamImpl(c, params)
+}
+
 func GetParamNShards(c ConfigAccessor) (uint32, error) {
 	param, err := getParamImpl[ParamGasPrice](c)
 	if err != nil {
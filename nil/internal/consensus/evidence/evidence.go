@@ -0,0 +1,150 @@
+package evidence
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Evidence proves that a validator signed two different proposals at the same height and round.
+// It holds both signed messages, so it can be checked by anyone who knows the validator set.
+type Evidence struct {
+	ShardId types.ShardId
+	First   *protoIBFT.IbftMessage
+	Second  *protoIBFT.IbftMessage
+}
+
+// Validator returns the public key of the validator that signed the messages.
+func (e *Evidence) Validator() []byte {
+	return e.First.GetFrom()
+}
+
+func (e *Evidence) Height() uint64 {
+	return e.First.GetView().GetHeight()
+}
+
+func (e *Evidence) Round() uint64 {
+	return e.First.GetView().GetRound()
+}
+
+func (e *Evidence) Type() protoIBFT.MessageType {
+	return e.First.GetType()
+}
+
+// Id identifies the equivocation: the evidence of the same validator signing conflicting messages
+// of the same type at the same height and round has the same id no matter which messages it holds.
+func (e *Evidence) Id() common.Hash {
+	return equivocationId(e.ShardId, e.First)
+}
+
+func equivocationId(shardId types.ShardId, msg *protoIBFT.IbftMessage) common.Hash {
+	data := append(shardId.Bytes(), msg.GetFrom()...)
+	data = binary.BigEndian.AppendUint64(data, msg.GetView().GetHeight())
+	data = binary.BigEndian.AppendUint64(data, msg.GetView().GetRound())
+	data = append(data, byte(msg.GetType()))
+	return common.KeccakHash(data)
+}
+
+// ProposalHash returns the hash of the proposal the message votes for.
+// It returns false for the messages that don't refer to a single proposal (i.e. round changes).
+func ProposalHash(msg *protoIBFT.IbftMessage) ([]byte, bool) {
+	switch payload := msg.GetPayload().(type) {
+	case *protoIBFT.IbftMessage_PreprepareData:
+		return payload.PreprepareData.GetProposalHash(), true
+	case *protoIBFT.IbftMessage_PrepareData:
+		return payload.PrepareData.GetProposalHash(), true
+	case *protoIBFT.IbftMessage_CommitData:
+		return payload.CommitData.GetProposalHash(), true
+	}
+	return nil, false
+}
+
+// Verify checks that the messages are signed by the same validator and vote for different proposals
+// in the same view. It doesn't check that the signer belongs to the validator set.
+func (e *Evidence) Verify() error {
+	if e.First == nil || e.Second == nil {
+		return errors.New("evidence must contain two messages")
+	}
+	if !bytes.Equal(e.First.GetFrom(), e.Second.GetFrom()) {
+		return errors.New("messages are signed by different validators")
+	}
+	if e.First.GetType() != e.Second.GetType() {
+		return errors.New("messages have different types")
+	}
+	if e.First.GetView().GetHeight() != e.Second.GetView().GetHeight() ||
+		e.First.GetView().GetRound() != e.Second.GetView().GetRound() {
+		return errors.New("messages have different views")
+	}
+
+	firstHash, ok := ProposalHash(e.First)
+	if !ok {
+		return fmt.Errorf("%s message can't be used as evidence", e.First.GetType())
+	}
+	secondHash, ok := ProposalHash(e.Second)
+	if !ok {
+		return fmt.Errorf("%s message can't be used as evidence", e.Second.GetType())
+	}
+	if bytes.Equal(firstHash, secondHash) {
+		return errors.New("messages vote for the same proposal")
+	}
+
+	if err := verifySignature(e.First); err != nil {
+		return fmt.Errorf("invalid signature of the first message: %w", err)
+	}
+	if err := verifySignature(e.Second); err != nil {
+		return fmt.Errorf("invalid signature of the second message: %w", err)
+	}
+	return nil
+}
+
+func verifySignature(msg *protoIBFT.IbftMessage) error {
+	data, err := msg.PayloadNoSig()
+	if err != nil {
+		return err
+	}
+	publicKey, err := bls.PublicKeyFromBytes(msg.GetFrom())
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+	sig, err := bls.SignatureFromBytes(msg.GetSignature())
+	if err != nil {
+		return err
+	}
+	return sig.Verify(publicKey, common.KeccakHash(data).Bytes())
+}
+
+// MarshalMessages returns the protobuf encoding of both messages.
+func (e *Evidence) MarshalMessages() ([]byte, []byte, error) {
+	first, err := proto.Marshal(e.First)
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := proto.Marshal(e.Second)
+	if err != nil {
+		return nil, nil, err
+	}
+	return first, second, nil
+}
+
+// UnmarshalEvidence decodes the evidence from the protobuf-encoded messages.
+func UnmarshalEvidence(shardId types.ShardId, first, second []byte) (*Evidence, error) {
+	e := &Evidence{
+		ShardId: shardId,
+		First:   &protoIBFT.IbftMessage{},
+		Second:  &protoIBFT.IbftMessage{},
+	}
+	if err := proto.Unmarshal(first, e.First); err != nil {
+		return nil, fmt.Errorf("failed to decode the first message: %w", err)
+	}
+	if err := proto.Unmarshal(second, e.Second); err != nil {
+		return nil, fmt.Errorf("failed to decode the second message: %w", err)
+	}
+	return e, nil
+}
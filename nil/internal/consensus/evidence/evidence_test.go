@@ -0,0 +1,149 @@
+package evidence
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newPrepare(t *testing.T, key bls.PrivateKey, height, round uint64, hash []byte) *protoIBFT.IbftMessage {
+	t.Helper()
+
+	from, err := key.PublicKey().Marshal()
+	require.NoError(t, err)
+
+	msg := &protoIBFT.IbftMessage{
+		View: &protoIBFT.View{Height: height, Round: round},
+		From: from,
+		Type: protoIBFT.MessageType_PREPARE,
+		Payload: &protoIBFT.IbftMessage_PrepareData{
+			PrepareData: &protoIBFT.PrepareMessage{ProposalHash: hash},
+		},
+	}
+
+	data, err := msg.PayloadNoSig()
+	require.NoError(t, err)
+	sig, err := key.Sign(common.KeccakHash(data).Bytes())
+	require.NoError(t, err)
+	msg.Signature, err = sig.Marshal()
+	require.NoError(t, err)
+	return msg
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	ctx := t.Context()
+	shardId := types.BaseShardId
+	key := bls.NewRandomKey()
+	tracker := NewTracker(shardId, database)
+	tracker.SetHeight(10)
+
+	first := newPrepare(t, key, 10, 0, []byte{1})
+
+	ev, err := tracker.Observe(ctx, first)
+	require.NoError(t, err)
+	require.Nil(t, ev)
+
+	// The same message is received again, e.g. within a certificate.
+	ev, err = tracker.Observe(ctx, newPrepare(t, key, 10, 0, []byte{1}))
+	require.NoError(t, err)
+	require.Nil(t, ev)
+
+	// Votes for different proposals in different rounds are fine.
+	ev, err = tracker.Observe(ctx, newPrepare(t, key, 10, 1, []byte{2}))
+	require.NoError(t, err)
+	require.Nil(t, ev)
+
+	second := newPrepare(t, key, 10, 0, []byte{2})
+	ev, err = tracker.Observe(ctx, second)
+	require.NoError(t, err)
+	require.NotNil(t, ev)
+	require.NoError(t, ev.Verify())
+	require.Equal(t, uint64(10), ev.Height())
+	require.Equal(t, protoIBFT.MessageType_PREPARE, ev.Type())
+
+	// The equivocation is reported only once.
+	ev, err = tracker.Observe(ctx, newPrepare(t, key, 10, 0, []byte{3}))
+	require.NoError(t, err)
+	require.Nil(t, ev)
+
+	tx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	stored, err := ReadAll(tx, shardId)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.NoError(t, stored[0].Verify())
+	require.Equal(t, second.GetSignature(), stored[0].Second.GetSignature())
+
+	// The messages of the old heights are forgotten and ignored.
+	tracker.SetHeight(10 + historyDepth + 1)
+	ev, err = tracker.Observe(ctx, newPrepare(t, key, 10, 1, []byte{3}))
+	require.NoError(t, err)
+	require.Nil(t, ev)
+
+	// The messages of the views beyond the window are not kept.
+	height := uint64(10 + historyDepth + 1)
+	for _, view := range []struct{ height, round uint64 }{
+		{height + futureDepth + 1, 0},
+		{height, maxRound + 1},
+	} {
+		_, err = tracker.Observe(ctx, newPrepare(t, key, view.height, view.round, []byte{1}))
+		require.NoError(t, err)
+		ev, err = tracker.Observe(ctx, newPrepare(t, key, view.height, view.round, []byte{2}))
+		require.NoError(t, err)
+		require.Nil(t, ev)
+	}
+	require.Empty(t, tracker.seen)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	key := bls.NewRandomKey()
+	first := newPrepare(t, key, 1, 0, []byte{1})
+	second := newPrepare(t, key, 1, 0, []byte{2})
+	require.NoError(t, (&Evidence{First: first, Second: second}).Verify())
+
+	t.Run("SameProposal", func(t *testing.T) {
+		t.Parallel()
+
+		ev := &Evidence{First: first, Second: newPrepare(t, key, 1, 0, []byte{1})}
+		require.Error(t, ev.Verify())
+	})
+
+	t.Run("DifferentViews", func(t *testing.T) {
+		t.Parallel()
+
+		ev := &Evidence{First: first, Second: newPrepare(t, key, 1, 1, []byte{2})}
+		require.Error(t, ev.Verify())
+	})
+
+	t.Run("DifferentValidators", func(t *testing.T) {
+		t.Parallel()
+
+		ev := &Evidence{First: first, Second: newPrepare(t, bls.NewRandomKey(), 1, 0, []byte{2})}
+		require.Error(t, ev.Verify())
+	})
+
+	t.Run("ForgedSignature", func(t *testing.T) {
+		t.Parallel()
+
+		// The message is signed by another key on behalf of the validator.
+		forged := newPrepare(t, bls.NewRandomKey(), 1, 0, []byte{2})
+		forged.From = first.GetFrom()
+		ev := &Evidence{First: first, Second: forged}
+		require.Error(t, ev.Verify())
+	})
+}
@@ -0,0 +1,79 @@
+package evidence
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// The evidence is keyed by the equivocation id. The value holds the length of the first message
+// followed by the protobuf-encoded first and second messages.
+
+const lengthSize = 4
+
+var errMalformedRecord = errors.New("malformed evidence record")
+
+// Write persists the evidence. It returns false if the equivocation has already been recorded.
+func Write(tx db.RwTx, e *Evidence) (bool, error) {
+	key := e.Id().Bytes()
+	exists, err := tx.ExistsInShard(e.ShardId, db.ConsensusEvidenceTable, key)
+	if err != nil || exists {
+		return false, err
+	}
+
+	first, second, err := e.MarshalMessages()
+	if err != nil {
+		return false, err
+	}
+	value := binary.BigEndian.AppendUint32(make([]byte, 0, lengthSize+len(first)+len(second)), uint32(len(first)))
+	value = append(value, first...)
+	value = append(value, second...)
+
+	if err := tx.PutToShard(e.ShardId, db.ConsensusEvidenceTable, key, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes the evidence of the equivocation with the given id.
+func Delete(tx db.RwTx, shardId types.ShardId, id common.Hash) error {
+	return tx.DeleteFromShard(shardId, db.ConsensusEvidenceTable, id.Bytes())
+}
+
+// ReadAll returns all evidence recorded for the shard.
+func ReadAll(tx db.RoTx, shardId types.ShardId) ([]*Evidence, error) {
+	iter, err := tx.RangeByShard(shardId, db.ConsensusEvidenceTable, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var res []*Evidence
+	for iter.HasNext() {
+		_, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeRecord(shardId, value)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func decodeRecord(shardId types.ShardId, value []byte) (*Evidence, error) {
+	if len(value) < lengthSize {
+		return nil, errMalformedRecord
+	}
+	firstLen := uint64(binary.BigEndian.Uint32(value))
+	value = value[lengthSize:]
+	if uint64(len(value)) < firstLen {
+		return nil, errMalformedRecord
+	}
+	return UnmarshalEvidence(shardId, value[:firstLen], value[firstLen:])
+}
@@ -0,0 +1,121 @@
+package evidence
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	// historyDepth is the number of heights below the current one the messages are kept for.
+	historyDepth = 16
+	// futureDepth is the number of heights above the current one the messages are kept for.
+	futureDepth = 2
+	// maxRound is the highest round the messages are kept for.
+	maxRound = 64
+)
+
+type viewKey struct {
+	height  uint64
+	round   uint64
+	msgType protoIBFT.MessageType
+	from    string
+}
+
+// Tracker remembers the messages signed by the validators of the shard and detects the conflicting ones.
+type Tracker struct {
+	shardId types.ShardId
+	db      db.DB
+
+	mu     sync.Mutex
+	seen   map[viewKey]*protoIBFT.IbftMessage
+	height uint64
+}
+
+func NewTracker(shardId types.ShardId, database db.DB) *Tracker {
+	return &Tracker{
+		shardId: shardId,
+		db:      database,
+		seen:    make(map[viewKey]*protoIBFT.IbftMessage),
+	}
+}
+
+// SetHeight sets the height the consensus is running for and forgets the messages of the old heights.
+// The height is not taken from the messages, so a validator can't make the tracker forget the history.
+func (t *Tracker) SetHeight(height uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.height = height
+	for key := range t.seen {
+		if !t.inWindow(key.height, key.round) {
+			delete(t.seen, key)
+		}
+	}
+}
+
+// inWindow reports whether the messages of the view are tracked. The window is bounded,
+// so the validators can't make the tracker keep an unlimited number of messages.
+func (t *Tracker) inWindow(height, round uint64) bool {
+	return height+historyDepth >= t.height && height <= t.height+futureDepth && round <= maxRound
+}
+
+// Observe checks the message against the ones seen before. The signature of the message must be verified.
+// If the validator has already signed a different proposal in the same view, the evidence is persisted and returned.
+// Nil is returned for the equivocations that have already been recorded.
+func (t *Tracker) Observe(ctx context.Context, msg *protoIBFT.IbftMessage) (*Evidence, error) {
+	hash, ok := ProposalHash(msg)
+	if !ok {
+		return nil, nil
+	}
+
+	key := viewKey{
+		height:  msg.GetView().GetHeight(),
+		round:   msg.GetView().GetRound(),
+		msgType: msg.GetType(),
+		from:    string(msg.GetFrom()),
+	}
+
+	t.mu.Lock()
+	if !t.inWindow(key.height, key.round) {
+		t.mu.Unlock()
+		return nil, nil
+	}
+	prev, ok := t.seen[key]
+	if !ok {
+		t.seen[key] = msg
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+	if prevHash, _ := ProposalHash(prev); bytes.Equal(prevHash, hash) {
+		return nil, nil
+	}
+
+	e := &Evidence{ShardId: t.shardId, First: prev, Second: msg}
+	written, err := t.write(ctx, e)
+	if err != nil || !written {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (t *Tracker) write(ctx context.Context, e *Evidence) (bool, error) {
+	tx, err := t.db.CreateRwTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	written, err := Write(tx, e)
+	if err != nil || !written {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -13,6 +13,7 @@ import (
 	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
 	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
 	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
@@ -53,6 +54,7 @@ type backendIBFT struct {
 	signer       *Signer
 	mh           *MetricsHandler
 	txFabric     db.DB
+	evidence     *evidence.Tracker
 }
 
 var _ core.Backend = &backendIBFT{}
@@ -224,6 +226,7 @@ func NewConsensus(cfg *ConsensusParams) (*backendIBFT, error) {
 		signer:    NewSigner(cfg.PrivateKey),
 		mh:        mh,
 		txFabric:  cfg.Db,
+		evidence:  evidence.NewTracker(cfg.ShardId, cfg.Db),
 	}
 	if backend.consensus, err = core.NewIBFTWithMetrics(l, backend, backend, telattr.ShardId(cfg.ShardId)); err != nil {
 		return nil, err
@@ -264,6 +267,7 @@ func (i *backendIBFT) RunSequence(ctx context.Context, height uint64) error {
 	i.mh.StartSequence(ctx, height)
 
 	i.ctx = ctx
+	i.evidence.SetHeight(height)
 	i.consensus.RunSequence(ctx, height)
 	return nil
 }
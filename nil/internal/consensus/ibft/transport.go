@@ -46,6 +46,9 @@ func (i *backendIBFT) setupTransport(ctx context.Context) error {
 
 	// Subscribe to the newly created topic
 	protocol := i.getProto()
+	if err := topic.RegisterTopicValidator(protocol, i.validateGossipMessage); err != nil {
+		return err
+	}
 	sub, err := topic.Subscribe(protocol)
 	if err != nil {
 		return err
@@ -2,7 +2,9 @@ package ibft
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/go-ibft/messages"
@@ -10,6 +12,8 @@ import (
 	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	protobuf "google.golang.org/protobuf/proto"
 )
 
 func (i *backendIBFT) IsValidProposal(rawProposal []byte) bool {
@@ -34,31 +38,48 @@ func (i *backendIBFT) IsValidProposal(rawProposal []byte) bool {
 	return err == nil
 }
 
+var (
+	// errValidatorsUnknown means that the message can't be checked now, it doesn't prove the sender is faulty.
+	errValidatorsUnknown = errors.New("failed to get validators")
+	errNotValidator      = errors.New("public key not found in validators list")
+)
+
 func (i *backendIBFT) IsValidValidator(msg *protoIBFT.IbftMessage) bool {
-	msgNoSig, err := msg.PayloadNoSig()
-	if err != nil {
+	logger := i.messageLogger(msg)
+	if err := i.verifyValidatorMessage(msg, logger); err != nil {
+		logger.Error().Err(err).Msg("Invalid validator message")
 		return false
 	}
 
-	// Here (and below) we use transportCtx because this method could be called from the transport goroutine
-	// or i.ctx can be changed in case we start new sequence for the next height.
-	lastBlock, _, err := i.validator.GetLastBlock(i.transportCtx)
-	if err != nil {
-		i.logger.Error().
-			Err(err).
-			Msg("Failed to get last block")
-		return false
-	}
+	i.checkEquivocation(msg, logger)
+	return true
+}
 
-	var height uint64
+func (i *backendIBFT) messageLogger(msg *protoIBFT.IbftMessage) logging.Logger {
 	loggerCtx := i.logger.With().Hex(logging.FieldPublicKey, msg.GetFrom())
 	if view := msg.GetView(); view != nil {
 		loggerCtx = loggerCtx.
 			Uint64(logging.FieldHeight, view.GetHeight()).
 			Uint64(logging.FieldRound, view.GetRound())
-		height = view.GetHeight()
 	}
-	logger := loggerCtx.Logger()
+	return loggerCtx.Logger()
+}
+
+// verifyValidatorMessage checks that the message is signed by a validator of the shard at the height of the message.
+func (i *backendIBFT) verifyValidatorMessage(msg *protoIBFT.IbftMessage, logger logging.Logger) error {
+	msgNoSig, err := msg.PayloadNoSig()
+	if err != nil {
+		return err
+	}
+
+	// Here (and below) we use transportCtx because this method could be called from the transport goroutine
+	// or i.ctx can be changed in case we start new sequence for the next height.
+	lastBlock, _, err := i.validator.GetLastBlock(i.transportCtx)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last block: %w", errValidatorsUnknown, err)
+	}
+
+	height := msg.GetView().GetHeight()
 
 	// Current message is from future.
 	// Some validator could commit block and start new sequence before we committed that block.
@@ -71,25 +92,72 @@ func (i *backendIBFT) IsValidValidator(msg *protoIBFT.IbftMessage) bool {
 
 	params, err := config.GetConfigParams(i.transportCtx, i.txFabric, i.shardId, height)
 	if err != nil {
-		logger.Error().
-			Err(err).
-			Msg("Failed to get validators")
-		return false
+		return fmt.Errorf("%w: %w", errValidatorsUnknown, err)
 	}
 
-	_, ok := params.PublicKeys.Find(config.Pubkey(msg.GetFrom()))
-	if !ok {
-		logger.Error().
-			Msg("public key not found in validators list")
-		return false
+	if _, ok := params.PublicKeys.Find(config.Pubkey(msg.GetFrom())); !ok {
+		return errNotValidator
 	}
 
 	if err := i.signer.VerifyWithKey(msg.GetFrom(), msgNoSig, msg.GetSignature()); err != nil {
-		logger.Err(err).Msg("Failed to verify signature")
-		return false
+		return fmt.Errorf("failed to verify signature: %w", err)
 	}
+	return nil
+}
 
-	return true
+// validateGossipMessage is run by the pubsub before the message is delivered to the consensus and relayed
+// to the other validators. The messages of the past heights are dropped without penalties,
+// since the sender may be lagging behind.
+func (i *backendIBFT) validateGossipMessage(
+	_ context.Context,
+	_ network.PeerID,
+	data []byte,
+) network.ValidationResult {
+	msg := &protoIBFT.IbftMessage{}
+	if err := protobuf.Unmarshal(data, msg); err != nil {
+		return network.ValidationReject
+	}
+	if msg.GetView() == nil {
+		return network.ValidationReject
+	}
+
+	lastBlock, _, err := i.validator.GetLastBlock(i.transportCtx)
+	if err != nil {
+		return network.ValidationIgnore
+	}
+	if msg.GetView().GetHeight() <= uint64(lastBlock.Id) {
+		return network.ValidationIgnore
+	}
+
+	logger := i.messageLogger(msg)
+	if err := i.verifyValidatorMessage(msg, logger); err != nil {
+		if errors.Is(err, errValidatorsUnknown) {
+			logger.Debug().Err(err).Msg("Can't validate gossip message")
+			return network.ValidationIgnore
+		}
+		logger.Warn().Err(err).Msg("Rejected gossip message")
+		return network.ValidationReject
+	}
+	return network.ValidationAccept
+}
+
+// checkEquivocation records the evidence if the validator has signed a conflicting message before.
+// The message is still accepted: the consensus tolerates the faulty validators.
+func (i *backendIBFT) checkEquivocation(msg *protoIBFT.IbftMessage, logger logging.Logger) {
+	ev, err := i.evidence.Observe(i.transportCtx, msg)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to record equivocation evidence")
+		return
+	}
+	if ev == nil {
+		return
+	}
+
+	logger.Error().
+		Stringer(logging.FieldType, ev.Type()).
+		Stringer("evidenceId", ev.Id()).
+		Msg("Validator signed conflicting messages")
+	i.mh.IncEquivocations(i.transportCtx, ev.Type().String())
 }
 
 func (i *backendIBFT) getPrevProposer(height uint64) *uint64 {
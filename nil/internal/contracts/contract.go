@@ -29,6 +29,8 @@ const (
 	NameNilConfigAbi  = "NilConfigAbi"
 	NameL1BlockInfo   = "system/L1BlockInfo"
 	NameGovernance    = "system/Governance"
+	NameStaking       = "system/Staking"
+	NameSlashing      = "system/Slashing"
 )
 
 var (
@@ -3,6 +3,7 @@ package db
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"reflect"
 
 	fastssz "github.com/NilFoundation/fastssz"
@@ -183,3 +184,84 @@ func ReadBlockByNumber(tx RoTx, shardId types.ShardId, blockNumber types.BlockNu
 	}
 	return ReadBlock(tx, shardId, blockHash)
 }
+
+// PrunedState describes the blocks whose state was removed by the pruner
+// or precedes the snapshot the node was bootstrapped from.
+type PrunedState struct {
+	// FirstKeptBlock is the first block whose state is kept. The state of the later blocks is kept too.
+	FirstKeptBlock types.BlockNumber
+	// CheckpointInterval is the interval of the blocks whose state is kept forever. Zero means no checkpoints.
+	CheckpointInterval uint64
+}
+
+// IsKept reports whether the state of the block is kept.
+func (s PrunedState) IsKept(blockId types.BlockNumber) bool {
+	return blockId >= s.FirstKeptBlock || (s.CheckpointInterval != 0 && uint64(blockId)%s.CheckpointInterval == 0)
+}
+
+// ReadPrunedState returns the pruned blocks of the shard. Nothing is pruned if the shard was never pruned.
+func ReadPrunedState(tx RoTx, shardId types.ShardId) (PrunedState, error) {
+	value, err := tx.Get(prunedStateTable, shardId.Bytes())
+	if errors.Is(err, ErrKeyNotFound) {
+		return PrunedState{}, nil
+	}
+	if err != nil {
+		return PrunedState{}, err
+	}
+	if len(value) != 16 {
+		return PrunedState{}, fmt.Errorf("invalid pruned state of shard %d: %x", shardId, value)
+	}
+	return PrunedState{
+		FirstKeptBlock:     types.BlockNumber(binary.BigEndian.Uint64(value[:8])),
+		CheckpointInterval: binary.BigEndian.Uint64(value[8:]),
+	}, nil
+}
+
+func WritePrunedState(tx RwTx, shardId types.ShardId, state PrunedState) error {
+	value := binary.BigEndian.AppendUint64(make([]byte, 0, 16), uint64(state.FirstKeptBlock))
+	value = binary.BigEndian.AppendUint64(value, state.CheckpointInterval)
+	return tx.Put(prunedStateTable, shardId.Bytes(), value)
+}
+
+// CheckStateAvailable returns ErrStatePruned if the state of the block is not available.
+func CheckStateAvailable(tx RoTx, shardId types.ShardId, blockId types.BlockNumber) error {
+	state, err := ReadPrunedState(tx, shardId)
+	if err != nil {
+		return err
+	}
+	if !state.IsKept(blockId) {
+		return fmt.Errorf("%w: block %d of shard %d, the earliest available block is %d",
+			ErrStatePruned, blockId, shardId, state.FirstKeptBlock)
+	}
+	return nil
+}
+
+// ReadFlatStateHead returns the hash of the block whose state is held in the flat state table of the shard.
+// The empty hash is returned if the table was never written.
+func ReadFlatStateHead(tx RoTx, shardId types.ShardId) (common.Hash, error) {
+	value, err := tx.Get(flatStateHeadTable, shardId.Bytes())
+	if errors.Is(err, ErrKeyNotFound) {
+		return common.EmptyHash, nil
+	}
+	return common.BytesToHash(value), err
+}
+
+func WriteFlatStateHead(tx RwTx, shardId types.ShardId, hash common.Hash) error {
+	return tx.Put(flatStateHeadTable, shardId.Bytes(), hash.Bytes())
+}
+
+var snapshotTargetKey = []byte("target")
+
+// ReadSnapshotTarget returns the hash of the main shard block the snapshot sync in progress is targeted at.
+func ReadSnapshotTarget(tx RoTx) (common.Hash, error) {
+	value, err := tx.Get(snapshotSyncTable, snapshotTargetKey)
+	return common.BytesToHash(value), err
+}
+
+func WriteSnapshotTarget(tx RwTx, hash common.Hash) error {
+	return tx.Put(snapshotSyncTable, snapshotTargetKey, hash.Bytes())
+}
+
+func DeleteSnapshotTarget(tx RwTx) error {
+	return tx.Delete(snapshotSyncTable, snapshotTargetKey)
+}
@@ -2,4 +2,8 @@ package db
 
 import "errors"
 
-var ErrKeyNotFound = errors.New("key not found in db")
+var (
+	ErrKeyNotFound = errors.New("key not found in db")
+	// ErrStatePruned is returned when the state of the requested block was removed by the pruner.
+	ErrStatePruned = errors.New("state pruned")
+)
@@ -30,11 +30,21 @@ const (
 	BlockHashAndOutTransactionIndexByTransactionHash = ShardedTableName(
 		"BlockHashAndOutTransactionIndexByTransactionHash")
 	AsyncCallContextTable = ShardedTableName("AsyncCallContext")
+	TxnPoolJournalTable   = ShardedTableName("TxnPoolJournal")
+	// ConsensusEvidenceTable holds the conflicting messages signed by the validators.
+	ConsensusEvidenceTable = ShardedTableName("ConsensusEvidence")
+	// FlatStateTable holds the accounts and the storage slots of the flat state head block.
+	FlatStateTable = ShardedTableName("FlatState")
+	// DevChangesTable holds the development API changes applied in the blocks by the block hash.
+	DevChangesTable = ShardedTableName("DevChanges")
 
 	collatorStateTable          = TableName("CollatorState")
 	errorByTransactionHashTable = TableName("ErrorByTransactionHash")
 	schemeVersionTable          = TableName("SchemeVersion")
 	LastBlockTable              = TableName("LastBlock")
+	prunedStateTable            = TableName("PrunedState")
+	snapshotSyncTable           = TableName("SnapshotSync")
+	flatStateHeadTable          = TableName("FlatStateHead")
 
 	DHTTable = TableName("DHT")
 )
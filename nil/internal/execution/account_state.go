@@ -57,6 +57,9 @@ type AccountState struct {
 	// Tokens holds the token changed during execution. If execution fails, these changes will be dropped.
 	Tokens map[types.TokenId]types.Value
 
+	// flatState is set if the account was read from the flat state, its committed storage is read from there too.
+	flatState *FlatStateReader
+
 	// Flag whether the account was marked as self-destructed. The self-destructed
 	// account is still accessible in the scope of same transaction.
 	selfDestructed bool
@@ -314,6 +317,10 @@ func (as *AccountState) setState(key common.Hash, value common.Hash) {
 
 // GetCommittedState retrieves a value from the committed account storage trie.
 func (as *AccountState) GetCommittedState(key common.Hash) (common.Hash, error) {
+	if as.flatState != nil {
+		return as.flatState.GetState(as.address, key)
+	}
+
 	res, err := as.StorageTree.Fetch(key)
 	if errors.Is(err, db.ErrKeyNotFound) {
 		return common.EmptyHash, nil
@@ -371,7 +378,16 @@ func (as *AccountState) Commit() (*types.SmartContract, error) {
 		return nil, err
 	}
 
-	acc := &types.SmartContract{
+	if err := db.WriteCode(as.db.GetRwTx(), as.address.ShardId(), as.CodeHash, as.Code); err != nil {
+		return nil, err
+	}
+
+	return as.smartContract(), nil
+}
+
+// smartContract returns the account as it is stored in the contract trie. The tries of the account must be committed.
+func (as *AccountState) smartContract() *types.SmartContract {
+	return &types.SmartContract{
 		Address:          as.address,
 		Balance:          as.Balance,
 		StorageRoot:      as.StorageTree.RootHash(),
@@ -381,10 +397,4 @@ func (as *AccountState) Commit() (*types.SmartContract, error) {
 		ExtSeqno:         as.ExtSeqno,
 		Seqno:            as.Seqno,
 	}
-
-	if err := db.WriteCode(as.db.GetRwTx(), as.address.ShardId(), as.CodeHash, as.Code); err != nil {
-		return nil, err
-	}
-
-	return acc, nil
 }
@@ -23,6 +23,11 @@ type BlockGeneratorParams struct {
 	DisableConsensus bool
 	FeeCalculator    FeeCalculator
 	ExecutionMode    string
+	// EnableFlatState makes the generated blocks update the flat state, see StateParams.WriteFlatState.
+	EnableFlatState bool
+	// AllowDevChanges allows the proposals to carry the changes requested via the development API.
+	// It must be set only on the nodes running the development API, the other nodes reject such proposals.
+	AllowDevChanges bool
 }
 
 func NewBlockGeneratorParams(shardId types.ShardId, nShards uint32) BlockGeneratorParams {
@@ -86,6 +91,7 @@ func NewBlockGenerator(
 		ConfigAccessor: configAccessor,
 		FeeCalculator:  params.FeeCalculator,
 		Mode:           params.ExecutionMode,
+		WriteFlatState: params.EnableFlatState,
 	})
 	if err != nil {
 		return nil, err
@@ -123,7 +129,11 @@ func (p *BlockGenerator) CollectGasPrices(prevBlockId types.BlockNumber) ([]type
 	if !p.params.ShardId.IsMainShard() {
 		return nil, nil
 	}
+	return collectGasPrices(p.rwTx, prevBlockId)
+}
 
+// collectGasPrices reads base fees of all shards as seen by the main shard block following prevBlockId.
+func collectGasPrices(tx db.RoTx, prevBlockId types.BlockNumber) ([]types.Uint256, error) {
 	// Basically we load configuration from block.MainShardHash.
 	// But for main shard this value should be block.PrevBlock.
 	// The first block uses configuration from itself.
@@ -132,12 +142,12 @@ func (p *BlockGenerator) CollectGasPrices(prevBlockId types.BlockNumber) ([]type
 		configBlockId--
 	}
 
-	mainBlock, err := db.ReadBlockByNumber(p.rwTx, types.MainShardId, configBlockId)
+	mainBlock, err := db.ReadBlockByNumber(tx, types.MainShardId, configBlockId)
 	if err != nil {
 		return nil, err
 	}
 
-	treeShards := NewDbShardBlocksTrieReader(p.rwTx, types.MainShardId, mainBlock.Id)
+	treeShards := NewDbShardBlocksTrieReader(tx, types.MainShardId, mainBlock.Id)
 	treeShards.SetRootHash(mainBlock.ChildBlocksRootHash)
 	shardHashes := make(map[types.ShardId]common.Hash)
 	for key, value := range treeShards.Iterate() {
@@ -158,7 +168,7 @@ func (p *BlockGenerator) CollectGasPrices(prevBlockId types.BlockNumber) ([]type
 			continue
 		}
 
-		block, err := db.ReadBlock(p.rwTx, shardId, shardHash)
+		block, err := db.ReadBlock(tx, shardId, shardHash)
 		if err != nil {
 			return nil, err
 		}
@@ -172,16 +182,19 @@ func (g *BlockGenerator) updateGasPrices(gasPrices []types.Uint256) error {
 	if !g.params.ShardId.IsMainShard() {
 		return nil
 	}
+	return updateGasPrices(g.executionState, gasPrices)
+}
 
+func updateGasPrices(es *ExecutionState, gasPrices []types.Uint256) error {
 	gasPriceParam := &config.ParamGasPrice{
 		Shards: gasPrices,
 	}
-	if err := config.SetParamGasPrice(g.executionState.GetConfigAccessor(), gasPriceParam); err != nil {
+	if err := config.SetParamGasPrice(es.GetConfigAccessor(), gasPriceParam); err != nil {
 		return fmt.Errorf("failed to set gas prices: %w", err)
 	}
 
 	// In main shard we don't need to update base fee.
-	g.executionState.BaseFee = types.DefaultGasPrice
+	es.BaseFee = types.DefaultGasPrice
 	return nil
 }
 
@@ -246,6 +259,15 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 	g.executionState.PatchLevel = proposal.PatchLevel
 	g.executionState.RollbackCounter = proposal.RollbackCounter
 
+	if proposal.HasDevChanges() && !g.params.AllowDevChanges {
+		return ErrDevChangesNotAllowed
+	}
+	if err := g.executionState.ApplyDevChanges(
+		proposal.AccountOverrides, proposal.Impersonated, proposal.TimeOffset,
+	); err != nil {
+		return err
+	}
+
 	for _, txn := range proposal.InternalTxns {
 		if err := g.handleTxn(txn); err != nil {
 			return err
@@ -0,0 +1,159 @@
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// ContractBundle is the state of a contract exported from one network to be imported into the zero state of another.
+// The state is taken at the given block and is accompanied by the proof of the account inclusion
+// into the contract trie of the block, so the bundle can be checked against the source network.
+// Async contexts of the pending requests are not exported, they make no sense in another network.
+type ContractBundle struct {
+	Address types.Address `yaml:"address" json:"address"`
+
+	BlockHash   common.Hash       `yaml:"blockHash" json:"blockHash"`
+	BlockNumber types.BlockNumber `yaml:"blockNumber" json:"blockNumber"`
+	// SmartContractsRoot of the block.
+	ContractsRoot common.Hash `yaml:"contractsRoot" json:"contractsRoot"`
+
+	Balance  types.Value                   `yaml:"balance" json:"balance"`
+	Seqno    types.Seqno                   `yaml:"seqno" json:"seqno"`
+	ExtSeqno types.Seqno                   `yaml:"extSeqno" json:"extSeqno"`
+	Code     hexutil.Bytes                 `yaml:"code" json:"code"`
+	Storage  map[common.Hash]types.Uint256 `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Tokens   map[types.TokenId]types.Value `yaml:"tokens,omitempty" json:"tokens,omitempty"`
+
+	// SSZ-encoded account as it is stored in the contract trie.
+	Contract hexutil.Bytes `yaml:"contract" json:"contract"`
+	// Encoded MPT proof of reading the account from the contract trie.
+	Proof hexutil.Bytes `yaml:"proof" json:"proof"`
+}
+
+// LoadContractBundle reads the JSON-encoded bundle from the file.
+func LoadContractBundle(fname string) (*ContractBundle, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	var bundle ContractBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode contract bundle %s: %w", fname, err)
+	}
+	return &bundle, nil
+}
+
+// Verify checks that the bundle is consistent: the account matches the exported code, storage, tokens and balances,
+// and the proof shows it is included into the contract trie with ContractsRoot.
+func (b *ContractBundle) Verify() error {
+	var contract types.SmartContract
+	if err := contract.UnmarshalSSZ(b.Contract); err != nil {
+		return fmt.Errorf("failed to decode account: %w", err)
+	}
+
+	switch {
+	case contract.Address != b.Address:
+		return fmt.Errorf("account address %s doesn't match %s", contract.Address, b.Address)
+	case !contract.Balance.Eq(b.Balance):
+		return fmt.Errorf("account balance %s doesn't match %s", contract.Balance, b.Balance)
+	case contract.Seqno != b.Seqno || contract.ExtSeqno != b.ExtSeqno:
+		return errors.New("account seqno doesn't match")
+	case contract.CodeHash != types.Code(b.Code).Hash():
+		return errors.New("account code hash doesn't match")
+	}
+
+	storageRoot, err := bundleStorageRoot(b.Storage)
+	if err != nil {
+		return err
+	}
+	if storageRoot != contract.StorageRoot {
+		return errors.New("account storage root doesn't match")
+	}
+
+	tokenRoot, err := bundleTokenRoot(b.Tokens)
+	if err != nil {
+		return err
+	}
+	if tokenRoot != contract.TokenRoot {
+		return errors.New("account token root doesn't match")
+	}
+
+	proof, err := mpt.DecodeProof(b.Proof)
+	if err != nil {
+		return fmt.Errorf("failed to decode proof: %w", err)
+	}
+	ok, err := proof.VerifyRead(b.Address.Hash().Bytes(), b.Contract, b.ContractsRoot)
+	if err != nil {
+		return fmt.Errorf("failed to verify proof: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("account is not included into the contract trie of block %s", b.BlockHash)
+	}
+	return nil
+}
+
+func bundleStorageRoot(storage map[common.Hash]types.Uint256) (common.Hash, error) {
+	trie := NewStorageTrie(mpt.NewInMemMPT())
+	for key, value := range storage {
+		if err := trie.Update(key, &value); err != nil {
+			return common.EmptyHash, err
+		}
+	}
+	return trie.RootHash(), nil
+}
+
+func bundleTokenRoot(tokens map[types.TokenId]types.Value) (common.Hash, error) {
+	trie := NewTokenTrie(mpt.NewInMemMPT())
+	for id, value := range tokens {
+		if err := trie.Update(id, &value); err != nil {
+			return common.EmptyHash, err
+		}
+	}
+	return trie.RootHash(), nil
+}
+
+// importContract creates the account from the bundle in the zero state.
+func (es *ExecutionState) importContract(bundle *ContractBundle) error {
+	if err := bundle.Verify(); err != nil {
+		return fmt.Errorf("invalid bundle of %s: %w", bundle.Address, err)
+	}
+
+	if err := es.CreateAccount(bundle.Address); err != nil {
+		return err
+	}
+	if err := es.CreateContract(bundle.Address); err != nil {
+		return err
+	}
+	if err := es.SetCode(bundle.Address, bundle.Code); err != nil {
+		return err
+	}
+	if err := es.SetBalance(bundle.Address, bundle.Balance); err != nil {
+		return err
+	}
+	if err := es.SetSeqno(bundle.Address, bundle.Seqno); err != nil {
+		return err
+	}
+	if err := es.SetExtSeqno(bundle.Address, bundle.ExtSeqno); err != nil {
+		return err
+	}
+	for key, value := range bundle.Storage {
+		if err := es.SetState(bundle.Address, key, value.Bytes32()); err != nil {
+			return err
+		}
+	}
+	acc, err := es.GetAccount(bundle.Address)
+	if err != nil {
+		return err
+	}
+	for id, value := range bundle.Tokens {
+		acc.SetTokenBalance(id, value)
+	}
+	return nil
+}
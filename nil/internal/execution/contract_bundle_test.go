@@ -0,0 +1,138 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// exportTestContract commits the contract to the new database and exports it the way `nil contract export` does.
+func exportTestContract(t *testing.T, addr types.Address, token types.TokenId) (*ContractBundle, *types.SmartContract) {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	shardId := addr.ShardId()
+	es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+
+	code := types.Code("some code")
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetCode(addr, code))
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(100)))
+	require.NoError(t, es.SetSeqno(addr, 3))
+	require.NoError(t, es.SetExtSeqno(addr, 5))
+	require.NoError(t, es.SetState(addr, common.HexToHash("0x01"), common.HexToHash("0x02")))
+	acc, err := es.GetAccount(addr)
+	require.NoError(t, err)
+	acc.SetTokenBalance(token, types.NewValueFromUint64(7))
+
+	res, err := es.Commit(0, nil)
+	require.NoError(t, err)
+
+	contracts := mpt.NewDbReader(tx, shardId, db.ContractTrieTable)
+	contracts.SetRootHash(res.Block.SmartContractsRoot)
+	key := addr.Hash().Bytes()
+	contractRaw, err := contracts.Get(key)
+	require.NoError(t, err)
+	proof, err := mpt.BuildProof(contracts, key, mpt.ReadMPTOperation)
+	require.NoError(t, err)
+	proofEncoded, err := proof.Encode()
+	require.NoError(t, err)
+
+	var contract types.SmartContract
+	require.NoError(t, contract.UnmarshalSSZ(contractRaw))
+
+	return &ContractBundle{
+		Address:       addr,
+		BlockHash:     res.BlockHash,
+		BlockNumber:   res.Block.Id,
+		ContractsRoot: res.Block.SmartContractsRoot,
+		Balance:       contract.Balance,
+		Seqno:         contract.Seqno,
+		ExtSeqno:      contract.ExtSeqno,
+		Code:          []byte(code),
+		Storage:       map[common.Hash]types.Uint256{common.HexToHash("0x01"): *types.NewUint256(2)},
+		Tokens:        map[types.TokenId]types.Value{token: types.NewValueFromUint64(7)},
+		Contract:      contractRaw,
+		Proof:         proofEncoded,
+	}, &contract
+}
+
+func TestContractBundle(t *testing.T) {
+	t.Parallel()
+
+	shardId := types.BaseShardId
+	addr := types.GenerateRandomAddress(shardId)
+	token := types.TokenId(types.GenerateRandomAddress(shardId))
+	bundle, exported := exportTestContract(t, addr, token)
+	require.NoError(t, bundle.Verify())
+
+	t.Run("Inconsistent", func(t *testing.T) {
+		t.Parallel()
+
+		wrongBalance := *bundle
+		wrongBalance.Balance = types.NewValueFromUint64(1000)
+		require.Error(t, wrongBalance.Verify())
+
+		wrongStorage := *bundle
+		wrongStorage.Storage = map[common.Hash]types.Uint256{common.HexToHash("0x01"): *types.NewUint256(3)}
+		require.Error(t, wrongStorage.Verify())
+
+		wrongRoot := *bundle
+		wrongRoot.ContractsRoot = common.HexToHash("0x01")
+		require.Error(t, wrongRoot.Verify())
+	})
+
+	t.Run("Yaml", func(t *testing.T) {
+		t.Parallel()
+
+		// The zero state config is sent to the other nodes in YAML.
+		data, err := yaml.Marshal(&ZeroStateConfig{ImportedContracts: []*ContractBundle{bundle}})
+		require.NoError(t, err)
+
+		var decoded ZeroStateConfig
+		require.NoError(t, yaml.Unmarshal(data, &decoded))
+		require.Len(t, decoded.ImportedContracts, 1)
+		require.NoError(t, decoded.ImportedContracts[0].Verify())
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		t.Parallel()
+
+		database, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		defer database.Close()
+
+		tx, err := database.CreateRwTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+		require.NoError(t, err)
+		require.NoError(t, es.GenerateZeroState(&ZeroStateConfig{ImportedContracts: []*ContractBundle{bundle}}))
+		_, err = es.Commit(0, nil)
+		require.NoError(t, err)
+
+		acc, err := es.GetAccount(addr)
+		require.NoError(t, err)
+		require.Equal(t, exported.StorageRoot, acc.StorageTree.RootHash())
+		require.Equal(t, exported.TokenRoot, acc.TokenTree.RootHash())
+		require.Equal(t, exported.CodeHash, acc.CodeHash)
+		require.Equal(t, exported.Seqno, acc.Seqno)
+		require.Equal(t, exported.ExtSeqno, acc.ExtSeqno)
+		require.True(t, exported.Balance.Eq(acc.Balance))
+	})
+}
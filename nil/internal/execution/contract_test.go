@@ -9,6 +9,7 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
 	"github.com/NilFoundation/nil/nil/tools/solc"
@@ -102,6 +103,42 @@ func TestPrecompiles(t *testing.T) {
 	}
 }
 
+func TestVerifyBlsSignature(t *testing.T) {
+	t.Parallel()
+
+	state := newState(t)
+	defer state.tx.Rollback()
+	require.NoError(t, state.newVm(true, types.EmptyAddress))
+
+	key := bls.NewRandomKey()
+	pubkey, err := key.PublicKey().Marshal()
+	require.NoError(t, err)
+	hash := common.HexToHash("0x1234")
+	sig, err := key.Sign(hash.Bytes())
+	require.NoError(t, err)
+	sigBytes, err := sig.Marshal()
+	require.NoError(t, err)
+
+	verify := func(pubkey []byte, hash common.Hash, sig []byte) bool {
+		t.Helper()
+
+		input, err := vm.VerifySignatureArgs().Pack(pubkey, hash.Big(), sig)
+		require.NoError(t, err)
+		ret, _, err := state.evm.Call(
+			vm.AccountRef(types.EmptyAddress), vm.VerifyBlsSignatureAddress, input, 1_000_000, new(uint256.Int))
+		require.NoError(t, err)
+		return ret[len(ret)-1] == 1
+	}
+
+	require.True(t, verify(pubkey, hash, sigBytes))
+	require.False(t, verify(pubkey, common.HexToHash("0x4321"), sigBytes))
+
+	invalidKey := slices.Clone(pubkey)
+	invalidKey[0] ^= 0xff
+	require.False(t, verify(invalidKey, hash, sigBytes))
+	require.False(t, verify(pubkey[:64], hash, sigBytes))
+}
+
 func toGasCredit(gas types.Gas) types.Value {
 	return gas.ToValue(types.DefaultGasPrice)
 }
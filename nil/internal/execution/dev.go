@@ -0,0 +1,118 @@
+package execution
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+var ErrDevChangesNotAllowed = errors.New("development API changes are not allowed in the proposal")
+
+// HasDevChanges returns true if the proposal carries the changes requested via the development API.
+func (p *Proposal) HasDevChanges() bool {
+	return len(p.AccountOverrides) > 0 || len(p.Impersonated) > 0 || p.TimeOffset != 0
+}
+
+func (o *AccountOverride) Equal(other *AccountOverride) bool {
+	return o.Kind == other.Kind &&
+		o.Address == other.Address &&
+		o.Balance.Cmp(other.Balance) == 0 &&
+		o.Token == other.Token &&
+		bytes.Equal(o.Code, other.Code) &&
+		o.Key == other.Key &&
+		o.Value == other.Value
+}
+
+// ApplyDevChanges applies the changes requested via the development API before the transactions of a block.
+func (es *ExecutionState) ApplyDevChanges(
+	overrides []*AccountOverride,
+	impersonated []types.Address,
+	timeOffset uint64,
+) error {
+	es.TimeOffset = timeOffset
+	es.impersonated = impersonated
+	es.devChanges = nil
+	if len(overrides) > 0 || len(impersonated) > 0 || timeOffset != 0 {
+		es.devChanges = &DevChanges{
+			AccountOverrides: overrides,
+			Impersonated:     impersonated,
+			TimeOffset:       timeOffset,
+		}
+	}
+
+	for _, o := range overrides {
+		if err := es.applyAccountOverride(o); err != nil {
+			return fmt.Errorf("failed to override %s of %s: %w", o.Kind, o.Address, err)
+		}
+	}
+	return nil
+}
+
+func (es *ExecutionState) applyAccountOverride(o *AccountOverride) error {
+	acc, err := es.getOrNewAccount(o.Address)
+	if err != nil {
+		return err
+	}
+
+	switch o.Kind {
+	case OverrideBalance:
+		acc.SetBalance(o.Balance)
+	case OverrideCode:
+		acc.SetCode(o.Code.Hash(), o.Code)
+	case OverrideStorage:
+		return acc.SetState(o.Key, o.Value)
+	case OverrideTokenBalance:
+		acc.SetTokenBalance(o.Token, o.Balance)
+	default:
+		return fmt.Errorf("unknown override kind %d", o.Kind)
+	}
+	return nil
+}
+
+// isImpersonated returns true if external transactions to the address are accepted without verifyExternal.
+func (es *ExecutionState) isImpersonated(address types.Address) bool {
+	return slices.Contains(es.impersonated, address)
+}
+
+// ApplyBlockDevChanges applies the development API changes stored with the block.
+// It must be called before replaying the transactions of the block on the state of its previous block.
+func (es *ExecutionState) ApplyBlockDevChanges(blockHash common.Hash) error {
+	changes, err := ReadDevChanges(es.tx, es.ShardId, blockHash)
+	if err != nil {
+		return err
+	}
+	if changes == nil {
+		return nil
+	}
+	return es.ApplyDevChanges(changes.AccountOverrides, changes.Impersonated, changes.TimeOffset)
+}
+
+func writeDevChanges(tx db.RwTx, shardId types.ShardId, blockHash common.Hash, changes *DevChanges) error {
+	data, err := changes.MarshalSSZ()
+	if err != nil {
+		return err
+	}
+	return tx.PutToShard(shardId, db.DevChangesTable, blockHash.Bytes(), data)
+}
+
+// ReadDevChanges returns the development API changes applied in the block or nil if there were none.
+func ReadDevChanges(tx db.RoTx, shardId types.ShardId, blockHash common.Hash) (*DevChanges, error) {
+	data, err := tx.GetFromShard(shardId, db.DevChangesTable, blockHash.Bytes())
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes := new(DevChanges)
+	if err := changes.UnmarshalSSZ(data); err != nil {
+		return nil, fmt.Errorf("failed to decode development API changes of block %s: %w", blockHash, err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,307 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// The flat state maps the accounts and the storage slots of a shard to their values in the state of a single block,
+// the flat state head. The readers of the head state get the values by a single lookup instead of the trie traversal.
+//
+// The flat state is updated by CommitBlock of the blocks extending the head, so it follows the chain from the zero
+// state. It is left behind if the chain is switched without the execution (the snapshot sync, the rewinds of the
+// development API) or if it was disabled for a while, and the readers fall back to the tries then, until
+// RebuildFlatState fills it from the tries of the last block.
+// The tries remain the source of truth: the flat state is not used to build the blocks.
+
+// flatStateBatchSize is the number of the flat state entries written by a single transaction of the rebuild.
+const flatStateBatchSize = 10_000
+
+func flatAccountKey(addr types.Address) []byte {
+	return addr.Bytes()
+}
+
+func flatStorageKey(addr types.Address, key common.Hash) []byte {
+	return append(addr.Bytes(), key.Bytes()...)
+}
+
+type FlatStateReader struct {
+	tx      db.RoTx
+	shardId types.ShardId
+}
+
+// NewFlatStateReader returns the reader of the state of the block if the flat state holds it, nil otherwise.
+func NewFlatStateReader(tx db.RoTx, shardId types.ShardId, blockHash common.Hash) (*FlatStateReader, error) {
+	head, err := db.ReadFlatStateHead(tx, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flat state head: %w", err)
+	}
+	if head.Empty() || head != blockHash {
+		return nil, nil
+	}
+	return &FlatStateReader{tx: tx, shardId: shardId}, nil
+}
+
+// GetContract returns db.ErrKeyNotFound if the account doesn't exist.
+func (r *FlatStateReader) GetContract(addr types.Address) (*types.SmartContract, error) {
+	data, err := r.tx.GetFromShard(r.shardId, db.FlatStateTable, flatAccountKey(addr))
+	if err != nil {
+		return nil, err
+	}
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(data); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// GetState returns the empty hash for the unset slots.
+func (r *FlatStateReader) GetState(addr types.Address, key common.Hash) (common.Hash, error) {
+	data, err := r.tx.GetFromShard(r.shardId, db.FlatStateTable, flatStorageKey(addr, key))
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return common.EmptyHash, nil
+	}
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return common.BytesToHash(data), nil
+}
+
+// flatStateUpdate holds the changes of the flat state made by a block.
+type flatStateUpdate struct {
+	contracts map[types.Address]*types.SmartContract
+	// storage holds the changed slots, the empty values are removed.
+	storage map[types.Address]map[common.Hash]common.Hash
+}
+
+// collectFlatStorage returns the storage slots changed in the state. It must be called before the accounts are
+// committed, since the commit drops the emptied slots from the accounts.
+func (es *ExecutionState) collectFlatStorage() map[types.Address]map[common.Hash]common.Hash {
+	storage := make(map[types.Address]map[common.Hash]common.Hash)
+	for _, entry := range es.journal.entries {
+		ch, ok := entry.(storageChange)
+		if !ok {
+			continue
+		}
+		acc, ok := es.Accounts[*ch.account]
+		if !ok {
+			continue
+		}
+		slots, ok := storage[*ch.account]
+		if !ok {
+			slots = make(map[common.Hash]common.Hash)
+			storage[*ch.account] = slots
+		}
+		slots[ch.key] = acc.State[ch.key]
+	}
+	return storage
+}
+
+// collectFlatContracts returns the committed accounts of the state.
+func (es *ExecutionState) collectFlatContracts() map[types.Address]*types.SmartContract {
+	contracts := make(map[types.Address]*types.SmartContract, len(es.Accounts))
+	for addr, acc := range es.Accounts {
+		contracts[addr] = acc.smartContract()
+	}
+	return contracts
+}
+
+// commitFlatState applies the changes of the block to the flat state if the block extends its head.
+func (es *ExecutionState) commitFlatState(block *types.Block, blockHash common.Hash) error {
+	head, err := db.ReadFlatStateHead(es.tx, es.ShardId)
+	if err != nil {
+		return fmt.Errorf("failed to read flat state head: %w", err)
+	}
+	if head != block.PrevBlock {
+		es.logger.Trace().
+			Stringer("head", head).
+			Msg("Flat state is not updated: the block doesn't extend its head")
+		return nil
+	}
+
+	for addr, contract := range es.flatStateUpdate.contracts {
+		data, err := contract.MarshalSSZ()
+		if err != nil {
+			return err
+		}
+		if err := es.tx.PutToShard(es.ShardId, db.FlatStateTable, flatAccountKey(addr), data); err != nil {
+			return err
+		}
+	}
+	for addr, slots := range es.flatStateUpdate.storage {
+		for key, value := range slots {
+			var err error
+			if value.Empty() {
+				err = es.tx.DeleteFromShard(es.ShardId, db.FlatStateTable, flatStorageKey(addr, key))
+			} else {
+				err = es.tx.PutToShard(es.ShardId, db.FlatStateTable, flatStorageKey(addr, key), value.Bytes())
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return db.WriteFlatStateHead(es.tx, es.ShardId, blockHash)
+}
+
+// flatStateBatch writes the flat state by the transactions of at most flatStateBatchSize entries.
+type flatStateBatch struct {
+	ctx      context.Context
+	database db.DB
+	tx       db.RwTx
+	size     int
+}
+
+func (b *flatStateBatch) write(f func(tx db.RwTx) error) error {
+	if b.tx == nil {
+		var err error
+		if b.tx, err = b.database.CreateRwTx(b.ctx); err != nil {
+			return err
+		}
+	}
+	if err := f(b.tx); err != nil {
+		return err
+	}
+	b.size++
+	if b.size < flatStateBatchSize {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *flatStateBatch) flush() error {
+	if b.tx == nil {
+		return nil
+	}
+	err := b.tx.Commit()
+	b.tx, b.size = nil, 0
+	return err
+}
+
+func (b *flatStateBatch) rollback() {
+	if b.tx != nil {
+		b.tx.Rollback()
+	}
+}
+
+// RebuildFlatState fills the flat state of the shard from the tries of the last block unless it already holds
+// the state of that block. The head is cleared for the time of the rebuild, so the readers use the tries meanwhile.
+// The blocks must not be committed concurrently: the head is set only if the last block is the same at the end.
+func RebuildFlatState(ctx context.Context, database db.DB, shardId types.ShardId) error {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	block, blockHash, err := db.ReadLastBlock(tx, shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	head, err := db.ReadFlatStateHead(tx, shardId)
+	if err != nil {
+		return fmt.Errorf("failed to read flat state head: %w", err)
+	}
+	if head == blockHash {
+		return nil
+	}
+
+	logger := logging.NewLogger("flat_state").With().Stringer(logging.FieldShardId, shardId).Logger()
+	logger.Info().
+		Uint64(logging.FieldBlockNumber, uint64(block.Id)).
+		Msg("Rebuilding flat state")
+
+	batch := &flatStateBatch{ctx: ctx, database: database}
+	defer batch.rollback()
+
+	if err := batch.write(func(tx db.RwTx) error {
+		return db.WriteFlatStateHead(tx, shardId, common.EmptyHash)
+	}); err != nil {
+		return err
+	}
+	if err := batch.flush(); err != nil {
+		return err
+	}
+
+	// The entries of the old head are dropped, since the ones missing in the new state would be left otherwise.
+	it, err := tx.RangeByShard(shardId, db.FlatStateTable, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		key, _, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err := batch.write(func(tx db.RwTx) error {
+			return tx.DeleteFromShard(shardId, db.FlatStateTable, key)
+		}); err != nil {
+			return err
+		}
+	}
+
+	contracts := NewDbContractTrieReader(tx, shardId)
+	contracts.SetRootHash(block.SmartContractsRoot)
+	entries := 0
+	if err := contracts.Walk(nil, func(_, data []byte) (bool, error) {
+		var contract types.SmartContract
+		if err := contract.UnmarshalSSZ(data); err != nil {
+			return false, err
+		}
+		if err := batch.write(func(tx db.RwTx) error {
+			return tx.PutToShard(shardId, db.FlatStateTable, flatAccountKey(contract.Address), data)
+		}); err != nil {
+			return false, err
+		}
+		entries++
+
+		storage := NewDbStorageTrieReader(tx, shardId)
+		storage.SetRootHash(contract.StorageRoot)
+		return true, storage.Walk(nil, func(key, data []byte) (bool, error) {
+			var value types.Uint256
+			if err := value.UnmarshalSSZ(data); err != nil {
+				return false, err
+			}
+			slot := value.Bytes32()
+			entries++
+			return true, batch.write(func(tx db.RwTx) error {
+				return tx.PutToShard(
+					shardId, db.FlatStateTable, flatStorageKey(contract.Address, common.BytesToHash(key)), slot[:])
+			})
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to read state of block %d: %w", block.Id, err)
+	}
+
+	if err := batch.write(func(tx db.RwTx) error {
+		last, err := db.ReadLastBlockHash(tx, shardId)
+		if err != nil {
+			return err
+		}
+		if last != blockHash {
+			return fmt.Errorf("last block changed during the flat state rebuild: %s", last)
+		}
+		return db.WriteFlatStateHead(tx, shardId, blockHash)
+	}); err != nil {
+		return err
+	}
+	if err := batch.flush(); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Uint64(logging.FieldBlockNumber, uint64(block.Id)).
+		Int("entries", entries).
+		Msg("Flat state rebuilt")
+	return nil
+}
@@ -0,0 +1,264 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatState(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	addr := types.GenerateRandomAddress(shardId)
+	key1, key2 := common.IntToHash(1), common.IntToHash(2)
+
+	commit := func(prevBlock *types.Block, blockId types.BlockNumber, change func(es *ExecutionState)) *types.Block {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+			WriteFlatState: true,
+		})
+		require.NoError(t, err)
+		change(es)
+
+		res, err := es.Commit(blockId, nil)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+		return res.Block
+	}
+
+	readOnly := func(t *testing.T, block *types.Block) *ExecutionState {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(t.Context())
+		require.NoError(t, err)
+		t.Cleanup(tx.Rollback)
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          block,
+			ConfigAccessor: config.GetStubAccessor(),
+			ReadFlatState:  true,
+		})
+		require.NoError(t, err)
+		return es
+	}
+
+	head := func() common.Hash {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		hash, err := db.ReadFlatStateHead(tx, shardId)
+		require.NoError(t, err)
+		return hash
+	}
+
+	checkState := func(t *testing.T, es *ExecutionState, balance uint64, value1, value2 common.Hash) {
+		t.Helper()
+
+		actualBalance, err := es.GetBalance(addr)
+		require.NoError(t, err)
+		require.Equal(t, types.NewValueFromUint64(balance), actualBalance)
+
+		value, err := es.GetState(addr, key1)
+		require.NoError(t, err)
+		require.Equal(t, value1, value)
+
+		value, err = es.GetState(addr, key2)
+		require.NoError(t, err)
+		require.Equal(t, value2, value)
+	}
+
+	block0 := commit(nil, 0, func(es *ExecutionState) {
+		require.NoError(t, es.CreateAccount(addr))
+		require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(100)))
+		require.NoError(t, es.SetState(addr, key1, common.IntToHash(10)))
+		require.NoError(t, es.SetState(addr, key2, common.IntToHash(20)))
+	})
+	require.Equal(t, block0.Hash(shardId), head())
+
+	block1 := commit(block0, 1, func(es *ExecutionState) {
+		require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(200)))
+		require.NoError(t, es.SetState(addr, key1, common.EmptyHash))
+		require.NoError(t, es.SetState(addr, key2, common.IntToHash(30)))
+	})
+	require.Equal(t, block1.Hash(shardId), head())
+
+	t.Run("Head", func(t *testing.T) {
+		es := readOnly(t, block1)
+		require.NotNil(t, es.flatState)
+		checkState(t, es, 200, common.EmptyHash, common.IntToHash(30))
+
+		// The flat state holds the same accounts as the contract trie.
+		contract, err := es.flatState.GetContract(addr)
+		require.NoError(t, err)
+		trie := NewDbContractTrieReader(es.tx, shardId)
+		trie.SetRootHash(block1.SmartContractsRoot)
+		expected, err := trie.Fetch(addr.Hash())
+		require.NoError(t, err)
+		require.Equal(t, expected, contract)
+
+		// The emptied slot is removed.
+		exists, err := es.tx.ExistsInShard(shardId, db.FlatStateTable, flatStorageKey(addr, key1))
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		_, err = es.flatState.GetContract(types.GenerateRandomAddress(shardId))
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("PreviousBlock", func(t *testing.T) {
+		es := readOnly(t, block0)
+		require.Nil(t, es.flatState)
+		checkState(t, es, 100, common.IntToHash(10), common.IntToHash(20))
+	})
+
+	t.Run("Fork", func(t *testing.T) {
+		fork := commit(block0, 1, func(es *ExecutionState) {
+			require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(300)))
+		})
+		require.Equal(t, block1.Hash(shardId), head())
+
+		es := readOnly(t, fork)
+		require.Nil(t, es.flatState)
+		checkState(t, es, 300, common.IntToHash(10), common.IntToHash(20))
+	})
+}
+
+func TestRebuildFlatState(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	// The read-only transactions are rolled back by the cleanups, so the database is closed after them.
+	t.Cleanup(database.Close)
+
+	addr := types.GenerateRandomAddress(shardId)
+	staleAddr := types.GenerateRandomAddress(shardId)
+
+	// The state of a node that starts from a snapshot: the tries and the last block are there, the flat state is not.
+	commit := func(prevBlock *types.Block, blockId types.BlockNumber, change func(es *ExecutionState)) *types.Block {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+			WriteFlatState: true,
+		})
+		require.NoError(t, err)
+		change(es)
+
+		res, err := es.Commit(blockId, nil)
+		require.NoError(t, err)
+		hash := res.Block.Hash(shardId)
+		require.NoError(t, db.WriteBlock(tx, shardId, hash, res.Block))
+		require.NoError(t, db.WriteLastBlockHash(tx, shardId, hash))
+		require.NoError(t, tx.Commit())
+		return res.Block
+	}
+
+	update := func(f func(tx db.RwTx) error) {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+		require.NoError(t, f(tx))
+		require.NoError(t, tx.Commit())
+	}
+
+	// The entries of some other block are left in the table.
+	update(func(tx db.RwTx) error {
+		if err := tx.PutToShard(shardId, db.FlatStateTable, flatAccountKey(staleAddr), []byte("stale")); err != nil {
+			return err
+		}
+		return db.WriteFlatStateHead(tx, shardId, common.IntToHash(1))
+	})
+
+	block0 := commit(nil, 0, func(es *ExecutionState) {
+		require.NoError(t, es.CreateAccount(addr))
+		require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(100)))
+		require.NoError(t, es.SetState(addr, common.IntToHash(1), common.IntToHash(10)))
+		require.NoError(t, es.SetState(addr, common.IntToHash(2), common.IntToHash(20)))
+	})
+
+	readOnly := func(t *testing.T, block *types.Block) *ExecutionState {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(t.Context())
+		require.NoError(t, err)
+		t.Cleanup(tx.Rollback)
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          block,
+			ConfigAccessor: config.GetStubAccessor(),
+			ReadFlatState:  true,
+		})
+		require.NoError(t, err)
+		return es
+	}
+
+	// The block doesn't extend the head, so the flat state is left behind.
+	require.Nil(t, readOnly(t, block0).flatState)
+
+	require.NoError(t, RebuildFlatState(t.Context(), database, shardId))
+
+	es := readOnly(t, block0)
+	require.NotNil(t, es.flatState)
+	balance, err := es.GetBalance(addr)
+	require.NoError(t, err)
+	require.Equal(t, types.NewValueFromUint64(100), balance)
+	for i, expected := range []common.Hash{common.EmptyHash, common.IntToHash(10), common.IntToHash(20)} {
+		value, err := es.flatState.GetState(addr, common.IntToHash(i))
+		require.NoError(t, err)
+		require.Equal(t, expected, value)
+	}
+	_, err = es.flatState.GetContract(staleAddr)
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	// The rebuilt flat state is updated by the next blocks.
+	block1 := commit(block0, 1, func(es *ExecutionState) {
+		require.NoError(t, es.SetState(addr, common.IntToHash(1), common.EmptyHash))
+	})
+	es = readOnly(t, block1)
+	require.NotNil(t, es.flatState)
+	value, err := es.flatState.GetState(addr, common.IntToHash(1))
+	require.NoError(t, err)
+	require.Equal(t, common.EmptyHash, value)
+
+	// The flat state of the last block is not rebuilt again.
+	update(func(tx db.RwTx) error {
+		return tx.PutToShard(shardId, db.FlatStateTable, flatAccountKey(staleAddr), []byte("kept"))
+	})
+	require.NoError(t, RebuildFlatState(t.Context(), database, shardId))
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	data, err := tx.GetFromShard(shardId, db.FlatStateTable, flatAccountKey(staleAddr))
+	require.NoError(t, err)
+	require.Equal(t, []byte("kept"), data)
+}
@@ -30,6 +30,46 @@ type InternalTxnReference struct {
 	TxnIndex         types.TransactionIndex
 }
 
+type AccountOverrideKind uint8
+
+const (
+	OverrideBalance AccountOverrideKind = iota
+	OverrideCode
+	OverrideStorage
+	OverrideTokenBalance
+)
+
+func (k AccountOverrideKind) String() string {
+	switch k {
+	case OverrideBalance:
+		return "balance"
+	case OverrideCode:
+		return "code"
+	case OverrideStorage:
+		return "storage"
+	case OverrideTokenBalance:
+		return "tokenBalance"
+	}
+	return fmt.Sprintf("AccountOverrideKind(%d)", k)
+}
+
+// AccountOverride is a change of an account requested via the development API.
+// Overrides of a proposal are applied in order before its transactions.
+// They are not a part of the block, so blocks with overrides can't be replayed by other nodes,
+// the node that generated the block stores them separately (see DevChanges).
+// The proposals with overrides are accepted only by the nodes running the development API, see HasDevChanges.
+type AccountOverride struct {
+	Kind    AccountOverrideKind `json:"kind"`
+	Address types.Address       `json:"address"`
+
+	// Balance is the new balance of the account or of the token.
+	Balance types.Value   `json:"balance" ssz-size:"32"`
+	Token   types.TokenId `json:"token" ssz-size:"20"`
+	Code    types.Code    `json:"code,omitempty" ssz-max:"24576"`
+	Key     common.Hash   `json:"key"`
+	Value   common.Hash   `json:"value"`
+}
+
 type Proposal struct {
 	PrevBlockId     types.BlockNumber   `json:"prevBlockId"`
 	PrevBlockHash   common.Hash         `json:"prevBlockHash"`
@@ -42,6 +82,11 @@ type Proposal struct {
 	InternalTxns []*types.Transaction `json:"internalTxns"`
 	ExternalTxns []*types.Transaction `json:"externalTxns"`
 	ForwardTxns  []*types.Transaction `json:"forwardTxns"`
+
+	// Changes requested via the development API.
+	AccountOverrides []*AccountOverride `json:"accountOverrides,omitempty"`
+	Impersonated     []types.Address    `json:"impersonated,omitempty"`
+	TimeOffset       uint64             `json:"timeOffset,omitempty"`
 }
 
 type ProposalSSZ struct {
@@ -65,6 +110,20 @@ type ProposalSSZ struct {
 
 	// SpecialTxns are internal transactions produced by the collator. They appear only on the main shard.
 	SpecialTxns []*types.Transaction `ssz-max:"4096"`
+
+	// Changes requested via the development API. They are empty on regular networks,
+	// the validators without the development API reject the proposals carrying them.
+	AccountOverrides []*AccountOverride `ssz-max:"4096"`
+	Impersonated     []types.Address    `ssz-max:"4096"`
+	TimeOffset       uint64
+}
+
+// DevChanges are the changes requested via the development API that are applied before the transactions of a block.
+// They are stored along with the block, so that it can be replayed, see ReadDevChanges.
+type DevChanges struct {
+	AccountOverrides []*AccountOverride `ssz-max:"4096"`
+	Impersonated     []types.Address    `ssz-max:"4096"`
+	TimeOffset       uint64
 }
 
 func NewParentBlock(shardId types.ShardId, block *types.Block) *ParentBlock {
@@ -180,5 +239,9 @@ func ConvertProposal(proposal *ProposalSSZ) (*Proposal, error) {
 		InternalTxns: append(proposal.SpecialTxns, internalTxns...),
 		ExternalTxns: proposal.ExternalTxns,
 		ForwardTxns:  forwardTxns,
+
+		AccountOverrides: proposal.AccountOverrides,
+		Impersonated:     proposal.Impersonated,
+		TimeOffset:       proposal.TimeOffset,
 	}, nil
 }
@@ -0,0 +1,118 @@
+package execution
+
+import (
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// protocolRules are the execution rules that differ between the protocol versions.
+// The instruction set and the precompiled contracts are selected by the VM itself.
+type protocolRules struct {
+	// feeCalculator calculates the base fee of the block unless StateParams.FeeCalculator is set.
+	feeCalculator FeeCalculator
+	// validateExternal checks an external transaction before it is executed.
+	validateExternal func(es *ExecutionState, txn *types.Transaction) *ExecutionResult
+}
+
+// protocolRulesChanges holds the rules of the protocol versions that change them.
+var protocolRulesChanges = map[params.ProtocolVersion]*protocolRules{
+	params.ProtocolGenesis: {
+		feeCalculator:    &MainFeeCalculator{},
+		validateExternal: validateExternalTransactionGenesis,
+	},
+}
+
+func getProtocolRules(version params.ProtocolVersion) (*protocolRules, error) {
+	if _, err := params.GetProtocol(version); err != nil {
+		return nil, err
+	}
+	rules, ok := params.ResolveProtocolChanges(protocolRulesChanges, version)
+	if !ok {
+		return nil, fmt.Errorf("no execution rules for protocol version %d", version)
+	}
+	return rules, nil
+}
+
+// CalculateNextBaseFee returns the base fee of the block following prevBlock.
+// The fee calculator is taken from the rules of the protocol version of that block.
+func CalculateNextBaseFee(tx db.RoTx, shardId types.ShardId, prevBlock *types.Block) (types.Value, error) {
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+	version, err := getProtocolVersion(tx, shardId, prevBlock, configAccessor)
+	if err != nil {
+		return types.Value{}, err
+	}
+	rules, err := getProtocolRules(version)
+	if err != nil {
+		return types.Value{}, err
+	}
+	return rules.feeCalculator.CalculateBaseFee(prevBlock), nil
+}
+
+// getProtocolVersion returns the protocol version of the block following prevBlock.
+// The version is selected by the height of the main shard block the config of the block is taken from.
+func getProtocolVersion(
+	tx db.RoTx,
+	shardId types.ShardId,
+	prevBlock *types.Block,
+	configAccessor config.ConfigAccessor,
+) (params.ProtocolVersion, error) {
+	if prevBlock == nil || configAccessor == nil {
+		return params.ProtocolGenesis, nil
+	}
+
+	schedule, err := config.GetParamProtocolVersions(configAccessor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read protocol versions: %w", err)
+	}
+	if len(schedule.Activations) == 0 {
+		return params.ProtocolGenesis, nil
+	}
+
+	height, err := getMainShardHeight(tx, shardId, prevBlock)
+	if err != nil {
+		return 0, err
+	}
+	version, err := schedule.Version(height)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := params.GetProtocol(version); err != nil {
+		return 0, fmt.Errorf("main shard block %d: %w", height, err)
+	}
+	return version, nil
+}
+
+func getMainShardHeight(tx db.RoTx, shardId types.ShardId, prevBlock *types.Block) (types.BlockNumber, error) {
+	if shardId.IsMainShard() {
+		return prevBlock.Id + 1, nil
+	}
+	if prevBlock.MainShardHash.Empty() {
+		return 0, nil
+	}
+
+	// The version must not depend on the local head, so the referenced block is required
+	// (as in config.NewStrictConfigAccessorFromBlockWithTx).
+	mainBlock, err := db.ReadBlock(tx, types.MainShardId, prevBlock.MainShardHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read main shard block %s: %w", prevBlock.MainShardHash, err)
+	}
+	return mainBlock.Id, nil
+}
+
+// validateProtocolVersions checks that the scheduled versions are ordered and known to this node.
+func validateProtocolVersions(schedule *config.ParamProtocolVersions) error {
+	for _, a := range schedule.Activations {
+		if _, err := getProtocolRules(params.ProtocolVersion(a.Version)); err != nil {
+			return err
+		}
+	}
+	_, err := schedule.Version(0)
+	return err
+}
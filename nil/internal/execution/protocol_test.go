@@ -0,0 +1,108 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRules(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range params.Protocols {
+		rules, err := getProtocolRules(p.Version)
+		require.NoError(t, err, p.Name)
+		require.NotNil(t, rules.feeCalculator, p.Name)
+		require.NotNil(t, rules.validateExternal, p.Name)
+	}
+
+	changes := map[params.ProtocolVersion]string{0: "genesis", 2: "second"}
+	expected := map[params.ProtocolVersion]string{0: "genesis", 1: "genesis", 2: "second", 5: "second"}
+	for version, name := range expected {
+		value, ok := params.ResolveProtocolChanges(changes, version)
+		require.True(t, ok)
+		require.Equal(t, name, value)
+	}
+}
+
+func TestProtocolVersion(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	// The next version is unknown to the node, so it must refuse to execute the blocks starting from height 10.
+	unknown := params.ProtocolVersion(len(params.Protocols))
+	configAccessor := config.NewConfigAccessorFromMap(nil)
+	require.NoError(t, config.SetParamProtocolVersions(configAccessor, &config.ParamProtocolVersions{
+		Activations: []config.ProtocolVersionActivation{
+			{Version: uint32(params.ProtocolGenesis), Height: 0},
+			{Version: uint32(unknown), Height: 10},
+		},
+	}))
+
+	newState := func(shardId types.ShardId, prevBlock *types.Block, c config.ConfigAccessor) (*ExecutionState, error) {
+		return NewExecutionState(tx, shardId, StateParams{Block: prevBlock, ConfigAccessor: c})
+	}
+
+	t.Run("NoSchedule", func(t *testing.T) {
+		es, err := newState(types.MainShardId, &types.Block{BlockData: types.BlockData{Id: 100}}, config.GetStubAccessor())
+		require.NoError(t, err)
+		require.Equal(t, params.ProtocolGenesis, es.ProtocolVersion)
+	})
+
+	t.Run("MainShard", func(t *testing.T) {
+		es, err := newState(types.MainShardId, &types.Block{BlockData: types.BlockData{Id: 8}}, configAccessor)
+		require.NoError(t, err)
+		require.Equal(t, params.ProtocolGenesis, es.ProtocolVersion)
+
+		_, err = newState(types.MainShardId, &types.Block{BlockData: types.BlockData{Id: 9}}, configAccessor)
+		require.ErrorContains(t, err, "not supported")
+	})
+
+	t.Run("Shard", func(t *testing.T) {
+		for _, id := range []types.BlockNumber{9, 10} {
+			mainBlock := &types.Block{BlockData: types.BlockData{Id: id}}
+			hash := mainBlock.Hash(types.MainShardId)
+			require.NoError(t, db.WriteBlock(tx, types.MainShardId, hash, mainBlock))
+
+			prevBlock := &types.Block{BlockData: types.BlockData{Id: 100, MainShardHash: hash}}
+			es, err := newState(types.BaseShardId, prevBlock, configAccessor)
+			if id < 10 {
+				require.NoError(t, err)
+				require.Equal(t, params.ProtocolGenesis, es.ProtocolVersion)
+			} else {
+				require.ErrorContains(t, err, "not supported")
+			}
+		}
+	})
+
+	t.Run("MissingMainShardBlock", func(t *testing.T) {
+		// The local head must not be used instead of the referenced block.
+		prevBlock := &types.Block{BlockData: types.BlockData{Id: 100, MainShardHash: common.HexToHash("0x1234")}}
+		_, err := newState(types.BaseShardId, prevBlock, configAccessor)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("OutOfOrder", func(t *testing.T) {
+		c := config.NewConfigAccessorFromMap(nil)
+		require.NoError(t, config.SetParamProtocolVersions(c, &config.ParamProtocolVersions{
+			Activations: []config.ProtocolVersionActivation{
+				{Version: uint32(unknown), Height: 10},
+				{Version: uint32(params.ProtocolGenesis), Height: 20},
+			},
+		}))
+		_, err := newState(types.MainShardId, &types.Block{BlockData: types.BlockData{Id: 1}}, c)
+		require.ErrorContains(t, err, "out of order")
+	})
+}
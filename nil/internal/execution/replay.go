@@ -0,0 +1,193 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// TransactionReplayer re-executes incoming transactions of an already committed block
+// on top of the state of its parent block. The resulting state is never committed,
+// so it is safe to use over a read-only transaction.
+type TransactionReplayer struct {
+	ctx   context.Context
+	es    *ExecutionState
+	block *types.Block
+	txns  []*types.Transaction
+	next  int
+}
+
+func NewTransactionReplayer(
+	ctx context.Context,
+	tx db.RoTx,
+	shardId types.ShardId,
+	block *types.Block,
+) (*TransactionReplayer, error) {
+	if block.Id == 0 {
+		return nil, errors.New("zero-state block can't be replayed")
+	}
+
+	prevBlock, err := db.ReadBlock(tx, shardId, block.PrevBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous block: %w", err)
+	}
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+
+	es, err := NewExecutionState(tx, shardId, StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+		Mode:           ModeReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if shardId.IsMainShard() {
+		gasPrices, err := collectGasPrices(tx, prevBlock.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect gas prices: %w", err)
+		}
+		if err := updateGasPrices(es, gasPrices); err != nil {
+			return nil, err
+		}
+	}
+
+	es.BaseFee = block.BaseFee
+	es.MainShardHash = block.MainShardHash
+	es.PatchLevel = block.PatchLevel
+	es.RollbackCounter = block.RollbackCounter
+
+	// The changes requested via the development API were applied before the transactions of the block.
+	if err := es.ApplyBlockDevChanges(block.Hash(shardId)); err != nil {
+		return nil, fmt.Errorf("failed to apply development API changes: %w", err)
+	}
+
+	reader := NewDbTransactionTrieReader(tx, shardId)
+	reader.SetRootHash(block.InTransactionsRoot)
+	entries, err := reader.Entries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block transactions: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	txns := make([]*types.Transaction, len(entries))
+	for i, e := range entries {
+		txns[i] = e.Val
+	}
+
+	return &TransactionReplayer{
+		ctx:   ctx,
+		es:    es,
+		block: block,
+		txns:  txns,
+	}, nil
+}
+
+// ExecutionState returns the state with all replayed transactions applied.
+func (r *TransactionReplayer) ExecutionState() *ExecutionState {
+	return r.es
+}
+
+// Transactions returns incoming transactions of the block in the order of their execution.
+func (r *TransactionReplayer) Transactions() []*types.Transaction {
+	return r.txns
+}
+
+// Replay executes all transactions preceding the one with the given index without tracing,
+// and then the transaction itself with the given hooks attached.
+func (r *TransactionReplayer) Replay(index types.TransactionIndex, hooks *tracing.Hooks) (*ExecutionResult, error) {
+	if err := r.skipTo(index); err != nil {
+		return nil, err
+	}
+	return r.ReplayNext(hooks)
+}
+
+// ReplayStateDiff executes all transactions preceding the one with the given index,
+// and then the transaction itself recording the state changed by it.
+func (r *TransactionReplayer) ReplayStateDiff(index types.TransactionIndex) (*ExecutionResult, *StateDiff, error) {
+	if err := r.skipTo(index); err != nil {
+		return nil, nil, err
+	}
+
+	since := r.es.JournalLength()
+	res, err := r.ReplayNext(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := r.es.StateDiff(since)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, diff, nil
+}
+
+// skipTo executes all transactions preceding the one with the given index.
+func (r *TransactionReplayer) skipTo(index types.TransactionIndex) error {
+	if int(index) >= len(r.txns) {
+		return fmt.Errorf("transaction index %d is out of range [0, %d)", index, len(r.txns))
+	}
+	if int(index) < r.next {
+		return fmt.Errorf("transaction %d has already been replayed", index)
+	}
+
+	for r.next < int(index) {
+		if _, err := r.ReplayNext(nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayNext executes the next transaction of the block with the given hooks attached.
+func (r *TransactionReplayer) ReplayNext(hooks *tracing.Hooks) (*ExecutionResult, error) {
+	if r.next >= len(r.txns) {
+		return nil, errors.New("all transactions of the block have been replayed")
+	}
+	txn := r.txns[r.next]
+	r.next++
+
+	r.es.AddInTransaction(txn)
+
+	// Hooks are attached only to the transaction execution itself, validation is not traced.
+	handle := func(payer Payer) *ExecutionResult {
+		r.es.EvmTracingHooks = hooks
+		defer func() { r.es.EvmTracingHooks = nil }()
+		return r.es.HandleTransaction(r.ctx, txn, payer)
+	}
+
+	var res *ExecutionResult
+	if txn.IsInternal() {
+		if err := r.es.AcceptInternalTransaction(txn); err != nil {
+			res = NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
+		} else {
+			res = handle(NewTransactionPayer(txn, r.es))
+		}
+	} else {
+		res = ValidateExternalTransaction(r.es, txn)
+		if !res.Failed() {
+			acc, err := r.es.GetAccount(txn.To)
+			if err != nil {
+				return nil, err
+			}
+			verifyGas := res.GasUsed
+			res = handle(NewAccountPayer(acc, txn))
+			res.AddUsed(verifyGas)
+		}
+	}
+
+	if res.FatalError != nil {
+		return nil, res.FatalError
+	}
+	r.es.AddReceipt(res)
+	return res, nil
+}
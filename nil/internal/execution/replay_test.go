@@ -0,0 +1,147 @@
+package execution
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionReplayer(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.ShardId(5)
+	// Init code that copies 4 bytes of the runtime code to memory and returns them.
+	const code = "6004600c60003960046000f301020304"
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	newState := func(block *types.Block) *ExecutionState {
+		t.Helper()
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          block,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		es.BaseFee = types.DefaultGasPrice
+		return es
+	}
+
+	zeroState, err := newState(nil).Commit(0, &types.ConsensusParams{})
+	require.NoError(t, err)
+
+	es := newState(zeroState.Block)
+	from := types.GenerateRandomAddress(shardId)
+	var expected []types.Address
+	for i := range 3 {
+		payload := types.BuildDeployPayload(hexutil.FromHex(code), common.BytesToHash([]byte{byte(i)}))
+		txn := NewDeployTransaction(payload, shardId, from, types.Seqno(i), types.Value{})
+		txn.RefundTo = from
+		txn.BounceTo = from
+		txn.TxId = types.TransactionIndex(i)
+		es.AddInTransaction(txn)
+		require.NoError(t, es.AcceptInternalTransaction(txn))
+		res := es.HandleTransaction(ctx, txn, NewTransactionPayer(txn, es))
+		require.False(t, res.Failed())
+		es.AddReceipt(res)
+		expected = append(expected, txn.To)
+	}
+	blockRes, err := es.Commit(1, &types.ConsensusParams{})
+	require.NoError(t, err)
+
+	replayer, err := NewTransactionReplayer(ctx, tx, shardId, blockRes.Block)
+	require.NoError(t, err)
+	require.Len(t, replayer.Transactions(), 3)
+
+	var ops []vm.OpCode
+	var created []types.Address
+	hooks := &tracing.Hooks{
+		OnOpcode: func(_ uint64, op byte, _, _ uint64, _ tracing.OpContext, _ []byte, _ int, _ error) {
+			ops = append(ops, vm.OpCode(op))
+		},
+		OnEnter: func(depth int, typ byte, _, to types.Address, _ []byte, _ uint64, _ *big.Int) {
+			if depth == 0 {
+				require.Equal(t, vm.CREATE, vm.OpCode(typ))
+				created = append(created, to)
+			}
+		},
+	}
+
+	res, err := replayer.Replay(1, hooks)
+	require.NoError(t, err)
+	require.False(t, res.Failed())
+
+	// Only the requested transaction is traced.
+	require.Equal(t, expected[1:2], created)
+	require.Equal(t, []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.PUSH1, vm.CODECOPY, vm.PUSH1, vm.PUSH1, vm.RETURN}, ops)
+
+	code1, _, err := replayer.ExecutionState().GetCode(expected[1])
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 4}, code1)
+
+	_, err = replayer.Replay(0, nil)
+	require.Error(t, err)
+
+	t.Run("StateDiff", func(t *testing.T) {
+		replayer, err := NewTransactionReplayer(ctx, tx, shardId, blockRes.Block)
+		require.NoError(t, err)
+
+		res, diff, err := replayer.ReplayStateDiff(2)
+		require.NoError(t, err)
+		require.False(t, res.Failed())
+
+		// The deployed contract didn't exist before the transaction.
+		require.NotContains(t, diff.Pre, expected[2])
+		require.Contains(t, diff.Post, expected[2])
+		require.Equal(t, hexutil.Bytes{1, 2, 3, 4}, *diff.Post[expected[2]].Code)
+		require.NotContains(t, diff.Post, expected[1])
+	})
+
+	t.Run("DevChanges", func(t *testing.T) {
+		es := newState(blockRes.Block)
+		balance := types.NewValueFromUint64(1_000_000)
+		require.NoError(t, es.ApplyDevChanges([]*AccountOverride{
+			{Kind: OverrideBalance, Address: from, Balance: balance},
+		}, []types.Address{from}, 42))
+
+		payload := types.BuildDeployPayload(hexutil.FromHex(code), common.BytesToHash([]byte{3}))
+		txn := NewDeployTransaction(payload, shardId, from, 3, types.Value{})
+		txn.RefundTo = from
+		txn.BounceTo = from
+		txn.TxId = 3
+		es.AddInTransaction(txn)
+		require.NoError(t, es.AcceptInternalTransaction(txn))
+		res := es.HandleTransaction(ctx, txn, NewTransactionPayer(txn, es))
+		require.False(t, res.Failed())
+		es.AddReceipt(res)
+		devBlockRes, err := es.Commit(2, &types.ConsensusParams{})
+		require.NoError(t, err)
+
+		replayer, err := NewTransactionReplayer(ctx, tx, shardId, devBlockRes.Block)
+		require.NoError(t, err)
+		replayed := replayer.ExecutionState()
+		require.Equal(t, uint64(42), replayed.TimeOffset)
+		require.True(t, replayed.isImpersonated(from))
+		actual, err := replayed.GetBalance(from)
+		require.NoError(t, err)
+		require.Equal(t, balance, actual)
+
+		res, err = replayer.Replay(0, nil)
+		require.NoError(t, err)
+		require.False(t, res.Failed())
+	})
+}
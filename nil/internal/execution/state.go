@@ -20,6 +20,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
@@ -88,6 +89,13 @@ type ExecutionState struct {
 	PatchLevel      uint32
 	RollbackCounter uint32
 
+	// TimeOffset is added to the block time. It is set via the development API.
+	TimeOffset uint64
+	// impersonated accounts accept external transactions without verification (development API only).
+	impersonated []types.Address
+	// devChanges are the development API changes applied in the block, they are stored when the block is committed.
+	devChanges *DevChanges
+
 	InTransactionHash common.Hash
 	Logs              map[common.Hash][]*types.Log
 	DebugLogs         map[common.Hash][]*types.DebugLog
@@ -139,6 +147,17 @@ type ExecutionState struct {
 
 	FeeCalculator FeeCalculator
 
+	// ProtocolVersion selects the execution rules of the block.
+	ProtocolVersion params.ProtocolVersion
+	rules           *protocolRules
+
+	// flatState is set if the accounts are read from the flat state, see StateParams.ReadFlatState.
+	flatState *FlatStateReader
+	// If writeFlatState is set, BuildBlock collects the changes of the flat state into flatStateUpdate
+	// and CommitBlock writes them, see StateParams.WriteFlatState.
+	writeFlatState  bool
+	flatStateUpdate *flatStateUpdate
+
 	// filled in if a rollback was requested by a transaction
 	rollback *RollbackParams
 
@@ -268,7 +287,7 @@ func NewEVMBlockContext(es *ExecutionState) (*vm.BlockContext, error) {
 		currentBlockId = header.Id.Uint64() + 1
 		// TODO: we need to use header.Timestamp instead of but it's always zero for now.
 		// Let's return some kind of logical timestamp (monotonic increasing block number).
-		time = header.Id.Uint64()
+		time = header.Id.Uint64() + es.TimeOffset
 		rollbackCounter = header.RollbackCounter
 	}
 	return &vm.BlockContext{
@@ -281,6 +300,7 @@ func NewEVMBlockContext(es *ExecutionState) (*vm.BlockContext, error) {
 		Time:        time,
 
 		RollbackCounter: rollbackCounter,
+		ProtocolVersion: es.ProtocolVersion,
 	}, nil
 }
 
@@ -290,6 +310,11 @@ type StateParams struct {
 	FeeCalculator  FeeCalculator
 	Mode           string
 	GasLimit       types.Gas
+	// ReadFlatState makes the read-only state read the accounts and their storage from the flat state
+	// if it holds the state of the block.
+	ReadFlatState bool
+	// WriteFlatState makes CommitBlock update the flat state.
+	WriteFlatState bool
 }
 
 func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*ExecutionState, error) {
@@ -312,9 +337,18 @@ func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*Exec
 	}
 	logger := l.Logger()
 
+	protocolVersion, err := getProtocolVersion(resTx, shardId, params.Block, params.ConfigAccessor)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := getProtocolRules(protocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	feeCalculator := params.FeeCalculator
 	if feeCalculator == nil {
-		feeCalculator = &MainFeeCalculator{}
+		feeCalculator = rules.feeCalculator
 	}
 
 	var baseFeePerGas types.Value
@@ -360,9 +394,20 @@ func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*Exec
 
 		FeeCalculator: feeCalculator,
 
+		ProtocolVersion: protocolVersion,
+		rules:           rules,
+
+		writeFlatState: params.WriteFlatState,
+
 		logger: logger,
 	}
 
+	if params.ReadFlatState && isReadOnly {
+		if res.flatState, err = NewFlatStateReader(resTx, shardId, prevBlockHash); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, res.initTries()
 }
 
@@ -438,7 +483,13 @@ func (es *ExecutionState) GetAccount(addr types.Address) (*AccountState, error)
 		return acc, nil
 	}
 
-	data, err := es.ContractTree.GetContract(addr)
+	var data *types.SmartContract
+	var err error
+	if es.flatState != nil {
+		data, err = es.flatState.GetContract(addr)
+	} else {
+		data, err = es.ContractTree.GetContract(addr)
+	}
 	if errors.Is(err, db.ErrKeyNotFound) {
 		return nil, nil
 	}
@@ -450,6 +501,7 @@ func (es *ExecutionState) GetAccount(addr types.Address) (*AccountState, error)
 	if err != nil {
 		return nil, fmt.Errorf("NewAccountState failed: %w", err)
 	}
+	acc.flatState = es.flatState
 
 	es.Accounts[addr] = acc
 	return acc, nil
@@ -486,6 +538,9 @@ func (es *ExecutionState) AddLog(log *types.Log) error {
 		return errors.New("too many logs")
 	}
 	es.Logs[es.InTransactionHash] = append(es.Logs[es.InTransactionHash], log)
+	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnLog != nil {
+		es.EvmTracingHooks.OnLog(log)
+	}
 	return nil
 }
 
@@ -1463,9 +1518,16 @@ func (es *ExecutionState) writeTxCounts(root common.Hash, counts TxCounts) commo
 }
 
 func (es *ExecutionState) BuildBlock(blockId types.BlockNumber) (*BlockGenerationResult, error) {
+	var flatStorage map[types.Address]map[common.Hash]common.Hash
+	if es.writeFlatState {
+		flatStorage = es.collectFlatStorage()
+	}
 	if err := es.ContractTree.UpdateContracts(es.Accounts); err != nil {
 		return nil, err
 	}
+	if es.writeFlatState {
+		es.flatStateUpdate = &flatStateUpdate{contracts: es.collectFlatContracts(), storage: flatStorage}
+	}
 
 	treeShardsRootHash := common.EmptyHash
 	if len(es.ChildShardBlocks) > 0 {
@@ -1641,6 +1703,18 @@ func (es *ExecutionState) CommitBlock(src *BlockGenerationResult, params *types.
 		return err
 	}
 
+	if es.devChanges != nil {
+		if err := writeDevChanges(es.tx, es.ShardId, blockHash, es.devChanges); err != nil {
+			return fmt.Errorf("failed to write development API changes: %w", err)
+		}
+	}
+
+	if es.flatStateUpdate != nil {
+		if err := es.commitFlatState(block, blockHash); err != nil {
+			return fmt.Errorf("failed to update flat state: %w", err)
+		}
+	}
+
 	es.logger.Trace().
 		Stringer(logging.FieldBlockNumber, block.Id).
 		Stringer(logging.FieldBlockHash, blockHash).
@@ -1794,6 +1868,19 @@ func (es *ExecutionState) CallVerifyExternal(
 	return res
 }
 
+// StaticCall runs the read-only call of the contract with the calldata and returns the output.
+// It is used to read the state of the system contracts, the state is not changed.
+func (es *ExecutionState) StaticCall(addr types.Address, calldata []byte, gas types.Gas) ([]byte, error) {
+	if err := es.newVm(true, addr); err != nil {
+		return nil, fmt.Errorf("newVm failed: %w", err)
+	}
+	defer es.resetVm()
+	es.evm.Config.Tracer = nil
+
+	ret, _, err := es.evm.StaticCall((vm.AccountRef)(addr), addr, calldata, gas.Uint64())
+	return ret, err
+}
+
 func (es *ExecutionState) AddToken(addr types.Address, tokenId types.TokenId, amount types.Value) error {
 	es.logger.Debug().
 		Stringer("addr", addr).
@@ -2010,7 +2097,7 @@ func (es *ExecutionState) DeleteOutTransaction(index int, txnHash common.Hash) {
 }
 
 func (es *ExecutionState) preTxHookCall(txn *types.Transaction) {
-	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnTxEnd != nil {
+	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnTxStart != nil {
 		es.EvmTracingHooks.OnTxStart(es.evm.GetVMContext(), txn)
 	}
 }
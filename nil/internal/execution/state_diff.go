@@ -0,0 +1,183 @@
+package execution
+
+import (
+	"bytes"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// AccountDiff is a part of the account state affected by the changes.
+// Only the changed fields, storage slots and token balances are set.
+type AccountDiff struct {
+	Balance  *types.Value                  `json:"balance,omitempty"`
+	Seqno    *types.Seqno                  `json:"seqno,omitempty"`
+	ExtSeqno *types.Seqno                  `json:"extSeqno,omitempty"`
+	Code     *hexutil.Bytes                `json:"code,omitempty"`
+	Storage  map[common.Hash]common.Hash   `json:"storage,omitempty"`
+	Tokens   map[types.TokenId]types.Value `json:"tokens,omitempty"`
+}
+
+func (d *AccountDiff) empty() bool {
+	return d.Balance == nil && d.Seqno == nil && d.ExtSeqno == nil && d.Code == nil &&
+		len(d.Storage) == 0 && len(d.Tokens) == 0
+}
+
+// StateDiff contains the values of the changed accounts before and after the changes.
+// The accounts created by the changes are absent in Pre, the removed ones are absent in Post.
+type StateDiff struct {
+	Pre  map[types.Address]*AccountDiff `json:"pre"`
+	Post map[types.Address]*AccountDiff `json:"post"`
+}
+
+// JournalLength returns the number of the changes recorded in the journal.
+// It is the position to pass to StateDiff to get the changes made after the call.
+func (es *ExecutionState) JournalLength() int {
+	return es.journal.length()
+}
+
+// StateDiff returns the changes recorded in the journal since the given position.
+// The previous values are taken from the journal, the new ones are read from the current state.
+// Values changed and then restored are not reported.
+func (es *ExecutionState) StateDiff(since int) (*StateDiff, error) {
+	pre := make(map[types.Address]*AccountDiff)
+	created := make(map[types.Address]bool)
+	account := func(addr types.Address) *AccountDiff {
+		d, ok := pre[addr]
+		if !ok {
+			d = &AccountDiff{
+				Storage: make(map[common.Hash]common.Hash),
+				Tokens:  make(map[types.TokenId]types.Value),
+			}
+			pre[addr] = d
+		}
+		return d
+	}
+
+	// Only the first change of every value holds the value before the changes.
+	for _, entry := range es.journal.entries[since:] {
+		switch ch := entry.(type) {
+		case createAccountChange:
+			created[*ch.account] = true
+			account(*ch.account)
+		case balanceChange:
+			if d := account(*ch.account); d.Balance == nil {
+				d.Balance = &ch.prev
+			}
+		case selfDestructChange:
+			if d := account(*ch.account); d.Balance == nil {
+				d.Balance = &ch.prevbalance
+			}
+		case tokenChange:
+			if d := account(*ch.account); !hasKey(d.Tokens, ch.id) {
+				d.Tokens[ch.id] = ch.prev
+			}
+		case seqnoChange:
+			if d := account(*ch.account); d.Seqno == nil {
+				d.Seqno = &ch.prev
+			}
+		case extSeqnoChange:
+			if d := account(*ch.account); d.ExtSeqno == nil {
+				d.ExtSeqno = &ch.prev
+			}
+		case codeChange:
+			if d := account(*ch.account); d.Code == nil {
+				d.Code = (*hexutil.Bytes)(&ch.prevcode)
+			}
+		case storageChange:
+			if d := account(*ch.account); !hasKey(d.Storage, ch.key) {
+				d.Storage[ch.key] = ch.prevvalue
+			}
+		}
+	}
+
+	res := &StateDiff{
+		Pre:  make(map[types.Address]*AccountDiff),
+		Post: make(map[types.Address]*AccountDiff),
+	}
+	for addr, before := range pre {
+		acc, err := es.GetAccount(addr)
+		if err != nil {
+			return nil, err
+		}
+		if acc == nil {
+			if !created[addr] {
+				res.Pre[addr] = before
+			}
+			continue
+		}
+
+		after := diffAccount(before, acc)
+		if before.empty() {
+			continue
+		}
+		if !created[addr] {
+			res.Pre[addr] = before
+		}
+		res.Post[addr] = after
+	}
+	return res, nil
+}
+
+// diffAccount returns the current values of the fields set in before
+// and removes the fields that have not changed from it.
+func diffAccount(before *AccountDiff, acc *AccountState) *AccountDiff {
+	after := &AccountDiff{
+		Storage: make(map[common.Hash]common.Hash),
+		Tokens:  make(map[types.TokenId]types.Value),
+	}
+	if before.Balance != nil {
+		if before.Balance.Eq(acc.Balance) {
+			before.Balance = nil
+		} else {
+			after.Balance = &acc.Balance
+		}
+	}
+	if before.Seqno != nil {
+		if *before.Seqno == acc.Seqno {
+			before.Seqno = nil
+		} else {
+			after.Seqno = &acc.Seqno
+		}
+	}
+	if before.ExtSeqno != nil {
+		if *before.ExtSeqno == acc.ExtSeqno {
+			before.ExtSeqno = nil
+		} else {
+			after.ExtSeqno = &acc.ExtSeqno
+		}
+	}
+	if before.Code != nil {
+		if bytes.Equal(*before.Code, acc.Code) {
+			before.Code = nil
+		} else {
+			after.Code = (*hexutil.Bytes)(&acc.Code)
+		}
+	}
+	for key, prev := range before.Storage {
+		// The changed slots are always cached in the account.
+		if value := acc.State[key]; value == prev {
+			delete(before.Storage, key)
+		} else {
+			after.Storage[key] = value
+		}
+	}
+	for id, prev := range before.Tokens {
+		value := types.Value0
+		if balance := acc.GetTokenBalance(id); balance != nil {
+			value = *balance
+		}
+		if value.Eq(prev) {
+			delete(before.Tokens, id)
+		} else {
+			after.Tokens[id] = value
+		}
+	}
+	return after
+}
+
+func hasKey[K comparable, V any](m map[K]V, key K) bool {
+	_, ok := m[key]
+	return ok
+}
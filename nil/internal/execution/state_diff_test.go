@@ -0,0 +1,71 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiff(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := NewExecutionState(tx, types.BaseShardId, StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+	created := types.GenerateRandomAddress(types.BaseShardId)
+	token := types.TokenId(types.GenerateRandomAddress(types.BaseShardId))
+	key1 := common.HexToHash("0x01")
+	key2 := common.HexToHash("0x02")
+
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(100)))
+	require.NoError(t, es.SetState(addr, key1, common.HexToHash("0xaa")))
+
+	since := es.JournalLength()
+
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(90)))
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(80)))
+	require.NoError(t, es.SetState(addr, key1, common.HexToHash("0xbb")))
+	// Changed and restored values are not reported.
+	require.NoError(t, es.SetState(addr, key2, common.HexToHash("0xcc")))
+	require.NoError(t, es.SetState(addr, key2, common.EmptyHash))
+	require.NoError(t, es.SetSeqno(addr, 1))
+	require.NoError(t, es.SetSeqno(addr, 0))
+
+	require.NoError(t, es.CreateAccount(created))
+	acc, err := es.GetAccount(created)
+	require.NoError(t, err)
+	acc.SetTokenBalance(token, types.NewValueFromUint64(7))
+
+	diff, err := es.StateDiff(since)
+	require.NoError(t, err)
+
+	require.Len(t, diff.Pre, 1)
+	require.Equal(t, &AccountDiff{
+		Balance: &[]types.Value{types.NewValueFromUint64(100)}[0],
+		Storage: map[common.Hash]common.Hash{key1: common.HexToHash("0xaa")},
+		Tokens:  map[types.TokenId]types.Value{},
+	}, diff.Pre[addr])
+
+	require.Len(t, diff.Post, 2)
+	require.Equal(t, &AccountDiff{
+		Balance: &[]types.Value{types.NewValueFromUint64(80)}[0],
+		Storage: map[common.Hash]common.Hash{key1: common.HexToHash("0xbb")},
+		Tokens:  map[types.TokenId]types.Value{},
+	}, diff.Post[addr])
+	require.Equal(t, map[types.TokenId]types.Value{token: types.NewValueFromUint64(7)}, diff.Post[created].Tokens)
+}
@@ -185,12 +185,19 @@ func validateExternalExecutionTransaction(es *ExecutionState, transaction *types
 		return NewExecutionResult().SetError(types.NewWrapError(types.ErrorSeqnoGap, err))
 	}
 
+	if es.isImpersonated(to) {
+		return NewExecutionResult()
+	}
 	return es.CallVerifyExternal(transaction, account)
 }
 
+// ValidateExternalTransaction checks the external transaction by the rules of the protocol version of the block.
 func ValidateExternalTransaction(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
 	check.PanicIfNot(transaction.IsExternal())
+	return es.rules.validateExternal(es, transaction)
+}
 
+func validateExternalTransactionGenesis(es *ExecutionState, transaction *types.Transaction) *ExecutionResult {
 	if transaction.ChainId != types.DefaultChainId {
 		return NewExecutionResult().SetError(types.NewError(types.ErrorInvalidChainId))
 	}
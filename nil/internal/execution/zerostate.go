@@ -2,6 +2,7 @@ package execution
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"os"
 
@@ -32,11 +33,19 @@ type MainKeys struct {
 type ConfigParams struct {
 	Validators config.ParamValidators `yaml:"validators,omitempty" json:"validators,omitempty"`
 	GasPrice   config.ParamGasPrice   `yaml:"gasPrice" json:"gasPrice"`
+	// ProtocolVersions are the protocol versions activated at the given main shard heights.
+	ProtocolVersions config.ParamProtocolVersions `yaml:"protocolVersions,omitempty" json:"protocolVersions,omitempty"`
+	// ValidatorsEpoch sets the number of main shard blocks between the validator set changes.
+	ValidatorsEpoch config.ParamValidatorsEpoch `yaml:"validatorsEpoch,omitempty" json:"validatorsEpoch,omitempty"`
 }
 
 type ZeroStateConfig struct {
 	ConfigParams ConfigParams     `yaml:"config,omitempty" json:"config,omitempty"`
 	Contracts    []*ContractDescr `yaml:"contracts" json:"contracts"`
+	// Files with the bundles exported from another network by `nil contract export`.
+	// They are loaded into ImportedContracts before the zero state is generated.
+	ContractBundles   []string          `yaml:"contractBundles,omitempty" json:"contractBundles,omitempty"`
+	ImportedContracts []*ContractBundle `yaml:"importedContracts,omitempty" json:"importedContracts,omitempty"`
 }
 
 func CreateDefaultZeroStateConfig(mainPublicKey []byte) (*ZeroStateConfig, error) {
@@ -69,6 +78,18 @@ func CreateDefaultZeroStateConfig(mainPublicKey []byte) (*ZeroStateConfig, error
 				Address:  types.L1BlockInfoAddress,
 				Value:    types.Value0,
 			},
+			{
+				Name:     "Staking",
+				Contract: "system/Staking",
+				Address:  types.StakingAddress,
+				Value:    types.Value0,
+			},
+			{
+				Name:     "Slashing",
+				Contract: "system/Slashing",
+				Address:  types.SlashingAddress,
+				Value:    types.Value0,
+			},
 			{
 				Name:     "Governance",
 				Contract: "system/Governance",
@@ -121,6 +142,20 @@ func LoadMainKeys(fname string) (*ecdsa.PrivateKey, error) {
 	return mainPrivateKey, err
 }
 
+// LoadContractBundles reads the bundle files listed in ContractBundles into ImportedContracts,
+// so the config no longer depends on the local files, e.g. when it is sent to the other nodes.
+func (c *ZeroStateConfig) LoadContractBundles() error {
+	for _, fname := range c.ContractBundles {
+		bundle, err := LoadContractBundle(fname)
+		if err != nil {
+			return err
+		}
+		c.ImportedContracts = append(c.ImportedContracts, bundle)
+	}
+	c.ContractBundles = nil
+	return nil
+}
+
 func (c *ZeroStateConfig) FindContractByName(name string) *ContractDescr {
 	for _, contract := range c.Contracts {
 		if contract.Name == name {
@@ -152,6 +187,22 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 		if err != nil {
 			return err
 		}
+		if err := validateProtocolVersions(&stateConfig.ConfigParams.ProtocolVersions); err != nil {
+			return err
+		}
+		err = config.SetParamProtocolVersions(cfgAccessor, &stateConfig.ConfigParams.ProtocolVersions)
+		if err != nil {
+			return err
+		}
+		// The set changed by the epoch transaction of block N is used to verify block N+2 (the validators are taken
+		// from the config of the grandparent block), so the shorter epochs would overlap.
+		if length := stateConfig.ConfigParams.ValidatorsEpoch.Length; length == 1 {
+			return errors.New("validators epoch must be at least 2 blocks long")
+		}
+		err = config.SetParamValidatorsEpoch(cfgAccessor, &stateConfig.ConfigParams.ValidatorsEpoch)
+		if err != nil {
+			return err
+		}
 	}
 
 	if len(stateConfig.ConfigParams.GasPrice.Shards) != 0 {
@@ -224,5 +275,18 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 
 		es.logger.Debug().Str("name", contract.Name).Stringer("address", addr).Msg("Created zero state contract")
 	}
+
+	if len(stateConfig.ContractBundles) != 0 {
+		return errors.New("contract bundles are not loaded")
+	}
+	for _, bundle := range stateConfig.ImportedContracts {
+		if bundle.Address.ShardId() != es.ShardId {
+			continue
+		}
+		if err := es.importContract(bundle); err != nil {
+			return err
+		}
+		es.logger.Debug().Stringer("address", bundle.Address).Msg("Imported zero state contract")
+	}
 	return nil
 }
@@ -26,6 +26,7 @@ const maxRawKeyLen = 32
 
 type Reader struct {
 	getter Getter
+	cache  *NodeCache
 	root   Reference
 }
 
@@ -154,8 +155,10 @@ func NewReader(getter Getter) *Reader {
 	return &Reader{getter: getter}
 }
 
+// NewDbReader creates the reader of the trie stored in the table. The nodes are cached in GlobalNodeCache.
+// The readers walking the whole tries should be created by NewReader to keep the cache for the lookups.
 func NewDbReader(tx db.RoTx, shardId types.ShardId, name db.ShardedTableName) *Reader {
-	return NewReader(NewDbGetter(tx, shardId, name))
+	return &Reader{getter: NewDbGetter(tx, shardId, name), cache: GlobalNodeCache}
 }
 
 func NewMPT(setter Setter, reader *Reader) *MerklePatriciaTrie {
@@ -706,6 +709,9 @@ func (m *MerklePatriciaTrie) storeNode(node Node) (Reference, error) {
 	if err := m.setter.Set(key, data); err != nil {
 		return nil, err
 	}
+	if m.cache != nil {
+		m.cache.add(key, data)
+	}
 	return key, nil
 }
 
@@ -713,9 +719,17 @@ func (m *Reader) getNode(ref Reference) (Node, error) {
 	if len(ref) < 32 {
 		return DecodeNode(ref)
 	}
+	if m.cache != nil {
+		if data, ok := m.cache.get(ref); ok {
+			return DecodeNode(data)
+		}
+	}
 	data, err := m.getter.Get(ref)
 	if err != nil {
 		return nil, err
 	}
+	if m.cache != nil {
+		m.cache.add(ref, data)
+	}
 	return DecodeNode(data)
 }
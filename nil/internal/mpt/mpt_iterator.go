@@ -7,8 +7,14 @@ import (
 func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
 	type Yield = func([]byte, []byte) bool
 	return func(yield Yield) {
+		// stopped is set once the consumer breaks the loop, so that the outer recursion levels
+		// don't call yield again.
+		stopped := false
 		var iter func(ref Reference, path *Path)
 		iter = func(ref Reference, path *Path) {
+			if stopped {
+				return
+			}
 			node, err := m.getNode(ref)
 			if err != nil {
 				return
@@ -22,6 +28,7 @@ func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
 				// note: even though we access path.Data directly here is ok
 				// cause every key in the mpt is []byte, i.e. it consists of even number of nibbles
 				if !yield(path.Data, data) {
+					stopped = true
 					return
 				}
 			}
@@ -42,3 +49,114 @@ func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
 		}
 	}
 }
+
+// WalkNodes calls visit for every node of the trie in depth-first order. Nodes shorter than 32 bytes are
+// inlined into their parents, so only the references of at least 32 bytes are the keys of the nodes in the
+// storage. The children of the node are not visited if visit returns false.
+// Unlike Iterate, WalkNodes reports the errors of reading the nodes.
+func (m *Reader) WalkNodes(visit func(ref Reference, node Node) (bool, error)) error {
+	var walk func(ref Reference) error
+	walk = func(ref Reference) error {
+		node, err := m.getNode(ref)
+		if err != nil {
+			return err
+		}
+		if descend, err := visit(ref, node); err != nil || !descend {
+			return err
+		}
+		switch node := node.(type) {
+		case *BranchNode:
+			for _, br := range node.Branches {
+				if len(br) > 0 {
+					if err := walk(br); err != nil {
+						return err
+					}
+				}
+			}
+		case *ExtensionNode:
+			return walk(node.NextRef)
+		}
+		return nil
+	}
+	if !m.root.IsValid() || m.RootHash().Empty() {
+		return nil
+	}
+	return walk(m.root)
+}
+
+// Walk calls visit for every key and value of the trie in the order of the keys, starting from the first key
+// that is not lower than from (from the beginning if from is empty). The subtrees with lower keys are not read.
+// Unlike Iterate, Walk reports the errors of reading the nodes. The walk stops if visit returns false.
+func (m *Reader) Walk(from []byte, visit func(key, value []byte) (bool, error)) error {
+	fromPath := newPath(from, false)
+
+	// bounded is set while path is a proper prefix of from, i.e. the subtree may hold keys lower than from.
+	var walk func(ref Reference, path *Path, bounded bool) (bool, error)
+	walk = func(ref Reference, path *Path, bounded bool) (bool, error) {
+		node, err := m.getNode(ref)
+		if err != nil {
+			return false, err
+		}
+		if npath := node.Path(); npath != nil {
+			path = path.Combine(npath)
+			if bounded {
+				cmp := comparePrefix(path, fromPath)
+				if cmp < 0 {
+					return true, nil
+				}
+				bounded = cmp == 0
+			}
+		}
+		// The key of the data is a proper prefix of from while bounded is set, so it is lower than from.
+		if data := node.Data(); len(data) > 0 && !bounded {
+			if cont, err := visit(path.Data, data); err != nil || !cont {
+				return false, err
+			}
+		}
+		switch node := node.(type) {
+		case *BranchNode:
+			for i, br := range node.Branches {
+				if len(br) == 0 {
+					continue
+				}
+				childPath := path.Combine(newPath([]byte{byte(i)}, true))
+				childBounded := bounded
+				if bounded {
+					cmp := comparePrefix(childPath, fromPath)
+					if cmp < 0 {
+						continue
+					}
+					childBounded = cmp == 0
+				}
+				if cont, err := walk(br, childPath, childBounded); err != nil || !cont {
+					return false, err
+				}
+			}
+		case *ExtensionNode:
+			return walk(node.NextRef, path, bounded)
+		}
+		return true, nil
+	}
+	if !m.root.IsValid() || m.RootHash().Empty() {
+		return nil
+	}
+	_, err := walk(m.root, newPath(nil, false), !fromPath.Empty())
+	return err
+}
+
+// comparePrefix compares the keys under path with from: it returns -1 if all of them are lower than from,
+// 1 if none of them is, and 0 if path is a proper prefix of from.
+func comparePrefix(path, from *Path) int {
+	for i := range min(path.Size(), from.Size()) {
+		if a, b := path.At(i), from.At(i); a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	if path.Size() >= from.Size() {
+		return 1
+	}
+	return 0
+}
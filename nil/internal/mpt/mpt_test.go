@@ -1,6 +1,7 @@
 package mpt_test
 
 import (
+	"bytes"
 	"encoding/binary"
 	rand "math/rand/v2"
 	"testing"
@@ -121,6 +122,139 @@ func TestIterate(t *testing.T) {
 		i++
 	}
 	require.Len(t, keys, i)
+
+	// Breaking the loop early must not resume the iteration
+	i = 0
+	for k := range trie.Iterate() {
+		require.Equal(t, k, keys[i])
+		i++
+		if i == 2 {
+			break
+		}
+	}
+	require.Equal(t, 2, i)
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	holder := mpt.NewInMemHolder()
+	trie := mpt.NewMPTFromMap(holder)
+	require.NoError(t, trie.Walk(nil, func([]byte, []byte) (bool, error) {
+		require.Fail(t, "the empty trie has no entries")
+		return true, nil
+	}))
+
+	gen := newRandGen()
+	for _, kv := range generateTestCase(gen, 200, 1, 20, "abcdef") {
+		require.NoError(t, trie.Set(kv.key, kv.value))
+	}
+
+	// The entries are the same as the ones of Iterate and come in the same order.
+	var expected, walked []kvPair
+	for k, v := range trie.Iterate() {
+		expected = append(expected, kvPair{k, v})
+	}
+	require.NoError(t, trie.Walk(nil, func(k, v []byte) (bool, error) {
+		walked = append(walked, kvPair{k, v})
+		return true, nil
+	}))
+	require.Equal(t, expected, walked)
+
+	// The walk starts from the first key that is not lower than from.
+	for _, from := range [][]byte{expected[0].key, expected[len(expected)/2].key, []byte("c"), []byte("zz")} {
+		var fromExpected, fromWalked []kvPair
+		for _, kv := range expected {
+			if bytes.Compare(kv.key, from) >= 0 {
+				fromExpected = append(fromExpected, kv)
+			}
+		}
+		require.NoError(t, trie.Walk(from, func(k, v []byte) (bool, error) {
+			fromWalked = append(fromWalked, kvPair{k, v})
+			return true, nil
+		}))
+		require.Equal(t, fromExpected, fromWalked, "from %q", from)
+	}
+
+	// Returning false stops the walk.
+	visited := 0
+	require.NoError(t, trie.Walk(nil, func([]byte, []byte) (bool, error) {
+		visited++
+		return visited < 3, nil
+	}))
+	require.Equal(t, 3, visited)
+
+	// Missing nodes are reported.
+	for key := range holder {
+		delete(holder, key)
+	}
+	require.Error(t, trie.Walk(nil, func([]byte, []byte) (bool, error) {
+		return true, nil
+	}))
+}
+
+func TestWalkNodes(t *testing.T) {
+	t.Parallel()
+
+	holder := mpt.NewInMemHolder()
+	trie := mpt.NewMPTFromMap(holder)
+
+	walk := func(root common.Hash, stored map[string]struct{}) int {
+		t.Helper()
+
+		trie.SetRootHash(root)
+		visited := 0
+		require.NoError(t, trie.WalkNodes(func(ref mpt.Reference, node mpt.Node) (bool, error) {
+			visited++
+			if len(ref) >= 32 {
+				stored[string(ref)] = struct{}{}
+			}
+			return true, nil
+		}))
+		return visited
+	}
+
+	require.Zero(t, walk(common.EmptyHash, make(map[string]struct{})))
+
+	gen := newRandGen()
+	var roots []common.Hash
+	for _, kv := range generateTestCase(gen, 500, 1, 40, "abcdef") {
+		require.NoError(t, trie.Set(kv.key, kv.value))
+		roots = append(roots, trie.RootHash())
+	}
+	latest := roots[len(roots)-1]
+
+	// Every stored node belongs to some version of the trie.
+	all := make(map[string]struct{})
+	for _, root := range roots {
+		walk(root, all)
+	}
+	require.Len(t, all, len(holder))
+	for key := range holder {
+		require.Contains(t, all, key)
+	}
+
+	// The latest version uses only a part of them.
+	live := make(map[string]struct{})
+	walk(latest, live)
+	require.Less(t, len(live), len(all))
+
+	// Not descending into the children visits only the root.
+	trie.SetRootHash(latest)
+	visited := 0
+	require.NoError(t, trie.WalkNodes(func(mpt.Reference, mpt.Node) (bool, error) {
+		visited++
+		return false, nil
+	}))
+	require.Equal(t, 1, visited)
+
+	// Missing nodes are reported.
+	for key := range live {
+		delete(holder, key)
+	}
+	require.Error(t, trie.WalkNodes(func(mpt.Reference, mpt.Node) (bool, error) {
+		return true, nil
+	}))
 }
 
 func TestInsertGetLots(t *testing.T) {
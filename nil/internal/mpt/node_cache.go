@@ -0,0 +1,47 @@
+package mpt
+
+import (
+	"github.com/NilFoundation/nil/nil/common"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// NodeCache keeps the encoded nodes recently read from or written to the database.
+// The nodes are stored by the hashes of their contents, so a cached node is valid for every trie, shard and
+// transaction that references it, and the cache is never invalidated. The cache doesn't know whether a node is
+// still in the database, so the readers that check the presence of the nodes must not use it.
+type NodeCache struct {
+	nodes *lru.Cache[common.Hash, []byte]
+}
+
+// GlobalNodeCache is used by the database readers created by NewDbReader. Nil disables the caching.
+var GlobalNodeCache *NodeCache
+
+func InitGlobalNodeCache(size int) error {
+	var err error
+	GlobalNodeCache, err = NewNodeCache(size)
+	return err
+}
+
+// NewNodeCache creates the cache holding at most size nodes.
+func NewNodeCache(size int) (*NodeCache, error) {
+	nodes, err := lru.New[common.Hash, []byte](size)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeCache{nodes: nodes}, nil
+}
+
+func (c *NodeCache) Len() int {
+	return c.nodes.Len()
+}
+
+// The references of the stored nodes are 32 bytes long, see storeNode.
+// The shorter nodes are inlined into their references and never reach the cache.
+
+func (c *NodeCache) get(ref Reference) ([]byte, bool) {
+	return c.nodes.Get(common.BytesToHash(ref))
+}
+
+func (c *NodeCache) add(ref Reference, data []byte) {
+	c.nodes.Add(common.BytesToHash(ref), data)
+}
@@ -0,0 +1,49 @@
+package mpt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeCache(t *testing.T) {
+	t.Parallel()
+
+	const size = 16
+
+	cache, err := NewNodeCache(size)
+	require.NoError(t, err)
+
+	holder := NewInMemHolder()
+	trie := NewMPT(&holder, &Reader{getter: holder, cache: cache})
+	for i := range 100 {
+		require.NoError(t, trie.Set(fmt.Appendf(nil, "key-%d", i), fmt.Appendf(nil, "value-%d", i)))
+	}
+	require.Equal(t, size, cache.Len())
+
+	// The nodes are read from the cache even if they are gone from the storage.
+	key, value := []byte("key-42"), []byte("value-42")
+	got, err := trie.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+
+	clear(holder)
+
+	got, err = trie.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+
+	// The reader without the cache doesn't see them.
+	reader := NewReader(holder)
+	reader.SetRootHash(trie.RootHash())
+	_, err = reader.Get(key)
+	require.Error(t, err)
+
+	// The nodes are shared by the readers of the same cache.
+	other := &Reader{getter: NewInMemHolder(), cache: cache}
+	other.SetRootHash(trie.RootHash())
+	got, err = other.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+}
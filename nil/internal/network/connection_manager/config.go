@@ -14,6 +14,9 @@ type reputationChangeReason string
 
 const (
 	ReputationChangeInvalidBlockSignature = reputationChangeReason("invalid block signature")
+	ReputationChangeInvalidSnapshotChunk  = reputationChangeReason("invalid snapshot chunk")
+	ReputationChangeInvalidPubSubMessage  = reputationChangeReason("invalid pubsub message")
+	ReputationChangeLowPubSubScore        = reputationChangeReason("low pubsub score")
 )
 
 type ReputationChangeSettings = map[reputationChangeReason]Reputation
@@ -21,6 +24,9 @@ type ReputationChangeSettings = map[reputationChangeReason]Reputation
 func DefaultReputationChangeSettings() ReputationChangeSettings {
 	return ReputationChangeSettings{
 		ReputationChangeInvalidBlockSignature: -100,
+		ReputationChangeInvalidSnapshotChunk:  -100,
+		ReputationChangeInvalidPubSubMessage:  -50,
+		ReputationChangeLowPubSubScore:        -50,
 	}
 }
 
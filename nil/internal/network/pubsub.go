@@ -5,14 +5,36 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 )
 
-const subscriptionChannelSize = 100
+const (
+	subscriptionChannelSize = 100
+	validatorTimeout        = 10 * time.Second
+)
+
+// ValidationResult is the verdict of a topic validator.
+type ValidationResult = pubsub.ValidationResult
+
+const (
+	// ValidationAccept delivers the message to the subscribers and relays it to the other peers.
+	ValidationAccept = pubsub.ValidationAccept
+	// ValidationReject drops the message and penalizes the peer it has been received from.
+	ValidationReject = pubsub.ValidationReject
+	// ValidationIgnore drops the message without a penalty, e.g. if it is outdated.
+	ValidationIgnore = pubsub.ValidationIgnore
+)
+
+// TopicValidator checks a message before it is delivered to the subscribers and relayed to the other peers.
+// It is called concurrently, including for the messages published by the node itself.
+// The signature and the seqno of the message are checked by the pubsub before.
+type TopicValidator func(ctx context.Context, from PeerID, data []byte) ValidationResult
 
 type PubSub struct {
 	impl   *pubsub.PubSub // +checklocksignore: mu is not required, it just happens to be held always.
@@ -22,9 +44,12 @@ type PubSub struct {
 	topics map[string]*pubsub.Topic // +checklocks:mu
 	self   PeerID
 
+	reputationTracker cm.PeerReputationTracker
+
 	meter         telemetry.Meter
 	published     telemetry.Counter
 	publishedSize telemetry.Counter
+	rejected      telemetry.Counter
 
 	logger logging.Logger
 }
@@ -50,11 +75,6 @@ type Subscription struct {
 
 // newPubSub creates a new PubSub instance. It must be closed after use.
 func newPubSub(ctx context.Context, h Host, conf *Config, logger logging.Logger) (*PubSub, error) {
-	impl, err := pubsub.NewGossipSub(ctx, h, conf.PubSubOptions...)
-	if err != nil {
-		return nil, err
-	}
-
 	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/network/pubsub")
 	published, err := meter.Int64Counter("published_messages")
 	if err != nil {
@@ -64,19 +84,34 @@ func newPubSub(ctx context.Context, h Host, conf *Config, logger logging.Logger)
 	if err != nil {
 		return nil, err
 	}
+	rejected, err := meter.Int64Counter("rejected_messages")
+	if err != nil {
+		return nil, err
+	}
 
-	return &PubSub{
-		prefix:        conf.Prefix,
-		impl:          impl,
-		topics:        make(map[string]*pubsub.Topic),
-		self:          h.ID(),
-		meter:         meter,
-		published:     published,
-		publishedSize: publishedSize,
+	ps := &PubSub{
+		prefix:            conf.Prefix,
+		topics:            make(map[string]*pubsub.Topic),
+		self:              h.ID(),
+		reputationTracker: cm.TryGetPeerReputationTracker(h),
+		meter:             meter,
+		published:         published,
+		publishedSize:     publishedSize,
+		rejected:          rejected,
 		logger: logger.With().
 			Str(logging.FieldComponent, "pub-sub").
 			Logger(),
-	}, nil
+	}
+
+	opts := []pubsub.Option{
+		pubsub.WithPeerScore(newPeerScoreParams(), newPeerScoreThresholds()),
+		pubsub.WithPeerScoreInspect(pubsub.PeerScoreInspectFn(ps.inspectPeerScores), peerScoreInspectInterval),
+	}
+	ps.impl, err = pubsub.NewGossipSub(ctx, h, append(opts, conf.PubSubOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
 }
 
 func (ps *PubSub) Close() error {
@@ -164,6 +199,52 @@ func (ps *PubSub) Subscribe(topic string) (*Subscription, error) {
 	}, nil
 }
 
+// RegisterTopicValidator sets the validator of the topic. The messages rejected by the validator are not relayed,
+// and the peers that have sent them lose both the gossipsub score and the reputation.
+func (ps *PubSub) RegisterTopicValidator(topic string, validator TopicValidator) error {
+	topic = ps.withNetworkPrefix(topic)
+	return ps.impl.RegisterTopicValidator(
+		topic,
+		func(ctx context.Context, from PeerID, msg *pubsub.Message) ValidationResult {
+			res := validator(ctx, from, msg.GetData())
+			if res == ValidationReject {
+				ps.reportInvalidMessage(ctx, topic, from)
+			}
+			return res
+		},
+		pubsub.WithValidatorTimeout(validatorTimeout))
+}
+
+func (ps *PubSub) reportInvalidMessage(ctx context.Context, topic string, from PeerID) {
+	ps.logger.Debug().
+		Str(logging.FieldTopic, topic).
+		Stringer(logging.FieldPeerId, from).
+		Msg("Rejected invalid message")
+	ps.rejected.Add(ctx, 1, telattr.With(telattr.Topic(topic), telattr.P2PIdentity(ps.self)))
+
+	if from != ps.self && ps.reputationTracker != nil {
+		ps.reputationTracker.ReportPeer(from, cm.ReputationChangeInvalidPubSubMessage)
+	}
+}
+
+// inspectPeerScores reports the peers graylisted by the gossipsub to the connection manager,
+// so the peers that keep misbehaving are eventually disconnected.
+func (ps *PubSub) inspectPeerScores(scores map[PeerID]float64) {
+	if ps.reputationTracker == nil {
+		return
+	}
+	threshold := newPeerScoreThresholds().GraylistThreshold
+	for peer, score := range scores {
+		if score < threshold {
+			ps.logger.Debug().
+				Stringer(logging.FieldPeerId, peer).
+				Float64("score", score).
+				Msg("Peer is graylisted")
+			ps.reputationTracker.ReportPeer(peer, cm.ReputationChangeLowPubSubScore)
+		}
+	}
+}
+
 func (ps *PubSub) ListPeers(topic string) []PeerID {
 	t, err := ps.getTopic(topic)
 	if err != nil {
@@ -189,6 +270,9 @@ func (ps *PubSub) getTopic(topic string) (*pubsub.Topic, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := t.SetScoreParams(newTopicScoreParams()); err != nil {
+		return nil, errors.Join(err, t.Close())
+	}
 
 	ps.topics[topic] = t
 	return t, nil
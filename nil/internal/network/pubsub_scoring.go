@@ -0,0 +1,69 @@
+package network
+
+import (
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// The gossipsub peer scoring is driven by the topic validators: the peers relaying the rejected messages
+// get negative scores, so they are excluded from the meshes, then ignored, and finally reported
+// to the connection manager that disconnects them.
+// The mesh delivery rate penalties are disabled since the traffic of the topics is too irregular to expect a rate.
+
+const peerScoreInspectInterval = 10 * time.Second
+
+func newPeerScoreThresholds() *pubsub.PeerScoreThresholds {
+	return &pubsub.PeerScoreThresholds{
+		// The gossip is not emitted to and not accepted from the peers below the threshold.
+		GossipThreshold: -500,
+		// The messages published by the node itself are not sent to the peers below the threshold.
+		PublishThreshold: -1000,
+		// All RPCs from the peers below the threshold are ignored.
+		GraylistThreshold: -2500,
+		// The peer exchange is accepted only from the peers with the high score.
+		AcceptPXThreshold: 100,
+		// The mesh is improved by grafting better peers when its median score falls below the threshold.
+		OpportunisticGraftThreshold: 5,
+	}
+}
+
+func newPeerScoreParams() *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		Topics:        make(map[string]*pubsub.TopicScoreParams),
+		TopicScoreCap: 100,
+
+		AppSpecificScore:  func(peer.ID) float64 { return 0 },
+		AppSpecificWeight: 1,
+
+		// The local networks run many nodes on the same IP.
+		IPColocationFactorWeight: 0,
+
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(10 * time.Minute),
+
+		DecayInterval: pubsub.DefaultDecayInterval,
+		DecayToZero:   pubsub.DefaultDecayToZero,
+		RetainScore:   time.Hour,
+	}
+}
+
+func newTopicScoreParams() *pubsub.TopicScoreParams {
+	return &pubsub.TopicScoreParams{
+		TopicWeight: 1,
+
+		TimeInMeshWeight:  0.01,
+		TimeInMeshQuantum: time.Second,
+		TimeInMeshCap:     300,
+
+		FirstMessageDeliveriesWeight: 1,
+		FirstMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+		FirstMessageDeliveriesCap:    50,
+
+		// A single invalid message outweighs everything a peer can earn in a topic.
+		InvalidMessageDeliveriesWeight: -200,
+		InvalidMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+	}
+}
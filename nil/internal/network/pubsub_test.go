@@ -1,6 +1,8 @@
 package network
 
 import (
+	"bytes"
+	"context"
 	"testing"
 	"time"
 
@@ -70,6 +72,31 @@ func (s *PubSubSuite) TestSingleHost() {
 	s.ensureSkipped(sub, ch, 0)
 }
 
+func (s *PubSubSuite) TestTopicValidator() {
+	manager := s.newManager()
+	defer manager.Close()
+
+	const topic = "validated"
+	valid := []byte("valid")
+	err := manager.PubSub().RegisterTopicValidator(topic, func(_ context.Context, _ PeerID, data []byte) ValidationResult {
+		if bytes.Equal(data, valid) {
+			return ValidationAccept
+		}
+		return ValidationReject
+	})
+	s.Require().NoError(err)
+
+	sub, err := manager.PubSub().Subscribe(topic)
+	s.Require().NoError(err)
+	defer sub.Close()
+	ch := sub.Start(s.context, false)
+
+	// The messages published by the node itself are validated too.
+	s.Require().Error(manager.PubSub().Publish(s.context, topic, []byte("invalid")))
+	s.Require().NoError(manager.PubSub().Publish(s.context, topic, valid))
+	s.receive(ch, valid)
+}
+
 func (s *PubSubSuite) TestTwoHosts() {
 	m1 := s.newManager()
 	defer m1.Close()
@@ -9,4 +9,6 @@ import "math/big"
 // set of configuration options.
 type ChainConfig struct {
 	ChainID *big.Int `json:"chainId"` // chainId identifies the current chain and is used for replay protection
+
+	ProtocolVersion ProtocolVersion `json:"protocolVersion"` // ProtocolVersion selects the execution rules of the block
 }
@@ -0,0 +1,49 @@
+package params
+
+import "fmt"
+
+// ProtocolVersion identifies the execution rules of a block: the instruction set, the precompiled contracts,
+// the fee calculation and the validation of transactions. The versions are activated at the main shard heights
+// scheduled in the "protocol_versions" config param, so all nodes switch to the new rules at the same block.
+type ProtocolVersion uint32
+
+// ProtocolGenesis is the version every network starts with.
+const ProtocolGenesis ProtocolVersion = 0
+
+// Protocol describes a protocol version known to this node.
+type Protocol struct {
+	Version ProtocolVersion
+	Name    string
+	// Eips are enabled on top of the Cancun instruction set, see vm.EnableEIP.
+	Eips []int
+}
+
+// Protocols lists the known versions in the order of activation.
+// A version may only be scheduled once all nodes of the network know it.
+var Protocols = []Protocol{
+	{Version: ProtocolGenesis, Name: "genesis"},
+}
+
+func GetProtocol(version ProtocolVersion) (*Protocol, error) {
+	for i := range Protocols {
+		if Protocols[i].Version == version {
+			return &Protocols[i], nil
+		}
+	}
+	return nil, fmt.Errorf("protocol version %d is not supported by this node", version)
+}
+
+// ResolveProtocolChanges returns the value introduced by the latest version not above the given one.
+// The layers of the node keep only the versions that change them, so a version that doesn't touch a layer
+// inherits it from the preceding one.
+func ResolveProtocolChanges[T any](changes map[ProtocolVersion]T, version ProtocolVersion) (T, bool) {
+	var res T
+	found := false
+	var resVersion ProtocolVersion
+	for v, value := range changes {
+		if v <= version && (!found || v > resVersion) {
+			res, resVersion, found = value, v, true
+		}
+	}
+	return res, found
+}
@@ -0,0 +1,145 @@
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+// CallLog is a log emitted within a call frame.
+type CallLog struct {
+	Address types.Address `json:"address"`
+	Topics  []common.Hash `json:"topics"`
+	Data    hexutil.Bytes `json:"data"`
+	// Position of the log relative to the subcalls of the frame.
+	Position hexutil.Uint `json:"position"`
+}
+
+// CallFrame describes a single (sub)call made during the execution.
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    types.Address  `json:"from"`
+	To      types.Address  `json:"to"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Value   *hexutil.Big   `json:"value,omitempty"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []CallFrame    `json:"calls,omitempty"`
+	Logs    []CallLog      `json:"logs,omitempty"`
+}
+
+type callTracerConfig struct {
+	// Trace only the top-level call, nested calls are skipped.
+	OnlyTopCall bool `json:"onlyTopCall"`
+	// Include logs emitted by the calls.
+	WithLog bool `json:"withLog"`
+}
+
+type callTracer struct {
+	cfg callTracerConfig
+
+	// Stack of frames currently being executed, the first one is the top-level call.
+	callstack []CallFrame
+	// Set once the top-level call has exited, further frames are ignored.
+	done bool
+}
+
+func newCallTracer(cfg *Config) (*Tracer, error) {
+	t := &callTracer{}
+	if len(cfg.TracerConfig) > 0 {
+		if err := json.Unmarshal(cfg.TracerConfig, &t.cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s config: %w", CallTracerName, err)
+		}
+	}
+
+	hooks := &tracing.Hooks{
+		OnEnter: t.onEnter,
+		OnExit:  t.onExit,
+	}
+	if t.cfg.WithLog {
+		hooks.OnLog = t.onLog
+	}
+	return &Tracer{
+		Hooks:     hooks,
+		GetResult: t.getResult,
+	}, nil
+}
+
+func (t *callTracer) onEnter(
+	depth int, typ byte, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int,
+) {
+	if t.done || (t.cfg.OnlyTopCall && depth > 0) {
+		return
+	}
+	// Frames started outside of the top-level call (e.g. verification) are not traced.
+	if depth > 0 && len(t.callstack) == 0 {
+		return
+	}
+
+	frame := CallFrame{
+		Type:  vm.OpCode(typ).String(),
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.callstack = append(t.callstack, frame)
+}
+
+func (t *callTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.done || (t.cfg.OnlyTopCall && depth > 0) || len(t.callstack) == 0 {
+		return
+	}
+
+	size := len(t.callstack)
+	frame := &t.callstack[size-1]
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.Output = common.CopyBytes(output)
+	if err != nil {
+		frame.Error = err.Error()
+		if errors.Is(err, vm.ErrExecutionReverted) || reverted {
+			frame.Output = nil
+		}
+	}
+
+	if size == 1 {
+		t.done = true
+		return
+	}
+
+	t.callstack = t.callstack[:size-1]
+	parent := &t.callstack[size-2]
+	parent.Calls = append(parent.Calls, *frame)
+}
+
+func (t *callTracer) onLog(log *types.Log) {
+	if t.done || len(t.callstack) == 0 {
+		return
+	}
+	frame := &t.callstack[len(t.callstack)-1]
+	frame.Logs = append(frame.Logs, CallLog{
+		Address:  log.Address,
+		Topics:   log.Topics,
+		Data:     common.CopyBytes(log.Data),
+		Position: hexutil.Uint(len(frame.Calls)),
+	})
+}
+
+func (t *callTracer) getResult() (json.RawMessage, error) {
+	if len(t.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	return json.Marshal(t.callstack[0])
+}
@@ -0,0 +1,84 @@
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type prestateTracerConfig struct {
+	// Report the state after the transaction along with the state before it.
+	DiffMode bool `json:"diffMode"`
+}
+
+// journaledState is the state that records the changes made by the transactions, i.e. the execution state.
+type journaledState interface {
+	JournalLength() int
+	StateDiff(since int) (*execution.StateDiff, error)
+}
+
+// prestateTracer reports the accounts changed by the transaction code.
+// Only the changed fields, storage slots and token balances of the accounts are reported.
+type prestateTracer struct {
+	cfg prestateTracerConfig
+
+	state journaledState
+	since int
+
+	diff *execution.StateDiff
+	err  error
+}
+
+func newPrestateTracer(cfg *Config) (*Tracer, error) {
+	t := &prestateTracer{}
+	if len(cfg.TracerConfig) > 0 {
+		if err := json.Unmarshal(cfg.TracerConfig, &t.cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s config: %w", PrestateTracerName, err)
+		}
+	}
+
+	return &Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.onTxStart,
+			OnTxEnd:   t.onTxEnd,
+		},
+		GetResult: t.getResult,
+	}, nil
+}
+
+func (t *prestateTracer) onTxStart(env *tracing.VMContext, _ *types.Transaction) {
+	if t.state != nil {
+		return
+	}
+	state, ok := env.StateDB.(journaledState)
+	if !ok {
+		t.err = errors.New("state doesn't record the changes")
+		return
+	}
+	t.state = state
+	t.since = state.JournalLength()
+}
+
+func (t *prestateTracer) onTxEnd(*tracing.VMContext, *types.Transaction, types.ExecError) {
+	if t.state == nil {
+		return
+	}
+	t.diff, t.err = t.state.StateDiff(t.since)
+}
+
+func (t *prestateTracer) getResult() (json.RawMessage, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if t.diff == nil {
+		return nil, errors.New("transaction was not executed")
+	}
+	if t.cfg.DiffMode {
+		return json.Marshal(t.diff)
+	}
+	return json.Marshal(t.diff.Pre)
+}
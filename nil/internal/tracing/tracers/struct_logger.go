@@ -0,0 +1,173 @@
+package tracers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/holiman/uint256"
+)
+
+// StructLog is emitted by the struct logger for every executed opcode.
+type StructLog struct {
+	Pc         uint64                      `json:"pc"`
+	Op         string                      `json:"op"`
+	Gas        uint64                      `json:"gas"`
+	GasCost    uint64                      `json:"gasCost"`
+	Depth      int                         `json:"depth"`
+	Error      string                      `json:"error,omitempty"`
+	Stack      []string                    `json:"stack,omitempty"`
+	Memory     []string                    `json:"memory,omitempty"`
+	ReturnData hexutil.Bytes               `json:"returnData,omitempty"`
+	Storage    map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Refund     uint64                      `json:"refund,omitempty"`
+}
+
+// StructLoggerResult is the result of the struct logger.
+type StructLoggerResult struct {
+	Gas         uint64        `json:"gas"`
+	Failed      bool          `json:"failed"`
+	ReturnValue hexutil.Bytes `json:"returnValue"`
+	StructLogs  []StructLog   `json:"structLogs"`
+}
+
+type structLogger struct {
+	cfg *Config
+	env *tracing.VMContext
+
+	storage map[types.Address]map[common.Hash]common.Hash
+	logs    []StructLog
+
+	output  []byte
+	gasUsed uint64
+	err     error
+}
+
+func newStructLogger(cfg *Config) (*Tracer, error) {
+	l := &structLogger{
+		cfg:     cfg,
+		storage: make(map[types.Address]map[common.Hash]common.Hash),
+	}
+	return &Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: l.onTxStart,
+			OnExit:    l.onExit,
+			OnOpcode:  l.onOpcode,
+		},
+		GetResult: l.getResult,
+	}, nil
+}
+
+func (l *structLogger) onTxStart(env *tracing.VMContext, _ *types.Transaction) {
+	l.env = env
+}
+
+func (l *structLogger) onExit(depth int, output []byte, gasUsed uint64, err error, _ bool) {
+	if depth != 0 {
+		return
+	}
+	l.output = common.CopyBytes(output)
+	l.gasUsed = gasUsed
+	l.err = err
+}
+
+func (l *structLogger) onOpcode(
+	pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error,
+) {
+	if l.cfg.Limit != 0 && len(l.logs) >= l.cfg.Limit {
+		return
+	}
+
+	op := vm.OpCode(opcode)
+	log := StructLog{
+		Pc:      pc,
+		Op:      op.String(),
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+
+	stack := scope.StackData()
+	if !l.cfg.DisableStack {
+		log.Stack = make([]string, len(stack))
+		for i, v := range stack {
+			log.Stack[i] = v.Hex()
+		}
+	}
+
+	if l.cfg.EnableMemory {
+		memory := scope.MemoryData()
+		log.Memory = make([]string, 0, (len(memory)+31)/32)
+		for i := 0; i < len(memory); i += 32 {
+			log.Memory = append(log.Memory, hex.EncodeToString(memory[i:min(i+32, len(memory))]))
+		}
+	}
+
+	if l.cfg.EnableReturnData && len(rData) > 0 {
+		log.ReturnData = common.CopyBytes(rData)
+	}
+
+	if !l.cfg.DisableStorage && (op == vm.SLOAD || op == vm.SSTORE) {
+		log.Storage = l.captureStorage(op, scope, stack)
+	}
+
+	if l.env != nil && l.env.StateDB != nil {
+		log.Refund = l.env.StateDB.GetRefund()
+	}
+
+	l.logs = append(l.logs, log)
+}
+
+// captureStorage updates the known storage of the current contract with the slot accessed by the opcode
+// and returns a copy of it.
+func (l *structLogger) captureStorage(
+	op vm.OpCode, scope tracing.OpContext, stack []uint256.Int,
+) map[common.Hash]common.Hash {
+	addr := scope.Address()
+	storage, ok := l.storage[addr]
+	if !ok {
+		storage = make(map[common.Hash]common.Hash)
+		l.storage[addr] = storage
+	}
+
+	switch op {
+	case vm.SLOAD:
+		if len(stack) < 1 || l.env == nil || l.env.StateDB == nil {
+			break
+		}
+		slot := common.Hash(stack[len(stack)-1].Bytes32())
+		value, err := l.env.StateDB.GetState(addr, slot)
+		if err != nil {
+			break
+		}
+		storage[slot] = value
+	case vm.SSTORE:
+		if len(stack) < 2 {
+			break
+		}
+		slot := common.Hash(stack[len(stack)-1].Bytes32())
+		storage[slot] = common.Hash(stack[len(stack)-2].Bytes32())
+	}
+	return maps.Clone(storage)
+}
+
+func (l *structLogger) getResult() (json.RawMessage, error) {
+	res := &StructLoggerResult{
+		Gas:         l.gasUsed,
+		Failed:      l.err != nil,
+		ReturnValue: l.output,
+		StructLogs:  l.logs,
+	}
+	if res.StructLogs == nil {
+		res.StructLogs = []StructLog{}
+	}
+	return json.Marshal(res)
+}
@@ -0,0 +1,77 @@
+// Package tracers contains EVM tracers that can be attached to a transaction execution
+// through tracing.Hooks, e.g. by the debug RPC methods.
+package tracers
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+)
+
+const (
+	// StructLoggerName is the name of the default opcode-level tracer.
+	StructLoggerName = "structLogger"
+	// CallTracerName is the name of the tracer that produces a tree of call frames.
+	CallTracerName = "callTracer"
+	// PrestateTracerName is the name of the tracer that reports the state changed by the transaction.
+	PrestateTracerName = "prestateTracer"
+)
+
+// Config holds the options common for all tracers.
+// TracerConfig contains options specific to the selected tracer.
+type Config struct {
+	EnableMemory     bool            `json:"enableMemory"`
+	DisableStack     bool            `json:"disableStack"`
+	DisableStorage   bool            `json:"disableStorage"`
+	EnableReturnData bool            `json:"enableReturnData"`
+	Limit            int             `json:"limit"`
+	TracerConfig     json.RawMessage `json:"tracerConfig,omitempty"`
+}
+
+// TraceConfig selects the tracer by its name and configures it.
+type TraceConfig struct {
+	Config
+
+	// Tracer is the name of the tracer, the struct logger is used if empty.
+	Tracer string `json:"tracer,omitempty"`
+}
+
+// Tracer is a set of hooks collecting the trace and a function to fetch the result
+// once the execution is over.
+type Tracer struct {
+	*tracing.Hooks
+
+	GetResult func() (json.RawMessage, error)
+}
+
+type ctorFn func(cfg *Config) (*Tracer, error)
+
+var tracers = map[string]ctorFn{
+	StructLoggerName:   newStructLogger,
+	CallTracerName:     newCallTracer,
+	PrestateTracerName: newPrestateTracer,
+}
+
+// New creates the tracer selected by the config. A nil config selects the struct logger with default options.
+func New(cfg *TraceConfig) (*Tracer, error) {
+	if cfg == nil {
+		cfg = &TraceConfig{}
+	}
+	name := cfg.Tracer
+	if name == "" {
+		name = StructLoggerName
+	}
+	ctor, ok := tracers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tracer %q, available tracers: %v", name, Names())
+	}
+	return ctor(&cfg.Config)
+}
+
+// Names returns names of all available tracers.
+func Names() []string {
+	return slices.Sorted(maps.Keys(tracers))
+}
@@ -0,0 +1,181 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+type opContextStub struct {
+	addr   types.Address
+	stack  []uint256.Int
+	memory []byte
+}
+
+func (c *opContextStub) MemoryData() []byte       { return c.memory }
+func (c *opContextStub) StackData() []uint256.Int { return c.stack }
+func (c *opContextStub) Caller() types.Address    { return types.EmptyAddress }
+func (c *opContextStub) Address() types.Address   { return c.addr }
+func (c *opContextStub) CallValue() *uint256.Int  { return uint256.NewInt(0) }
+func (c *opContextStub) CallInput() []byte        { return nil }
+func (c *opContextStub) Code() []byte             { return nil }
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tracer, err := New(nil)
+	require.NoError(t, err)
+	require.NotNil(t, tracer.OnOpcode)
+
+	_, err = New(&TraceConfig{Tracer: "unknown"})
+	require.ErrorContains(t, err, "unknown tracer")
+
+	_, err = New(&TraceConfig{Tracer: CallTracerName, Config: Config{TracerConfig: json.RawMessage("[]")}})
+	require.Error(t, err)
+}
+
+func TestStructLogger(t *testing.T) {
+	t.Parallel()
+
+	tracer, err := New(&TraceConfig{Config: Config{EnableMemory: true, Limit: 2}})
+	require.NoError(t, err)
+
+	scope := &opContextStub{
+		addr:   types.GenerateRandomAddress(types.BaseShardId),
+		stack:  []uint256.Int{*uint256.NewInt(7), *uint256.NewInt(1)},
+		memory: make([]byte, 40),
+	}
+	tracer.OnOpcode(0, byte(vm.PUSH1), 100, 3, scope, nil, 1, nil)
+	tracer.OnOpcode(2, byte(vm.SSTORE), 97, 20000, scope, nil, 1, nil)
+	// Exceeds the limit.
+	tracer.OnOpcode(3, byte(vm.STOP), 0, 0, scope, nil, 1, nil)
+	tracer.OnExit(0, []byte{0x1}, 20003, nil, false)
+
+	raw, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var res StructLoggerResult
+	require.NoError(t, json.Unmarshal(raw, &res))
+	require.EqualValues(t, 20003, res.Gas)
+	require.False(t, res.Failed)
+	require.EqualValues(t, []byte{0x1}, res.ReturnValue)
+	require.Len(t, res.StructLogs, 2)
+
+	require.Equal(t, "PUSH1", res.StructLogs[0].Op)
+	require.Equal(t, []string{"0x7", "0x1"}, res.StructLogs[0].Stack)
+	require.Len(t, res.StructLogs[0].Memory, 2)
+	require.Nil(t, res.StructLogs[0].Storage)
+
+	require.Equal(t, "SSTORE", res.StructLogs[1].Op)
+	require.Len(t, res.StructLogs[1].Storage, 1)
+	for slot, value := range res.StructLogs[1].Storage {
+		require.EqualValues(t, 1, slot.Uint256().Uint64())
+		require.EqualValues(t, 7, value.Uint256().Uint64())
+	}
+}
+
+func TestCallTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer, err := New(&TraceConfig{Tracer: CallTracerName})
+	require.NoError(t, err)
+
+	a := types.GenerateRandomAddress(types.BaseShardId)
+	b := types.GenerateRandomAddress(types.BaseShardId)
+	c := types.GenerateRandomAddress(types.BaseShardId)
+
+	tracer.OnEnter(0, byte(vm.CALL), a, b, []byte{1}, 1000, big.NewInt(5))
+	tracer.OnEnter(1, byte(vm.STATICCALL), b, c, []byte{2}, 500, nil)
+	tracer.OnExit(1, []byte{3}, 100, nil, false)
+	tracer.OnEnter(1, byte(vm.CALL), b, c, nil, 300, big.NewInt(0))
+	tracer.OnExit(1, nil, 300, vm.ErrOutOfGas, true)
+	tracer.OnExit(0, []byte{4}, 600, nil, false)
+	// Frames after the top-level call are ignored.
+	tracer.OnEnter(0, byte(vm.CALL), a, c, nil, 1000, nil)
+
+	raw, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var res CallFrame
+	require.NoError(t, json.Unmarshal(raw, &res))
+	require.Equal(t, "CALL", res.Type)
+	require.Equal(t, b, res.To)
+	require.EqualValues(t, 600, res.GasUsed)
+	require.EqualValues(t, 5, res.Value.ToInt().Int64())
+	require.Len(t, res.Calls, 2)
+
+	require.Equal(t, "STATICCALL", res.Calls[0].Type)
+	require.EqualValues(t, []byte{3}, res.Calls[0].Output)
+	require.Empty(t, res.Calls[0].Error)
+
+	require.Equal(t, vm.ErrOutOfGas.Error(), res.Calls[1].Error)
+}
+
+func TestPrestateTracer(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, types.BaseShardId, execution.StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(100)))
+
+	run := func(tracer *Tracer, balance uint64) {
+		t.Helper()
+
+		env := &tracing.VMContext{StateDB: es}
+		tracer.OnTxStart(env, nil)
+		require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(balance)))
+		tracer.OnTxEnd(env, nil, nil)
+	}
+
+	t.Run("Prestate", func(t *testing.T) {
+		tracer, err := New(&TraceConfig{Tracer: PrestateTracerName})
+		require.NoError(t, err)
+		run(tracer, 200)
+
+		raw, err := tracer.GetResult()
+		require.NoError(t, err)
+
+		var res map[types.Address]*execution.AccountDiff
+		require.NoError(t, json.Unmarshal(raw, &res))
+		require.Len(t, res, 1)
+		require.Equal(t, types.NewValueFromUint64(100), *res[addr].Balance)
+	})
+
+	t.Run("DiffMode", func(t *testing.T) {
+		tracer, err := New(&TraceConfig{
+			Tracer: PrestateTracerName,
+			Config: Config{TracerConfig: json.RawMessage(`{"diffMode": true}`)},
+		})
+		require.NoError(t, err)
+		run(tracer, 300)
+
+		raw, err := tracer.GetResult()
+		require.NoError(t, err)
+
+		var res execution.StateDiff
+		require.NoError(t, json.Unmarshal(raw, &res))
+		require.Equal(t, types.NewValueFromUint64(200), *res.Pre[addr].Balance)
+		require.Equal(t, types.NewValueFromUint64(300), *res.Post[addr].Balance)
+	})
+}
@@ -31,6 +31,8 @@ var (
 	BtcFaucetAddress        = ShardAndHexToAddress(BaseShardId, "111111111111111111111111111111111114")
 	UsdcFaucetAddress       = ShardAndHexToAddress(BaseShardId, "111111111111111111111111111111111115")
 	L1BlockInfoAddress      = ShardAndHexToAddress(MainShardId, "222222222222222222222222222222222222")
+	StakingAddress          = ShardAndHexToAddress(MainShardId, "333333333333333333333333333333333333")
+	SlashingAddress         = ShardAndHexToAddress(MainShardId, "444444444444444444444444444444444444")
 	GovernanceAddress       = ShardAndHexToAddress(MainShardId, "777777777777777777777777777777777777")
 )
 
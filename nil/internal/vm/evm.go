@@ -20,8 +20,7 @@ type (
 )
 
 func (evm *EVM) precompile(addr types.Address) (PrecompiledContract, bool) {
-	precompiles := PrecompiledContractsPrague
-	p, ok := precompiles[addr]
+	p, ok := evm.precompiles[addr]
 	return p, ok
 }
 
@@ -42,6 +41,8 @@ type BlockContext struct {
 	Random *common.Hash // Provides information for PREVRANDAO
 
 	RollbackCounter uint32 // Provides information for rollback handling
+
+	ProtocolVersion params.ProtocolVersion // Selects the instruction set and the precompiled contracts
 }
 
 // TxContext provides the EVM with information about a transaction.
@@ -75,6 +76,8 @@ type EVM struct {
 
 	// chainConfig contains information about the current chain
 	chainConfig *params.ChainConfig
+	// precompiles are the precompiled contracts of the protocol version of the block
+	precompiles map[types.Address]PrecompiledContract
 	// virtual machine configuration options used to initialise the
 	// evm.
 	Config Config
@@ -117,7 +120,11 @@ func NewEVM(
 			Origin:   origin,
 			GasPrice: gasPrice.ToBig(),
 		},
-		chainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		chainConfig: &params.ChainConfig{
+			ChainID:         big.NewInt(1),
+			ProtocolVersion: blockContext.ProtocolVersion,
+		},
+		precompiles: activePrecompiles(blockContext.ProtocolVersion),
 	}
 	evm.interpreter = NewEVMInterpreter(evm)
 	return evm
@@ -138,9 +145,16 @@ func (evm *EVM) Call(
 	input []byte,
 	gas uint64,
 	value *uint256.Int,
-) ([]byte, uint64, error) {
+) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = false
 
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, CALL, caller.Address(), addr, input, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -157,7 +171,6 @@ func (evm *EVM) Call(
 	snapshot := evm.StateDB.Snapshot()
 	p, isPrecompile := evm.precompile(addr)
 
-	var ret []byte
 	var runErr error
 	if isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, value, caller, readOnly)
@@ -241,9 +254,16 @@ func (evm *EVM) CallCode(
 	input []byte,
 	gas uint64,
 	value *uint256.Int,
-) ([]byte, uint64, error) {
+) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = false
 
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, CALLCODE, caller.Address(), addr, input, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -261,7 +281,6 @@ func (evm *EVM) CallCode(
 	snapshot := evm.StateDB.Snapshot()
 
 	// It is allowed to call precompiles, even via delegatecall
-	var ret []byte
 	var runErr error
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, value, caller, readOnly)
@@ -296,9 +315,26 @@ func (evm *EVM) CallCode(
 //
 // DelegateCall differs from CallCode in the sense that it executes the given address'
 // code with the caller as context and the caller is set to the caller of the caller.
-func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byte, gas uint64) ([]byte, uint64, error) {
+func (evm *EVM) DelegateCall(
+	caller ContractRef,
+	addr types.Address,
+	input []byte,
+	gas uint64,
+) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = false
 
+	if evm.Config.Tracer != nil {
+		// DELEGATECALL inherits value from parent call
+		var value *big.Int
+		if c, ok := caller.(*Contract); ok && c.value != nil {
+			value = c.value.ToBig()
+		}
+		evm.captureBegin(evm.depth, DELEGATECALL, caller.Address(), addr, input, gas, value)
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -306,7 +342,6 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byt
 	snapshot := evm.StateDB.Snapshot()
 
 	// It is allowed to call precompiles, even via delegatecall
-	var ret []byte
 	var runErr error
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, nil, caller, readOnly)
@@ -339,9 +374,21 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byt
 // as parameters while disallowing any modifications to the state during the call.
 // Opcodes that attempt to perform such modifications will result in exceptions
 // instead of performing the modifications.
-func (evm *EVM) StaticCall(caller ContractRef, addr types.Address, input []byte, gas uint64) ([]byte, uint64, error) {
+func (evm *EVM) StaticCall(
+	caller ContractRef,
+	addr types.Address,
+	input []byte,
+	gas uint64,
+) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = true
 
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, STATICCALL, caller.Address(), addr, input, gas, nil)
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -353,7 +400,6 @@ func (evm *EVM) StaticCall(caller ContractRef, addr types.Address, input []byte,
 	// We could change this, but for now it's left for legacy reasons
 	snapshot := evm.StateDB.Snapshot()
 
-	var ret []byte
 	var runErr error
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, nil, caller, readOnly)
@@ -393,7 +439,15 @@ func (evm *EVM) create(
 	gas uint64,
 	value *uint256.Int,
 	address types.Address,
-) ([]byte, types.Address, uint64, error) {
+	typ OpCode,
+) (ret []byte, createAddress types.Address, leftOverGas uint64, err error) {
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, typ, caller.Address(), address, codeAndHash, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Depth check execution. Fail if we're trying to execute above the
 	// limit.
 	if evm.depth > int(params.CallCreateDepth) {
@@ -464,7 +518,7 @@ func (evm *EVM) create(
 	contract := NewContract(caller, AccountRef(address), value, gas, nil)
 	contract.SetCallCode(address, codeAndHash.Hash(), codeAndHash)
 
-	ret, err := evm.interpreter.Run(contract, nil, false)
+	ret, err = evm.interpreter.Run(contract, nil, false)
 
 	// Check whether the max code size has been exceeded (EIP-158)
 	if err == nil && len(ret) > params.MaxCodeSize {
@@ -507,7 +561,7 @@ func (evm *EVM) Deploy(
 	gas uint64,
 	value *uint256.Int,
 ) (ret []byte, deployAddr types.Address, leftOverGas uint64, err error) {
-	return evm.create(caller, code, gas, value, addr)
+	return evm.create(caller, code, gas, value, addr, CREATE)
 }
 
 // Create creates a new contract using code as deployment code.
@@ -533,7 +587,7 @@ func (evm *EVM) Create(
 	binary.BigEndian.PutUint64(salt[24:32], extSeqno.Uint64())
 	payload := types.BuildDeployPayload(code, salt)
 	contractAddr = types.CreateAddress(caller.Address().ShardId(), payload)
-	return evm.create(caller, code, gas, value, contractAddr)
+	return evm.create(caller, code, gas, value, contractAddr, CREATE)
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -548,7 +602,35 @@ func (evm *EVM) Create2(
 	salt *uint256.Int,
 ) (ret []byte, contractAddr types.Address, leftOverGas uint64, err error) {
 	contractAddr = types.CreateAddressForCreate2(caller.Address(), code, common.BytesToHash(salt.Bytes()))
-	return evm.create(caller, code, gas, endowment, contractAddr)
+	return evm.create(caller, code, gas, endowment, contractAddr, CREATE2)
+}
+
+func (evm *EVM) captureBegin(
+	depth int,
+	typ OpCode,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	startGas uint64,
+	value *big.Int,
+) {
+	tracer := evm.Config.Tracer
+	if tracer.OnEnter != nil {
+		tracer.OnEnter(depth, byte(typ), from, to, input, startGas, value)
+	}
+	if tracer.OnGasChange != nil {
+		tracer.OnGasChange(0, startGas, tracing.GasChangeCallInitialBalance)
+	}
+}
+
+func (evm *EVM) captureEnd(depth int, startGas uint64, leftOverGas uint64, ret []byte, err error) {
+	tracer := evm.Config.Tracer
+	if leftOverGas != 0 && tracer.OnGasChange != nil {
+		tracer.OnGasChange(leftOverGas, 0, tracing.GasChangeCallLeftOverReturned)
+	}
+	if tracer.OnExit != nil {
+		tracer.OnExit(depth, ret, startGas-leftOverGas, err, err != nil)
+	}
 }
 
 // canTransfer checks whether there are enough funds in the address' account to make a transfer.
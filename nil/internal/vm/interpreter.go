@@ -80,7 +80,7 @@ type EVMInterpreter struct {
 }
 
 func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
-	return &EVMInterpreter{evm: evm, table: &CancunInstructionSet}
+	return &EVMInterpreter{evm: evm, table: instructionSet(evm.Context.ProtocolVersion)}
 }
 
 // Run loops and evaluates the contract's code with the given input data and returns
@@ -73,6 +73,19 @@ func validate(jt JumpTable) JumpTable {
 	return jt
 }
 
+// copyJumpTable creates copy of the operations from the provided source JumpTable,
+// so the EIP activators can modify it without polluting the source.
+func copyJumpTable(source *JumpTable) *JumpTable {
+	dest := *source
+	for i, op := range source {
+		if op != nil {
+			opCopy := *op
+			dest[i] = &opCopy
+		}
+	}
+	return &dest
+}
+
 func newCancunInstructionSet() JumpTable {
 	instructionSet := newShanghaiInstructionSet()
 	enable4844(&instructionSet) // EIP-4844 (BLOBHASH opcode)
@@ -29,6 +29,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm/console"
@@ -84,19 +85,20 @@ type SimplePrecompiledContract interface {
 }
 
 var (
-	AsyncCallAddress         = types.BytesToAddress([]byte{0xfd})
-	VerifySignatureAddress   = types.BytesToAddress([]byte{0xfe})
-	CheckIsInternalAddress   = types.BytesToAddress([]byte{0xff})
-	ManageTokenAddress       = types.BytesToAddress([]byte{0xd0})
-	TokenBalanceAddress      = types.BytesToAddress([]byte{0xd1})
-	SendTokensAddress        = types.BytesToAddress([]byte{0xd2})
-	TransactionTokensAddress = types.BytesToAddress([]byte{0xd3})
-	GetGasPriceAddress       = types.BytesToAddress([]byte{0xd4})
-	ConfigParamAddress       = types.BytesToAddress([]byte{0xd7})
-	CheckIsResponseAddress   = types.BytesToAddress([]byte{0xd9})
-	LogAddress               = types.BytesToAddress([]byte{0xda})
-	GovernanceAddress        = types.BytesToAddress([]byte{0xdb})
-	ConsoleAddress           = types.HexToAddress("0x00000000000000000000000000000000000dEBa6")
+	AsyncCallAddress          = types.BytesToAddress([]byte{0xfd})
+	VerifySignatureAddress    = types.BytesToAddress([]byte{0xfe})
+	CheckIsInternalAddress    = types.BytesToAddress([]byte{0xff})
+	ManageTokenAddress        = types.BytesToAddress([]byte{0xd0})
+	TokenBalanceAddress       = types.BytesToAddress([]byte{0xd1})
+	SendTokensAddress         = types.BytesToAddress([]byte{0xd2})
+	TransactionTokensAddress  = types.BytesToAddress([]byte{0xd3})
+	GetGasPriceAddress        = types.BytesToAddress([]byte{0xd4})
+	ConfigParamAddress        = types.BytesToAddress([]byte{0xd7})
+	CheckIsResponseAddress    = types.BytesToAddress([]byte{0xd9})
+	LogAddress                = types.BytesToAddress([]byte{0xda})
+	GovernanceAddress         = types.BytesToAddress([]byte{0xdb})
+	VerifyBlsSignatureAddress = types.BytesToAddress([]byte{0xdc})
+	ConsoleAddress            = types.HexToAddress("0x00000000000000000000000000000000000dEBa6")
 )
 
 // PrecompiledContractsPrague contains the set of pre-compiled Ethereum
@@ -123,19 +125,20 @@ var PrecompiledContractsPrague = map[types.Address]PrecompiledContract{
 	types.BytesToAddress([]byte{0x13}): &simple{&bls12381MapG2{}},
 
 	// NilFoundation precompiled contracts
-	AsyncCallAddress:         &asyncCall{},
-	VerifySignatureAddress:   &simple{&verifySignature{}},
-	CheckIsInternalAddress:   &checkIsInternal{},
-	ManageTokenAddress:       &manageToken{},
-	TokenBalanceAddress:      &tokenBalance{},
-	SendTokensAddress:        &sendTokenSync{},
-	TransactionTokensAddress: &getTransactionTokens{},
-	GetGasPriceAddress:       &getGasPrice{},
-	ConfigParamAddress:       &configParam{},
-	CheckIsResponseAddress:   &checkIsResponse{},
-	LogAddress:               &emitLog{},
-	GovernanceAddress:        &governance{},
-	ConsoleAddress:           &consolePrecompile{},
+	AsyncCallAddress:          &asyncCall{},
+	VerifySignatureAddress:    &simple{&verifySignature{}},
+	CheckIsInternalAddress:    &checkIsInternal{},
+	ManageTokenAddress:        &manageToken{},
+	TokenBalanceAddress:       &tokenBalance{},
+	SendTokensAddress:         &sendTokenSync{},
+	TransactionTokensAddress:  &getTransactionTokens{},
+	GetGasPriceAddress:        &getGasPrice{},
+	ConfigParamAddress:        &configParam{},
+	CheckIsResponseAddress:    &checkIsResponse{},
+	LogAddress:                &emitLog{},
+	GovernanceAddress:         &governance{},
+	VerifyBlsSignatureAddress: &simple{&verifyBlsSignature{}},
+	ConsoleAddress:            &consolePrecompile{},
 }
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
@@ -522,6 +525,41 @@ func (a *verifySignature) Run(input []byte) ([]byte, error) {
 	return common.EmptyHash[:], nil
 }
 
+// verifyBlsSignature checks the BLS signature of the hash made by the validator key.
+// It takes the same arguments as verifySignature.
+type verifyBlsSignature struct{}
+
+var _ SimplePrecompiledContract = (*verifyBlsSignature)(nil)
+
+func (c *verifyBlsSignature) RequiredGas([]byte) uint64 {
+	return 100_000
+}
+
+func (a *verifyBlsSignature) Run(input []byte) ([]byte, error) {
+	values, err := VerifySignatureArgs().Unpack(input)
+	if err != nil || len(values) != 3 {
+		return common.EmptyHash[:], nil //nolint:nilerr
+	}
+	pubkey, ok1 := values[0].([]byte)
+	hash, ok2 := values[1].(*big.Int)
+	sig, ok3 := values[2].([]byte)
+	if !ok1 || !ok2 || !ok3 {
+		return common.EmptyHash[:], nil
+	}
+	pk, err := bls.PublicKeyFromBytes(pubkey)
+	if err != nil {
+		return common.EmptyHash[:], nil //nolint:nilerr
+	}
+	signature, err := bls.SignatureFromBytes(sig)
+	if err != nil {
+		return common.EmptyHash[:], nil //nolint:nilerr
+	}
+	if err := signature.Verify(pk, common.BigToHash(hash).Bytes()); err != nil {
+		return common.EmptyHash[:], nil //nolint:nilerr
+	}
+	return common.LeftPadBytes([]byte{1}, 32), nil
+}
+
 // arguments: bytes pubkey, uint256 hash, bytes signature
 // returns: bool signatureValid
 var (
@@ -904,6 +942,13 @@ func (c *configParam) Run(state StateDB, input []byte, value *uint256.Int, calle
 			return nil, types.NewVmVerboseError(types.ErrorAbiUnpackFailed, err.Error())
 		}
 
+		// The validators with keys that can't be parsed would halt the chain, so such a set is never accepted.
+		if validators, ok := params.(*config.ParamValidators); ok {
+			if err := validators.CheckPublicKeys(); err != nil {
+				return nil, types.NewVmVerboseError(types.ErrorPrecompileConfigSetParamFailed, err.Error())
+			}
+		}
+
 		if !state.GetShardID().IsMainShard() {
 			return nil, types.NewVmError(types.ErrorOnlyMainShardContractsCanChangeConfig)
 		}
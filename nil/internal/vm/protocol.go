@@ -0,0 +1,47 @@
+package vm
+
+import (
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// precompilesChanges holds the precompiled contracts of the protocol versions that change them.
+var precompilesChanges = map[params.ProtocolVersion]map[types.Address]PrecompiledContract{
+	params.ProtocolGenesis: PrecompiledContractsPrague,
+}
+
+var (
+	instructionSets = make(map[params.ProtocolVersion]*JumpTable)
+	precompiles     = make(map[params.ProtocolVersion]map[types.Address]PrecompiledContract)
+)
+
+func init() {
+	for _, p := range params.Protocols {
+		jt := copyJumpTable(&CancunInstructionSet)
+		for _, eip := range p.Eips {
+			check.PanicIfErr(EnableEIP(eip, jt))
+		}
+		validate(*jt)
+		instructionSets[p.Version] = jt
+
+		contracts, ok := params.ResolveProtocolChanges(precompilesChanges, p.Version)
+		check.PanicIfNotf(ok, "no precompiled contracts for protocol version %d", p.Version)
+		precompiles[p.Version] = contracts
+	}
+}
+
+// instructionSet returns the jump table of the protocol version.
+// The version is checked against the known ones when the execution state is created.
+func instructionSet(version params.ProtocolVersion) *JumpTable {
+	jt, ok := instructionSets[version]
+	check.PanicIfNotf(ok, "unknown protocol version %d", version)
+	return jt
+}
+
+// activePrecompiles returns the precompiled contracts of the protocol version.
+func activePrecompiles(version params.ProtocolVersion) map[types.Address]PrecompiledContract {
+	contracts, ok := precompiles[version]
+	check.PanicIfNotf(ok, "unknown protocol version %d", version)
+	return contracts
+}
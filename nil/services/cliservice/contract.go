@@ -1,10 +1,14 @@
 package cliservice
 
 import (
+	"crypto/ecdsa"
+	"fmt"
+
 	"github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -53,9 +57,14 @@ func (s *Service) GetSeqno(contractAddress types.Address) (types.Seqno, error) {
 func (s *Service) GetInfo(address types.Address) (string, string, error) {
 	s.logger.Info().Msgf("Address: %s", address)
 
+	publicKey, err := s.publicKey()
+	if err != nil {
+		return "", "", err
+	}
+
 	var pub string
-	if s.privateKey != nil {
-		pubBytes := crypto.CompressPubkey(&s.privateKey.PublicKey)
+	if publicKey != nil {
+		pubBytes := crypto.CompressPubkey(publicKey)
 		pub = hexutil.Encode(pubBytes)
 		s.logger.Info().Msgf("Public key: %s", pub)
 	}
@@ -88,12 +97,66 @@ func (s *Service) GetDebugContract(contractAddress types.Address, blockId any) (
 	return contract, nil
 }
 
+// ExportContract retrieves the state of the contract at the given block as a bundle
+// that can be imported into the zero state of another network
+func (s *Service) ExportContract(contractAddress types.Address, blockId any) (*execution.ContractBundle, error) {
+	debugBlock, err := s.client.GetDebugBlock(s.ctx, contractAddress.ShardId(), blockId, false)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get block")
+		return nil, err
+	}
+	if debugBlock == nil {
+		return nil, fmt.Errorf("block %v not found", blockId)
+	}
+	block, err := debugBlock.DecodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+	blockHash := block.Hash(contractAddress.ShardId())
+
+	// The block is referenced by hash, so the contract is taken from exactly the same block.
+	contract, err := s.GetDebugContract(contractAddress, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(contract.Contract) == 0 {
+		return nil, fmt.Errorf("contract %s not found in block %s", contractAddress, blockHash)
+	}
+	var account types.SmartContract
+	if err := account.UnmarshalSSZ(contract.Contract); err != nil {
+		return nil, err
+	}
+
+	bundle := &execution.ContractBundle{
+		Address:       contractAddress,
+		BlockHash:     blockHash,
+		BlockNumber:   block.Id,
+		ContractsRoot: block.SmartContractsRoot,
+		Balance:       account.Balance,
+		Seqno:         account.Seqno,
+		ExtSeqno:      account.ExtSeqno,
+		Code:          contract.Code,
+		Storage:       contract.Storage,
+		Tokens:        contract.Tokens,
+		Contract:      contract.Contract,
+		Proof:         contract.Proof,
+	}
+	if err := bundle.Verify(); err != nil {
+		return nil, fmt.Errorf("exported contract is inconsistent: %w", err)
+	}
+	return bundle, nil
+}
+
 // RunContract runs bytecode on the specified contract address
 func (s *Service) RunContract(smartAccount types.Address, bytecode []byte, fee types.FeePack, value types.Value,
 	tokens []types.TokenBalance, contract types.Address,
 ) (common.Hash, error) {
+	privateKey, err := s.privateKey()
+	if err != nil {
+		return common.EmptyHash, err
+	}
 	txHash, err := s.client.SendTransactionViaSmartAccount(
-		s.ctx, smartAccount, bytecode, fee, value, tokens, contract, s.privateKey)
+		s.ctx, smartAccount, bytecode, fee, value, tokens, contract, privateKey)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send new transaction")
 		return common.EmptyHash, err
@@ -107,9 +170,12 @@ func (s *Service) RunContract(smartAccount types.Address, bytecode []byte, fee t
 
 // SendExternalTransaction runs bytecode on the specified contract address
 func (s *Service) SendExternalTransaction(bytecode []byte, contract types.Address, noSign bool) (common.Hash, error) {
-	pk := s.privateKey
-	if noSign {
-		pk = nil
+	var pk *ecdsa.PrivateKey
+	if !noSign {
+		var err error
+		if pk, err = s.privateKey(); err != nil {
+			return common.EmptyHash, err
+		}
 	}
 	txHash, err := s.client.SendExternalTransaction(
 		s.ctx, types.Code(bytecode), contract, pk, types.NewFeePackFromGas(0))
@@ -131,8 +197,12 @@ func (s *Service) DeployContractViaSmartAccount(
 	deployPayload types.DeployPayload,
 	value types.Value,
 ) (common.Hash, types.Address, error) {
+	privateKey, err := s.privateKey()
+	if err != nil {
+		return common.EmptyHash, types.EmptyAddress, err
+	}
 	txHash, contractAddr, err := s.client.DeployContract(s.ctx, shardId, smartAccount, deployPayload, value,
-		types.NewFeePackFromGas(10_000_000), s.privateKey)
+		types.NewFeePackFromGas(10_000_000), privateKey)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send new transaction")
 		return common.EmptyHash, types.EmptyAddress, err
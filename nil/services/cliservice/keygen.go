@@ -1,6 +1,9 @@
 package cliservice
 
 import (
+	"crypto/ecdsa"
+
+	"github.com/NilFoundation/nil/nil/common/check"
 	nilcrypto "github.com/NilFoundation/nil/nil/internal/crypto"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -13,7 +16,7 @@ func (s *Service) GenerateNewKey() error {
 		return err
 	}
 
-	s.privateKey = privateKey
+	s.keys = StaticKey(privateKey)
 	return nil
 }
 
@@ -24,13 +27,20 @@ func (s *Service) GenerateKeyFromHex(hexKey string) error {
 		return err
 	}
 
-	s.privateKey = privateKey
+	s.keys = StaticKey(privateKey)
 	return nil
 }
 
 // GetPrivateKey returns the private key in hexadecimal format
 func (s *Service) GetPrivateKey() string {
-	return nilcrypto.PrivateKeyToEthereumFormat(s.privateKey)
+	return nilcrypto.PrivateKeyToEthereumFormat(s.GetPrivateKeyECDSA())
+}
+
+// GetPrivateKeyECDSA returns the generated private key
+func (s *Service) GetPrivateKeyECDSA() *ecdsa.PrivateKey {
+	privateKey, err := s.privateKey()
+	check.PanicIfErr(err)
+	return privateKey
 }
 
 // GenerateNewKey generates a new private key
@@ -0,0 +1,333 @@
+// Package keystore stores private keys encrypted with a passphrase.
+//
+// The keys are stored one per file in the Web3 Secret Storage format (scrypt and AES-128-CTR),
+// so the files can be used with geth and other Ethereum tools. Every key has a name,
+// and the file of the key is named after it. Besides the standard fields, the files hold the public keys,
+// so they can be shown without the passphrase.
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+const keyFileExt = ".json"
+
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrKeyExists       = errors.New("key already exists")
+	ErrInvalidName     = errors.New("invalid key name")
+	ErrWrongPassphrase = keystore.ErrDecrypt
+)
+
+// The names are used as file names and as config section names, which are case-insensitive.
+var nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func ValidateName(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf(
+			"%w %q: only lowercase letters, digits, '-' and '_' are allowed", ErrInvalidName, name)
+	}
+	return nil
+}
+
+type KeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// New creates a keystore in the given directory. The directory is created when the first key is stored.
+func New(dir string) *KeyStore {
+	return &KeyStore{
+		dir:     dir,
+		scryptN: keystore.StandardScryptN,
+		scryptP: keystore.StandardScryptP,
+	}
+}
+
+// NewLight creates a keystore that uses much less memory and CPU time to encrypt the keys,
+// which also makes them easier to brute-force. It is intended for tests and development networks.
+func NewLight(dir string) *KeyStore {
+	return &KeyStore{
+		dir:     dir,
+		scryptN: keystore.LightScryptN,
+		scryptP: keystore.LightScryptP,
+	}
+}
+
+func (ks *KeyStore) Dir() string {
+	return ks.dir
+}
+
+// KeyInfo describes a stored key. It is read without decrypting the key.
+type KeyInfo struct {
+	Name string
+	// Address is the Ethereum address of the key. (It is not the address of a smart account.)
+	Address ethcommon.Address
+	// PublicKey is nil if the key file doesn't hold it, e.g., if the file is created by another tool.
+	PublicKey *ecdsa.PublicKey
+	Path      string
+}
+
+func (ks *KeyStore) path(name string) string {
+	return filepath.Join(ks.dir, name+keyFileExt)
+}
+
+// Has reports whether the key with the given name is stored.
+func (ks *KeyStore) Has(name string) (bool, error) {
+	if err := ValidateName(name); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(ks.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Store encrypts the key with the passphrase and stores it under the given name.
+func (ks *KeyStore) Store(name string, key *ecdsa.PrivateKey, passphrase string) (*KeyInfo, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := ks.encrypt(key, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(ks.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	path := ks.path(name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyExists, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return &KeyInfo{
+		Name:      name,
+		Address:   crypto.PubkeyToAddress(key.PublicKey),
+		PublicKey: &key.PublicKey,
+		Path:      path,
+	}, nil
+}
+
+// Load decrypts the key stored under the given name.
+func (ks *KeyStore) Load(name string, passphrase string) (*ecdsa.PrivateKey, error) {
+	data, err := ks.read(name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key %s: %w", name, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// ChangePassphrase re-encrypts the key with the new passphrase.
+func (ks *KeyStore) ChangePassphrase(name string, passphrase string, newPassphrase string) error {
+	key, err := ks.Load(name, passphrase)
+	if err != nil {
+		return err
+	}
+	data, err := ks.encrypt(key, newPassphrase)
+	if err != nil {
+		return err
+	}
+
+	// The key is replaced atomically, so it is not lost if the write fails.
+	tmp, err := os.CreateTemp(ks.dir, "."+name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return os.Rename(tmp.Name(), ks.path(name))
+}
+
+// List returns the stored keys sorted by name.
+func (ks *KeyStore) List() ([]KeyInfo, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore directory: %w", err)
+	}
+
+	res := make([]KeyInfo, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), keyFileExt)
+		if !ok || entry.IsDir() || ValidateName(name) != nil {
+			continue
+		}
+		info, err := ks.info(name)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *info)
+	}
+	slices.SortFunc(res, func(a, b KeyInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
+
+// info reads the description of the key without decrypting it.
+func (ks *KeyStore) info(name string) (*KeyInfo, error) {
+	data, err := ks.read(name)
+	if err != nil {
+		return nil, err
+	}
+	var header struct {
+		Address   string `json:"address"`
+		PublicKey string `json:"publicKey"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", ks.path(name), err)
+	}
+
+	res := &KeyInfo{
+		Name:    name,
+		Address: ethcommon.HexToAddress(header.Address),
+		Path:    ks.path(name),
+	}
+	if header.PublicKey != "" {
+		pubBytes, err := hex.DecodeString(header.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key of %s: %w", name, err)
+		}
+		if res.PublicKey, err = crypto.DecompressPubkey(pubBytes); err != nil {
+			return nil, fmt.Errorf("failed to parse public key of %s: %w", name, err)
+		}
+	}
+	return res, nil
+}
+
+func (ks *KeyStore) read(name string) ([]byte, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(ks.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return data, nil
+}
+
+func (ks *KeyStore) encrypt(key *ecdsa.PrivateKey, passphrase string) ([]byte, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	data, err := keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt key: %w", err)
+	}
+
+	// The other tools ignore the unknown fields.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	fields["publicKey"], err = json.Marshal(hex.EncodeToString(crypto.CompressPubkey(&key.PublicKey)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	return json.Marshal(fields)
+}
+
+// PassphraseFunc returns the passphrase of the key with the given name, e.g., by prompting the user.
+type PassphraseFunc func(name string) (string, error)
+
+// Key is a stored key that is decrypted on the first use.
+type Key struct {
+	ks         *KeyStore
+	name       string
+	passphrase PassphraseFunc
+
+	once sync.Once
+	key  *ecdsa.PrivateKey
+	err  error
+}
+
+// Key returns the stored key with the given name. The passphrase is requested only when the key is used.
+func (ks *KeyStore) Key(name string, passphrase PassphraseFunc) *Key {
+	return &Key{
+		ks:         ks,
+		name:       name,
+		passphrase: passphrase,
+	}
+}
+
+func (k *Key) Name() string {
+	return k.name
+}
+
+// PublicKey returns the public key without decrypting the key.
+// It returns nil if the key file doesn't hold the public key.
+func (k *Key) PublicKey() (*ecdsa.PublicKey, error) {
+	info, err := k.ks.info(k.name)
+	if err != nil {
+		return nil, err
+	}
+	return info.PublicKey, nil
+}
+
+// PrivateKey decrypts the key.
+func (k *Key) PrivateKey() (*ecdsa.PrivateKey, error) {
+	k.once.Do(func() {
+		if _, k.err = k.ks.read(k.name); k.err != nil {
+			return
+		}
+		var passphrase string
+		if passphrase, k.err = k.passphrase(k.name); k.err != nil {
+			return
+		}
+		k.key, k.err = k.ks.Load(k.name, passphrase)
+	})
+	return k.key, k.err
+}
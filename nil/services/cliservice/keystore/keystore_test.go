@@ -0,0 +1,126 @@
+package keystore
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyStore(t *testing.T) {
+	t.Parallel()
+
+	ks := NewLight(t.TempDir())
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	info, err := ks.Store("deployer", key, "secret")
+	require.NoError(t, err)
+	require.Equal(t, address, info.Address)
+
+	t.Run("Load", func(t *testing.T) {
+		loaded, err := ks.Load("deployer", "secret")
+		require.NoError(t, err)
+		require.Equal(t, key.D, loaded.D)
+
+		_, err = ks.Load("deployer", "wrong")
+		require.ErrorIs(t, err, ErrWrongPassphrase)
+
+		_, err = ks.Load("unknown", "secret")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("GethCompatible", func(t *testing.T) {
+		data, err := os.ReadFile(info.Path)
+		require.NoError(t, err)
+
+		gethKey, err := keystore.DecryptKey(data, "secret")
+		require.NoError(t, err)
+		require.Equal(t, address, gethKey.Address)
+		require.Equal(t, key.D, gethKey.PrivateKey.D)
+
+		// The key file of another tool has no public key.
+		gethData, err := keystore.EncryptKey(&keystore.Key{Id: uuid.New(), Address: address, PrivateKey: key},
+			"secret", keystore.LightScryptN, keystore.LightScryptP)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(ks.path("geth"), gethData, 0o600))
+		k := ks.Key("geth", nil)
+		pub, err := k.PublicKey()
+		require.NoError(t, err)
+		require.Nil(t, pub)
+		require.NoError(t, os.Remove(ks.path("geth")))
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		_, err := ks.Store("deployer", key, "other")
+		require.ErrorIs(t, err, ErrKeyExists)
+
+		has, err := ks.Has("deployer")
+		require.NoError(t, err)
+		require.True(t, has)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		for _, name := range []string{"", "Deployer", "../deployer", "a.b"} {
+			_, err := ks.Store(name, key, "secret")
+			require.ErrorIs(t, err, ErrInvalidName, name)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		_, err := ks.Store("alice", key, "secret")
+		require.NoError(t, err)
+
+		keys, err := ks.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, "alice", keys[0].Name)
+		require.Equal(t, "deployer", keys[1].Name)
+		require.Equal(t, address, keys[1].Address)
+	})
+
+	t.Run("ChangePassphrase", func(t *testing.T) {
+		require.ErrorIs(t, ks.ChangePassphrase("deployer", "wrong", "new"), ErrWrongPassphrase)
+		require.NoError(t, ks.ChangePassphrase("deployer", "secret", "new"))
+
+		loaded, err := ks.Load("deployer", "new")
+		require.NoError(t, err)
+		require.Equal(t, key.D, loaded.D)
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		calls := 0
+		k := ks.Key("alice", func(name string) (string, error) {
+			calls++
+			require.Equal(t, "alice", name)
+			return "secret", nil
+		})
+		pub, err := k.PublicKey()
+		require.NoError(t, err)
+		require.Equal(t, key.PublicKey, *pub)
+		require.Zero(t, calls)
+
+		for range 2 {
+			loaded, err := k.PrivateKey()
+			require.NoError(t, err)
+			require.Equal(t, key.D, loaded.D)
+		}
+		require.Equal(t, 1, calls)
+
+		_, err = ks.Key("unknown", nil).PrivateKey()
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
+
+func TestListEmpty(t *testing.T) {
+	t.Parallel()
+
+	keys, err := New(t.TempDir() + "/missing").List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
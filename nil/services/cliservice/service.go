@@ -10,27 +10,58 @@ import (
 	"github.com/NilFoundation/nil/nil/services/faucet"
 )
 
+// KeySource provides the private key used to sign external transactions.
+// The key is requested only when a transaction is signed,
+// so, e.g., the passphrase of an encrypted key is not requested for read-only requests.
+type KeySource interface {
+	// PublicKey returns the public key without decrypting the private key or nil if it is unknown.
+	PublicKey() (*ecdsa.PublicKey, error)
+	PrivateKey() (*ecdsa.PrivateKey, error)
+}
+
+type staticKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k staticKey) PublicKey() (*ecdsa.PublicKey, error) {
+	return &k.key.PublicKey, nil
+}
+
+func (k staticKey) PrivateKey() (*ecdsa.PrivateKey, error) {
+	return k.key, nil
+}
+
+// StaticKey returns the key source that provides the given key.
+func StaticKey(privateKey *ecdsa.PrivateKey) KeySource {
+	if privateKey == nil {
+		return nil
+	}
+	return staticKey{privateKey}
+}
+
 type Service struct {
 	// ctx is common for all application so we don't need to pass it to each function separately
 	ctx          context.Context
 	client       client.Client
-	privateKey   *ecdsa.PrivateKey
+	keys         KeySource
 	logger       logging.Logger
 	faucetClient *faucet.Client
 }
 
 // NewService initializes a new Service with the given client
 func NewService(ctx context.Context, c client.Client, privateKey *ecdsa.PrivateKey, fc *faucet.Client) *Service {
-	s := &Service{
+	return NewServiceWithKeys(ctx, c, StaticKey(privateKey), fc)
+}
+
+// NewServiceWithKeys initializes a new Service that signs the transactions with the keys from the given source
+func NewServiceWithKeys(ctx context.Context, c client.Client, keys KeySource, fc *faucet.Client) *Service {
+	return &Service{
 		ctx:          ctx,
 		client:       c,
+		keys:         keys,
 		faucetClient: fc,
 		logger:       logging.NewLogger("cliservice"),
 	}
-
-	s.privateKey = privateKey
-
-	return s
 }
 
 func (s *Service) Client() client.Client {
@@ -39,6 +70,22 @@ func (s *Service) Client() client.Client {
 
 func (s *Service) CloneWithPrivateKey(privateKey *ecdsa.PrivateKey) *Service {
 	service := common.CopyPtr(s)
-	service.privateKey = privateKey
+	service.keys = StaticKey(privateKey)
 	return service
 }
+
+// privateKey returns the signing key or nil if the service has no keys.
+func (s *Service) privateKey() (*ecdsa.PrivateKey, error) {
+	if s.keys == nil {
+		return nil, nil
+	}
+	return s.keys.PrivateKey()
+}
+
+// publicKey returns the public key or nil if it is unknown. The private key is not decrypted.
+func (s *Service) publicKey() (*ecdsa.PublicKey, error) {
+	if s.keys == nil {
+		return nil, nil
+	}
+	return s.keys.PublicKey()
+}
@@ -21,7 +21,11 @@ func (s *Service) handleTokenTx(txHash common.Hash, contractAddr types.Address)
 }
 
 func (s *Service) TokenCreate(contractAddr types.Address, amount types.Value, name string) (*types.TokenId, error) {
-	txHash, err := s.client.SetTokenName(s.ctx, contractAddr, name, s.privateKey)
+	privateKey, err := s.privateKey()
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := s.client.SetTokenName(s.ctx, contractAddr, name, privateKey)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send setTokenName transaction")
 		return nil, err
@@ -30,7 +34,7 @@ func (s *Service) TokenCreate(contractAddr types.Address, amount types.Value, na
 		return nil, err
 	}
 
-	txHash, err = s.client.ChangeTokenAmount(s.ctx, contractAddr, amount, s.privateKey, true /* mint */)
+	txHash, err = s.client.ChangeTokenAmount(s.ctx, contractAddr, amount, privateKey, true /* mint */)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send minToken transaction")
 		return nil, err
@@ -45,7 +49,11 @@ func (s *Service) TokenCreate(contractAddr types.Address, amount types.Value, na
 }
 
 func (s *Service) ChangeTokenAmount(contractAddr types.Address, amount types.Value, mint bool) (common.Hash, error) {
-	txHash, err := s.client.ChangeTokenAmount(s.ctx, contractAddr, amount, s.privateKey, mint)
+	privateKey, err := s.privateKey()
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	txHash, err := s.client.ChangeTokenAmount(s.ctx, contractAddr, amount, privateKey, mint)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to send transaction for token amount change")
 		return common.EmptyHash, err
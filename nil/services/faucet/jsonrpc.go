@@ -3,16 +3,23 @@ package faucet
 import (
 	"context"
 	"errors"
-	"fmt"
-	"sync"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/client"
-	"github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/types"
-	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
-	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+const (
+	// waitTimeout is the time the faucet tracks a sent transaction for.
+	waitTimeout = 2 * time.Minute
+	// waitWorkers is the number of the sent transactions tracked at the same time.
+	waitWorkers = 16
+	// waitQueueSize is the number of the sent transactions waiting to be tracked.
+	// The top-up requests are blocked while the queue is full.
+	waitQueueSize = 1024
 )
 
 type API interface {
@@ -23,43 +30,28 @@ type API interface {
 
 type APIImpl struct {
 	client client.Client
-
-	// Requests are served by one which is the easiest way to avoid seqno gaps.
-	mu sync.Mutex
-	// As long as we have only one faucet, we can manage seqnos locally
-	// which can be more correct than getting tx count each time.
-	seqnos map[types.Address]types.Seqno
+	// The sender reserves the seqnos of the faucets, so that the requests are served concurrently.
+	sender *client.Sender
+	// The sent transactions are tracked by the workers running until the context of the API is done.
+	sent   chan *client.PendingTransaction
+	logger logging.Logger
 }
 
 var _ API = (*APIImpl)(nil)
 
-func NewAPI(client client.Client) *APIImpl {
-	return &APIImpl{
-		client: client,
-		seqnos: make(map[types.Address]types.Seqno),
+// NewAPI creates the faucet API. The transactions it sends are tracked until ctx is done.
+func NewAPI(ctx context.Context, c client.Client) *APIImpl {
+	logger := logging.NewLogger("faucet")
+	api := &APIImpl{
+		client: c,
+		sender: client.NewSender(c, client.NewDefaultSenderConfig(), logger),
+		sent:   make(chan *client.PendingTransaction, waitQueueSize),
+		logger: logger,
 	}
-}
-
-func (c *APIImpl) fetchSeqno(ctx context.Context, addr types.Address) (types.Seqno, error) {
-	return c.client.GetTransactionCount(ctx, addr, transport.BlockNumberOrHash(transport.PendingBlock))
-}
-
-func (c *APIImpl) getOrFetchSeqno(ctx context.Context, faucetAddress types.Address) (types.Seqno, error) {
-	// todo: currently, no better solution than to fetch seqno each time
-	// Keeping in-memory seqno is not reliable because of possible desync with the chain.
-	// seqno, ok := c.seqnos[faucetAddress]
-	// if ok {
-	//	return seqno, nil
-	// }
-
-	seqno, err := c.fetchSeqno(ctx, faucetAddress)
-	if err != nil {
-		return 0, err
+	for range waitWorkers {
+		go api.track(ctx)
 	}
-
-	c.seqnos[faucetAddress] = seqno
-
-	return seqno, nil
+	return api
 }
 
 func (c *APIImpl) TopUpViaFaucet(
@@ -68,14 +60,6 @@ func (c *APIImpl) TopUpViaFaucet(
 	contractAddressTo types.Address,
 	amount types.Value,
 ) (common.Hash, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	seqno, err := c.getOrFetchSeqno(ctx, faucetAddress)
-	if err != nil {
-		return common.EmptyHash, err
-	}
-
 	contractName := contracts.NameFaucet
 	if faucetAddress != types.FaucetAddress {
 		contractName = contracts.NameFaucetToken
@@ -84,49 +68,54 @@ func (c *APIImpl) TopUpViaFaucet(
 	if err != nil {
 		return common.EmptyHash, err
 	}
-	extTxn := &types.ExternalTransaction{
-		To:           faucetAddress,
-		Data:         callData,
-		Seqno:        seqno,
-		Kind:         types.ExecutionTransactionKind,
+
+	// Faucets accept unsigned transactions.
+	txn, err := c.sender.Send(ctx, faucetAddress, callData, nil, types.FeePack{
 		FeeCredit:    types.GasToValue(100_000),
 		MaxFeePerGas: types.MaxFeePerGasDefault,
-	}
-
-	data, err := extTxn.MarshalSSZ()
+	})
 	if err != nil {
 		return common.EmptyHash, err
 	}
 
-	hash, err := c.client.SendRawTransaction(ctx, data)
-	if err != nil && !errors.Is(err, rpc.ErrRPCError) && !errors.Is(err, jsonrpc.ErrTransactionDiscarded) {
-		return common.EmptyHash, err
+	// The transaction is tracked after the request is served.
+	select {
+	case c.sent <- txn:
+	case <-ctx.Done():
+		c.logger.Warn().
+			Stringer(logging.FieldTransactionHash, txn.Hash()).
+			Msgf("Top-up transaction %d from faucet %s is not tracked", txn.Seqno(), txn.To())
 	}
-	if err != nil {
-		actualSeqno, err2 := c.fetchSeqno(ctx, faucetAddress)
-		if err2 != nil {
-			return common.EmptyHash, fmt.Errorf(
-				"failed to send transaction %d with %w and failed to get seqno: %w", seqno, err, err2)
-		}
-
-		extTxn.Seqno = actualSeqno
-		data, err2 = extTxn.MarshalSSZ()
-		if err2 != nil {
-			return common.EmptyHash, err2
-		}
+	return txn.Hash(), nil
+}
 
-		hash, err2 = c.client.SendRawTransaction(ctx, data)
-		if err2 != nil {
-			return common.EmptyHash, fmt.Errorf(
-				"failed to send transaction %d with %w and then %d with %w", seqno, err, actualSeqno, err2)
+// track waits for the sent transactions one by one until ctx is done.
+func (c *APIImpl) track(ctx context.Context) {
+	for {
+		select {
+		case txn := <-c.sent:
+			c.wait(ctx, txn)
+		case <-ctx.Done():
+			return
 		}
-
-		seqno = actualSeqno
 	}
+}
 
-	c.seqnos[faucetAddress] = seqno + 1
+// wait waits for the transaction, so that it is rebroadcast or its fee is bumped if it gets stuck.
+// If it is not processed anyway, the seqnos of the faucet are fetched from the node again:
+// otherwise, the seqno of the lost transaction would block all the later ones.
+func (c *APIImpl) wait(ctx context.Context, txn *client.PendingTransaction) {
+	_, err := c.sender.Wait(ctx, txn, waitTimeout)
+	if err == nil {
+		return
+	}
 
-	return hash, nil
+	c.logger.Warn().Err(err).
+		Stringer(logging.FieldTransactionHash, txn.Hash()).
+		Msgf("Top-up transaction %d from faucet %s failed", txn.Seqno(), txn.To())
+	if errors.Is(err, client.ErrTransactionStuck) {
+		c.sender.Seqnos().Forget(txn.To())
+	}
 }
 
 func (c *APIImpl) GetFaucets() map[string]types.Address {
@@ -14,8 +14,9 @@ type Service struct {
 	impl API
 }
 
-func NewService(client client.Client) (*Service, error) {
-	return &Service{impl: NewAPI(client)}, nil
+// NewService creates the faucet service. The transactions sent by the faucet are tracked until ctx is done.
+func NewService(ctx context.Context, client client.Client) (*Service, error) {
+	return &Service{impl: NewAPI(ctx, client)}, nil
 }
 
 func (s *Service) Run(ctx context.Context, endpoint string) error {
@@ -106,7 +106,7 @@ func (c NilLoadGeneratorAPIImpl) CallSwap(
 	if err != nil {
 		return common.EmptyHash, err
 	}
-	return c.service.client.SendTransactionViaSmartAccount(
+	txn, err := c.service.sender.SendViaSmartAccount(
 		context.Background(),
 		uniswapSmartAccount.Addr,
 		calldata,
@@ -121,6 +121,10 @@ func (c NilLoadGeneratorAPIImpl) CallSwap(
 		c.service.pairs[res.ShardId-1].Addr,
 		uniswapSmartAccount.PrivateKey,
 	)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return txn.Hash(), nil
 }
 
 func (c NilLoadGeneratorAPIImpl) CallQuote(
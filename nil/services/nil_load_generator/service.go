@@ -11,6 +11,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/NilFoundation/nil/nil/client"
 	rpc_client "github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
@@ -75,14 +76,17 @@ type Service struct {
 	pairs []*uniswap.Pair
 
 	client *rpc_client.Client
+	// sender sends the swaps requested via RPC concurrently.
+	sender *client.Sender
 }
 
 func newService(config *Config, logger logging.Logger) *Service {
-	client := rpc_client.NewClient(config.Endpoint, logger)
+	c := rpc_client.NewClient(config.Endpoint, logger)
 	return &Service{
 		config: config,
 		logger: logger,
-		client: client,
+		client: c,
+		sender: client.NewSender(c, client.NewDefaultSenderConfig(), logger),
 	}
 }
 
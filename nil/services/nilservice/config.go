@@ -19,7 +19,10 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/NilFoundation/nil/nil/services/indexer"
+	"github.com/NilFoundation/nil/nil/services/pruner"
 	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
 type RunMode int
@@ -32,6 +35,16 @@ const (
 	RpcRunMode
 )
 
+// StorageMode defines which historical state is kept in the database.
+type StorageMode string
+
+const (
+	// ArchiveStorageMode keeps the state of all blocks.
+	ArchiveStorageMode StorageMode = "archive"
+	// FullStorageMode keeps the state of the latest blocks and checkpoints only, the rest is pruned.
+	FullStorageMode StorageMode = "full"
+)
+
 type Config struct {
 	// Set by the command line
 	RunMode RunMode `yaml:"-"`
@@ -45,6 +58,10 @@ type Config struct {
 	RPCPort        int                   `yaml:"rpcPort,omitempty"`
 	BootstrapPeers network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
 	EnableDevApi   bool                  `yaml:"enableDevApi,omitempty"`
+	// ManualMining makes collators produce blocks only on dev_mine requests.
+	ManualMining bool `yaml:"manualMining,omitempty"`
+	// EnableWebSocket makes the RPC server serve WebSocket connections (required for eth_subscribe).
+	EnableWebSocket bool `yaml:"enableWebSocket,omitempty"`
 
 	// Profiling
 	PprofPort int `yaml:"pprofPort,omitempty"`
@@ -72,6 +89,18 @@ type Config struct {
 	Topology             string `yaml:"-"`
 	EnableConfigCache    bool   `yaml:"-"`
 
+	// Collator
+	InternalGasReservePercent uint32 `yaml:"internalGasReservePercent,omitempty"`
+
+	// Storage
+	StorageMode StorageMode `yaml:"storageMode,omitempty"`
+	// NodeCacheSize is the number of the trie nodes cached in memory. Zero disables the cache.
+	NodeCacheSize int `yaml:"nodeCacheSize,omitempty"`
+	// EnableFlatState makes the node keep the latest accounts and storage slots in a flat table,
+	// so the RPC reads them without the trie traversal. The table is rebuilt from the tries at startup
+	// if it doesn't hold the state of the last block (e.g., after the snapshot sync).
+	EnableFlatState bool `yaml:"enableFlatState,omitempty"`
+
 	// Consensus
 	Validators       map[types.ShardId][]config.ValidatorInfo `yaml:"validators,omitempty"`
 	DisableConsensus bool                                     `yaml:"-"`
@@ -84,6 +113,9 @@ type Config struct {
 	Cometa    *cometa.Config             `yaml:"cometa,omitempty"`
 	Indexer   *indexer.Config            `yaml:"indexer,omitempty"`
 	RpcNode   *RpcNodeConfig             `yaml:"rpcNode,omitempty"`
+	GetLogs   *jsonrpc.GetLogsConfig     `yaml:"getLogs,omitempty"`
+	TxnPool   *txnpool.Config            `yaml:"txnPool,omitempty"`
+	Pruner    *pruner.Config             `yaml:"pruner,omitempty"`
 
 	L1Fetcher rollup.L1BlockFetcher `yaml:"-"`
 
@@ -92,8 +124,11 @@ type Config struct {
 }
 
 const (
-	DefaultNShards   types.ShardId = 5
-	DefaultPprofPort uint32        = 6060
+	DefaultNShards       types.ShardId = 5
+	DefaultPprofPort     uint32        = 6060
+	DefaultNodeCacheSize int           = 1 << 18
+
+	DefaultInternalGasReservePercent uint32 = 10
 )
 
 func NewDefaultConfig() *Config {
@@ -108,12 +143,19 @@ func NewDefaultConfig() *Config {
 		Topology:          collate.TrivialShardTopologyId,
 		EnableConfigCache: true,
 
+		StorageMode:   ArchiveStorageMode,
+		NodeCacheSize: DefaultNodeCacheSize,
+
+		InternalGasReservePercent: DefaultInternalGasReservePercent,
+
 		Validators: make(map[types.ShardId][]config.ValidatorInfo),
 
 		Network:   network.NewDefaultConfig(),
 		Telemetry: telemetry.NewDefaultConfig(),
 		Replay:    NewDefaultReplayConfig(),
 		RpcNode:   NewDefaultRpcNodeConfig(),
+		GetLogs:   jsonrpc.NewDefaultGetLogsConfig(),
+		Pruner:    pruner.NewDefaultConfig(),
 		PprofPort: int(DefaultPprofPort),
 	}
 }
@@ -181,6 +223,28 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.InternalGasReservePercent > 100 {
+		return fmt.Errorf("internal gas reserve must be at most 100%%, got %d%%", c.InternalGasReservePercent)
+	}
+
+	switch c.StorageMode {
+	case "", ArchiveStorageMode:
+	case FullStorageMode:
+		if c.RunMode == ArchiveRunMode {
+			return errors.New("archive node can't run in full storage mode")
+		}
+	default:
+		return fmt.Errorf("unknown storage mode %q", c.StorageMode)
+	}
+
+	if c.NodeCacheSize < 0 {
+		return fmt.Errorf("node cache size must be non-negative, got %d", c.NodeCacheSize)
+	}
+
+	if c.ManualMining && !c.EnableDevApi {
+		return errors.New("manual mining requires the development API")
+	}
+
 	if c.MyShards != nil && !c.DisableConsensus {
 		if !slices.Contains(c.MyShards, uint(types.MainShardId)) {
 			return errors.New("main shard must be included in MyShards")
@@ -227,5 +291,6 @@ func (c *Config) BlockGeneratorParams(shardId types.ShardId) execution.BlockGene
 		MainKeysPath:     c.MainKeysPath,
 		DisableConsensus: c.DisableConsensus,
 		FeeCalculator:    c.FeeCalculator,
+		EnableFlatState:  c.EnableFlatState,
 	}
 }
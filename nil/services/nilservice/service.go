@@ -19,6 +19,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/consensus/ibft"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -27,6 +28,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/faucet"
 	"github.com/NilFoundation/nil/nil/services/indexer"
 	"github.com/NilFoundation/nil/nil/services/indexer/driver"
+	"github.com/NilFoundation/nil/nil/services/pruner"
 	"github.com/NilFoundation/nil/nil/services/rollup"
 	"github.com/NilFoundation/nil/nil/services/rpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
@@ -47,6 +49,7 @@ func startRpcServer(
 	cfg *Config,
 	rawApi rawapi.NodeApi,
 	db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool,
 	client client.Client,
 ) error {
 	logger := logging.NewLogger("RPC").With().
@@ -61,6 +64,7 @@ func startRpcServer(
 	httpConfig := &httpcfg.HttpCfg{
 		HttpURL:         addr,
 		HttpCompression: true,
+		WSEnabled:       cfg.EnableWebSocket,
 		TraceRequests:   true,
 		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
 		HttpCORSDomain:  []string{"*"},
@@ -72,11 +76,11 @@ func startRpcServer(
 
 	var ethApiService any
 	if cfg.RunMode == NormalRunMode || cfg.RunMode == RpcRunMode {
-		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, txnPools, pollBlocksForLogs, cfg.LogClientRpcEvents, cfg.GetLogs)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	} else {
-		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, txnPools, pollBlocksForLogs, cfg.LogClientRpcEvents, cfg.GetLogs)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	}
@@ -155,7 +159,7 @@ func startRpcServer(
 	}
 
 	if cfg.IsFaucetApiEnabled() {
-		f, err := faucet.NewService(client)
+		f, err := faucet.NewService(ctx, client)
 		if err != nil {
 			return fmt.Errorf("failed to create faucet service: %w", err)
 		}
@@ -214,6 +218,7 @@ func getRawApi(
 	networkManager network.Manager,
 	database db.DB,
 	txnPools map[types.ShardId]txnpool.Pool,
+	dev *devApiState,
 ) rawapi.NodeApi {
 	nodeApiBuilder := rawapi.NodeApiBuilder(database, networkManager)
 
@@ -240,7 +245,7 @@ func getRawApi(
 				nodeApiBuilder.WithLocalShardApiRw(shardId, txnPools[shardId])
 			}
 			if cfg.EnableDevApi {
-				nodeApiBuilder.WithLocalShardApiDev(shardId)
+				nodeApiBuilder.WithLocalShardApiDev(shardId, dev.states[shardId], dev.snapshots)
 			}
 		}
 
@@ -269,6 +274,9 @@ func initSyncers(ctx context.Context, syncers []*collate.Syncer, allowDbDrop boo
 		if err := syncer.GenerateZerostateIfShardIsEmpty(ctx); err != nil {
 			return err
 		}
+		if err := syncer.RebuildFlatState(ctx); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -284,6 +292,7 @@ func getSyncerConfig(name string, cfg *Config, shardId types.ShardId) *collate.S
 		BootstrapPeers:       cfg.BootstrapPeers,
 		BlockGeneratorParams: cfg.BlockGeneratorParams(shardId),
 		ZeroStateConfig:      cfg.ZeroState,
+		ReplayFromGenesis:    cfg.RunMode == ArchiveRunMode,
 	}
 }
 
@@ -404,6 +413,13 @@ func (i *Node) Close(ctx context.Context) {
 	telemetry.Shutdown(ctx)
 }
 
+// devApiState is the state of the collators shared with the development API.
+type devApiState struct {
+	states map[types.ShardId]*collate.DevState
+	// snapshots is nil unless the node collates all shards.
+	snapshots *collate.DevSnapshots
+}
+
 func runNormalOrCollatorsOnly(
 	ctx context.Context,
 	funcs []concurrent.Task,
@@ -411,45 +427,74 @@ func runNormalOrCollatorsOnly(
 	database db.DB,
 	networkManager network.Manager,
 	logger logging.Logger,
-) ([]concurrent.Task, map[types.ShardId]txnpool.Pool, error) {
+) ([]concurrent.Task, map[types.ShardId]txnpool.Pool, *devApiState, error) {
 	if err := cfg.LoadValidatorKeys(); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if !cfg.SplitShards && len(cfg.ZeroState.GetValidators()) == 0 {
 		if err := initDefaultValidator(cfg); err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	validators, err := createValidators(ctx, cfg, database, networkManager)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	syncersResult, err := createSyncers("sync", cfg, validators, networkManager, database, logger)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	funcs = append(funcs, syncersResult.funcs...)
 
 	shardFuncs, err := createShards(cfg, validators, syncersResult, database, networkManager, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create collators")
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	txPools := make(map[types.ShardId]txnpool.Pool)
+	dev := &devApiState{states: make(map[types.ShardId]*collate.DevState)}
 	for shardId, validator := range validators {
 		if pool := validator.TxPool(); pool != nil {
 			var ok bool
 			txPools[types.ShardId(shardId)], ok = pool.(*txnpool.TxnPool)
 			check.PanicIfNot(ok)
 		}
+		if state := validator.DevState(); state != nil {
+			dev.states[types.ShardId(shardId)] = state
+		}
+	}
+
+	if cfg.EnableDevApi {
+		dev.snapshots, err = collate.NewDevSnapshots(database, validators)
+		if err != nil {
+			logger.Info().Err(err).Msg("Snapshots of the development API are disabled")
+		}
 	}
 
 	funcs = append(funcs, shardFuncs...)
-	return funcs, txPools, nil
+	if cfg.StorageMode == FullStorageMode {
+		funcs = append(funcs, createPruners(cfg, database)...)
+	}
+	return funcs, txPools, dev, nil
+}
+
+// createPruners creates the pruners for all shards, since the node keeps the state of the synced shards as well.
+func createPruners(cfg *Config, database db.DB) []concurrent.Task {
+	prunerCfg := pruner.NewDefaultConfig()
+	if cfg.Pruner != nil {
+		prunerCfg = cfg.Pruner
+	}
+
+	funcs := make([]concurrent.Task, 0, cfg.NShards)
+	for i := range cfg.NShards {
+		p := pruner.New(prunerCfg, database, types.ShardId(i))
+		funcs = append(funcs, concurrent.MakeTask(fmt.Sprintf("[%d] pruner", i), p.Run))
+	}
+	return funcs
 }
 
 func CreateNode(
@@ -474,6 +519,13 @@ func CreateNode(
 		}
 	}
 
+	if cfg.NodeCacheSize > 0 {
+		if err := mpt.InitGlobalNodeCache(cfg.NodeCacheSize); err != nil {
+			logger.Error().Err(err).Msg("Failed to initialize trie node cache")
+			return nil, err
+		}
+	}
+
 	if err := telemetry.Init(ctx, cfg.Telemetry); err != nil {
 		logger.Error().Err(err).Msg("Failed to initialize telemetry")
 		return nil, err
@@ -501,6 +553,10 @@ func CreateNode(
 			return nil, err
 		}
 	}
+	if err := cfg.ZeroState.LoadContractBundles(); err != nil {
+		logger.Error().Err(err).Msg("Failed to load contract bundles")
+		return nil, err
+	}
 
 	createNetworkManager := cfg.NetworkManagerFactory
 	if createNetworkManager == nil {
@@ -513,10 +569,11 @@ func CreateNode(
 	}
 
 	var txnPools map[types.ShardId]txnpool.Pool
+	dev := &devApiState{}
 	var syncersResult *syncersResult
 	switch cfg.RunMode {
 	case NormalRunMode, CollatorsOnlyRunMode:
-		funcs, txnPools, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
+		funcs, txnPools, dev, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -584,8 +641,8 @@ func CreateNode(
 			return nil
 		}))
 
-	rawApi := getRawApi(cfg, networkManager, database, txnPools)
-	funcs = addRpcServerWorkerIfEnabled(funcs, cfg, rawApi, syncersResult, database, logger)
+	rawApi := getRawApi(cfg, networkManager, database, txnPools, dev)
+	funcs = addRpcServerWorkerIfEnabled(funcs, cfg, rawApi, txnPools, syncersResult, database, logger)
 
 	if cfg.RunMode != CollatorsOnlyRunMode && cfg.RunMode != RpcRunMode {
 		if err := rawApi.SetP2pRequestHandlers(ctx, networkManager, logger); err != nil {
@@ -611,6 +668,7 @@ func addRpcServerWorkerIfEnabled(
 	tasks []concurrent.Task,
 	cfg *Config,
 	rawApi rawapi.NodeApi,
+	txnPools map[types.ShardId]txnpool.Pool,
 	syncersResult *syncersResult,
 	database db.DB,
 	logger logging.Logger,
@@ -636,7 +694,7 @@ func addRpcServerWorkerIfEnabled(
 					return fmt.Errorf("failed to create node client: %w", err)
 				}
 			}
-			if err := startRpcServer(ctx, cfg, rawApi, database, cl); err != nil {
+			if err := startRpcServer(ctx, cfg, rawApi, database, txnPools, cl); err != nil {
 				logger.Error().Err(err).Msg("RPC server goroutine failed")
 				return err
 			}
@@ -727,12 +785,22 @@ func createValidators(
 		var err error
 		var txpool *txnpool.TxnPool
 		if cfg.IsShardActive(shardId) {
-			txpool, err = txnpool.New(ctx, txnpool.NewConfig(shardId), networkManager)
+			txnPoolCfg := txnpool.NewConfig(shardId)
+			if cfg.TxnPool != nil {
+				txnPoolCfg = *cfg.TxnPool
+				txnPoolCfg.ShardId = shardId
+			}
+			txpool, err = txnpool.New(ctx, txnPoolCfg, networkManager, database)
 			if err != nil {
 				return nil, err
 			}
 		}
 
+		if cfg.EnableDevApi && cfg.IsShardActive(shardId) {
+			params.DevState = collate.NewDevState(shardId, cfg.ManualMining)
+			params.AllowDevChanges = true
+		}
+
 		list[i], err = collate.NewValidator(params, list[0], database, txpool, networkManager)
 		if err != nil {
 			return nil, err
@@ -810,5 +878,7 @@ func createCollateParams(shard types.ShardId, cfg *Config, collatorTickPeriod ti
 		Timeout:              collatorTickPeriod,
 		Topology:             collate.GetShardTopologyById(cfg.Topology),
 		L1Fetcher:            cfg.L1Fetcher,
+
+		InternalGasReservePercent: cfg.InternalGasReservePercent,
 	}
 }
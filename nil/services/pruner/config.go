@@ -0,0 +1,34 @@
+package pruner
+
+import "time"
+
+const (
+	DefaultKeepBlocks = 1024
+	DefaultInterval   = 10 * time.Minute
+)
+
+type Config struct {
+	// KeepBlocks is the number of the latest blocks whose state is kept.
+	KeepBlocks uint64 `yaml:"keepBlocks,omitempty"`
+	// CheckpointInterval makes the pruner keep the state of every n-th block forever. Zero disables checkpoints.
+	CheckpointInterval uint64 `yaml:"checkpointInterval,omitempty"`
+	// Interval is the time between pruning rounds.
+	Interval time.Duration `yaml:"interval,omitempty"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		KeepBlocks: DefaultKeepBlocks,
+		Interval:   DefaultInterval,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	if c.KeepBlocks == 0 {
+		c.KeepBlocks = DefaultKeepBlocks
+	}
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+	return c
+}
@@ -0,0 +1,327 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// prunedTables are the tables holding the state tries.
+// The ShardBlocksTrie of the main shard is not pruned, because the child blocks it holds
+// are a part of the block data served to the syncing peers.
+var prunedTables = []db.ShardedTableName{
+	db.ContractTrieTable,
+	db.StorageTrieTable,
+	db.TokenTrieTable,
+	db.AsyncCallContextTable,
+}
+
+// sweepBatchSize is the maximum number of nodes removed in a single db transaction.
+const sweepBatchSize = 10_000
+
+// Pruner removes the state of the old blocks of a shard.
+//
+// The trie nodes are addressed by their hashes and shared between the versions of the state,
+// so they can't be removed together with the blocks. Instead, every round marks the nodes reachable
+// from the state of the kept blocks and sweeps the rest. Every sweep batch marks the state of the blocks
+// committed since the round started and reads the nodes it removes, so the batch fails with a conflict
+// instead of removing a node that a concurrently committed block has written again.
+type Pruner struct {
+	cfg     Config
+	db      db.DB
+	shardId types.ShardId
+
+	logger logging.Logger
+}
+
+func New(cfg *Config, database db.DB, shardId types.ShardId) *Pruner {
+	return &Pruner{
+		cfg:     cfg.withDefaults(),
+		db:      database,
+		shardId: shardId,
+		logger: logging.NewLogger("pruner").With().
+			Stringer(logging.FieldShardId, shardId).
+			Logger(),
+	}
+}
+
+func (p *Pruner) Run(ctx context.Context) error {
+	p.logger.Info().
+		Uint64("keepBlocks", p.cfg.KeepBlocks).
+		Uint64("checkpointInterval", p.cfg.CheckpointInterval).
+		Msg("Starting pruner...")
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Prune(ctx); err != nil {
+			p.logger.Error().Err(err).Msg("Failed to prune state")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune runs a single pruning round.
+func (p *Pruner) Prune(ctx context.Context) error {
+	tx, err := p.db.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	latest, _, err := db.ReadLastBlock(tx, p.shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the last block: %w", err)
+	}
+	if uint64(latest.Id) < p.cfg.KeepBlocks {
+		return nil
+	}
+
+	prev, err := db.ReadPrunedState(tx, p.shardId)
+	if err != nil {
+		return err
+	}
+	state := db.PrunedState{
+		FirstKeptBlock:     latest.Id - types.BlockNumber(p.cfg.KeepBlocks) + 1,
+		CheckpointInterval: p.cfg.CheckpointInterval,
+	}
+	if prev.FirstKeptBlock != 0 && prev.CheckpointInterval != state.CheckpointInterval {
+		// The state of the new checkpoints might be pruned already.
+		p.logger.Warn().
+			Uint64("checkpointInterval", prev.CheckpointInterval).
+			Msg("Checkpoint interval can't be changed after pruning, keeping the previous one")
+		state.CheckpointInterval = prev.CheckpointInterval
+	}
+	if state.FirstKeptBlock <= prev.FirstKeptBlock {
+		return nil
+	}
+
+	// Readers must get an error instead of the partially removed state.
+	if err := p.update(ctx, func(tx db.RwTx) error {
+		return db.WritePrunedState(tx, p.shardId, state)
+	}); err != nil {
+		return fmt.Errorf("failed to write pruned state: %w", err)
+	}
+
+	m := newMarker(p.shardId)
+	for id := range p.keptBlocks(state, latest.Id) {
+		if err := m.markBlock(tx, id); err != nil {
+			return err
+		}
+	}
+	m.lastMarked = latest.Id
+
+	removed := 0
+	for _, table := range prunedTables {
+		n, err := p.sweep(ctx, tx, m, table)
+		removed += n
+		if err != nil {
+			return fmt.Errorf("failed to sweep %s: %w", table, err)
+		}
+	}
+
+	p.logger.Info().
+		Uint64("firstKeptBlock", uint64(state.FirstKeptBlock)).
+		Int("removedNodes", removed).
+		Msg("Pruned state")
+	return nil
+}
+
+// keptBlocks returns the checkpoints and the latest blocks up to the given one.
+func (p *Pruner) keptBlocks(state db.PrunedState, latest types.BlockNumber) func(func(types.BlockNumber) bool) {
+	return func(yield func(types.BlockNumber) bool) {
+		if state.CheckpointInterval != 0 {
+			interval := types.BlockNumber(state.CheckpointInterval)
+			for id := types.BlockNumber(0); id < state.FirstKeptBlock; id += interval {
+				if !yield(id) {
+					return
+				}
+			}
+		}
+		for id := state.FirstKeptBlock; id <= latest; id++ {
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+// sweep removes the unmarked nodes of the table. Returns the number of removed nodes.
+func (p *Pruner) sweep(ctx context.Context, tx db.RoTx, m *marker, table db.ShardedTableName) (int, error) {
+	iter, err := tx.RangeByShard(p.shardId, table, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+
+	removed := 0
+	batch := make([][]byte, 0, sweepBatchSize)
+	removeBatch := func() error {
+		err := p.update(ctx, func(tx db.RwTx) error {
+			if err := m.markNewBlocks(tx); err != nil {
+				return err
+			}
+			for _, key := range batch {
+				if m.isMarked(table, key) {
+					continue
+				}
+				// Reading the node makes the commit fail if the node is written concurrently.
+				if _, err := tx.GetFromShard(p.shardId, table, key); err != nil {
+					if errors.Is(err, db.ErrKeyNotFound) {
+						continue
+					}
+					return err
+				}
+				if err := tx.DeleteFromShard(p.shardId, table, key); err != nil {
+					return err
+				}
+				removed++
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+
+	for iter.HasNext() {
+		key, _, err := iter.Next()
+		if err != nil {
+			return removed, err
+		}
+		if m.isMarked(table, key) {
+			continue
+		}
+		batch = append(batch, key)
+		if len(batch) == sweepBatchSize {
+			if err := removeBatch(); err != nil {
+				return removed, err
+			}
+		}
+	}
+	if len(batch) != 0 {
+		if err := removeBatch(); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+func (p *Pruner) update(ctx context.Context, f func(tx db.RwTx) error) error {
+	tx, err := p.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// marker collects the keys of the trie nodes reachable from the state of the kept blocks.
+type marker struct {
+	shardId types.ShardId
+	marked  map[db.ShardedTableName]map[string]struct{}
+
+	// lastMarked is the latest block whose state is marked.
+	lastMarked types.BlockNumber
+}
+
+func newMarker(shardId types.ShardId) *marker {
+	m := &marker{
+		shardId: shardId,
+		marked:  make(map[db.ShardedTableName]map[string]struct{}, len(prunedTables)),
+	}
+	for _, table := range prunedTables {
+		m.marked[table] = make(map[string]struct{})
+	}
+	return m
+}
+
+func (m *marker) isMarked(table db.ShardedTableName, key []byte) bool {
+	_, ok := m.marked[table][string(key)]
+	return ok
+}
+
+// markNewBlocks marks the state of the blocks committed after lastMarked.
+func (m *marker) markNewBlocks(tx db.RoTx) error {
+	latest, _, err := db.ReadLastBlock(tx, m.shardId)
+	if err != nil {
+		return fmt.Errorf("failed to read the last block: %w", err)
+	}
+	for id := m.lastMarked + 1; id <= latest.Id; id++ {
+		if err := m.markBlock(tx, id); err != nil {
+			return err
+		}
+	}
+	m.lastMarked = max(m.lastMarked, latest.Id)
+	return nil
+}
+
+func (m *marker) markBlock(tx db.RoTx, id types.BlockNumber) error {
+	block, err := db.ReadBlockByNumber(tx, m.shardId, id)
+	if err != nil {
+		return fmt.Errorf("failed to read block %d: %w", id, err)
+	}
+
+	markContract := func(data []byte) error {
+		var contract types.SmartContract
+		if err := contract.UnmarshalSSZ(data); err != nil {
+			return err
+		}
+		if err := m.markTrie(tx, db.StorageTrieTable, contract.StorageRoot, nil); err != nil {
+			return err
+		}
+		if err := m.markTrie(tx, db.TokenTrieTable, contract.TokenRoot, nil); err != nil {
+			return err
+		}
+		return m.markTrie(tx, db.AsyncCallContextTable, contract.AsyncContextRoot, nil)
+	}
+	if err := m.markTrie(tx, db.ContractTrieTable, block.SmartContractsRoot, markContract); err != nil {
+		return fmt.Errorf("failed to mark state of block %d: %w", id, err)
+	}
+	return nil
+}
+
+// markTrie marks the nodes of the trie and calls onValue for the values stored in the newly marked nodes.
+// The subtries of the marked nodes are skipped, since they were marked along with them.
+func (m *marker) markTrie(
+	tx db.RoTx, table db.ShardedTableName, root common.Hash, onValue func(data []byte) error,
+) error {
+	marked := m.marked[table]
+
+	// The node cache is bypassed: it would be flooded by the walked nodes.
+	reader := mpt.NewReader(mpt.NewDbGetter(tx, m.shardId, table))
+	reader.SetRootHash(root)
+	return reader.WalkNodes(func(ref mpt.Reference, node mpt.Node) (bool, error) {
+		if len(ref) >= len(common.EmptyHash) {
+			if _, ok := marked[string(ref)]; ok {
+				return false, nil
+			}
+			marked[string(ref)] = struct{}{}
+		}
+		if data := node.Data(); onValue != nil && len(data) > 0 {
+			if err := onValue(data); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,127 @@
+package pruner
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	const shardId = types.BaseShardId
+	const numBlocks = 10
+	addr := types.GenerateRandomAddress(shardId)
+	key := common.BytesToHash([]byte("key"))
+
+	newState := func(tx any, block *types.Block) *execution.ExecutionState {
+		t.Helper()
+
+		es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+			Block:          block,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		return es
+	}
+
+	countNodes := func() int {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		n := 0
+		for _, table := range prunedTables {
+			iter, err := tx.RangeByShard(shardId, table, nil, nil)
+			require.NoError(t, err)
+			for iter.HasNext() {
+				_, _, err := iter.Next()
+				require.NoError(t, err)
+				n++
+			}
+			iter.Close()
+		}
+		return n
+	}
+
+	blocks := make([]*types.Block, 0, numBlocks)
+	for i := range numBlocks {
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+
+		var prev *types.Block
+		if i > 0 {
+			prev = blocks[i-1]
+		}
+		es := newState(tx, prev)
+		if i == 0 {
+			require.NoError(t, es.CreateAccount(addr))
+		}
+		require.NoError(t, es.SetState(addr, key, common.IntToHash(i)))
+
+		res, err := es.Commit(types.BlockNumber(i), nil)
+		require.NoError(t, err)
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, res, execution.ModeVerify))
+		require.NoError(t, tx.Commit())
+
+		blocks = append(blocks, res.Block)
+	}
+
+	nodesBefore := countNodes()
+
+	p := New(&Config{KeepBlocks: 3, CheckpointInterval: 4}, database, shardId)
+	require.NoError(t, p.Prune(ctx))
+
+	require.Less(t, countNodes(), nodesBefore)
+
+	tx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	state, err := db.ReadPrunedState(tx, shardId)
+	require.NoError(t, err)
+	require.Equal(t, db.PrunedState{FirstKeptBlock: 7, CheckpointInterval: 4}, state)
+
+	for i, block := range blocks {
+		kept := state.IsKept(block.Id)
+
+		err := db.CheckStateAvailable(tx, shardId, block.Id)
+		if !kept {
+			require.ErrorIs(t, err, db.ErrStatePruned, "block %d", i)
+
+			contracts := execution.NewDbContractTrieReader(tx, shardId)
+			contracts.SetRootHash(block.SmartContractsRoot)
+			_, err = contracts.Fetch(addr.Hash())
+			require.ErrorIs(t, err, db.ErrKeyNotFound, "block %d", i)
+			continue
+		}
+		require.NoError(t, err, "block %d", i)
+
+		value, err := newState(tx, block).GetState(addr, key)
+		require.NoError(t, err, "block %d", i)
+		require.Equal(t, common.IntToHash(i), value, "block %d", i)
+	}
+
+	t.Run("Idempotent", func(t *testing.T) {
+		nodes := countNodes()
+		require.NoError(t, p.Prune(ctx))
+		require.Equal(t, nodes, countNodes())
+	})
+
+	t.Run("NotEnoughBlocks", func(t *testing.T) {
+		p := New(&Config{KeepBlocks: 100}, database, shardId)
+		require.NoError(t, p.Prune(ctx))
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 	"sync"
@@ -32,8 +33,9 @@ type MetaLog struct {
 }
 
 type Filter struct {
-	query  *FilterQuery
-	output chan *MetaLog
+	shardId types.ShardId
+	query   *FilterQuery
+	output  chan *MetaLog
 }
 
 // FilterQuery contains options for contract log filtering.
@@ -55,30 +57,68 @@ type FilterQuery struct {
 	// {{A}, {B}}         matches topic A in first position AND B in second position
 	// {{A, B}, {C, D}}   matches topic (A OR B) in first position AND (C OR D) in second position
 	Topics [][]common.Hash
+
+	ShardIds []types.ShardId // used by eth_getLogs, restricts the search to the shards, empty means all shards
+}
+
+// MatchTopics checks whether the topics of the log satisfy the query.
+func (q *FilterQuery) MatchTopics(log *types.Log) bool {
+	if len(q.Topics) > log.TopicsNum() {
+		return false
+	}
+	for i, alternatives := range q.Topics {
+		if len(alternatives) != 0 && !slices.Contains(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchBloom checks whether a block with the given bloom may contain logs satisfying the query.
+// False positives are possible, so the logs of the matched blocks must be checked with MatchTopics.
+func (q *FilterQuery) MatchBloom(bloom types.Bloom) bool {
+	if len(q.Addresses) != 0 && !slices.ContainsFunc(q.Addresses, func(addr types.Address) bool {
+		return types.BloomLookup(bloom, addr)
+	}) {
+		return false
+	}
+	for _, alternatives := range q.Topics {
+		if len(alternatives) != 0 && !slices.ContainsFunc(alternatives, func(topic common.Hash) bool {
+			return types.BloomLookup(bloom, topic)
+		}) {
+			return false
+		}
+	}
+	return true
 }
 
 type (
 	SubscriptionID string
 )
 
+type blocksListener struct {
+	shardId types.ShardId
+	output  chan<- *types.Block
+}
+
 type FiltersManager struct {
 	ctx       context.Context
 	db        db.ReadOnlyDB
-	shardId   types.ShardId
 	filters   map[SubscriptionID]*Filter
-	blockSubs map[SubscriptionID]chan<- *types.Block
+	blockSubs map[SubscriptionID]blocksListener
 	mutex     sync.RWMutex
-	lastHash  common.Hash
-	wg        sync.WaitGroup
+	// lastHashes holds the hash of the last processed block for every watched shard.
+	lastHashes map[types.ShardId]common.Hash
+	wg         sync.WaitGroup
 }
 
 func NewFiltersManager(ctx context.Context, db db.ReadOnlyDB, noPolling bool) *FiltersManager {
 	f := &FiltersManager{
-		ctx:       ctx,
-		db:        db,
-		filters:   make(map[SubscriptionID]*Filter),
-		blockSubs: make(map[SubscriptionID]chan<- *types.Block),
-		lastHash:  common.EmptyHash,
+		ctx:        ctx,
+		db:         db,
+		filters:    make(map[SubscriptionID]*Filter),
+		blockSubs:  make(map[SubscriptionID]blocksListener),
+		lastHashes: map[types.ShardId]common.Hash{types.MainShardId: common.EmptyHash},
 	}
 
 	if !noPolling {
@@ -97,14 +137,21 @@ func (f *Filter) LogsChannel() <-chan *MetaLog {
 	return f.output
 }
 
+// NewFilter creates a filter for the logs of the main shard.
 func (m *FiltersManager) NewFilter(query *FilterQuery) (SubscriptionID, *Filter) {
+	return m.NewShardFilter(types.MainShardId, query)
+}
+
+// NewShardFilter creates a filter for the logs of the given shard.
+func (m *FiltersManager) NewShardFilter(shardId types.ShardId, query *FilterQuery) (SubscriptionID, *Filter) {
 	id := generateSubscriptionID()
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	filter := &Filter{query: query, output: make(chan *MetaLog, 100)}
+	filter := &Filter{shardId: shardId, query: query, output: make(chan *MetaLog, 100)}
 	m.filters[id] = filter
+	m.watchShardLocked(shardId)
 
 	if query.FromBlock != nil || query.ToBlock != nil {
 		if err := m.processBlocksRange(filter); err != nil {
@@ -128,27 +175,47 @@ func (m *FiltersManager) RemoveFilter(id SubscriptionID) bool {
 	return exist
 }
 
+// AddBlocksListener subscribes to the new blocks of the main shard.
 func (m *FiltersManager) AddBlocksListener() (SubscriptionID, <-chan *types.Block) {
+	return m.AddShardBlocksListener(types.MainShardId)
+}
+
+// AddShardBlocksListener subscribes to the new blocks of the given shard.
+func (m *FiltersManager) AddShardBlocksListener(shardId types.ShardId) (SubscriptionID, <-chan *types.Block) {
 	id := generateSubscriptionID()
 	ch := make(chan *types.Block, 100)
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	m.blockSubs[id] = ch
+	m.blockSubs[id] = blocksListener{shardId: shardId, output: ch}
+	m.watchShardLocked(shardId)
 	return id, ch
 }
 
 func (m *FiltersManager) RemoveBlocksListener(id SubscriptionID) bool {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	_, exist := m.blockSubs[id]
+	listener, exist := m.blockSubs[id]
 	if exist {
-		close(m.blockSubs[id])
+		close(listener.output)
 		delete(m.blockSubs, id)
 	}
 	return exist
 }
 
+// watchShardLocked starts polling the shard if it is not polled yet.
+// Blocks committed before the call are not delivered to the listeners.
+func (m *FiltersManager) watchShardLocked(shardId types.ShardId) {
+	if _, ok := m.lastHashes[shardId]; ok {
+		return
+	}
+	lastHash, err := m.getLastBlockHash(shardId)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		logger.Warn().Err(err).Stringer(logging.FieldShardId, shardId).Msg("getLastBlockHash failed")
+	}
+	m.lastHashes[shardId] = lastHash
+}
+
 // PollBlocks polls the blockchain for new committed blocks, if found - parse it's receipts and send logs to the matched
 // filters. TODO: Remove polling, probably blockchain should raise events about new blocks by itself.
 func (m *FiltersManager) PollBlocks(delay time.Duration) {
@@ -160,38 +227,61 @@ func (m *FiltersManager) PollBlocks(delay time.Duration) {
 		case <-time.After(delay):
 		}
 
-		lastHash, err := m.getLastBlockHash()
+		m.mutex.RLock()
+		shardIds := slices.Collect(maps.Keys(m.lastHashes))
+		m.mutex.RUnlock()
+
+		for _, shardId := range shardIds {
+			m.pollShard(shardId)
+		}
+	}
+}
+
+func (m *FiltersManager) pollShard(shardId types.ShardId) {
+	lastHash, err := m.getLastBlockHash(shardId)
+	if err != nil {
+		if !errors.Is(err, db.ErrKeyNotFound) {
+			logger.Warn().Err(err).Stringer(logging.FieldShardId, shardId).Msg("getLastBlockHash failed")
+		}
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	prevHash := m.lastHashes[shardId]
+	if prevHash == lastHash {
+		return
+	}
+
+	// Collect the new blocks from the latest one back to the last processed,
+	// then deliver them in the order of their numbers.
+	var blocks []*types.Block
+	var receipts []types.Receipts
+	for currHash := lastHash; currHash != prevHash && currHash != common.EmptyHash; {
+		block, blockReceipts, err := m.readBlockWithReceipts(shardId, currHash)
 		if err != nil {
-			if !errors.Is(err, db.ErrKeyNotFound) {
-				logger.Warn().Err(err).Msg("getLastBlockHash failed")
-			}
-			continue
+			logger.Warn().Err(err).Stringer(logging.FieldShardId, shardId).Msg("processBlockHash failed")
+			return
 		}
+		blocks = append(blocks, block)
+		receipts = append(receipts, blockReceipts)
+		currHash = block.PrevBlock
+	}
 
-		if m.lastHash != lastHash {
-			m.mutex.Lock()
-			for currHash := lastHash; m.lastHash != currHash; {
-				block, err := m.processBlockHash(currHash)
-				if err != nil {
-					logger.Warn().Err(err).Msg("processBlockHash failed")
-					continue
-				}
-				for _, ch := range m.blockSubs {
-					// Don't send if the channel is full.
-					// Probably subscriber just disconnected, and it shouldn't block us.
-					if len(ch) < cap(ch) {
-						ch <- block
-					}
-				}
-				currHash = block.PrevBlock
-				if currHash == common.EmptyHash {
-					break
-				}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if err := m.process(shardId, blocks[i], receipts[i]); err != nil {
+			logger.Warn().Err(err).Stringer(logging.FieldShardId, shardId).Msg("processing block failed")
+		}
+		for _, listener := range m.blockSubs {
+			// Don't send if the channel is full.
+			// Probably subscriber just disconnected, and it shouldn't block us.
+			if listener.shardId == shardId && len(listener.output) < cap(listener.output) {
+				listener.output <- blocks[i]
 			}
-			m.lastHash = lastHash
-			m.mutex.Unlock()
 		}
 	}
+	m.lastHashes[shardId] = lastHash
 }
 
 // / If FromBlock is set in the filter, then processBlocksRange processes all blocks in the range [FromBlock..ToBlock].
@@ -213,7 +303,7 @@ func (m *FiltersManager) processBlocksRange(filter *Filter) error {
 	if filter.query.ToBlock != nil {
 		lastBlockNum = filter.query.ToBlock.Uint64()
 	} else {
-		lastBlock, _, err := db.ReadLastBlock(tx, m.shardId)
+		lastBlock, _, err := db.ReadLastBlock(tx, filter.shardId)
 		if err != nil {
 			return err
 		}
@@ -221,11 +311,11 @@ func (m *FiltersManager) processBlocksRange(filter *Filter) error {
 	}
 
 	for ; fromBlockNum <= lastBlockNum; fromBlockNum++ {
-		block, err := db.ReadBlockByNumber(tx, m.shardId, types.BlockNumber(fromBlockNum))
+		block, err := db.ReadBlockByNumber(tx, filter.shardId, types.BlockNumber(fromBlockNum))
 		if err != nil {
 			return err
 		}
-		receipts, err := m.readReceipts(tx, block)
+		receipts, err := m.readReceipts(tx, filter.shardId, block)
 		if err != nil {
 			return err
 		}
@@ -236,30 +326,32 @@ func (m *FiltersManager) processBlocksRange(filter *Filter) error {
 	return nil
 }
 
-func (m *FiltersManager) readReceipts(tx db.RoTx, block *types.Block) ([]*types.Receipt, error) {
-	reader := execution.NewDbReceiptTrieReader(tx, m.shardId)
+func (m *FiltersManager) readReceipts(tx db.RoTx, shardId types.ShardId, block *types.Block) ([]*types.Receipt, error) {
+	reader := execution.NewDbReceiptTrieReader(tx, shardId)
 	reader.SetRootHash(block.ReceiptsRoot)
 	return reader.Values()
 }
 
-func (m *FiltersManager) processBlockHash(lastHash common.Hash) (*types.Block, error) {
+func (m *FiltersManager) readBlockWithReceipts(
+	shardId types.ShardId,
+	hash common.Hash,
+) (*types.Block, types.Receipts, error) {
 	tx, err := m.db.CreateRoTx(m.ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer tx.Rollback()
 
-	block, err := db.ReadBlock(tx, m.shardId, lastHash)
+	block, err := db.ReadBlock(tx, shardId, hash)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	receipts, err := m.readReceipts(tx, block)
+	receipts, err := m.readReceipts(tx, shardId, block)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return block, m.process(block, receipts)
+	return block, receipts, nil
 }
 
 func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, receipts types.Receipts) error {
@@ -271,27 +363,7 @@ func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, recei
 			continue
 		}
 		for _, log := range receipt.Logs {
-			found := true
-			for i, topics := range filter.query.Topics {
-				if i >= log.TopicsNum() {
-					found = false
-					break
-				}
-				switch len(topics) {
-				case 0:
-					continue
-				case 1: // valid case, process after switch block
-				default:
-					panic("TODO: Topics disjunction isn't supported yet")
-				}
-
-				logTopic := log.Topics[i]
-				if logTopic != topics[0] {
-					found = false
-					break
-				}
-			}
-			if found {
+			if filter.query.MatchTopics(log) {
 				filter.output <- &MetaLog{log, block.Id}
 			}
 		}
@@ -299,8 +371,11 @@ func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, recei
 	return nil
 }
 
-func (m *FiltersManager) process(block *types.Block, receipts types.Receipts) error {
+func (m *FiltersManager) process(shardId types.ShardId, block *types.Block, receipts types.Receipts) error {
 	for _, filter := range m.filters {
+		if filter.shardId != shardId {
+			continue
+		}
 		err := m.processFilter(block, filter, receipts)
 		if err != nil {
 			return err
@@ -313,14 +388,14 @@ func (m *FiltersManager) process(block *types.Block, receipts types.Receipts) er
 func (m *FiltersManager) OnNewBlock(block *types.Block) {
 }
 
-func (m *FiltersManager) getLastBlockHash() (common.Hash, error) {
+func (m *FiltersManager) getLastBlockHash(shardId types.ShardId) (common.Hash, error) {
 	tx, err := m.db.CreateRoTx(m.ctx)
 	if err != nil {
 		return common.EmptyHash, err
 	}
 	defer tx.Rollback()
 
-	return db.ReadLastBlockHash(tx, m.shardId)
+	return db.ReadLastBlockHash(tx, shardId)
 }
 
 var globalSubscriptionId uint64
@@ -351,6 +426,7 @@ func (args *FilterQuery) UnmarshalJSON(data []byte) error {
 		ToBlock   *transport.BlockNumber `json:"toBlock"`
 		Addresses any                    `json:"address"`
 		Topics    []any                  `json:"topics"`
+		ShardIds  []types.ShardId        `json:"shardIds"`
 	}
 
 	var raw input
@@ -374,6 +450,7 @@ func (args *FilterQuery) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	args.ShardIds = raw.ShardIds
 	args.Addresses = []types.Address{}
 
 	if raw.Addresses != nil {
@@ -69,7 +69,7 @@ func (s *SuiteFilters) TestMatcherOneReceipt() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Len(f.output, 3)
 	s.Equal((<-f.LogsChannel()).Log, logs[0])
 	s.Equal((<-f.LogsChannel()).Log, logs[1])
@@ -81,7 +81,7 @@ func (s *SuiteFilters) TestMatcherOneReceipt() {
 		&FilterQuery{Addresses: []types.Address{address1}, Topics: [][]common.Hash{{{0x01}}, {{0x02}}}})
 	s.NotEmpty(id)
 	s.NotNil(f)
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Len(f.output, 1)
 	s.Equal((<-f.LogsChannel()).Log, logs[0])
 	filters.RemoveFilter(id)
@@ -91,7 +91,18 @@ func (s *SuiteFilters) TestMatcherOneReceipt() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
+	s.Len(f.output, 2)
+	s.Equal((<-f.LogsChannel()).Log, logs[0])
+	s.Equal((<-f.LogsChannel()).Log, logs[1])
+	filters.RemoveFilter(id)
+
+	// Only logs with [1 or 3] topics
+	id, f = filters.NewFilter(&FilterQuery{Topics: [][]common.Hash{{{0x01}, {0x03}}}})
+	s.NotEmpty(id)
+	s.NotNil(f)
+
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Len(f.output, 2)
 	s.Equal((<-f.LogsChannel()).Log, logs[0])
 	s.Equal((<-f.LogsChannel()).Log, logs[1])
@@ -153,7 +164,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Len(f.output, 6)
 	s.Equal((<-f.LogsChannel()).Log, logs1[0])
 	s.Equal((<-f.LogsChannel()).Log, logs1[1])
@@ -168,7 +179,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Len(f.output, 4)
 	s.Equal((<-f.LogsChannel()).Log, logs1[0])
 	s.Equal((<-f.LogsChannel()).Log, logs1[1])
@@ -181,7 +192,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Len(f.output, 2)
 	s.Equal((<-f.LogsChannel()).Log, logs2[0])
 	s.Equal((<-f.LogsChannel()).Log, logs2[1])
@@ -195,7 +206,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Require().Len(f.LogsChannel(), 2)
 	s.Equal((<-f.LogsChannel()).Log, logs1[0])
 	s.Equal((<-f.LogsChannel()).Log, logs1[3])
@@ -206,7 +217,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Require().Len(f.LogsChannel(), 2)
 	s.Equal((<-f.LogsChannel()).Log, logs1[0])
 	s.Equal((<-f.LogsChannel()).Log, logs2[0])
@@ -217,7 +228,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Require().Len(f.LogsChannel(), 2)
 	s.Equal((<-f.LogsChannel()).Log, logs1[3])
 	s.Equal((<-f.LogsChannel()).Log, logs2[1])
@@ -231,7 +242,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Require().Len(f.LogsChannel(), 2)
 	s.Equal((<-f.LogsChannel()).Log, logs1[1])
 	s.Equal((<-f.LogsChannel()).Log, logs1[3])
@@ -242,7 +253,7 @@ func (s *SuiteFilters) TestMatcherTwoReceipts() {
 	s.NotEmpty(id)
 	s.NotNil(f)
 
-	s.Require().NoError(filters.process(&block, receipts))
+	s.Require().NoError(filters.process(types.MainShardId, &block, receipts))
 	s.Require().Len(f.LogsChannel(), 3)
 	s.Equal((<-f.LogsChannel()).Log, logs1[1])
 	s.Equal((<-f.LogsChannel()).Log, logs1[3])
@@ -396,11 +407,50 @@ func (s *SuiteFilters) TestBlocksRange() {
 	s.Require().NoError(tx.Commit())
 
 	// Check that only filter2 can get new logs, because it doesn't have `ToBlock` field
-	s.Require().NoError(filters.process(&block, []*types.Receipt{receipt}))
+	s.Require().NoError(filters.process(types.MainShardId, &block, []*types.Receipt{receipt}))
 	s.Empty(filter1.output)
 	s.GreaterOrEqual(len(filter2.output), 1)
 }
 
+func (s *SuiteFilters) TestShardBlocksListener() {
+	s.filters = NewFiltersManager(s.ctx, s.db, true)
+
+	const shardId = types.ShardId(2)
+	writeBlocks := func(blocks ...*types.Block) {
+		s.T().Helper()
+		tx, err := s.db.CreateRwTx(s.ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+		for _, block := range blocks {
+			s.Require().NoError(db.WriteBlock(tx, shardId, block.Hash(shardId), block))
+		}
+		s.Require().NoError(db.WriteLastBlockHash(tx, shardId, blocks[len(blocks)-1].Hash(shardId)))
+		s.Require().NoError(tx.Commit())
+	}
+
+	block1 := &types.Block{BlockData: types.BlockData{Id: 1}}
+	writeBlocks(block1)
+
+	// The listener sees only the blocks committed after subscription.
+	id, ch := s.filters.AddShardBlocksListener(shardId)
+	_, mainCh := s.filters.AddBlocksListener()
+
+	block2 := &types.Block{BlockData: types.BlockData{Id: 2, PrevBlock: block1.Hash(shardId)}}
+	block3 := &types.Block{BlockData: types.BlockData{Id: 3, PrevBlock: block2.Hash(shardId)}}
+	writeBlocks(block2, block3)
+
+	s.filters.pollShard(types.MainShardId)
+	s.filters.pollShard(shardId)
+
+	s.Require().Len(ch, 2)
+	s.Equal(block2.Id, (<-ch).Id)
+	s.Equal(block3.Id, (<-ch).Id)
+	s.Empty(mainCh)
+
+	s.True(s.filters.RemoveBlocksListener(id))
+	s.False(s.filters.RemoveBlocksListener(id))
+}
+
 func TestFilters(t *testing.T) {
 	t.Parallel()
 
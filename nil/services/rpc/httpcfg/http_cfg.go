@@ -40,6 +40,7 @@ type HttpCfg struct {
 	HttpURL         string
 	HttpCORSDomain  []string
 	HttpCompression bool
+	WSEnabled       bool // Serve WebSocket connections on the HTTP endpoint
 
 	TraceRequests      bool // Print requests to logs at INFO level
 	DebugSingleRequest bool // Print single-request-related debugging info to logs at INFO level
@@ -2,11 +2,14 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
@@ -28,8 +31,35 @@ type DebugAPI interface {
 		blockNrOrHash transport.BlockNumberOrHash,
 	) (*DebugRPCContract, error)
 	GetBootstrapConfig(ctx context.Context) (*rpctypes.BootstrapConfig, error)
+	TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (json.RawMessage, error)
+	GetTransactionStateDiff(ctx context.Context, hash common.Hash) (*execution.StateDiff, error)
+	GetConsensusEvidence(ctx context.Context, shardId types.ShardId) ([]*DebugConsensusEvidence, error)
+	TraceCall(
+		ctx context.Context,
+		args CallArgs,
+		mainBlockNrOrHash transport.BlockNumberOrHash,
+		overrides *StateOverrides,
+		config *TraceConfig,
+	) (json.RawMessage, error)
+	TraceTransactionTree(ctx context.Context, hash common.Hash, config *TraceConfig) (*DebugTransactionTrace, error)
+	GetStorageRange(
+		ctx context.Context,
+		contractAddr types.Address,
+		startKey common.Hash,
+		maxResults uint64,
+		blockNrOrHash transport.BlockNumberOrHash,
+	) (*DebugStorageRange, error)
 }
 
+const MaxStorageRangeResults = 1024
+
+const (
+	// MaxTransactionTreeDepth and MaxTransactionTreeNodes bound the tree traced by debug_traceTransactionTree.
+	// The outbound transactions beyond the limits are not traced and their parent is marked as truncated.
+	MaxTransactionTreeDepth = 64
+	MaxTransactionTreeNodes = 256
+)
+
 type DebugAPIImpl struct {
 	logger logging.Logger
 	rawApi rawapi.NodeApi
@@ -124,6 +154,207 @@ func (api *DebugAPIImpl) GetContract(
 	}, nil
 }
 
+// GetStorageRange implements debug_getStorageRange. Returns a page of the contract storage
+// starting from startKey in the order of the keys. NextKey of the result is the startKey of the next page.
+// At most MaxStorageRangeResults entries are returned, that is also the page size if maxResults is zero.
+func (api *DebugAPIImpl) GetStorageRange(
+	ctx context.Context,
+	contractAddr types.Address,
+	startKey common.Hash,
+	maxResults uint64,
+	blockNrOrHash transport.BlockNumberOrHash,
+) (*DebugStorageRange, error) {
+	if maxResults == 0 || maxResults > MaxStorageRangeResults {
+		maxResults = MaxStorageRangeResults
+	}
+
+	storageRange, err := api.rawApi.GetStorageRange(
+		ctx, contractAddr, startKey, maxResults, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DebugStorageRange{
+		Storage: storageRange.Storage,
+		NextKey: storageRange.NextKey,
+	}, nil
+}
+
 func (api *DebugAPIImpl) GetBootstrapConfig(ctx context.Context) (*rpctypes.BootstrapConfig, error) {
 	return api.rawApi.GetBootstrapConfig(ctx)
 }
+
+// TraceTransaction implements debug_traceTransaction.
+// Re-executes the transaction on top of the state of the previous block and returns its trace
+// produced by the tracer selected in the config.
+func (api *DebugAPIImpl) TraceTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	config *TraceConfig,
+) (json.RawMessage, error) {
+	shardId := types.ShardIdFromHash(hash)
+	return api.rawApi.TraceTransaction(ctx, shardId, hash, config)
+}
+
+// GetTransactionStateDiff implements debug_getTransactionStateDiff.
+// Re-executes the transaction on top of the state of the previous block and returns
+// the balances, seqnos, code, storage slots and token balances it changed, before and after the transaction.
+func (api *DebugAPIImpl) GetTransactionStateDiff(ctx context.Context, hash common.Hash) (*execution.StateDiff, error) {
+	shardId := types.ShardIdFromHash(hash)
+	return api.rawApi.GetTransactionStateDiff(ctx, shardId, hash)
+}
+
+// GetConsensusEvidence implements debug_getConsensusEvidence.
+// Returns the pairs of conflicting messages signed by the shard validators that were seen by the node.
+func (api *DebugAPIImpl) GetConsensusEvidence(
+	ctx context.Context,
+	shardId types.ShardId,
+) ([]*DebugConsensusEvidence, error) {
+	list, err := api.rawApi.GetConsensusEvidence(ctx, shardId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*DebugConsensusEvidence, len(list))
+	for i, ev := range list {
+		if res[i], err = NewDebugConsensusEvidence(ev); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// TraceCall implements debug_traceCall.
+// Executes the call like eth_call does and returns its trace produced by the tracer selected in the config.
+func (api *DebugAPIImpl) TraceCall(
+	ctx context.Context,
+	args CallArgs,
+	mainBlockNrOrHash transport.BlockNumberOrHash,
+	overrides *StateOverrides,
+	config *TraceConfig,
+) (json.RawMessage, error) {
+	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
+	if args.Fee.FeeCredit.IsZero() {
+		args.Fee = types.NewFeePackFromGas(1_000_000_000_000_000_000)
+	}
+	return api.rawApi.TraceCall(ctx, args, blockRef, overrides, config)
+}
+
+// TraceTransactionTree implements debug_traceTransactionTree.
+// Starts from the given transaction and follows its outbound transactions across shards,
+// re-executing each of them with the tracer selected in the config (the call tracer by default).
+// Outbound transactions that are not executed yet are marked as pending.
+// At most MaxTransactionTreeNodes transactions up to MaxTransactionTreeDepth levels deep are traced.
+func (api *DebugAPIImpl) TraceTransactionTree(
+	ctx context.Context,
+	hash common.Hash,
+	config *TraceConfig,
+) (*DebugTransactionTrace, error) {
+	return api.traceTransactionTreeWithLimits(ctx, hash, config, MaxTransactionTreeDepth, MaxTransactionTreeNodes)
+}
+
+func (api *DebugAPIImpl) traceTransactionTreeWithLimits(
+	ctx context.Context,
+	hash common.Hash,
+	config *TraceConfig,
+	maxDepth int,
+	maxNodes int,
+) (*DebugTransactionTrace, error) {
+	if config == nil {
+		config = &TraceConfig{Tracer: tracers.CallTracerName}
+	}
+
+	info, err := api.rawApi.GetInTransactionReceipt(ctx, types.ShardIdFromHash(hash), hash)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("receipt for transaction %s not found", hash)
+	}
+	t := &transactionTreeTracer{api: api, config: config, maxDepth: maxDepth, nodesLeft: maxNodes}
+	return t.trace(ctx, hash, info, 0)
+}
+
+// transactionTreeTracer traces the transaction tree within the limits of its depth and size.
+type transactionTreeTracer struct {
+	api       *DebugAPIImpl
+	config    *TraceConfig
+	maxDepth  int
+	nodesLeft int
+}
+
+func (t *transactionTreeTracer) trace(
+	ctx context.Context,
+	hash common.Hash,
+	info *rawapitypes.ReceiptInfo,
+	depth int,
+) (*DebugTransactionTrace, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	t.nodesLeft--
+
+	shardId := types.ShardIdFromHash(hash)
+	node := &DebugTransactionTrace{
+		TxnHash: hash,
+		ShardId: shardId,
+		Pending: info == nil,
+	}
+	if info == nil {
+		return node, nil
+	}
+
+	receipt := &types.Receipt{}
+	if err := receipt.UnmarshalSSZ(info.ReceiptSSZ); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt of %s: %w", hash, err)
+	}
+	node.BlockNumber = info.BlockId
+	node.BlockHash = info.BlockHash
+	node.Flags = info.Flags
+	node.Forwarded = receipt.Forwarded
+	node.GasUsed = receipt.GasUsed
+	node.Success = receipt.Success
+	node.Status = receipt.Status.String()
+	node.ErrorMessage = info.ErrorMessage
+
+	// Temporary receipts belong to transactions that failed before being included in a block,
+	// so there is nothing to re-execute.
+	if !info.Temporary {
+		txnInfo, err := t.api.rawApi.GetInTransaction(ctx, shardId, makeRequestByHash(hash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction %s: %w", hash, err)
+		}
+		txn, _, err := unmarshalTxnAndReceipt(txnInfo)
+		if err != nil {
+			return nil, err
+		}
+		node.From = txn.From
+		node.To = txn.To
+		node.RequestId = txn.RequestId
+		node.RequestChain = txn.RequestChain
+		node.FeeCredit = txn.FeeCredit
+		node.Value = txn.Value
+		node.Token = txn.Token
+
+		node.Trace, err = t.api.rawApi.TraceTransaction(ctx, shardId, hash, t.config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trace transaction %s: %w", hash, err)
+		}
+	}
+
+	for i, outHash := range info.OutTransactions {
+		if depth >= t.maxDepth || t.nodesLeft <= 0 {
+			node.Truncated = true
+			break
+		}
+		var outInfo *rawapitypes.ReceiptInfo
+		if i < len(info.OutReceipts) {
+			outInfo = info.OutReceipts[i]
+		}
+		child, err := t.trace(ctx, outHash, outInfo, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
@@ -2,14 +2,17 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -209,8 +212,199 @@ func (suite *SuiteDbgContracts) TestGetContract() {
 	})
 }
 
+func (suite *SuiteDbgContracts) TestGetStorageRange() {
+	ctx := context.Background()
+	latest := transport.BlockNumberOrHash{BlockNumber: transport.LatestBlock.BlockNumber}
+
+	res, err := suite.debugApi.GetStorageRange(ctx, suite.smcAddr, common.EmptyHash, 0, latest)
+	suite.Require().NoError(err)
+	suite.Require().Equal(map[common.Hash]types.Uint256{
+		{0x1}: *types.NewUint256(2),
+		{0x3}: *types.NewUint256(4),
+	}, res.Storage)
+	suite.Nil(res.NextKey)
+
+	res, err = suite.debugApi.GetStorageRange(ctx, suite.smcAddr, common.EmptyHash, 1, latest)
+	suite.Require().NoError(err)
+	suite.Require().Equal(map[common.Hash]types.Uint256{{0x1}: *types.NewUint256(2)}, res.Storage)
+	suite.Require().NotNil(res.NextKey)
+	suite.Equal(common.Hash{0x3}, *res.NextKey)
+
+	res, err = suite.debugApi.GetStorageRange(ctx, suite.smcAddr, *res.NextKey, 1, latest)
+	suite.Require().NoError(err)
+	suite.Require().Equal(map[common.Hash]types.Uint256{{0x3}: *types.NewUint256(4)}, res.Storage)
+	suite.Nil(res.NextKey)
+
+	unknownAddr := types.GenerateRandomAddress(types.BaseShardId)
+	res, err = suite.debugApi.GetStorageRange(ctx, unknownAddr, common.EmptyHash, 1, latest)
+	suite.Require().NoError(err)
+	suite.Empty(res.Storage)
+	suite.Nil(res.NextKey)
+}
+
 func TestSuiteDbgContracts(t *testing.T) {
 	t.Parallel()
 
 	suite.Run(t, new(SuiteDbgContracts))
 }
+
+func TestDebugTraceTransactionTree(t *testing.T) {
+	t.Parallel()
+
+	const (
+		srcShard = types.ShardId(1)
+		dstShard = types.ShardId(2)
+		// Init code that copies 4 bytes of the runtime code to memory and returns them.
+		code = "6004600c60003960046000f301020304"
+	)
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	newState := func(shardId types.ShardId, prev *execution.BlockGenerationResult) *execution.ExecutionState {
+		t.Helper()
+		params := execution.StateParams{ConfigAccessor: config.GetStubAccessor()}
+		if prev != nil {
+			params.Block = prev.Block
+		}
+		es, err := execution.NewExecutionState(tx, shardId, params)
+		require.NoError(t, err)
+		es.BaseFee = types.DefaultGasPrice
+		return es
+	}
+	commit := func(shardId types.ShardId, es *execution.ExecutionState, id types.BlockNumber) *execution.BlockGenerationResult {
+		t.Helper()
+		res, err := es.Commit(id, &types.ConsensusParams{})
+		require.NoError(t, err)
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, res, execution.ModeVerify))
+		return res
+	}
+	handle := func(es *execution.ExecutionState, txn *types.Transaction, out ...*types.InternalTransactionPayload) {
+		t.Helper()
+		es.AddInTransaction(txn)
+		require.NoError(t, es.AcceptInternalTransaction(txn))
+		res := es.HandleTransaction(ctx, txn, execution.NewTransactionPayer(txn, es))
+		require.False(t, res.Failed())
+		for _, payload := range out {
+			_, err := es.AddOutTransaction(txn.To, payload, 0)
+			require.NoError(t, err)
+		}
+		es.AddReceipt(res)
+	}
+	deployPayload := func(salt byte) types.DeployPayload {
+		return types.BuildDeployPayload(hexutil.FromHex(code), common.BytesToHash([]byte{salt}))
+	}
+
+	srcZero := commit(srcShard, newState(srcShard, nil), 0)
+	dstZero := commit(dstShard, newState(dstShard, nil), 0)
+
+	// The root transaction deploys a contract which sends two deploy transactions to another shard.
+	from := types.GenerateRandomAddress(srcShard)
+	root := execution.NewDeployTransaction(deployPayload(0), srcShard, from, 0, types.Value{})
+	root.RefundTo = from
+	root.BounceTo = from
+	outPayloads := make([]*types.InternalTransactionPayload, 2)
+	for i := range outPayloads {
+		payload := deployPayload(byte(i + 1))
+		outPayloads[i] = &types.InternalTransactionPayload{
+			Kind:        types.DeployTransactionKind,
+			FeeCredit:   types.GasToValue(1_000_000),
+			ForwardKind: types.ForwardKindNone,
+			To:          types.CreateAddress(dstShard, payload),
+			RefundTo:    from,
+			BounceTo:    from,
+			Data:        payload.Bytes(),
+		}
+	}
+
+	srcEs := newState(srcShard, srcZero)
+	handle(srcEs, root, outPayloads...)
+	rootHash := root.Hash()
+	// The refund of the unspent fee credit goes first.
+	outTxns := srcEs.OutTransactions[rootHash]
+	require.Len(t, outTxns, 3)
+	require.True(t, outTxns[0].IsRefund())
+	outTxns = outTxns[1:]
+	commit(srcShard, srcEs, 1)
+
+	// Only the first outbound transaction is executed.
+	dstEs := newState(dstShard, dstZero)
+	handle(dstEs, outTxns[0].Transaction)
+	commit(dstShard, dstEs, 1)
+
+	require.NoError(t, tx.Commit())
+
+	api := NewDebugAPI(
+		rawapi.NodeApiBuilder(database, nil).
+			WithLocalShardApiRo(srcShard, nil).
+			WithLocalShardApiRo(dstShard, nil).
+			BuildAndReset(),
+		logging.GlobalLogger)
+
+	res, err := api.TraceTransactionTree(ctx, rootHash, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, rootHash, res.TxnHash)
+	require.Equal(t, srcShard, res.ShardId)
+	require.EqualValues(t, 1, res.BlockNumber)
+	require.Equal(t, from, res.From)
+	require.True(t, res.Success)
+	require.False(t, res.Pending)
+
+	var frame tracers.CallFrame
+	require.NoError(t, json.Unmarshal(res.Trace, &frame))
+	require.Equal(t, "CREATE", frame.Type)
+	require.Equal(t, root.To, frame.To)
+
+	require.Len(t, res.Children, 3)
+	require.True(t, res.Children[0].Pending)
+
+	executed := res.Children[1]
+	require.Equal(t, outTxns[0].TxnHash, executed.TxnHash)
+	require.Equal(t, dstShard, executed.ShardId)
+	require.EqualValues(t, 1, executed.BlockNumber)
+	require.Equal(t, root.To, executed.From)
+	require.Equal(t, outPayloads[0].To, executed.To)
+	require.Equal(t, outPayloads[0].FeeCredit, executed.FeeCredit)
+	require.NotEmpty(t, executed.Trace)
+	// Its refund is not executed yet.
+	require.Len(t, executed.Children, 1)
+	require.True(t, executed.Children[0].Pending)
+	require.Equal(t, srcShard, executed.Children[0].ShardId)
+
+	pending := res.Children[2]
+	require.Equal(t, outTxns[1].TxnHash, pending.TxnHash)
+	require.Equal(t, dstShard, pending.ShardId)
+	require.True(t, pending.Pending)
+	require.Empty(t, pending.Trace)
+
+	_, err = api.TraceTransactionTree(ctx, common.EmptyHash, nil)
+	require.Error(t, err)
+
+	t.Run("Limits", func(t *testing.T) {
+		// The refund of the executed transaction is beyond the depth limit.
+		res, err := api.traceTransactionTreeWithLimits(ctx, rootHash, nil, 1, MaxTransactionTreeNodes)
+		require.NoError(t, err)
+		require.False(t, res.Truncated)
+		require.Len(t, res.Children, 3)
+		require.True(t, res.Children[1].Truncated)
+		require.Empty(t, res.Children[1].Children)
+
+		// Only the root and its first outbound transaction fit into the node limit.
+		res, err = api.traceTransactionTreeWithLimits(ctx, rootHash, nil, MaxTransactionTreeDepth, 2)
+		require.NoError(t, err)
+		require.True(t, res.Truncated)
+		require.Len(t, res.Children, 1)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = api.traceTransactionTreeWithLimits(cancelled, rootHash, nil, MaxTransactionTreeDepth, 2)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -3,18 +3,41 @@ package jsonrpc
 import (
 	"context"
 
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 )
 
+// DevAPI is available only if the node runs with the development API enabled.
+// The state changes are applied at the beginning of the next block of the shard.
+// The blocks with such changes can't be replayed by other nodes, so the API is meant for local networks only.
 type DevAPI interface {
 	DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error)
+
+	SetBalance(ctx context.Context, address types.Address, balance types.Value) error
+	SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) error
+	SetStorageAt(ctx context.Context, address types.Address, key common.Hash, value common.Hash) error
+	SetTokenBalance(ctx context.Context, address types.Address, token types.TokenId, balance types.Value) error
+
+	Impersonate(ctx context.Context, address types.Address) error
+	StopImpersonating(ctx context.Context, address types.Address) error
+
+	IncreaseTime(ctx context.Context, shardId types.ShardId, seconds hexutil.Uint64) (hexutil.Uint64, error)
+	SetNextBlockTimestamp(ctx context.Context, shardId types.ShardId, timestamp hexutil.Uint64) error
+	Mine(ctx context.Context, shardId types.ShardId, count hexutil.Uint64) (hexutil.Uint64, error)
+
+	Snapshot(ctx context.Context) (hexutil.Uint64, error)
+	Revert(ctx context.Context, id hexutil.Uint64) error
 }
 
 type DevAPIImpl struct {
 	rawApi rawapi.NodeApi
 }
 
+var _ DevAPI = (*DevAPIImpl)(nil)
+
 func NewDevAPI(rawApi rawapi.NodeApi) DevAPI {
 	return &DevAPIImpl{
 		rawApi: rawApi,
@@ -24,3 +47,101 @@ func NewDevAPI(rawApi rawapi.NodeApi) DevAPI {
 func (d *DevAPIImpl) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
 	return d.rawApi.DoPanicOnShard(ctx, shardId)
 }
+
+// SetBalance implements dev_setBalance.
+func (d *DevAPIImpl) SetBalance(ctx context.Context, address types.Address, balance types.Value) error {
+	return d.rawApi.OverrideAccount(ctx, &execution.AccountOverride{
+		Kind:    execution.OverrideBalance,
+		Address: address,
+		Balance: balance,
+	})
+}
+
+// SetCode implements dev_setCode.
+func (d *DevAPIImpl) SetCode(ctx context.Context, address types.Address, code hexutil.Bytes) error {
+	return d.rawApi.OverrideAccount(ctx, &execution.AccountOverride{
+		Kind:    execution.OverrideCode,
+		Address: address,
+		Code:    types.Code(code),
+	})
+}
+
+// SetStorageAt implements dev_setStorageAt.
+func (d *DevAPIImpl) SetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	value common.Hash,
+) error {
+	return d.rawApi.OverrideAccount(ctx, &execution.AccountOverride{
+		Kind:    execution.OverrideStorage,
+		Address: address,
+		Key:     key,
+		Value:   value,
+	})
+}
+
+// SetTokenBalance implements dev_setTokenBalance.
+func (d *DevAPIImpl) SetTokenBalance(
+	ctx context.Context,
+	address types.Address,
+	token types.TokenId,
+	balance types.Value,
+) error {
+	return d.rawApi.OverrideAccount(ctx, &execution.AccountOverride{
+		Kind:    execution.OverrideTokenBalance,
+		Address: address,
+		Token:   token,
+		Balance: balance,
+	})
+}
+
+// Impersonate implements dev_impersonate.
+// External transactions to the address are accepted without calling its verifyExternal.
+func (d *DevAPIImpl) Impersonate(ctx context.Context, address types.Address) error {
+	return d.rawApi.Impersonate(ctx, address, true)
+}
+
+// StopImpersonating implements dev_stopImpersonating.
+func (d *DevAPIImpl) StopImpersonating(ctx context.Context, address types.Address) error {
+	return d.rawApi.Impersonate(ctx, address, false)
+}
+
+// IncreaseTime implements dev_increaseTime. It returns the total time shift of the shard.
+func (d *DevAPIImpl) IncreaseTime(
+	ctx context.Context,
+	shardId types.ShardId,
+	seconds hexutil.Uint64,
+) (hexutil.Uint64, error) {
+	offset, err := d.rawApi.IncreaseTime(ctx, shardId, uint64(seconds))
+	return hexutil.Uint64(offset), err
+}
+
+// SetNextBlockTimestamp implements dev_setNextBlockTimestamp.
+func (d *DevAPIImpl) SetNextBlockTimestamp(
+	ctx context.Context,
+	shardId types.ShardId,
+	timestamp hexutil.Uint64,
+) error {
+	return d.rawApi.SetNextBlockTimestamp(ctx, shardId, uint64(timestamp))
+}
+
+// Mine implements dev_mine. It produces count blocks and returns the number of the last one.
+// It works both with the paused (manual mining) and the running collator.
+func (d *DevAPIImpl) Mine(ctx context.Context, shardId types.ShardId, count hexutil.Uint64) (hexutil.Uint64, error) {
+	blockId, err := d.rawApi.Mine(ctx, shardId, uint64(count))
+	return hexutil.Uint64(blockId), err
+}
+
+// Snapshot implements dev_snapshot. It saves the state of all shards and returns the id of the snapshot.
+// It's available only if the node collates all shards.
+func (d *DevAPIImpl) Snapshot(ctx context.Context) (hexutil.Uint64, error) {
+	id, err := d.rawApi.Snapshot(ctx)
+	return hexutil.Uint64(id), err
+}
+
+// Revert implements dev_revert. It rewinds all shards and their transaction pools to the snapshot.
+// The snapshot and all the later ones can't be used after that.
+func (d *DevAPIImpl) Revert(ctx context.Context, id hexutil.Uint64) error {
+	return d.rawApi.Revert(ctx, uint64(id))
+}
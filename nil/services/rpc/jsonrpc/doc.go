@@ -3,6 +3,8 @@ package jsonrpc
 // @component Address address string "The address of the account or contract."
 // @component TransactionCount transactionCount integer "The transaction count of the account."
 // @component ContractBytecode contractBytecode string "The bytecode of the contract."
+// @component StorageSlot slot string "The key of the storage slot."
+// @component StorageValue storageValue string "The 32-byte value stored in the slot."
 // @component Balance balance integer "The balance of the account."
 // @component BlockShardId shardId integer "The ID of the shard where the block was generated."
 // @component TransactionShardId shardId integer "The ID of the shard where the transaction was recorded."
@@ -19,6 +21,7 @@ package jsonrpc
 // @componentprop ToBlock toBlock integer false "The end of the range of the blocks whose logs should be retrieved by the filter."
 // @componentprop Addresses addresses array true "The addresses of the accounts/contracts the logs for whose events should be retrieved by the filter."
 // @componentprop Topics topics array true "The topics of the events whose lgos should be retrieved by the filter."
+// @componentprop ShardIds shardIds array false "The IDs of the shards whose logs should be retrieved by eth_getLogs. All shards are searched if omitted."
 // @component Value value integer "The amount of tokens."
 // @component IsDeleted isDeleted boolean "The flag that shows whether the filter has been successfully deleted."
 // @component PollFilterId id string "The ID of the filter that should be polled."
@@ -26,11 +29,17 @@ package jsonrpc
 // @component FilterId id string "The ID of the filter."
 // @component FilterChanges filterChanges array "The array of logs, block headers or pending transactions that have occurred since the last poll of the filter."
 // @component FilterLogs filterLogs array "The array of logs that have been recorded since the last poll of the filter."
+// @component Logs logs array "The array of logs matching the query."
 // @component ShardIds shardIds array "The array of shard IDs."
 // @component NumShards numShards integer "The number of shards."
 // @component GasShardId shardId integer "The ID of the shard whose gas price is requested."
 // @component BaseFee baseFee integer "The current base fee the given shard."
 // @component GasPrice gasPrice integer "The current gas price in the given shard."
+// @component BlockCount blockCount integer "The number of blocks in the requested range."
+// @component NewestBlock newestBlock integer "The number of the last block in the requested range."
+// @component RewardPercentiles rewardPercentiles array "The increasing percentiles of the priority fees to sample in each block."
+// @component CallsBatch calls array "The array of calls to execute, each one is a CallArgs object."
+// @component MaxPriorityFeePerGas maxPriorityFeePerGas integer "The suggested priority fee per gas."
 // @component ChainId chainId integer "The chain ID of the network."
 // @component ReturnedValue returnedValue string "The returned value of the executed contract."
 // @component FullTx fullTx boolean "The flag that determines whether full transaction information is returned in the output."
@@ -65,6 +65,21 @@ func (api *APIImplRo) GetCode(
 	return hexutil.Bytes(code), nil
 }
 
+// GetStorageAt implements eth_getStorageAt. Returns the value from a storage slot at a given address.
+// The value of a missing slot (or of a missing contract) is zero.
+func (api *APIImplRo) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	slot common.Hash,
+	blockNrOrHash transport.BlockNumberOrHash,
+) (common.Hash, error) {
+	value, err := api.rawapi.GetStorageAt(ctx, address, slot, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return value.Bytes32(), nil
+}
+
 // GetProof implements eth_getProof. For more info refer to `EthProof`.
 func (api *APIImplRo) GetProof(
 	ctx context.Context,
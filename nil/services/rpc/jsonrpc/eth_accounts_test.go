@@ -135,6 +135,28 @@ func (suite *SuiteEthAccounts) TestGetCode() {
 	suite.Empty(res)
 }
 
+func (suite *SuiteEthAccounts) TestGetStorageAt() {
+	ctx := suite.T().Context()
+
+	blockNum := transport.BlockNumberOrHash{BlockNumber: transport.LatestBlock.BlockNumber}
+	res, err := suite.api.GetStorageAt(ctx, suite.smcAddr, common.HexToHash("0x3"), blockNum)
+	suite.Require().NoError(err)
+	suite.Equal(common.HexToHash("0x4"), res)
+
+	blockHash := transport.BlockNumberOrHash{BlockHash: &suite.blockHash}
+	res, err = suite.api.GetStorageAt(ctx, suite.smcAddr, common.HexToHash("0x1"), blockHash)
+	suite.Require().NoError(err)
+	suite.Equal(common.HexToHash("0x2"), res)
+
+	res, err = suite.api.GetStorageAt(ctx, suite.smcAddr, common.HexToHash("0x2"), blockNum)
+	suite.Require().NoError(err)
+	suite.Equal(common.EmptyHash, res)
+
+	res, err = suite.api.GetStorageAt(ctx, types.GenerateRandomAddress(types.BaseShardId), common.HexToHash("0x1"), blockNum)
+	suite.Require().NoError(err)
+	suite.Equal(common.EmptyHash, res)
+}
+
 func (suite *SuiteEthAccounts) TestGetSeqno() {
 	ctx := suite.T().Context()
 
@@ -6,12 +6,14 @@ import (
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/common/math"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
 type EthAPIRo interface {
@@ -175,6 +177,35 @@ type EthAPIRo interface {
 	*/
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 
+	/*
+		@name FeeHistory
+		@summary Returns the fee history of the shard for the given range of blocks.
+		@description Implements eth_feeHistory.
+		@tags [Transactions]
+		@param shardId GasShardId
+		@param blockCount BlockCount
+		@param newestBlock NewestBlock
+		@param rewardPercentiles RewardPercentiles
+		@returns feeHistory FeeHistory
+	*/
+	FeeHistory(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockCount math.HexOrDecimal64,
+		newestBlock transport.BlockNumber,
+		rewardPercentiles []float64,
+	) (*FeeHistory, error)
+
+	/*
+		@name MaxPriorityFeePerGas
+		@summary Returns the suggested priority fee for the transactions of the shard.
+		@description Implements eth_maxPriorityFeePerGas.
+		@tags [Transactions]
+		@param shardId GasShardId
+		@returns maxPriorityFeePerGas MaxPriorityFeePerGas
+	*/
+	MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error)
+
 	/*
 		@name GetTransactionCount
 		@summary Returns the transaction count of the account with the given address and at the given block.
@@ -199,6 +230,23 @@ type EthAPIRo interface {
 	GetCode(
 		ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash) (hexutil.Bytes, error)
 
+	/*
+		@name GetStorageAt
+		@summary Returns the value from the storage slot of the contract with the given address and at the given block.
+		@description Implements eth_getStorageAt.
+		@tags [Accounts]
+		@param address Address
+		@param slot StorageSlot
+		@param blockNumberOrHash BlockNumberOrHash
+		@returns storageValue StorageValue
+	*/
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		slot common.Hash,
+		blockNrOrHash transport.BlockNumberOrHash,
+	) (common.Hash, error)
+
 	/*
 		@name NewFilter
 		@summary Creates a new filter.
@@ -257,6 +305,17 @@ type EthAPIRo interface {
 	*/
 	GetFilterLogs(_ context.Context, id string) ([]*RPCLog, error)
 
+	/*
+		@name GetLogs
+		@summary Returns the logs matching the query.
+		@description Implements eth_getLogs. Searches the blocks of the given range (or the block with the given hash)
+		in the listed shards, or in all shards if the list is empty.
+		@tags [Filters]
+		@param query FilterQuery
+		@returns logs Logs
+	*/
+	GetLogs(ctx context.Context, query filters.FilterQuery) ([]*RPCLog, error)
+
 	/*
 		@name GetShardsIdList
 		@summary Retrieves a list of IDs of all shards.
@@ -292,6 +351,40 @@ type EthAPIRo interface {
 		overrides *StateOverrides,
 	) (*CallRes, error)
 
+	/*
+		@name CallMany
+		@summary Executes a batch of calls against the same main block.
+		@description Implements eth_callMany. The calls may target contracts of different shards.
+		A batch holds at most as many calls as a JSON-RPC batch. A call without the fee credit gets the gas limit of a block.
+		Once the calls of the batch have used the gas of ten blocks, the remaining calls fail.
+		@tags [Calls]
+		@param calls CallsBatch
+		@param mainBlockNrOrHash BlockNumberOrHash
+		@param overrides StateOverrides
+		@returns callManyRes CallManyRes
+	*/
+	CallMany(
+		ctx context.Context,
+		calls []CallArgs,
+		mainBlockNrOrHash transport.BlockNumberOrHash,
+		overrides *StateOverrides,
+	) (*CallManyRes, error)
+
+	/*
+		@name SimulateTransaction
+		@summary Executes the signed transaction and all the transactions produced by it without sending them.
+		@description Implements eth_simulateTransaction. All the shards are executed on top of the same main block.
+		@tags [Calls]
+		@param encoded Encoded
+		@param mainBlockNrOrHash BlockNumberOrHash
+		@returns simulationRes SimulationRes
+	*/
+	SimulateTransaction(
+		ctx context.Context,
+		encoded hexutil.Bytes,
+		mainBlockNrOrHash transport.BlockNumberOrHash,
+	) (*SimulationRes, error)
+
 	/*
 		@name EstimateFee
 		@summary Executes a new transaction call and returns recommended feeCredit.
@@ -352,9 +445,12 @@ type APIImplRo struct {
 	accessor *execution.StateAccessor
 
 	logs            *LogsAggregator
+	getLogsConfig   *GetLogsConfig
 	logger          logging.Logger
 	clientEventsLog logging.Logger
 	rawapi          rawapi.NodeApi
+	// txnPools are the pools of the shards run by the node, they are used to subscribe to pending transactions.
+	txnPools map[types.ShardId]txnpool.Pool
 }
 
 // APIImpl is implementation of the EthAPI interface based on remote Db access
@@ -371,14 +467,18 @@ func NewEthAPIRo(
 	ctx context.Context,
 	rawapi rawapi.NodeApi,
 	db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool,
 	pollBlocksForLogs bool,
 	logClientEvents bool,
+	getLogsConfig *GetLogsConfig,
 ) *APIImplRo {
 	accessor := execution.NewStateAccessor()
 	api := &APIImplRo{
 		logger:          logging.NewLogger("eth-api"),
 		accessor:        accessor,
 		rawapi:          rawapi,
+		txnPools:        txnPools,
+		getLogsConfig:   getLogsConfig.withDefaults(),
 		clientEventsLog: logging.NewLogger("eth-api-rpc-requests"),
 	}
 	api.logs = NewLogsAggregator(ctx, db, pollBlocksForLogs)
@@ -393,10 +493,12 @@ func NewEthAPI(
 	ctx context.Context,
 	rawapi rawapi.NodeApi,
 	db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool,
 	pollBlocksForLogs bool,
 	logClientEvents bool,
+	getLogsConfig *GetLogsConfig,
 ) *APIImpl {
-	roApi := NewEthAPIRo(ctx, rawapi, db, pollBlocksForLogs, logClientEvents)
+	roApi := NewEthAPIRo(ctx, rawapi, db, txnPools, pollBlocksForLogs, logClientEvents, getLogsConfig)
 	return &APIImpl{roApi}
 }
 
@@ -17,7 +17,7 @@ func NewPools(ctx context.Context, t *testing.T, n int) map[types.ShardId]txnpoo
 
 	pools := make(map[types.ShardId]txnpool.Pool, n)
 	for i := range types.ShardId(n) {
-		pool, err := txnpool.New(ctx, txnpool.NewConfig(i), nil)
+		pool, err := txnpool.New(ctx, txnpool.NewConfig(i), nil, nil)
 		require.NoError(t, err)
 		pools[i] = pool
 	}
@@ -36,7 +36,7 @@ func NewTestEthAPI(ctx context.Context, t *testing.T, db db.DB, nShards int) *AP
 			WithLocalShardApiRo(shardId, nil).
 			WithLocalShardApiRw(shardId, pools[shardId])
 	}
-	return NewEthAPI(ctx, nodeApiBuilder.BuildAndReset(), db, true, false)
+	return NewEthAPI(ctx, nodeApiBuilder.BuildAndReset(), db, pools, true, false, nil)
 }
 
 func TestGetTransactionReceipt(t *testing.T) {
@@ -4,15 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"golang.org/x/sync/errgroup"
 )
 
+const (
+	// maxCallsInBatch is the maximum number of calls in a single eth_callMany request.
+	// It matches the number of requests allowed in a JSON-RPC batch, so that eth_callMany doesn't bypass it.
+	maxCallsInBatch = transport.DefaultBatchLimit
+	// maxGasInCallMany bounds the total gas used by the calls of a single eth_callMany request.
+	maxGasInCallMany = 10 * types.DefaultMaxGasInBlock
+	// callManyConcurrency is the number of calls of a batch executed in parallel.
+	callManyConcurrency = 16
+)
+
+var errCallManyGasExhausted = errors.New("the gas limit of the batch is exhausted")
+
 // Call implements eth_call.
 // Executes a new transaction call immediately without creating a transaction on the block chain.
 func (api *APIImplRo) Call(
@@ -22,6 +37,15 @@ func (api *APIImplRo) Call(
 	overrides *StateOverrides,
 ) (*CallRes, error) {
 	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
+	return api.call(ctx, args, blockRef, overrides)
+}
+
+func (api *APIImplRo) call(
+	ctx context.Context,
+	args CallArgs,
+	blockRef rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *StateOverrides,
+) (*CallRes, error) {
 	if args.Fee.FeeCredit.IsZero() {
 		args.Fee = types.NewFeePackFromGas(1_000_000_000_000_000_000)
 	}
@@ -32,6 +56,92 @@ func (api *APIImplRo) Call(
 	return toCallRes(res)
 }
 
+// CallMany implements eth_callMany.
+// Executes a batch of calls to the contracts of any shards against the same main block.
+// A failure of a call doesn't fail the batch: the error is returned in the result of the call.
+// The calls are charged to the gas limit of the batch (maxGasInCallMany), the calls made after it's exhausted fail.
+func (api *APIImplRo) CallMany(
+	ctx context.Context,
+	calls []CallArgs,
+	mainBlockNrOrHash transport.BlockNumberOrHash,
+	overrides *StateOverrides,
+) (*CallManyRes, error) {
+	if len(calls) > maxCallsInBatch {
+		return nil, fmt.Errorf("too many calls in batch: %d, max is %d", len(calls), maxCallsInBatch)
+	}
+
+	// Resolve the main block once, so that all the calls see the same state of the shards
+	// even if new blocks are produced while the batch is executed.
+	mainBlockHash, childBlocks, err := api.resolveMainBlock(ctx, mainBlockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	blockRef := rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(mainBlockHash, childBlocks)
+
+	results := make([]*CallRes, len(calls))
+	var gasLeft atomic.Int64
+	gasLeft.Store(int64(maxGasInCallMany))
+	var g errgroup.Group
+	g.SetLimit(callManyConcurrency)
+	for i, args := range calls {
+		g.Go(func() error {
+			results[i] = api.callInBatch(ctx, args, blockRef, overrides, &gasLeft)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return &CallManyRes{
+		MainBlockHash: mainBlockHash,
+		Results:       results,
+	}, nil
+}
+
+// callInBatch executes a call of eth_callMany and charges the gas it used to the gas left for the batch.
+// The calls without the fee credit get the gas limit of a block.
+func (api *APIImplRo) callInBatch(
+	ctx context.Context,
+	args CallArgs,
+	blockRef rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *StateOverrides,
+	gasLeft *atomic.Int64,
+) *CallRes {
+	if gasLeft.Load() <= 0 {
+		return &CallRes{Error: errCallManyGasExhausted.Error()}
+	}
+	if args.Fee.FeeCredit.IsZero() {
+		args.Fee = types.NewFeePackFromGas(types.DefaultMaxGasInBlock)
+	}
+
+	res, err := api.rawapi.Call(ctx, args, blockRef, overrides)
+	if err != nil {
+		return &CallRes{Error: err.Error()}
+	}
+	if !res.BaseFee.IsZero() {
+		gasLeft.Add(-int64(res.CoinsUsed.ToGas(res.BaseFee)))
+	}
+
+	callRes, err := toCallRes(res)
+	if err != nil {
+		return &CallRes{Error: err.Error()}
+	}
+	return callRes
+}
+
+func (api *APIImplRo) resolveMainBlock(
+	ctx context.Context, mainBlockNrOrHash transport.BlockNumberOrHash,
+) (common.Hash, []common.Hash, error) {
+	data, err := api.rawapi.GetFullBlockData(ctx, types.MainShardId, toBlockReference(mainBlockNrOrHash))
+	if err != nil {
+		return common.EmptyHash, nil, fmt.Errorf("failed to get main block: %w", err)
+	}
+	block, err := data.DecodeSSZ()
+	if err != nil {
+		return common.EmptyHash, nil, err
+	}
+	return block.Hash(types.MainShardId), data.ChildBlocks, nil
+}
+
 // Add some gap (20%) to be sure that it's enough for transaction processing.
 // For now it's just heuristic function without any mathematical rationality.
 func refineResult(input types.Value) types.Value {
@@ -2,6 +2,7 @@ package jsonrpc
 
 import (
 	"math/big"
+	"sync/atomic"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -10,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/internal/vm"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/tools/solc"
 	"github.com/ethereum/go-ethereum/common/compiler"
@@ -165,6 +167,56 @@ func (s *SuiteEthCall) TestChainCall() {
 	s.EqualValues(0x7b, s.unpackGetValue(res.Data))
 }
 
+func (s *SuiteEthCall) TestCallMany() {
+	ctx := s.T().Context()
+
+	abi := solc.ExtractABI(s.contracts["SimpleContract"])
+	calldata, err := abi.Pack("getValue")
+	s.Require().NoError(err)
+	data := hexutil.Bytes(calldata)
+
+	calls := []CallArgs{
+		{Data: &data, To: s.simple, Fee: types.NewFeePackFromGas(10_000)},
+		// Out of gas
+		{Data: &data, To: s.simple, Fee: types.NewFeePackFromGas(1)},
+		// Shard that doesn't exist
+		{Data: &data, To: types.GenerateRandomAddress(types.ShardId(100))},
+		// Default fee credit
+		{Data: &data, To: s.simple},
+	}
+	res, err := s.api.CallMany(ctx, calls, latestBlockId, nil)
+	s.Require().NoError(err)
+	s.Require().Len(res.Results, len(calls))
+	s.NotEqual(common.EmptyHash, res.MainBlockHash)
+
+	s.Require().Empty(res.Results[0].Error)
+	s.EqualValues(0x2a, s.unpackGetValue(res.Results[0].Data))
+	s.Contains(res.Results[1].Error, vm.ErrOutOfGas.Error())
+	s.NotEmpty(res.Results[2].Error)
+	s.Require().Empty(res.Results[3].Error)
+	s.EqualValues(0x2a, s.unpackGetValue(res.Results[3].Data))
+
+	s.Run("TooManyCalls", func() {
+		_, err := s.api.CallMany(ctx, make([]CallArgs, maxCallsInBatch+1), latestBlockId, nil)
+		s.Require().Error(err)
+	})
+
+	s.Run("GasLimit", func() {
+		blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(latestBlockId))
+		var gasLeft atomic.Int64
+		gasLeft.Store(int64(maxGasInCallMany))
+
+		// The used gas is charged to the batch.
+		res := s.api.callInBatch(ctx, calls[3], blockRef, nil, &gasLeft)
+		s.Require().Empty(res.Error)
+		s.Less(gasLeft.Load(), int64(maxGasInCallMany))
+
+		gasLeft.Store(0)
+		res = s.api.callInBatch(ctx, calls[0], blockRef, nil, &gasLeft)
+		s.Equal(errCallManyGasExhausted.Error(), res.Error)
+	})
+}
+
 func TestSuiteEthCall(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,198 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/math"
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+const (
+	// MaxFeeHistoryBlocks is the maximum number of blocks eth_feeHistory can return.
+	MaxFeeHistoryBlocks = 1024
+	// MaxFeeHistoryPercentiles is the maximum number of reward percentiles eth_feeHistory accepts.
+	MaxFeeHistoryPercentiles = 100
+
+	// priorityFeeSuggestionBlocks is the number of the latest blocks eth_maxPriorityFeePerGas looks at.
+	priorityFeeSuggestionBlocks = 20
+	// priorityFeeSuggestionPercentile is the percentile of the priority fees paid in a block
+	// that is taken as the price of getting into this block.
+	priorityFeeSuggestionPercentile = 60
+)
+
+var errInvalidRewardPercentiles = errors.New("reward percentiles must be increasing values in [0, 100]")
+
+// FeeHistory implements eth_feeHistory.
+// Returns the base fees, the gas used ratios and the effective priority fee percentiles
+// (weighted by the gas used) of blockCount blocks of the shard ending with newestBlock.
+// The base fee of the block following newestBlock is appended to the base fees.
+func (api *APIImplRo) FeeHistory(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockCount math.HexOrDecimal64,
+	newestBlock transport.BlockNumber,
+	rewardPercentiles []float64,
+) (*FeeHistory, error) {
+	if blockCount == 0 {
+		return nil, errors.New("block count must be positive")
+	}
+	if len(rewardPercentiles) > MaxFeeHistoryPercentiles {
+		return nil, fmt.Errorf("too many reward percentiles: %d requested, at most %d allowed",
+			len(rewardPercentiles), MaxFeeHistoryPercentiles)
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 || (i > 0 && p <= rewardPercentiles[i-1]) {
+			return nil, errInvalidRewardPercentiles
+		}
+	}
+	blockCount = min(blockCount, MaxFeeHistoryBlocks)
+
+	newest, err := api.getBlockHeader(ctx, shardId, blockNrToBlockReference(newestBlock))
+	if err != nil {
+		return nil, err
+	}
+	oldest := types.BlockNumber(0)
+	if uint64(newest.Id) >= uint64(blockCount) {
+		oldest = newest.Id - types.BlockNumber(blockCount) + 1
+	}
+
+	res := &FeeHistory{
+		OldestBlock:   oldest,
+		BaseFeePerGas: make([]types.Value, 0, newest.Id-oldest+2),
+		GasUsedRatio:  make([]float64, 0, newest.Id-oldest+1),
+	}
+	if len(rewardPercentiles) != 0 {
+		res.Reward = make([][]types.Value, 0, newest.Id-oldest+1)
+	}
+
+	for number := oldest; number <= newest.Id; number++ {
+		header := newest
+		if number != newest.Id {
+			header, err = api.getBlockHeader(ctx, shardId, rawapitypes.BlockNumberAsBlockReference(number))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get block %d of shard %d: %w", number, shardId, err)
+			}
+		}
+
+		res.BaseFeePerGas = append(res.BaseFeePerGas, header.BaseFee)
+		res.GasUsedRatio = append(res.GasUsedRatio,
+			float64(header.GasUsed)/float64(types.DefaultMaxGasInBlock))
+
+		if len(rewardPercentiles) != 0 {
+			rewards, err := api.blockRewards(ctx, shardId, header, rewardPercentiles)
+			if err != nil {
+				return nil, err
+			}
+			res.Reward = append(res.Reward, rewards)
+		}
+	}
+
+	nextBaseFee, err := api.rawapi.GetNextBaseFee(
+		ctx, shardId, rawapitypes.BlockHashAsBlockReference(newest.Hash(shardId)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the base fee of block %d of shard %d: %w", newest.Id+1, shardId, err)
+	}
+	res.BaseFeePerGas = append(res.BaseFeePerGas, nextBaseFee)
+
+	return res, nil
+}
+
+// MaxPriorityFeePerGas implements eth_maxPriorityFeePerGas.
+// Suggests the priority fee for a transaction of the shard to be included in a timely manner.
+// The suggestion is the median of the priority fees that were enough to get into the latest non-empty blocks.
+func (api *APIImplRo) MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	history, err := api.FeeHistory(
+		ctx,
+		shardId,
+		priorityFeeSuggestionBlocks,
+		transport.LatestBlockNumber,
+		[]float64{priorityFeeSuggestionPercentile})
+	if err != nil {
+		return types.Value{}, err
+	}
+
+	fees := make([]types.Value, 0, len(history.Reward))
+	for i, rewards := range history.Reward {
+		if history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		fees = append(fees, rewards[0])
+	}
+	if len(fees) == 0 {
+		return types.Value0, nil
+	}
+
+	slices.SortFunc(fees, types.Value.Cmp)
+	return fees[len(fees)/2], nil
+}
+
+// blockRewards returns the effective priority fees paid in the block at the given percentiles.
+// Every transaction is weighted by the gas it used.
+func (api *APIImplRo) blockRewards(
+	ctx context.Context, shardId types.ShardId, header *types.Block, percentiles []float64,
+) ([]types.Value, error) {
+	rewards := make([]types.Value, len(percentiles))
+	for i := range rewards {
+		rewards[i] = types.Value0
+	}
+	if header.GasUsed == 0 {
+		return rewards, nil
+	}
+
+	data, err := api.rawapi.GetFullBlockData(
+		ctx, shardId, rawapitypes.BlockHashAsBlockReference(header.Hash(shardId)))
+	if err != nil {
+		return nil, err
+	}
+	txns, err := sszx.DecodeContainer[*types.Transaction](data.InTransactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transactions of block %d: %w", header.Id, err)
+	}
+	receipts, err := sszx.DecodeContainer[*types.Receipt](data.Receipts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode receipts of block %d: %w", header.Id, err)
+	}
+	if len(txns) != len(receipts) {
+		return nil, fmt.Errorf("block %d has %d transactions and %d receipts", header.Id, len(txns), len(receipts))
+	}
+
+	type txnReward struct {
+		gasUsed types.Gas
+		reward  types.Value
+	}
+	sorted := make([]txnReward, 0, len(txns))
+	var totalGasUsed types.Gas
+	for i, txn := range txns {
+		reward, ok := execution.GetEffectivePriorityFee(header.BaseFee, txn)
+		if !ok {
+			reward = types.Value0
+		}
+		sorted = append(sorted, txnReward{gasUsed: receipts[i].GasUsed, reward: reward})
+		totalGasUsed += receipts[i].GasUsed
+	}
+	if len(sorted) == 0 {
+		return rewards, nil
+	}
+	slices.SortStableFunc(sorted, func(a, b txnReward) int {
+		return a.reward.Cmp(b.reward)
+	})
+
+	var index int
+	sumGasUsed := sorted[0].gasUsed
+	for i, p := range percentiles {
+		threshold := types.Gas(float64(totalGasUsed) * p / 100)
+		for sumGasUsed < threshold && index < len(sorted)-1 {
+			index++
+			sumGasUsed += sorted[index].gasUsed
+		}
+		rewards[i] = sorted[index].reward
+	}
+	return rewards, nil
+}
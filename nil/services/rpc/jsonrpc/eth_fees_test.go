@@ -0,0 +1,135 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	api := NewTestEthAPI(ctx, t, database, 2)
+
+	const shardId = types.BaseShardId
+	addr := types.GenerateRandomAddress(shardId)
+
+	newTxn := func(seqno types.Seqno, maxFee, priorityFee uint64) *types.Transaction {
+		txn := newTransaction(addr, seqno, priorityFee, nil)
+		txn.MaxFeePerGas = types.NewValueFromUint64(maxFee)
+		return txn
+	}
+
+	type testBlock struct {
+		baseFee uint64
+		txns    []*types.Transaction
+		gasUsed []types.Gas
+	}
+	blocks := []testBlock{
+		{baseFee: 10},
+		{
+			baseFee: 10,
+			txns:    []*types.Transaction{newTxn(0, 100, 5), newTxn(1, 100, 1), newTxn(2, 12, 10)},
+			gasUsed: []types.Gas{100, 100, 100},
+		},
+		{baseFee: 15},
+		{
+			baseFee: 20,
+			txns:    []*types.Transaction{newTxn(3, 100, 3)},
+			gasUsed: []types.Gas{100},
+		},
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	var latest *types.Block
+	prevHash := common.EmptyHash
+	for i, b := range blocks {
+		receipts := make([]*types.Receipt, len(b.txns))
+		var blockGasUsed types.Gas
+		for j, txn := range b.txns {
+			receipts[j] = &types.Receipt{TxnHash: txn.Hash(), GasUsed: b.gasUsed[j], Success: true}
+			blockGasUsed += b.gasUsed[j]
+		}
+		block := &types.Block{
+			BlockData: types.BlockData{
+				Id:                 types.BlockNumber(i),
+				PrevBlock:          prevHash,
+				InTransactionsRoot: writeTransactions(t, tx, shardId, b.txns).RootHash(),
+				ReceiptsRoot:       writeReceipts(t, tx, shardId, receipts).RootHash(),
+				BaseFee:            types.NewValueFromUint64(b.baseFee),
+				GasUsed:            blockGasUsed,
+			},
+		}
+		hash := block.Hash(shardId)
+		require.NoError(t, db.WriteBlock(tx, shardId, hash, block))
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, &execution.BlockGenerationResult{
+			BlockHash: hash,
+			Block:     block,
+		}, execution.ModeVerify))
+		prevHash = hash
+		latest = block
+	}
+	require.NoError(t, tx.Commit())
+
+	values := func(vs ...uint64) []types.Value {
+		res := make([]types.Value, len(vs))
+		for i, v := range vs {
+			res[i] = types.NewValueFromUint64(v)
+		}
+		return res
+	}
+
+	t.Run("History", func(t *testing.T) {
+		res, err := api.FeeHistory(ctx, shardId, 3, transport.BlockNumber(2), []float64{25, 50, 100})
+		require.NoError(t, err)
+		require.Equal(t, types.BlockNumber(0), res.OldestBlock)
+		require.Len(t, res.BaseFeePerGas, 4)
+		require.Equal(t, values(10, 10, 15), res.BaseFeePerGas[:3])
+		require.Equal(t, []float64{0, 300 / float64(types.DefaultMaxGasInBlock), 0}, res.GasUsedRatio)
+
+		// The third transaction pays only 2 since it is capped by MaxFeePerGas.
+		require.Equal(t, [][]types.Value{values(0, 0, 0), values(1, 2, 5), values(0, 0, 0)}, res.Reward)
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		res, err := api.FeeHistory(ctx, shardId, 100, transport.LatestBlockNumber, nil)
+		require.NoError(t, err)
+		require.Equal(t, types.BlockNumber(0), res.OldestBlock)
+		require.Len(t, res.GasUsedRatio, 4)
+		require.Nil(t, res.Reward)
+
+		feeCalculator := &execution.MainFeeCalculator{}
+		require.Equal(t, feeCalculator.CalculateBaseFee(latest), res.BaseFeePerGas[4])
+	})
+
+	t.Run("InvalidArgs", func(t *testing.T) {
+		_, err := api.FeeHistory(ctx, shardId, 0, transport.LatestBlockNumber, nil)
+		require.ErrorContains(t, err, "block count must be positive")
+
+		_, err = api.FeeHistory(ctx, shardId, 1, transport.LatestBlockNumber, []float64{50, 10})
+		require.ErrorIs(t, err, errInvalidRewardPercentiles)
+
+		_, err = api.FeeHistory(ctx, shardId, 1, transport.LatestBlockNumber, []float64{101})
+		require.ErrorIs(t, err, errInvalidRewardPercentiles)
+	})
+
+	t.Run("MaxPriorityFeePerGas", func(t *testing.T) {
+		// The 60th percentiles of the non-empty blocks are 2 and 3.
+		fee, err := api.MaxPriorityFeePerGas(ctx, shardId)
+		require.NoError(t, err)
+		require.Equal(t, types.NewValueFromUint64(3), fee)
+	})
+}
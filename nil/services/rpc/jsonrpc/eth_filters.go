@@ -55,8 +55,9 @@ func (l *LogsAggregator) CreateBlocksListener() (filters.SubscriptionID, error)
 
 	go func() {
 		for block := range ch {
+			// Filter changes list the blocks from the newest one.
 			l.blocksMap.DoAndStore(id, func(t []*types.Block, ok bool) []*types.Block {
-				return append(t, block)
+				return append([]*types.Block{block}, t...)
 			})
 		}
 	}()
@@ -0,0 +1,176 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/holiman/uint256"
+)
+
+const (
+	DefaultGetLogsMaxBlockRange = 10_000
+	DefaultGetLogsMaxResults    = 10_000
+)
+
+// GetLogsConfig limits the amount of work a single eth_getLogs request can do.
+// The zero fields are replaced with the defaults.
+type GetLogsConfig struct {
+	// MaxBlockRange is the maximum number of blocks searched in a single shard.
+	MaxBlockRange uint64 `yaml:"maxBlockRange,omitempty"`
+	// MaxResults is the maximum number of logs returned.
+	MaxResults uint64 `yaml:"maxResults,omitempty"`
+}
+
+func NewDefaultGetLogsConfig() *GetLogsConfig {
+	return &GetLogsConfig{
+		MaxBlockRange: DefaultGetLogsMaxBlockRange,
+		MaxResults:    DefaultGetLogsMaxResults,
+	}
+}
+
+func (c *GetLogsConfig) withDefaults() *GetLogsConfig {
+	res := NewDefaultGetLogsConfig()
+	if c == nil {
+		return res
+	}
+	if c.MaxBlockRange != 0 {
+		res.MaxBlockRange = c.MaxBlockRange
+	}
+	if c.MaxResults != 0 {
+		res.MaxResults = c.MaxResults
+	}
+	return res
+}
+
+// GetLogs implements eth_getLogs. Returns the logs matching the query.
+// If neither the block hash nor the range bounds are set, only the latest blocks are searched.
+func (api *APIImplRo) GetLogs(ctx context.Context, query filters.FilterQuery) ([]*RPCLog, error) {
+	res := make([]*RPCLog, 0)
+
+	if query.BlockHash != nil {
+		shardId := types.ShardIdFromHash(*query.BlockHash)
+		header, err := api.getBlockHeader(ctx, shardId, rawapitypes.BlockHashAsBlockReference(*query.BlockHash))
+		if err != nil {
+			return nil, err
+		}
+		return api.appendBlockLogs(ctx, res, shardId, header, &query)
+	}
+
+	shardIds := query.ShardIds
+	if len(shardIds) == 0 {
+		list, err := api.rawapi.GetShardIdList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		shardIds = append([]types.ShardId{types.MainShardId}, list...)
+	}
+
+	for _, shardId := range shardIds {
+		latest, err := api.getBlockHeader(ctx, shardId, blockNrToBlockReference(transport.LatestBlockNumber))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest block of shard %d: %w", shardId, err)
+		}
+		from := resolveLogsRangeBound(query.FromBlock, latest.Id)
+		to := resolveLogsRangeBound(query.ToBlock, latest.Id)
+		if from > to {
+			return nil, fmt.Errorf("invalid block range: %d > %d", from, to)
+		}
+		if uint64(to-from) >= api.getLogsConfig.MaxBlockRange {
+			return nil, fmt.Errorf("block range is too large: %d blocks requested, at most %d allowed",
+				to-from+1, api.getLogsConfig.MaxBlockRange)
+		}
+
+		for number := from; number <= to; number++ {
+			header := latest
+			if number != latest.Id {
+				header, err = api.getBlockHeader(ctx, shardId, rawapitypes.BlockNumberAsBlockReference(number))
+				if err != nil {
+					return nil, fmt.Errorf("failed to get block %d of shard %d: %w", number, shardId, err)
+				}
+			}
+			if res, err = api.appendBlockLogs(ctx, res, shardId, header, &query); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// resolveLogsRangeBound returns the block number of the range bound, clamped to the latest block.
+// FilterQuery keeps block tags as their negative values converted to unsigned, so the numbers
+// not fitting into int64 are the tags, and all of them are resolved to the latest block.
+func resolveLogsRangeBound(bound *uint256.Int, latest types.BlockNumber) types.BlockNumber {
+	if bound == nil || !bound.IsUint64() || bound.Uint64() > math.MaxInt64 {
+		return latest
+	}
+	return min(types.BlockNumber(bound.Uint64()), latest)
+}
+
+func (api *APIImplRo) getBlockHeader(
+	ctx context.Context, shardId types.ShardId, ref rawapitypes.BlockReference,
+) (*types.Block, error) {
+	raw, err := api.rawapi.GetBlockHeader(ctx, shardId, ref)
+	if err != nil {
+		return nil, err
+	}
+	block := &types.Block{}
+	if err := block.UnmarshalSSZ(raw); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// appendBlockLogs appends the logs of the block matching the query to res.
+// The receipts are fetched only if the block bloom may contain matching logs.
+func (api *APIImplRo) appendBlockLogs(
+	ctx context.Context, res []*RPCLog, shardId types.ShardId, header *types.Block, query *filters.FilterQuery,
+) ([]*RPCLog, error) {
+	if !query.MatchBloom(header.LogsBloom) {
+		return res, nil
+	}
+
+	blockHash := header.Hash(shardId)
+	data, err := api.rawapi.GetFullBlockData(ctx, shardId, rawapitypes.BlockHashAsBlockReference(blockHash))
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := sszx.DecodeContainer[*types.Receipt](data.Receipts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode receipts of block %d: %w", header.Id, err)
+	}
+
+	var logIndex uint64
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			index := logIndex
+			logIndex++
+
+			if len(query.Addresses) != 0 && !slices.Contains(query.Addresses, log.Address) {
+				continue
+			}
+			if !query.MatchTopics(log) {
+				continue
+			}
+			if uint64(len(res)) >= api.getLogsConfig.MaxResults {
+				return nil, fmt.Errorf("query returned more than %d results", api.getLogsConfig.MaxResults)
+			}
+
+			txnHash := receipt.TxnHash
+			rpcLog := NewRPCLog(log, header.Id)
+			rpcLog.BlockHash = &blockHash
+			rpcLog.TxnHash = &txnHash
+			rpcLog.ShardId = &shardId
+			rpcLog.LogIndex = &index
+			res = append(res, rpcLog)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,161 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLogs(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	api := NewTestEthAPI(ctx, t, database, 2)
+
+	const shardId = types.BaseShardId
+	addrA := types.GenerateRandomAddress(shardId)
+	addrB := types.GenerateRandomAddress(shardId)
+	topic1, topic2, topic3 := common.Hash{0x01}, common.Hash{0x02}, common.Hash{0x03}
+
+	logsByBlock := [][]*types.Receipt{
+		{},
+		{
+			{TxnHash: common.Hash{0x11}, Logs: []*types.Log{
+				{Address: addrA, Topics: []common.Hash{topic1, topic2}},
+				{Address: addrB, Topics: []common.Hash{topic1}},
+			}},
+			{TxnHash: common.Hash{0x12}, Logs: []*types.Log{
+				{Address: addrA, Topics: []common.Hash{topic3}},
+			}},
+		},
+		{},
+		{
+			{TxnHash: common.Hash{0x13}, Logs: []*types.Log{
+				{Address: addrA, Topics: []common.Hash{topic2}},
+			}},
+		},
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	blockHashes := make([]common.Hash, len(logsByBlock))
+	prevHash := common.EmptyHash
+	for i, receipts := range logsByBlock {
+		block := &types.Block{
+			BlockData: types.BlockData{
+				Id:           types.BlockNumber(i),
+				PrevBlock:    prevHash,
+				ReceiptsRoot: writeReceipts(t, tx, shardId, receipts).RootHash(),
+			},
+			LogsBloom: types.CreateBloom(receipts),
+		}
+		blockHashes[i] = block.Hash(shardId)
+		require.NoError(t, db.WriteBlock(tx, shardId, blockHashes[i], block))
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, &execution.BlockGenerationResult{
+			BlockHash: blockHashes[i],
+			Block:     block,
+		}, execution.ModeVerify))
+		prevHash = blockHashes[i]
+	}
+	require.NoError(t, tx.Commit())
+
+	query := func(from, to uint64, addresses []types.Address, topics [][]common.Hash) filters.FilterQuery {
+		return filters.FilterQuery{
+			FromBlock: uint256.NewInt(from),
+			ToBlock:   uint256.NewInt(to),
+			Addresses: addresses,
+			Topics:    topics,
+			ShardIds:  []types.ShardId{shardId},
+		}
+	}
+
+	t.Run("ByAddress", func(t *testing.T) {
+		logs, err := api.GetLogs(ctx, query(0, 3, []types.Address{addrA}, nil))
+		require.NoError(t, err)
+		require.Len(t, logs, 3)
+
+		require.Equal(t, types.BlockNumber(1), logs[0].BlockNumber)
+		require.Equal(t, blockHashes[1], *logs[0].BlockHash)
+		require.Equal(t, common.Hash{0x11}, *logs[0].TxnHash)
+		require.Equal(t, shardId, *logs[0].ShardId)
+		require.EqualValues(t, 0, *logs[0].LogIndex)
+
+		require.Equal(t, common.Hash{0x12}, *logs[1].TxnHash)
+		require.EqualValues(t, 2, *logs[1].LogIndex)
+
+		require.Equal(t, types.BlockNumber(3), logs[2].BlockNumber)
+	})
+
+	t.Run("TopicsDisjunction", func(t *testing.T) {
+		logs, err := api.GetLogs(ctx, query(0, 3, nil, [][]common.Hash{{topic2, topic3}}))
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		require.Equal(t, topic3, logs[0].Topics[0])
+		require.Equal(t, topic2, logs[1].Topics[0])
+
+		logs, err = api.GetLogs(ctx, query(0, 3, nil, [][]common.Hash{{}, {topic2}}))
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, addrA, logs[0].Address)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		logs, err := api.GetLogs(ctx, query(2, 100, []types.Address{addrA}, nil))
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+
+		logs, err = api.GetLogs(ctx, filters.FilterQuery{ShardIds: []types.ShardId{shardId}})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, types.BlockNumber(3), logs[0].BlockNumber)
+
+		_, err = api.GetLogs(ctx, query(3, 1, nil, nil))
+		require.ErrorContains(t, err, "invalid block range")
+	})
+
+	t.Run("BlockHash", func(t *testing.T) {
+		logs, err := api.GetLogs(ctx, filters.FilterQuery{BlockHash: &blockHashes[1]})
+		require.NoError(t, err)
+		require.Len(t, logs, 3)
+
+		logs, err = api.GetLogs(ctx, filters.FilterQuery{BlockHash: &blockHashes[2]})
+		require.NoError(t, err)
+		require.Empty(t, logs)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		limited := NewEthAPIRo(ctx, api.rawapi, database, nil, false, false, &GetLogsConfig{
+			MaxBlockRange: 2,
+			MaxResults:    2,
+		})
+
+		_, err := limited.GetLogs(ctx, query(0, 3, nil, nil))
+		require.ErrorContains(t, err, "block range is too large")
+
+		_, err = limited.GetLogs(ctx, query(1, 2, nil, nil))
+		require.ErrorContains(t, err, "query returned more than 2 results")
+
+		logs, err := limited.GetLogs(ctx, query(2, 3, nil, nil))
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+
+		// The limits that are not set are the default ones.
+		partial := NewEthAPIRo(ctx, api.rawapi, database, nil, false, false, &GetLogsConfig{MaxResults: 2})
+		require.Equal(t, uint64(DefaultGetLogsMaxBlockRange), partial.getLogsConfig.MaxBlockRange)
+
+		_, err = partial.GetLogs(ctx, query(0, 3, nil, nil))
+		require.ErrorContains(t, err, "query returned more than 2 results")
+	})
+}
@@ -0,0 +1,109 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+// maxSimulatedTransactions limits the size of the transaction tree executed by eth_simulateTransaction.
+// It protects the node from the contracts that keep sending transactions to each other.
+const maxSimulatedTransactions = 1000
+
+type simulationStep struct {
+	raw  []byte
+	node *SimulatedTransaction
+}
+
+// SimulateTransaction implements eth_simulateTransaction.
+// Executes the external transaction and then all the transactions produced by it on their destination shards
+// (including responses, bounces and refunds) on top of the same main block. Nothing is sent to the network.
+func (api *APIImplRo) SimulateTransaction(
+	ctx context.Context,
+	encoded hexutil.Bytes,
+	mainBlockNrOrHash transport.BlockNumberOrHash,
+) (*SimulationRes, error) {
+	var extTxn types.ExternalTransaction
+	if err := extTxn.UnmarshalSSZ(encoded); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	root, err := extTxn.ToTransaction().MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+
+	mainBlockHash, childBlocks, err := api.resolveMainBlock(ctx, mainBlockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	blockRef := rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(mainBlockHash, childBlocks)
+
+	res := &SimulationRes{
+		MainBlockHash: mainBlockHash,
+		Transaction:   &SimulatedTransaction{Hash: extTxn.Hash()},
+		TotalFee:      types.NewZeroValue(),
+		StateDiffs:    make(map[types.ShardId]StateOverrides),
+	}
+	overrides := make(StateOverrides)
+
+	// Transactions are executed level by level, the way the shards would include them into their next blocks.
+	queue := []simulationStep{{raw: root, node: res.Transaction}}
+	for executed := 0; len(queue) > 0; executed++ {
+		if executed == maxSimulatedTransactions {
+			return nil, fmt.Errorf("transaction tree exceeds %d transactions", maxSimulatedTransactions)
+		}
+
+		step := queue[0]
+		queue = queue[1:]
+
+		raw := hexutil.Bytes(step.raw)
+		callRes, err := api.rawapi.ExecuteTransaction(ctx, CallArgs{Transaction: &raw}, blockRef, &overrides)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute transaction %s: %w", step.node.Hash, err)
+		}
+
+		var txn types.Transaction
+		if err := txn.UnmarshalSSZ(step.raw); err != nil {
+			return nil, err
+		}
+		node := step.node
+		node.ShardId = txn.To.ShardId()
+		node.Flags = txn.Flags
+		node.From = txn.From
+		node.To = txn.To
+		node.Value = txn.Value
+		node.Success = callRes.Error == ""
+		node.Error = callRes.Error
+		node.Data = callRes.Data
+		node.CoinsUsed = callRes.CoinsUsed
+		node.Logs = callRes.Logs
+		res.TotalFee = res.TotalFee.Add(callRes.CoinsUsed)
+
+		// The returned overrides contain the whole state changed by the transactions executed so far.
+		overrides = callRes.StateOverrides
+
+		node.OutTransactions = make([]*SimulatedTransaction, len(callRes.OutTransactions))
+		for i, out := range callRes.OutTransactions {
+			var outTxn types.Transaction
+			if err := outTxn.UnmarshalSSZ(out.TransactionSSZ); err != nil {
+				return nil, err
+			}
+			node.OutTransactions[i] = &SimulatedTransaction{Hash: outTxn.Hash()}
+			queue = append(queue, simulationStep{raw: out.TransactionSSZ, node: node.OutTransactions[i]})
+		}
+	}
+
+	for addr, contract := range overrides {
+		diff, ok := res.StateDiffs[addr.ShardId()]
+		if !ok {
+			diff = make(StateOverrides)
+			res.StateDiffs[addr.ShardId()] = diff
+		}
+		diff[addr] = contract
+	}
+	return res, nil
+}
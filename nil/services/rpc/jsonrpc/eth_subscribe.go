@@ -0,0 +1,139 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+// NewHeads implements eth_subscribe("newHeads", shardId).
+// Sends a notification with the header of each new block of the shard.
+func (api *APIImplRo) NewHeads(ctx context.Context, shardId types.ShardId) (*transport.Subscription, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return nil, transport.ErrNotificationsUnsupported
+	}
+
+	id, blocks := api.logs.filters.AddShardBlocksListener(shardId)
+	if blocks == nil {
+		return nil, errors.New("cannot add blocks listener")
+	}
+
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer api.logs.filters.RemoveBlocksListener(id)
+
+		for {
+			select {
+			case block, ok := <-blocks:
+				if !ok {
+					return
+				}
+				header, err := NewRPCBlock(shardId, &BlockWithEntities{Block: block}, false)
+				if err != nil {
+					api.logger.Error().Err(err).Stringer(logging.FieldShardId, shardId).Msg("Failed to convert block")
+					continue
+				}
+				if err := notifier.Notify(sub.ID, header); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// Logs implements eth_subscribe("logs", shardId, query).
+// Sends a notification for each new log of the shard that matches the query.
+func (api *APIImplRo) Logs(
+	ctx context.Context, shardId types.ShardId, query filters.FilterQuery,
+) (*transport.Subscription, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return nil, transport.ErrNotificationsUnsupported
+	}
+	if query.BlockHash != nil || query.FromBlock != nil || query.ToBlock != nil {
+		return nil, errors.New("block range is not supported by logs subscription")
+	}
+
+	id, filter := api.logs.filters.NewShardFilter(shardId, &query)
+	if len(id) == 0 || filter == nil {
+		return nil, errors.New("cannot create new filter")
+	}
+
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer api.logs.filters.RemoveFilter(id)
+
+		logs := filter.LogsChannel()
+		for {
+			select {
+			case log, ok := <-logs:
+				if !ok {
+					return
+				}
+				if err := notifier.Notify(sub.ID, NewRPCLog(log.Log, log.BlockId)); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// ErrPendingTransactionsUnsupported is returned by the pending transactions subscription
+// on the nodes that don't run the txnpool of the shard, e.g., the RPC-only nodes.
+var ErrPendingTransactionsUnsupported = errors.New("pending transactions notifications not supported")
+
+// NewPendingTransactions implements eth_subscribe("newPendingTransactions", shardId, fullTx).
+// Sends a notification with the hash (or the whole transaction if fullTx is set)
+// of each transaction that enters the txnpool of the shard.
+// Only the nodes that run the txnpool of the shard support it, others return ErrPendingTransactionsUnsupported.
+func (api *APIImplRo) NewPendingTransactions(
+	ctx context.Context, shardId types.ShardId, fullTx *bool,
+) (*transport.Subscription, error) {
+	pool, ok := api.txnPools[shardId]
+	if !ok {
+		return nil, fmt.Errorf("%w for shard %d", ErrPendingTransactionsUnsupported, shardId)
+	}
+
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return nil, transport.ErrNotificationsUnsupported
+	}
+
+	id, txns := pool.SubscribeAdded()
+
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer pool.UnsubscribeAdded(id)
+
+		for {
+			select {
+			case txn := <-txns:
+				var data any = txn.Hash()
+				if fullTx != nil && *fullTx {
+					data = NewTransaction(txn.Transaction)
+				}
+				if err := notifier.Notify(sub.ID, data); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
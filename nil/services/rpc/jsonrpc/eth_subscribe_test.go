@@ -0,0 +1,36 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPendingTransactionsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	api := NewTestEthAPI(ctx, t, database, 2)
+
+	// The node runs the pool of the shard, but plain HTTP doesn't support notifications.
+	_, err = api.NewPendingTransactions(ctx, types.BaseShardId, nil)
+	require.ErrorIs(t, err, transport.ErrNotificationsUnsupported)
+
+	// The node doesn't run the pool of the shard.
+	_, err = api.NewPendingTransactions(ctx, types.ShardId(2), nil)
+	require.ErrorIs(t, err, ErrPendingTransactionsUnsupported)
+
+	// An RPC-only node runs no pools.
+	rpcOnly := NewEthAPIRo(ctx, api.rawapi, database, nil, false, false, nil)
+	defer rpcOnly.Shutdown()
+
+	_, err = rpcOnly.NewPendingTransactions(ctx, types.BaseShardId, nil)
+	require.ErrorIs(t, err, ErrPendingTransactionsUnsupported)
+}
@@ -39,7 +39,7 @@ func (suite *SuiteTxnPoolApi) SetupSuite() {
 	suite.SuiteAccountsBase.SetupSuite()
 	var err error
 
-	suite.pool, err = txnpool.New(suite.T().Context(), txnpool.NewConfig(types.MainShardId), nil)
+	suite.pool, err = txnpool.New(suite.T().Context(), txnpool.NewConfig(types.MainShardId), nil, nil)
 	suite.Require().NoError(err)
 
 	database, err := db.NewBadgerDbInMemory()
@@ -1,12 +1,15 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -16,6 +19,7 @@ type (
 	Contract       = rpctypes.Contract
 	CallArgs       = rpctypes.CallArgs
 	StateOverrides = rpctypes.StateOverrides
+	TraceConfig    = tracers.TraceConfig
 )
 
 // @component RPCInTransaction rpcInTransaction object "The transaction whose information is requested."
@@ -116,6 +120,8 @@ type ChainConfig struct {
 	Validators  *config.ParamValidators  `json:"validators"`
 	GasPrices   *config.ParamGasPrice    `json:"gasPrices"`
 	L1BlockInfo *config.ParamL1BlockInfo `json:"l1BlockInfo"`
+
+	ProtocolVersions *config.ParamProtocolVersions `json:"protocolVersions,omitempty"`
 }
 
 func NewChainConfigFromMap(data map[string][]byte) (*ChainConfig, error) {
@@ -135,10 +141,18 @@ func NewChainConfigFromMap(data map[string][]byte) (*ChainConfig, error) {
 	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
 		return nil, err
 	}
+	var protocolVersions *config.ParamProtocolVersions
+	if _, ok := data[config.NameProtocolVersions]; ok {
+		protocolVersions, err = config.GetParamProtocolVersions(configAccessor)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &ChainConfig{
-		Validators:  validators,
-		GasPrices:   gasPrices,
-		L1BlockInfo: l1BlockInfo,
+		Validators:       validators,
+		GasPrices:        gasPrices,
+		L1BlockInfo:      l1BlockInfo,
+		ProtocolVersions: protocolVersions,
 	}, nil
 }
 
@@ -165,6 +179,13 @@ func (c *ChainConfig) ToMap() (map[string][]byte, error) {
 		}
 		result[config.NameL1Block] = l1BlockInfo
 	}
+	if c.ProtocolVersions != nil {
+		protocolVersions, err := c.ProtocolVersions.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		result[config.NameProtocolVersions] = protocolVersions
+	}
 	return result, nil
 }
 
@@ -272,6 +293,10 @@ type RPCReceipt struct {
 type RPCLog struct {
 	*types.Log
 	BlockNumber types.BlockNumber `json:"blockNumber"`
+	BlockHash   *common.Hash      `json:"blockHash,omitempty"`
+	TxnHash     *common.Hash      `json:"transactionHash,omitempty"`
+	ShardId     *types.ShardId    `json:"shardId,omitempty"`
+	LogIndex    *uint64           `json:"logIndex,omitempty"`
 }
 
 type RPCDebugLog struct {
@@ -429,7 +454,7 @@ func NewRPCLog(
 		return nil
 	}
 
-	return &RPCLog{log, blockId}
+	return &RPCLog{Log: log, BlockNumber: blockId}
 }
 
 func NewRPCReceipt(info *rawapitypes.ReceiptInfo) (*RPCReceipt, error) {
@@ -506,6 +531,88 @@ type DebugRPCContract struct {
 	AsyncContext map[types.TransactionIndex]types.AsyncContext `json:"asyncContext"`
 }
 
+// @component DebugStorageRange debugStorageRange object "The page of the contract storage."
+// @componentprop Storage storage object true "The key-value pairs of the storage slots."
+// @componentprop NextKey nextKey string false "The key to start the next page from. Null if there are no more slots."
+type DebugStorageRange struct {
+	Storage map[common.Hash]types.Uint256 `json:"storage"`
+	NextKey *common.Hash                  `json:"nextKey"`
+}
+
+// @component DebugConsensusEvidence debugConsensusEvidence object "The proof of a validator signing two proposals."
+// @componentprop Id id string true "The ID of the equivocation, the same for all its evidence."
+// @componentprop ShardId shardId integer true "The ID of the shard where the messages were signed."
+// @componentprop Validator validator string true "The public key of the validator."
+// @componentprop Height height integer true "The height the messages were signed at."
+// @componentprop Round round integer true "The round the messages were signed at."
+// @componentprop Type type string true "The type of the messages."
+// @componentprop First first string true "The first protobuf-encoded signed message."
+// @componentprop Second second string true "The second protobuf-encoded signed message."
+type DebugConsensusEvidence struct {
+	Id        common.Hash    `json:"id"`
+	ShardId   types.ShardId  `json:"shardId"`
+	Validator hexutil.Bytes  `json:"validator"`
+	Height    hexutil.Uint64 `json:"height"`
+	Round     hexutil.Uint64 `json:"round"`
+	Type      string         `json:"type"`
+	First     hexutil.Bytes  `json:"first"`
+	Second    hexutil.Bytes  `json:"second"`
+}
+
+func NewDebugConsensusEvidence(ev *evidence.Evidence) (*DebugConsensusEvidence, error) {
+	first, second, err := ev.MarshalMessages()
+	if err != nil {
+		return nil, err
+	}
+	return &DebugConsensusEvidence{
+		Id:        ev.Id(),
+		ShardId:   ev.ShardId,
+		Validator: ev.Validator(),
+		Height:    hexutil.Uint64(ev.Height()),
+		Round:     hexutil.Uint64(ev.Round()),
+		Type:      ev.Type().String(),
+		First:     first,
+		Second:    second,
+	}, nil
+}
+
+// @component DebugTransactionTrace debugTransactionTrace object "The trace of a transaction and all transactions it produced."
+// @componentprop TxnHash transactionHash string true "The hash of the transaction."
+// @componentprop ShardId shardId integer true "The ID of the shard where the transaction was executed."
+// @componentprop BlockNumber blockNumber integer true "The number of the block containing the transaction."
+// @componentprop RequestId requestId integer false "The ID of the async request the transaction belongs to."
+// @componentprop RequestChain requestChain array false "The chain of async requests awaiting the response."
+// @componentprop FeeCredit feeCredit string true "The fee credit (gas) forwarded to the transaction."
+// @componentprop Value value string true "The value forwarded to the transaction."
+// @componentprop Forwarded forwarded string true "The value the transaction forwarded to its outbound transactions."
+// @componentprop Pending pending boolean false "The flag that shows whether the transaction is not executed yet."
+// @componentprop Trace trace object false "The result of the tracer for the transaction."
+// @componentprop Children children array false "The traces of the outbound transactions."
+// @componentprop Truncated truncated boolean false "The flag that shows whether some outbound transactions are not traced because of the tree limits."
+type DebugTransactionTrace struct {
+	TxnHash      common.Hash               `json:"transactionHash"`
+	ShardId      types.ShardId             `json:"shardId"`
+	BlockNumber  types.BlockNumber         `json:"blockNumber"`
+	BlockHash    common.Hash               `json:"blockHash"`
+	Flags        types.TransactionFlags    `json:"flags"`
+	From         types.Address             `json:"from"`
+	To           types.Address             `json:"to"`
+	RequestId    uint64                    `json:"requestId,omitempty"`
+	RequestChain []*types.AsyncRequestInfo `json:"requestChain,omitempty"`
+	FeeCredit    types.Value               `json:"feeCredit"`
+	Value        types.Value               `json:"value"`
+	Token        []types.TokenBalance      `json:"token,omitempty"`
+	Forwarded    types.Value               `json:"forwarded"`
+	GasUsed      types.Gas                 `json:"gasUsed"`
+	Success      bool                      `json:"success"`
+	Status       string                    `json:"status"`
+	ErrorMessage string                    `json:"errorMessage,omitempty"`
+	Pending      bool                      `json:"pending,omitempty"`
+	Trace        json.RawMessage           `json:"trace,omitempty"`
+	Children     []*DebugTransactionTrace  `json:"children,omitempty"`
+	Truncated    bool                      `json:"truncated,omitempty"`
+}
+
 // @component OutTransaction outTransaction object "Outbound transaction produced by eth_call and result of its execution."
 // @componentprop Transaction transaction object true "Transaction data"
 // @componentprop Data data string false "Result of VM execution."
@@ -588,6 +695,66 @@ func toCallRes(input *rpctypes.CallResWithGasPrice) (*CallRes, error) {
 	return output, err
 }
 
+// @component CallManyRes callManyRes object "Response for eth_callMany."
+// @componentprop MainBlockHash mainBlockHash string true "The hash of the main block the calls were executed against."
+// @componentprop Results results array true "The results of the calls in the order of the request."
+type CallManyRes struct {
+	MainBlockHash common.Hash `json:"mainBlockHash"`
+	Results       []*CallRes  `json:"results"`
+}
+
+// @component SimulatedTransaction simulatedTransaction object "A transaction executed by eth_simulateTransaction."
+// @componentprop Hash hash string true "The hash of the transaction."
+// @componentprop ShardId shardId integer true "The ID of the shard where the transaction was executed."
+// @componentprop Flags flags string true "The array of transaction flags."
+// @componentprop From from string true "The address of the sender."
+// @componentprop To to string true "The address of the recipient."
+// @componentprop Value value string true "The amount of coins sent with the transaction."
+// @componentprop Success success boolean true "The flag that shows whether the transaction was executed successfully."
+// @componentprop Error error string false "The error produced by the transaction."
+// @componentprop Data data string false "The data returned by the transaction."
+// @componentprop CoinsUsed coinsUsed string true "The amount of coins spent on the transaction."
+// @componentprop Logs logs array false "The logs produced by the transaction."
+// @componentprop OutTransactions outTransactions array false "The transactions produced by the transaction."
+type SimulatedTransaction struct {
+	Hash            common.Hash             `json:"hash"`
+	ShardId         types.ShardId           `json:"shardId"`
+	Flags           types.TransactionFlags  `json:"flags"`
+	From            types.Address           `json:"from"`
+	To              types.Address           `json:"to"`
+	Value           types.Value             `json:"value"`
+	Success         bool                    `json:"success"`
+	Error           string                  `json:"error,omitempty"`
+	Data            hexutil.Bytes           `json:"data,omitempty"`
+	CoinsUsed       types.Value             `json:"coinsUsed"`
+	Logs            []*types.Log            `json:"logs,omitempty"`
+	OutTransactions []*SimulatedTransaction `json:"outTransactions,omitempty"`
+}
+
+// @component SimulationRes simulationRes object "Response for eth_simulateTransaction."
+// @componentprop MainBlockHash mainBlockHash string true "The hash of the main block used for the execution."
+// @componentprop Transaction transaction object true "The tree of the executed transactions."
+// @componentprop TotalFee totalFee string true "The amount of coins spent on all the transactions of the tree."
+// @componentprop StateDiffs stateDiffs object true "The contracts state changed by the transactions grouped by shard."
+type SimulationRes struct {
+	MainBlockHash common.Hash                      `json:"mainBlockHash"`
+	Transaction   *SimulatedTransaction            `json:"transaction"`
+	TotalFee      types.Value                      `json:"totalFee"`
+	StateDiffs    map[types.ShardId]StateOverrides `json:"stateDiffs"`
+}
+
+// @component FeeHistory feeHistory object "The fee history of the shard."
+// @componentprop OldestBlock oldestBlock integer true "The number of the oldest block in the range."
+// @componentprop BaseFeePerGas baseFeePerGas array true "The base fees of the blocks in the range and of the block following the range."
+// @componentprop GasUsedRatio gasUsedRatio array true "The ratios of the gas used to the gas limit of the blocks in the range."
+// @componentprop Reward reward array false "The effective priority fees at the requested percentiles for every block in the range."
+type FeeHistory struct {
+	OldestBlock   types.BlockNumber `json:"oldestBlock"`
+	BaseFeePerGas []types.Value     `json:"baseFeePerGas"`
+	GasUsedRatio  []float64         `json:"gasUsedRatio"`
+	Reward        [][]types.Value   `json:"reward,omitempty"`
+}
+
 type EstimateFeeRes struct {
 	FeeCredit          types.Value `json:"feeCredit"`
 	AveragePriorityFee types.Value `json:"averagePriorityFee"`
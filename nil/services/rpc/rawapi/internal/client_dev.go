@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -27,7 +28,56 @@ func newShardApiClientNetworkDev(shardId types.ShardId, networkManager network.M
 	return client
 }
 
+func newShardApiClientDirectEmulatorDev(shardApi shardApiDev) *shardApiClientDev {
+	client, err := newShardApiClientDirectEmulator[shardApiClientDev, shardApiDev, NetworkTransportProtocolDev](
+		constructShardApiClientDev, apiNameDev, shardApi)
+	check.PanicIfErr(err)
+	return client
+}
+
 func (api *shardApiClientDev) DoPanicOnShard(ctx context.Context) (uint64, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[uint64](
 		ctx, api.shardApiRequestPerformer, "DoPanicOnShard")
 }
+
+func (api *shardApiClientDev) OverrideAccount(
+	ctx context.Context,
+	override *execution.AccountOverride,
+) (struct{}, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[struct{}](
+		ctx, api.shardApiRequestPerformer, "OverrideAccount", override)
+}
+
+func (api *shardApiClientDev) Impersonate(
+	ctx context.Context,
+	address types.Address,
+	enabled bool,
+) (struct{}, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[struct{}](
+		ctx, api.shardApiRequestPerformer, "Impersonate", address, enabled)
+}
+
+func (api *shardApiClientDev) IncreaseTime(ctx context.Context, seconds uint64) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](
+		ctx, api.shardApiRequestPerformer, "IncreaseTime", seconds)
+}
+
+func (api *shardApiClientDev) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (struct{}, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[struct{}](
+		ctx, api.shardApiRequestPerformer, "SetNextBlockTimestamp", timestamp)
+}
+
+func (api *shardApiClientDev) Mine(ctx context.Context, count uint64) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](
+		ctx, api.shardApiRequestPerformer, "Mine", count)
+}
+
+func (api *shardApiClientDev) Snapshot(ctx context.Context) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](
+		ctx, api.shardApiRequestPerformer, "Snapshot")
+}
+
+func (api *shardApiClientDev) Revert(ctx context.Context, id uint64) (struct{}, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[struct{}](
+		ctx, api.shardApiRequestPerformer, "Revert", id)
+}
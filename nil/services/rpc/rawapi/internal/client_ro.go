@@ -2,11 +2,15 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -87,6 +91,24 @@ func (api *shardApiClientRo) GetContract(
 		ctx, api, "GetContract", address, blockReference)
 }
 
+func (api *shardApiClientRo) GetStorageAt(
+	ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference,
+) (types.Uint256, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.Uint256](
+		ctx, api, "GetStorageAt", address, key, blockReference)
+}
+
+func (api *shardApiClientRo) GetStorageRange(
+	ctx context.Context,
+	address types.Address,
+	startKey common.Hash,
+	maxResults uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StorageRange, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.StorageRange](
+		ctx, api, "GetStorageRange", address, startKey, maxResults, blockReference)
+}
+
 func (api *shardApiClientRo) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
@@ -97,6 +119,45 @@ func (api *shardApiClientRo) Call(
 		ctx, api, "Call", args, mainBlockReferenceOrHashWithChildren, overrides)
 }
 
+func (api *shardApiClientRo) ExecuteTransaction(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rpctypes.CallResWithGasPrice](
+		ctx, api, "ExecuteTransaction", args, mainBlockReferenceOrHashWithChildren, overrides)
+}
+
+func (api *shardApiClientRo) TraceTransaction(
+	ctx context.Context, hash common.Hash, config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[json.RawMessage](
+		ctx, api, "TraceTransaction", hash, config)
+}
+
+func (api *shardApiClientRo) GetTransactionStateDiff(
+	ctx context.Context, hash common.Hash,
+) (*execution.StateDiff, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*execution.StateDiff](
+		ctx, api, "GetTransactionStateDiff", hash)
+}
+
+func (api *shardApiClientRo) GetConsensusEvidence(ctx context.Context) ([]*evidence.Evidence, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*evidence.Evidence](ctx, api, "GetConsensusEvidence")
+}
+
+func (api *shardApiClientRo) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[json.RawMessage](
+		ctx, api, "TraceCall", args, mainBlockReferenceOrHashWithChildren, overrides, config)
+}
+
 func (api *shardApiClientRo) GetInTransaction(
 	ctx context.Context, request rawapitypes.TransactionRequest,
 ) (*rawapitypes.TransactionInfo, error) {
@@ -115,6 +176,12 @@ func (api *shardApiClientRo) GasPrice(ctx context.Context) (types.Value, error)
 	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GasPrice")
 }
 
+func (api *shardApiClientRo) GetNextBaseFee(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GetNextBaseFee", blockReference)
+}
+
 func (api *shardApiClientRo) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[[]types.ShardId](ctx, api, "GetShardIdList")
 }
@@ -189,6 +189,109 @@ func (api *localShardApiRo) GetContract(
 	}, nil
 }
 
+func (api *localShardApiRo) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (types.Uint256, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return types.Uint256{}, fmt.Errorf("address is not in the shard %d", api.shard)
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return types.Uint256{}, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return types.Uint256{}, err
+	}
+	flatState, err := execution.NewFlatStateReader(tx, shardId, blockHash)
+	if err != nil {
+		return types.Uint256{}, err
+	}
+	if flatState != nil {
+		value, err := flatState.GetState(address, key)
+		if err != nil {
+			return types.Uint256{}, err
+		}
+		return *types.NewUint256FromBytes(value.Bytes()), nil
+	}
+
+	acc, err := api.getSmartContract(tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return types.Uint256{}, nil
+		}
+		return types.Uint256{}, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, shardId)
+	storageReader.SetRootHash(acc.StorageRoot)
+	value, err := storageReader.Fetch(key)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return types.Uint256{}, nil
+		}
+		return types.Uint256{}, err
+	}
+	return *value, nil
+}
+
+// GetStorageRange returns at most maxResults storage entries of the contract starting from startKey.
+// The entries are iterated in the order of the keys, so NextKey of the result can be used to get the next page.
+func (api *localShardApiRo) GetStorageRange(
+	ctx context.Context,
+	address types.Address,
+	startKey common.Hash,
+	maxResults uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StorageRange, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return nil, fmt.Errorf("address is not in the shard %d", api.shard)
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	res := &rawapitypes.StorageRange{Storage: make(map[common.Hash]types.Uint256)}
+
+	acc, err := api.getSmartContract(tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, shardId)
+	storageReader.SetRootHash(acc.StorageRoot)
+	if err := storageReader.Walk(startKey.Bytes(), func(rawKey, rawValue []byte) (bool, error) {
+		key := common.BytesToHash(rawKey)
+		if uint64(len(res.Storage)) >= maxResults {
+			res.NextKey = &key
+			return false, nil
+		}
+		var value types.Uint256
+		if err := value.UnmarshalSSZ(rawValue); err != nil {
+			return false, err
+		}
+		res.Storage[key] = value
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 type proofBuilder = func(operation mpt.MPTOperation) (mpt.Proof, error)
 
 func makeProofBuilder(root *mpt.Reader, key []byte) proofBuilder {
@@ -202,7 +305,19 @@ func (api *localShardApiRo) getRawSmartContract(
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
 ) ([]byte, proofBuilder, error) {
-	rawBlock, err := api.getBlockByReference(tx, blockReference, false)
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return nil, nil, err
+	}
+	return api.getRawSmartContractByBlockHash(tx, address, blockHash)
+}
+
+func (api *localShardApiRo) getRawSmartContractByBlockHash(
+	tx db.RoTx,
+	address types.Address,
+	blockHash common.Hash,
+) ([]byte, proofBuilder, error) {
+	rawBlock, err := api.getBlockByHash(tx, blockHash, false)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -213,6 +328,9 @@ func (api *localShardApiRo) getRawSmartContract(
 	if err := block.UnmarshalSSZ(rawBlock.Block); err != nil {
 		return nil, nil, err
 	}
+	if err := db.CheckStateAvailable(tx, api.shardId(), block.Id); err != nil {
+		return nil, nil, err
+	}
 
 	root := mpt.NewDbReader(tx, api.shardId(), db.ContractTrieTable)
 	root.SetRootHash(block.SmartContractsRoot)
@@ -234,7 +352,21 @@ func (api *localShardApiRo) getSmartContract(
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
 ) (*types.SmartContract, error) {
-	contractRaw, _, err := api.getRawSmartContract(tx, address, blockReference)
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	// Most of the requests are made to the latest block, which is usually held in the flat state.
+	flatState, err := execution.NewFlatStateReader(tx, api.shardId(), blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if flatState != nil {
+		return flatState.GetContract(address)
+	}
+
+	contractRaw, _, err := api.getRawSmartContractByBlockHash(tx, address, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -163,17 +163,24 @@ func (api *localShardApiRo) handleOutTransactions(
 	return outTransactions, nil
 }
 
-func (api *localShardApiRo) Call(
-	ctx context.Context, args rpctypes.CallArgs,
+// callState is an execution state prepared for running a call on top of the specified block.
+type callState struct {
+	es            *execution.ExecutionState
+	txn           *types.Transaction
+	payer         execution.Payer
+	block         *types.Block
+	mainBlockHash common.Hash
+	childBlocks   []common.Hash
+}
+
+func (api *localShardApiRo) prepareCall(
+	ctx context.Context,
+	tx db.RoTx,
+	methodName string,
+	args rpctypes.CallArgs,
 	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 	overrides *rpctypes.StateOverrides,
-) (*rpctypes.CallResWithGasPrice, error) {
-	tx, err := api.db.CreateRoTx(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
+) (*callState, error) {
 	txn, err := args.ToTransaction()
 	if err != nil {
 		return nil, err
@@ -208,7 +215,7 @@ func (api *localShardApiRo) Call(
 	if !shardId.IsMainShard() {
 		if len(childBlocks) < int(shardId) {
 			return nil, fmt.Errorf("%w: main shard includes only %d blocks",
-				makeShardNotFoundError(methodNameChecked("Call"), shardId), len(childBlocks))
+				makeShardNotFoundError(methodName, shardId), len(childBlocks))
 		}
 		hash = childBlocks[shardId-1]
 	} else {
@@ -219,6 +226,9 @@ func (api *localShardApiRo) Call(
 	if err != nil {
 		return nil, fmt.Errorf("failed to read block %s: %w", hash, err)
 	}
+	if err := db.CheckStateAvailable(tx, shardId, block.Id); err != nil {
+		return nil, err
+	}
 
 	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, block, shardId)
 	if err != nil {
@@ -229,12 +239,25 @@ func (api *localShardApiRo) Call(
 		Block:          block,
 		ConfigAccessor: configAccessor,
 		Mode:           execution.ModeReadOnly,
+		ReadFlatState:  true,
 	})
 	if err != nil {
 		return nil, err
 	}
 	es.MainShardHash = mainBlockHash
 
+	// The overrides of the development API are already in the state of the block,
+	// but the time shift and the impersonation stay in effect for the calls on top of it.
+	devChanges, err := execution.ReadDevChanges(tx, shardId, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read development API changes: %w", err)
+	}
+	if devChanges != nil {
+		if err := es.ApplyDevChanges(nil, devChanges.Impersonated, devChanges.TimeOffset); err != nil {
+			return nil, err
+		}
+	}
+
 	if overrides != nil {
 		if err := overrides.Override(es); err != nil {
 			return nil, err
@@ -267,8 +290,37 @@ func (api *localShardApiRo) Call(
 	}
 
 	txn.TxId = es.InTxCounts[txn.From.ShardId()]
+	return &callState{
+		es:            es,
+		txn:           txn,
+		payer:         payer,
+		block:         block,
+		mainBlockHash: mainBlockHash,
+		childBlocks:   childBlocks,
+	}, nil
+}
+
+func (api *localShardApiRo) Call(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("Call")
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	state, err := api.prepareCall(ctx, tx, methodName, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+	es, txn := state.es, state.txn
+
 	txnHash := es.AddInTransaction(txn)
-	res := es.HandleTransaction(ctx, txn, payer)
+	res := es.HandleTransaction(ctx, txn, state.payer)
 
 	result := &rpctypes.CallResWithGasPrice{
 		Data:      res.ReturnData,
@@ -282,10 +334,11 @@ func (api *localShardApiRo) Call(
 		return result, nil
 	}
 
-	esOld, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
-		Block:          block,
+	esOld, err := execution.NewExecutionState(tx, es.ShardId, execution.StateParams{
+		Block:          state.block,
 		ConfigAccessor: config.GetStubAccessor(),
 		Mode:           execution.ModeReadOnly,
+		ReadFlatState:  true,
 	})
 	if err != nil {
 		return nil, err
@@ -299,8 +352,8 @@ func (api *localShardApiRo) Call(
 	outTransactions, err := api.handleOutTransactions(
 		ctx,
 		execOutTransactions,
-		mainBlockHash,
-		childBlocks,
+		state.mainBlockHash,
+		state.childBlocks,
 		&stateOverrides,
 	)
 	if err != nil {
@@ -312,3 +365,92 @@ func (api *localShardApiRo) Call(
 	result.BaseFee = es.BaseFee
 	return result, nil
 }
+
+// ExecuteTransaction executes a single transaction the way the collator does: external transactions
+// are validated and paid by the destination account, internal ones are paid from their fee credit.
+// Unlike Call, the outbound transactions (including responses, bounces and refunds) are not executed,
+// they are returned to the caller to be executed on their destination shards.
+func (api *localShardApiRo) ExecuteTransaction(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("ExecuteTransaction")
+
+	if args.Transaction == nil {
+		return nil, errors.New("transaction is not specified")
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	state, err := api.prepareCall(ctx, tx, methodName, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+	es, txn := state.es, state.txn
+
+	txnHash := es.AddInTransaction(txn)
+
+	var res *execution.ExecutionResult
+	if txn.IsInternal() {
+		if err := es.AcceptInternalTransaction(txn); err != nil {
+			res = execution.NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
+		} else {
+			res = es.HandleTransaction(ctx, txn, state.payer)
+		}
+	} else {
+		res = execution.ValidateExternalTransaction(es, txn)
+		if !res.Failed() {
+			verifyGas := res.GasUsed
+			res = es.HandleTransaction(ctx, txn, state.payer)
+			res.AddUsed(verifyGas)
+		}
+	}
+	if res.FatalError != nil {
+		return nil, res.FatalError
+	}
+
+	result := &rpctypes.CallResWithGasPrice{
+		Data:      res.ReturnData,
+		CoinsUsed: res.CoinsUsed(),
+		Logs:      es.Logs[txnHash],
+		DebugLogs: es.DebugLogs[txnHash],
+		BaseFee:   es.BaseFee,
+	}
+	if res.Failed() {
+		result.Error = res.GetError().Error()
+	}
+
+	// The fee is charged and the bounce is sent even if the transaction failed,
+	// so the state changes and the outbound transactions are returned in any case.
+	esOld, err := execution.NewExecutionState(tx, es.ShardId, execution.StateParams{
+		Block:          state.block,
+		ConfigAccessor: config.GetStubAccessor(),
+		Mode:           execution.ModeReadOnly,
+		ReadFlatState:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.StateOverrides, err = calculateStateChange(es, esOld, overrides); err != nil {
+		return nil, err
+	}
+
+	outTxns := es.OutTransactions[txnHash]
+	result.OutTransactions = make([]*rpctypes.OutTransaction, len(outTxns))
+	for i, outTxn := range outTxns {
+		raw, err := outTxn.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		result.OutTransactions[i] = &rpctypes.OutTransaction{
+			TransactionSSZ: raw,
+			ForwardKind:    outTxn.ForwardKind,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+func (api *localShardApiRo) TraceTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	tracer, err := tracers.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, index, err := api.getBlockAndInTransactionIndexByTransactionHash(tx, api.shardId(), hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction %s: %w", hash, err)
+	}
+
+	replayer, err := execution.NewTransactionReplayer(ctx, tx, api.shardId(), block)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := replayer.Replay(index.TransactionIndex, tracer.Hooks); err != nil {
+		return nil, fmt.Errorf("failed to replay transaction %s: %w", hash, err)
+	}
+	return tracer.GetResult()
+}
+
+func (api *localShardApiRo) GetTransactionStateDiff(
+	ctx context.Context,
+	hash common.Hash,
+) (*execution.StateDiff, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, index, err := api.getBlockAndInTransactionIndexByTransactionHash(tx, api.shardId(), hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction %s: %w", hash, err)
+	}
+
+	replayer, err := execution.NewTransactionReplayer(ctx, tx, api.shardId(), block)
+	if err != nil {
+		return nil, err
+	}
+	// Unlike the prestate tracer, the diff also covers the changes made outside the VM,
+	// e.g. the gas bought by the transaction and the refunds.
+	_, diff, err := replayer.ReplayStateDiff(index.TransactionIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay transaction %s: %w", hash, err)
+	}
+	return diff, nil
+}
+
+// GetConsensusEvidence returns the conflicting messages signed by the shard validators
+// that were seen by this node.
+func (api *localShardApiRo) GetConsensusEvidence(ctx context.Context) ([]*evidence.Evidence, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	return evidence.ReadAll(tx, api.shardId())
+}
+
+func (api *localShardApiRo) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceCall")
+
+	tracer, err := tracers.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	state, err := api.prepareCall(ctx, tx, methodName, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	state.es.AddInTransaction(state.txn)
+	state.es.EvmTracingHooks = tracer.Hooks
+	if res := state.es.HandleTransaction(ctx, state.txn, state.payer); res.FatalError != nil {
+		return nil, res.FatalError
+	}
+	return tracer.GetResult()
+}
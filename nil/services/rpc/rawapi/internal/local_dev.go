@@ -2,22 +2,46 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/collate"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
+var (
+	errNoCollator  = errors.New("the shard is not collated by this node")
+	errNoSnapshots = errors.New("snapshots require all shards to be collated by this node")
+)
+
 type localShardApiDev struct {
 	shard types.ShardId
+	db    db.ReadOnlyDB
+	// devState is nil if the shard is not collated by the node.
+	devState *collate.DevState
+	// snapshots is nil if the node doesn't collate all shards.
+	snapshots *collate.DevSnapshots
 }
 
 var _ shardApiDev = (*localShardApiDev)(nil)
 
-func newLocalShardApiDev(shardId types.ShardId) *localShardApiDev {
-	return &localShardApiDev{shard: shardId}
+func newLocalShardApiDev(
+	shardId types.ShardId,
+	db db.ReadOnlyDB,
+	devState *collate.DevState,
+	snapshots *collate.DevSnapshots,
+) *localShardApiDev {
+	return &localShardApiDev{
+		shard:     shardId,
+		db:        db,
+		devState:  devState,
+		snapshots: snapshots,
+	}
 }
 
 func (api *localShardApiDev) shardId() types.ShardId {
@@ -33,11 +57,11 @@ func (api *localShardApiDev) setAsP2pRequestHandlersIfAllowed(
 ) error {
 	return setRawApiRequestHandlers(
 		ctx,
-		reflect.TypeFor[NetworkTransportProtocolRo](),
-		reflect.TypeFor[shardApiRo](),
+		reflect.TypeFor[NetworkTransportProtocolDev](),
+		reflect.TypeFor[shardApiDev](),
 		api,
 		api.shard,
-		apiNameRo,
+		apiNameDev,
 		networkManager,
 		logger)
 }
@@ -49,3 +73,76 @@ func (api *localShardApiDev) DoPanicOnShard(_ context.Context) (uint64, error) {
 	}()
 	return 0, nil
 }
+
+func (api *localShardApiDev) getDevState() (*collate.DevState, error) {
+	if api.devState == nil {
+		return nil, errNoCollator
+	}
+	return api.devState, nil
+}
+
+func (api *localShardApiDev) OverrideAccount(_ context.Context, override *execution.AccountOverride) (struct{}, error) {
+	devState, err := api.getDevState()
+	if err != nil {
+		return struct{}{}, err
+	}
+	return struct{}{}, devState.Override(override)
+}
+
+func (api *localShardApiDev) Impersonate(_ context.Context, address types.Address, enabled bool) (struct{}, error) {
+	devState, err := api.getDevState()
+	if err != nil {
+		return struct{}{}, err
+	}
+	return struct{}{}, devState.Impersonate(address, enabled)
+}
+
+func (api *localShardApiDev) IncreaseTime(_ context.Context, seconds uint64) (uint64, error) {
+	devState, err := api.getDevState()
+	if err != nil {
+		return 0, err
+	}
+	return devState.IncreaseTime(seconds), nil
+}
+
+func (api *localShardApiDev) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (struct{}, error) {
+	devState, err := api.getDevState()
+	if err != nil {
+		return struct{}{}, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return struct{}{}, err
+	}
+	defer tx.Rollback()
+
+	lastBlock, _, err := db.ReadLastBlock(tx, api.shard)
+	if err != nil {
+		return struct{}{}, err
+	}
+	return struct{}{}, devState.SetNextBlockTimestamp(timestamp, lastBlock.Id)
+}
+
+func (api *localShardApiDev) Mine(ctx context.Context, count uint64) (uint64, error) {
+	devState, err := api.getDevState()
+	if err != nil {
+		return 0, err
+	}
+	blockId, err := devState.Mine(ctx, count)
+	return blockId.Uint64(), err
+}
+
+func (api *localShardApiDev) Snapshot(ctx context.Context) (uint64, error) {
+	if api.snapshots == nil {
+		return 0, errNoSnapshots
+	}
+	return api.snapshots.Snapshot(ctx)
+}
+
+func (api *localShardApiDev) Revert(ctx context.Context, id uint64) (struct{}, error) {
+	if api.snapshots == nil {
+		return struct{}{}, errNoSnapshots
+	}
+	return struct{}{}, api.snapshots.Revert(ctx, id)
+}
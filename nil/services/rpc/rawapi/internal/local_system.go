@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 func (api *localShardApiRo) GasPrice(ctx context.Context) (types.Value, error) {
@@ -29,6 +30,28 @@ func (api *localShardApiRo) GasPrice(ctx context.Context) (types.Value, error) {
 	return types.Value{Uint256: &param.Shards[api.shardId()]}, nil
 }
 
+// GetNextBaseFee returns the base fee of the block following the referenced one.
+func (api *localShardApiRo) GetNextBaseFee(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("cannot open tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	hash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return types.Value{}, err
+	}
+	block, err := db.ReadBlock(tx, api.shardId(), hash)
+	if err != nil {
+		return types.Value{}, err
+	}
+	return execution.CalculateNextBaseFee(tx, api.shardId(), block)
+}
+
 func (api *localShardApiRo) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
 	if api.shardId() != types.MainShardId {
 		return nil, errors.New("GetShardIdList is only supported for the main shard")
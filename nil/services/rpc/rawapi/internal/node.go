@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -9,7 +10,10 @@ import (
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -167,6 +171,45 @@ func (api *nodeApiOverShardApis) GetContract(
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (types.Uint256, error) {
+	methodName := methodNameChecked("GetStorageAt")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return types.Uint256{}, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStorageAt(ctx, address, key, blockReference)
+	if err != nil {
+		return types.Uint256{}, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetStorageRange(
+	ctx context.Context,
+	address types.Address,
+	startKey common.Hash,
+	maxResults uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StorageRange, error) {
+	methodName := methodNameChecked("GetStorageRange")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStorageRange(ctx, address, startKey, maxResults, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
@@ -192,6 +235,108 @@ func (api *nodeApiOverShardApis) Call(
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) ExecuteTransaction(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("ExecuteTransaction")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.ExecuteTransaction(ctx, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) TraceTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceTransaction")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceTransaction(ctx, hash, config)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetTransactionStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*execution.StateDiff, error) {
+	methodName := methodNameChecked("GetTransactionStateDiff")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTransactionStateDiff(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetConsensusEvidence(
+	ctx context.Context,
+	shardId types.ShardId,
+) ([]*evidence.Evidence, error) {
+	methodName := methodNameChecked("GetConsensusEvidence")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetConsensusEvidence(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceCall")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceCall(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, config)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) GetInTransaction(
 	ctx context.Context,
 	shardId types.ShardId,
@@ -239,6 +384,23 @@ func (api *nodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.Sha
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) GetNextBaseFee(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	methodName := methodNameChecked("GetNextBaseFee")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return types.Value{}, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetNextBaseFee(ctx, blockReference)
+	if err != nil {
+		return types.Value{}, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
 	methodName := methodNameChecked("GetShardIdList")
 	shardId := types.MainShardId
@@ -339,6 +501,109 @@ func (api *nodeApiOverShardApis) DoPanicOnShard(ctx context.Context, shardId typ
 	return shardApi.DoPanicOnShard(ctx)
 }
 
+func (api *nodeApiOverShardApis) OverrideAccount(ctx context.Context, override *execution.AccountOverride) error {
+	methodName := methodNameChecked("OverrideAccount")
+	shardId := override.Address.ShardId()
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return makeShardNotFoundError(methodName, shardId)
+	}
+	if _, err := shardApi.OverrideAccount(ctx, override); err != nil {
+		return makeCallError(methodName, shardId, err)
+	}
+	return nil
+}
+
+func (api *nodeApiOverShardApis) Impersonate(ctx context.Context, address types.Address, enabled bool) error {
+	methodName := methodNameChecked("Impersonate")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return makeShardNotFoundError(methodName, shardId)
+	}
+	if _, err := shardApi.Impersonate(ctx, address, enabled); err != nil {
+		return makeCallError(methodName, shardId, err)
+	}
+	return nil
+}
+
+func (api *nodeApiOverShardApis) IncreaseTime(
+	ctx context.Context,
+	shardId types.ShardId,
+	seconds uint64,
+) (uint64, error) {
+	methodName := methodNameChecked("IncreaseTime")
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.IncreaseTime(ctx, seconds)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) SetNextBlockTimestamp(
+	ctx context.Context,
+	shardId types.ShardId,
+	timestamp uint64,
+) error {
+	methodName := methodNameChecked("SetNextBlockTimestamp")
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return makeShardNotFoundError(methodName, shardId)
+	}
+	if _, err := shardApi.SetNextBlockTimestamp(ctx, timestamp); err != nil {
+		return makeCallError(methodName, shardId, err)
+	}
+	return nil
+}
+
+func (api *nodeApiOverShardApis) Mine(
+	ctx context.Context,
+	shardId types.ShardId,
+	count uint64,
+) (types.BlockNumber, error) {
+	methodName := methodNameChecked("Mine")
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.Mine(ctx, count)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return types.BlockNumber(result), nil
+}
+
+func (api *nodeApiOverShardApis) Snapshot(ctx context.Context) (uint64, error) {
+	methodName := methodNameChecked("Snapshot")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.Snapshot(ctx)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) Revert(ctx context.Context, id uint64) error {
+	methodName := methodNameChecked("Revert")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return makeShardNotFoundError(methodName, shardId)
+	}
+	if _, err := shardApi.Revert(ctx, id); err != nil {
+		return makeCallError(methodName, shardId, err)
+	}
+	return nil
+}
+
 func (api *nodeApiOverShardApis) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error) {
 	methodName := methodNameChecked("GetTxpoolStatus")
 	shardApi, ok := api.apisRw[shardId]
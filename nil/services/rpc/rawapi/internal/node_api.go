@@ -2,11 +2,15 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -50,6 +54,19 @@ type NodeApi interface {
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (types.Uint256, error)
+	GetStorageRange(
+		ctx context.Context,
+		address types.Address,
+		startKey common.Hash,
+		maxResults uint64,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.StorageRange, error)
 
 	Call(
 		ctx context.Context,
@@ -57,8 +74,32 @@ type NodeApi interface {
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	ExecuteTransaction(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+	) (*rpctypes.CallResWithGasPrice, error)
+
+	TraceTransaction(
+		ctx context.Context,
+		shardId types.ShardId,
+		hash common.Hash,
+		config *tracers.TraceConfig,
+	) (json.RawMessage, error)
+	GetTransactionStateDiff(ctx context.Context, shardId types.ShardId, hash common.Hash) (*execution.StateDiff, error)
+	GetConsensusEvidence(ctx context.Context, shardId types.ShardId) ([]*evidence.Evidence, error)
+	TraceCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		config *tracers.TraceConfig,
+	) (json.RawMessage, error)
 
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
+	GetNextBaseFee(
+		ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
 
@@ -71,6 +112,13 @@ type NodeApi interface {
 
 	SendTransaction(ctx context.Context, shardId types.ShardId, transaction []byte) (txnpool.DiscardReason, error)
 	DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error)
+	OverrideAccount(ctx context.Context, override *execution.AccountOverride) error
+	Impersonate(ctx context.Context, address types.Address, enabled bool) error
+	IncreaseTime(ctx context.Context, shardId types.ShardId, seconds uint64) (uint64, error)
+	SetNextBlockTimestamp(ctx context.Context, shardId types.ShardId, timestamp uint64) error
+	Mine(ctx context.Context, shardId types.ShardId, count uint64) (types.BlockNumber, error)
+	Snapshot(ctx context.Context) (uint64, error)
+	Revert(ctx context.Context, id uint64) error
 
 	SetP2pRequestHandlers(ctx context.Context, networkManager network.Manager, logger logging.Logger) error
 }
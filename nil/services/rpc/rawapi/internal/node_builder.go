@@ -2,6 +2,7 @@ package internal
 
 import (
 	"github.com/NilFoundation/nil/nil/common/assert"
+	"github.com/NilFoundation/nil/nil/internal/collate"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -77,9 +78,19 @@ func (nb *nodeApiBuilder) WithNetworkShardApiClientRw(shardId types.ShardId) *no
 	return nb
 }
 
-func (nb *nodeApiBuilder) WithLocalShardApiDev(shardId types.ShardId) *nodeApiBuilder {
-	nb.nodeApi.apisDev[shardId] = newLocalShardApiDev(shardId)
-	nb.nodeApi.allApis = append(nb.nodeApi.allApis, nb.nodeApi.apisDev[shardId])
+// WithLocalShardApiDev adds the development API of the shard. devState is nil if the node doesn't collate the shard,
+// snapshots is nil if the node doesn't collate all shards.
+func (nb *nodeApiBuilder) WithLocalShardApiDev(
+	shardId types.ShardId,
+	devState *collate.DevState,
+	snapshots *collate.DevSnapshots,
+) *nodeApiBuilder {
+	var localShardApi shardApiDev = newLocalShardApiDev(shardId, nb.db, devState, snapshots)
+	if assert.Enable {
+		localShardApi = newShardApiClientDirectEmulatorDev(localShardApi)
+	}
+	nb.nodeApi.apisDev[shardId] = localShardApi
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
 	return nb
 }
 
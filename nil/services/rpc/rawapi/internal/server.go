@@ -30,10 +30,19 @@ type NetworkTransportProtocolRo interface {
 	GetCode(request pb.AccountRequest) pb.CodeResponse
 	GetTokens(request pb.AccountRequest) pb.TokensResponse
 	GetContract(request pb.AccountRequest) pb.RawContractResponse
+	GetStorageAt(request pb.StorageAtRequest) pb.StorageAtResponse
+	GetStorageRange(request pb.StorageRangeRequest) pb.StorageRangeResponse
 
 	Call(pb.CallRequest) pb.CallResponse
+	ExecuteTransaction(pb.CallRequest) pb.CallResponse
+
+	TraceTransaction(pb.TraceTransactionRequest) pb.TraceResponse
+	GetTransactionStateDiff(pb.Hash) pb.StateDiffResponse
+	GetConsensusEvidence() pb.ConsensusEvidenceResponse
+	TraceCall(pb.TraceCallRequest) pb.TraceResponse
 
 	GasPrice() pb.GasPriceResponse
+	GetNextBaseFee(pb.BlockRequest) pb.GasPriceResponse
 	GetShardIdList() pb.ShardIdListResponse
 	GetNumShards() pb.Uint64Response
 
@@ -52,6 +61,15 @@ type NetworkTransportProtocolRw interface {
 
 type NetworkTransportProtocolDev interface {
 	DoPanicOnShard() pb.Uint64Response
+
+	OverrideAccount(pb.AccountOverrideRequest) pb.EmptyResponse
+	Impersonate(pb.ImpersonateRequest) pb.EmptyResponse
+	IncreaseTime(pb.Uint64Request) pb.Uint64Response
+	SetNextBlockTimestamp(pb.Uint64Request) pb.EmptyResponse
+	Mine(pb.Uint64Request) pb.Uint64Response
+
+	Snapshot() pb.Uint64Response
+	Revert(pb.Uint64Request) pb.EmptyResponse
 }
 
 func getRawApiRequestHandlers(
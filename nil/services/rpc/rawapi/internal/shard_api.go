@@ -2,11 +2,15 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -46,6 +50,19 @@ type shardApiRo interface {
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (types.Uint256, error)
+	GetStorageRange(
+		ctx context.Context,
+		address types.Address,
+		startKey common.Hash,
+		maxResults uint64,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.StorageRange, error)
 
 	Call(
 		ctx context.Context,
@@ -53,8 +70,26 @@ type shardApiRo interface {
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	ExecuteTransaction(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+	) (*rpctypes.CallResWithGasPrice, error)
+
+	TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig) (json.RawMessage, error)
+	GetTransactionStateDiff(ctx context.Context, hash common.Hash) (*execution.StateDiff, error)
+	GetConsensusEvidence(ctx context.Context) ([]*evidence.Evidence, error)
+	TraceCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		config *tracers.TraceConfig,
+	) (json.RawMessage, error)
 
 	GasPrice(ctx context.Context) (types.Value, error)
+	GetNextBaseFee(ctx context.Context, blockReference rawapitypes.BlockReference) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
 
@@ -82,4 +117,13 @@ type shardApiDev interface {
 	shardApiBase
 
 	DoPanicOnShard(ctx context.Context) (uint64, error)
+
+	OverrideAccount(ctx context.Context, override *execution.AccountOverride) (struct{}, error)
+	Impersonate(ctx context.Context, address types.Address, enabled bool) (struct{}, error)
+	IncreaseTime(ctx context.Context, seconds uint64) (uint64, error)
+	SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (struct{}, error)
+	Mine(ctx context.Context, count uint64) (uint64, error)
+
+	Snapshot(ctx context.Context) (uint64, error)
+	Revert(ctx context.Context, id uint64) (struct{}, error)
 }
@@ -607,6 +607,131 @@ func (rcr *RawContractResponse) UnpackProtoMessage() (*rawapitypes.SmartContract
 	return nil, errors.New("unexpected response type")
 }
 
+// StorageAtRequest converters
+
+func (r *StorageAtRequest) PackProtoMessage(
+	address types.Address, key common.Hash, blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Key = new(Hash)
+	if err := r.Key.PackProtoMessage(key); err != nil {
+		return err
+	}
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *StorageAtRequest) UnpackProtoMessage() (types.Address, common.Hash, rawapitypes.BlockReference, error) {
+	key, err := r.GetKey().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+	return r.GetAddress().UnpackProtoMessage(), key, blockReference, nil
+}
+
+// StorageAtResponse converters
+
+func (r *StorageAtResponse) PackProtoMessage(value types.Uint256, err error) error {
+	if err != nil {
+		r.Result = &StorageAtResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &StorageAtResponse_Data{Data: new(Uint256).PackProtoMessage(value)}
+	return nil
+}
+
+func (r *StorageAtResponse) UnpackProtoMessage() (types.Uint256, error) {
+	switch r.GetResult().(type) {
+	case *StorageAtResponse_Error:
+		return types.Uint256{}, r.GetError().UnpackProtoMessage()
+
+	case *StorageAtResponse_Data:
+		return r.GetData().UnpackProtoMessage(), nil
+	}
+	return types.Uint256{}, errors.New("unexpected response type")
+}
+
+// StorageRangeRequest converters
+
+func (r *StorageRangeRequest) PackProtoMessage(
+	address types.Address, startKey common.Hash, maxResults uint64, blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.StartKey = new(Hash)
+	if err := r.StartKey.PackProtoMessage(startKey); err != nil {
+		return err
+	}
+	r.MaxResults = maxResults
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *StorageRangeRequest) UnpackProtoMessage() (
+	types.Address, common.Hash, uint64, rawapitypes.BlockReference, error,
+) {
+	startKey, err := r.GetStartKey().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, 0, rawapitypes.BlockReference{}, err
+	}
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, 0, rawapitypes.BlockReference{}, err
+	}
+	return r.GetAddress().UnpackProtoMessage(), startKey, r.GetMaxResults(), blockReference, nil
+}
+
+// StorageRangeResponse converters
+
+func (r *StorageRangeResponse) PackProtoMessage(storageRange *rawapitypes.StorageRange, err error) error {
+	if err != nil {
+		r.Result = &StorageRangeResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &StorageRange{Storage: make(map[string]*Uint256, len(storageRange.Storage))}
+	for k, v := range storageRange.Storage {
+		data.Storage[k.Hex()] = new(Uint256).PackProtoMessage(v)
+	}
+	if storageRange.NextKey != nil {
+		data.NextKey = new(Hash)
+		if err := data.NextKey.PackProtoMessage(*storageRange.NextKey); err != nil {
+			return err
+		}
+	}
+	r.Result = &StorageRangeResponse_Data{Data: data}
+	return nil
+}
+
+func (r *StorageRangeResponse) UnpackProtoMessage() (*rawapitypes.StorageRange, error) {
+	switch r.GetResult().(type) {
+	case *StorageRangeResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *StorageRangeResponse_Data:
+		data := r.GetData()
+		result := &rawapitypes.StorageRange{
+			Storage: make(map[common.Hash]types.Uint256, len(data.GetStorage())),
+		}
+		for k, v := range data.GetStorage() {
+			result.Storage[common.HexToHash(k)] = v.UnpackProtoMessage()
+		}
+		if data.GetNextKey() != nil {
+			nextKey, err := data.GetNextKey().UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			result.NextKey = &nextKey
+		}
+		return result, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
 func (x *Contract) PackProtoMessage(contract rpctypes.Contract) *Contract {
 	if contract.Seqno != nil {
 		x.Seqno = (*uint64)(contract.Seqno)
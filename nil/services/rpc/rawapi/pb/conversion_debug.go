@@ -0,0 +1,311 @@
+package pb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+// TraceConfig converters
+
+func (c *TraceConfig) PackProtoMessage(cfg *tracers.TraceConfig) *TraceConfig {
+	if cfg == nil {
+		return nil
+	}
+	return &TraceConfig{
+		Tracer:           cfg.Tracer,
+		EnableMemory:     cfg.EnableMemory,
+		DisableStack:     cfg.DisableStack,
+		DisableStorage:   cfg.DisableStorage,
+		EnableReturnData: cfg.EnableReturnData,
+		Limit:            uint64(cfg.Limit),
+		TracerConfig:     cfg.TracerConfig,
+	}
+}
+
+func (c *TraceConfig) UnpackProtoMessage() *tracers.TraceConfig {
+	if c == nil {
+		return nil
+	}
+	return &tracers.TraceConfig{
+		Config: tracers.Config{
+			EnableMemory:     c.GetEnableMemory(),
+			DisableStack:     c.GetDisableStack(),
+			DisableStorage:   c.GetDisableStorage(),
+			EnableReturnData: c.GetEnableReturnData(),
+			Limit:            int(c.GetLimit()),
+			TracerConfig:     c.GetTracerConfig(),
+		},
+		Tracer: c.GetTracer(),
+	}
+}
+
+// TraceTransactionRequest converters
+
+func (r *TraceTransactionRequest) PackProtoMessage(hash common.Hash, cfg *tracers.TraceConfig) error {
+	r.Hash = &Hash{}
+	if err := r.GetHash().PackProtoMessage(hash); err != nil {
+		return err
+	}
+	r.Config = new(TraceConfig).PackProtoMessage(cfg)
+	return nil
+}
+
+func (r *TraceTransactionRequest) UnpackProtoMessage() (common.Hash, *tracers.TraceConfig, error) {
+	hash, err := r.GetHash().UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, nil, err
+	}
+	return hash, r.GetConfig().UnpackProtoMessage(), nil
+}
+
+// TraceCallRequest converters
+
+func (r *TraceCallRequest) PackProtoMessage(
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	cfg *tracers.TraceConfig,
+) error {
+	r.Args = new(CallArgs).PackProtoMessage(args)
+
+	r.MainBlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren{}
+	err := r.GetMainBlockReferenceOrHashWithChildren().PackProtoMessage(mainBlockReferenceOrHashWithChildren)
+	if err != nil {
+		return err
+	}
+
+	if overrides != nil {
+		r.StateOverrides = new(StateOverrides).PackProtoMessage(overrides)
+	}
+
+	r.Config = new(TraceConfig).PackProtoMessage(cfg)
+	return nil
+}
+
+func (r *TraceCallRequest) UnpackProtoMessage() (
+	rpctypes.CallArgs,
+	rawapitypes.BlockReferenceOrHashWithChildren,
+	*rpctypes.StateOverrides,
+	*tracers.TraceConfig,
+	error,
+) {
+	br, err := r.GetMainBlockReferenceOrHashWithChildren().UnpackProtoMessage()
+	if err != nil {
+		return rpctypes.CallArgs{}, rawapitypes.BlockReferenceOrHashWithChildren{}, nil, nil, err
+	}
+	return r.GetArgs().UnpackProtoMessage(), br, r.GetStateOverrides().UnpackProtoMessage(),
+		r.GetConfig().UnpackProtoMessage(), nil
+}
+
+// TraceResponse converters
+
+func (r *TraceResponse) PackProtoMessage(trace json.RawMessage, err error) error {
+	if err != nil {
+		r.Result = &TraceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &TraceResponse_Data{Data: trace}
+	return nil
+}
+
+func (r *TraceResponse) UnpackProtoMessage() (json.RawMessage, error) {
+	switch res := r.GetResult().(type) {
+	case *TraceResponse_Data:
+		return res.Data, nil
+	case *TraceResponse_Error:
+		return nil, res.Error.UnpackProtoMessage()
+	}
+	return nil, fmt.Errorf("unexpected response type: %T", r.GetResult())
+}
+
+// AccountDiff converters
+
+func (d *AccountDiff) PackProtoMessage(diff *execution.AccountDiff) *AccountDiff {
+	if diff.Balance != nil {
+		d.Balance = new(Uint256)
+		if diff.Balance.Uint256 != nil {
+			d.Balance.PackProtoMessage(*diff.Balance.Uint256)
+		}
+	}
+	d.Seqno = (*uint64)(diff.Seqno)
+	d.ExtSeqno = (*uint64)(diff.ExtSeqno)
+	if diff.Code != nil {
+		// The empty code is a valid previous value, so it must be distinguished from the unset one.
+		d.Code = append([]byte{}, *diff.Code...)
+	}
+	if len(diff.Storage) > 0 {
+		d.Storage = make(map[string]*Hash, len(diff.Storage))
+		for k, v := range diff.Storage {
+			kHex := k.Hex()
+			d.Storage[kHex] = &Hash{}
+			check.PanicIfErr(d.GetStorage()[kHex].PackProtoMessage(v))
+		}
+	}
+	if len(diff.Tokens) > 0 {
+		d.Tokens = make(map[string]*Uint256, len(diff.Tokens))
+		for k, v := range diff.Tokens {
+			u := new(Uint256)
+			if v.Uint256 != nil {
+				u = u.PackProtoMessage(*v.Uint256)
+			}
+			d.Tokens[k.String()] = u
+		}
+	}
+	return d
+}
+
+func (d *AccountDiff) UnpackProtoMessage() (*execution.AccountDiff, error) {
+	diff := &execution.AccountDiff{
+		Seqno:    (*types.Seqno)(d.Seqno),    //nolint: protogetter
+		ExtSeqno: (*types.Seqno)(d.ExtSeqno), //nolint: protogetter
+	}
+	if d.GetBalance() != nil {
+		v := newValueFromUint256(d.GetBalance())
+		diff.Balance = &v
+	}
+	if d.Code != nil { //nolint: protogetter
+		diff.Code = (*hexutil.Bytes)(&d.Code)
+	}
+	if len(d.GetStorage()) > 0 {
+		diff.Storage = make(map[common.Hash]common.Hash, len(d.GetStorage()))
+		for k, v := range d.GetStorage() {
+			value, err := v.UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			diff.Storage[common.HexToHash(k)] = value
+		}
+	}
+	if len(d.GetTokens()) > 0 {
+		diff.Tokens = make(map[types.TokenId]types.Value, len(d.GetTokens()))
+		for k, v := range d.GetTokens() {
+			diff.Tokens[types.TokenId(types.HexToAddress(k))] = newValueFromUint256(v)
+		}
+	}
+	return diff, nil
+}
+
+// StateDiff converters
+
+func packAccountDiffs(diffs map[types.Address]*execution.AccountDiff) map[string]*AccountDiff {
+	res := make(map[string]*AccountDiff, len(diffs))
+	for addr, diff := range diffs {
+		res[addr.Hex()] = new(AccountDiff).PackProtoMessage(diff)
+	}
+	return res
+}
+
+func unpackAccountDiffs(diffs map[string]*AccountDiff) (map[types.Address]*execution.AccountDiff, error) {
+	res := make(map[types.Address]*execution.AccountDiff, len(diffs))
+	for addr, diff := range diffs {
+		var err error
+		if res[types.HexToAddress(addr)], err = diff.UnpackProtoMessage(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (d *StateDiff) PackProtoMessage(diff *execution.StateDiff) *StateDiff {
+	d.Pre = packAccountDiffs(diff.Pre)
+	d.Post = packAccountDiffs(diff.Post)
+	return d
+}
+
+func (d *StateDiff) UnpackProtoMessage() (*execution.StateDiff, error) {
+	pre, err := unpackAccountDiffs(d.GetPre())
+	if err != nil {
+		return nil, err
+	}
+	post, err := unpackAccountDiffs(d.GetPost())
+	if err != nil {
+		return nil, err
+	}
+	return &execution.StateDiff{Pre: pre, Post: post}, nil
+}
+
+// StateDiffResponse converters
+
+func (r *StateDiffResponse) PackProtoMessage(diff *execution.StateDiff, err error) error {
+	if err != nil {
+		r.Result = &StateDiffResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &StateDiffResponse_Data{Data: new(StateDiff).PackProtoMessage(diff)}
+	return nil
+}
+
+func (r *StateDiffResponse) UnpackProtoMessage() (*execution.StateDiff, error) {
+	switch res := r.GetResult().(type) {
+	case *StateDiffResponse_Data:
+		return res.Data.UnpackProtoMessage()
+	case *StateDiffResponse_Error:
+		return nil, res.Error.UnpackProtoMessage()
+	}
+	return nil, fmt.Errorf("unexpected response type: %T", r.GetResult())
+}
+
+// ConsensusEvidence converters
+
+func (e *ConsensusEvidence) PackProtoMessage(ev *evidence.Evidence) error {
+	first, second, err := ev.MarshalMessages()
+	if err != nil {
+		return err
+	}
+	e.ShardId = uint32(ev.ShardId)
+	e.First = first
+	e.Second = second
+	return nil
+}
+
+func (e *ConsensusEvidence) UnpackProtoMessage() (*evidence.Evidence, error) {
+	return evidence.UnmarshalEvidence(types.ShardId(e.GetShardId()), e.GetFirst(), e.GetSecond())
+}
+
+// ConsensusEvidenceResponse converters
+
+func (r *ConsensusEvidenceResponse) PackProtoMessage(list []*evidence.Evidence, err error) error {
+	if err != nil {
+		r.Result = &ConsensusEvidenceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &ConsensusEvidenceList{Evidence: make([]*ConsensusEvidence, len(list))}
+	for i, ev := range list {
+		data.Evidence[i] = new(ConsensusEvidence)
+		if err := data.Evidence[i].PackProtoMessage(ev); err != nil {
+			return err
+		}
+	}
+	r.Result = &ConsensusEvidenceResponse_Data{Data: data}
+	return nil
+}
+
+func (r *ConsensusEvidenceResponse) UnpackProtoMessage() ([]*evidence.Evidence, error) {
+	switch res := r.GetResult().(type) {
+	case *ConsensusEvidenceResponse_Data:
+		list := make([]*evidence.Evidence, len(res.Data.GetEvidence()))
+		for i, e := range res.Data.GetEvidence() {
+			var err error
+			if list[i], err = e.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case *ConsensusEvidenceResponse_Error:
+		return nil, res.Error.UnpackProtoMessage()
+	}
+	return nil, fmt.Errorf("unexpected response type: %T", r.GetResult())
+}
@@ -0,0 +1,64 @@
+package pb
+
+import (
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// AccountOverrideRequest converters
+
+func (r *AccountOverrideRequest) PackProtoMessage(override *execution.AccountOverride) error {
+	data, err := override.MarshalSSZ()
+	if err != nil {
+		return err
+	}
+	r.OverrideSSZ = data
+	return nil
+}
+
+func (r *AccountOverrideRequest) UnpackProtoMessage() (*execution.AccountOverride, error) {
+	override := &execution.AccountOverride{}
+	if err := override.UnmarshalSSZ(r.GetOverrideSSZ()); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// ImpersonateRequest converters
+
+func (r *ImpersonateRequest) PackProtoMessage(address types.Address, enabled bool) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Enabled = enabled
+	return nil
+}
+
+func (r *ImpersonateRequest) UnpackProtoMessage() (types.Address, bool, error) {
+	return r.GetAddress().UnpackProtoMessage(), r.GetEnabled(), nil
+}
+
+// Uint64Request converters
+
+func (r *Uint64Request) PackProtoMessage(value uint64) error {
+	r.Value = value
+	return nil
+}
+
+func (r *Uint64Request) UnpackProtoMessage() (uint64, error) {
+	return r.GetValue(), nil
+}
+
+// EmptyResponse converters
+
+func (r *EmptyResponse) PackProtoMessage(_ struct{}, err error) error {
+	if err != nil {
+		r.Error = new(Error).PackProtoMessage(err)
+	}
+	return nil
+}
+
+func (r *EmptyResponse) UnpackProtoMessage() (struct{}, error) {
+	if r.GetError() != nil {
+		return struct{}{}, r.GetError().UnpackProtoMessage()
+	}
+	return struct{}{}, nil
+}
@@ -0,0 +1,74 @@
+package pb
+
+import (
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// SnapshotBlockRequest converters
+
+func (br *SnapshotBlockRequest) PackProtoMessage(
+	shardId types.ShardId, hash common.Hash, blockId types.BlockNumber,
+) error {
+	br.ShardId = uint32(shardId)
+	br.BlockId = uint64(blockId)
+	if hash.Empty() {
+		return nil
+	}
+	br.Hash = &Hash{}
+	return br.Hash.PackProtoMessage(hash)
+}
+
+func (br *SnapshotBlockRequest) UnpackProtoMessage() (types.ShardId, common.Hash, types.BlockNumber, error) {
+	shardId, blockId := types.ShardId(br.GetShardId()), types.BlockNumber(br.GetBlockId())
+	if br.GetHash() == nil {
+		return shardId, common.EmptyHash, blockId, nil
+	}
+	hash, err := br.GetHash().UnpackProtoMessage()
+	return shardId, hash, blockId, err
+}
+
+// SnapshotBlockResponse converters
+
+func (br *SnapshotBlockResponse) PackProtoMessage(block sszx.SSZEncodedData, err error) {
+	if err != nil {
+		br.Result = &SnapshotBlockResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return
+	}
+	br.Result = &SnapshotBlockResponse_BlockSSZ{BlockSSZ: block}
+}
+
+func (br *SnapshotBlockResponse) UnpackProtoMessage() (sszx.SSZEncodedData, error) {
+	switch br.GetResult().(type) {
+	case *SnapshotBlockResponse_Error:
+		return nil, br.GetError().UnpackProtoMessage()
+
+	case *SnapshotBlockResponse_BlockSSZ:
+		return br.GetBlockSSZ(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// SnapshotNodesResponse converters
+
+func (nr *SnapshotNodesResponse) PackProtoMessage(nodes [][]byte, err error) {
+	if err != nil {
+		nr.Result = &SnapshotNodesResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return
+	}
+	nr.Result = &SnapshotNodesResponse_Data{Data: &SnapshotNodes{Nodes: nodes}}
+}
+
+func (nr *SnapshotNodesResponse) UnpackProtoMessage() ([][]byte, error) {
+	switch nr.GetResult().(type) {
+	case *SnapshotNodesResponse_Error:
+		return nil, nr.GetError().UnpackProtoMessage()
+
+	case *SnapshotNodesResponse_Data:
+		return nr.GetData().GetNodes(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
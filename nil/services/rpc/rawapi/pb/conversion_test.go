@@ -6,6 +6,9 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/consensus/evidence"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -255,3 +258,120 @@ func TestErrorMap_PackUnpack(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, &Error{Message: "<invalid UTF-8 string>"}, val)
 }
+
+func TestStorageRangeResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	nextKey := common.HexToHash("0x03")
+	storageRange := &rawapitypes.StorageRange{
+		Storage: map[common.Hash]types.Uint256{
+			common.HexToHash("0x01"): *types.NewUint256(2),
+		},
+		NextKey: &nextKey,
+	}
+
+	for _, expected := range []*rawapitypes.StorageRange{
+		storageRange,
+		{Storage: map[common.Hash]types.Uint256{}},
+	} {
+		response := new(StorageRangeResponse)
+		require.NoError(t, response.PackProtoMessage(expected, nil))
+
+		data, err := proto.Marshal(response)
+		require.NoError(t, err)
+
+		var unpacked StorageRangeResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		actual, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestStateDiffResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	addr := types.HexToAddress("0x0001111111111111111111111111111111111111")
+	token := types.TokenId(types.HexToAddress("0x0001222222222222222222222222222222222222"))
+	seqno := types.Seqno(1)
+	nextSeqno := types.Seqno(2)
+	balance := types.NewValueFromUint64(100)
+	code := hexutil.Bytes{}
+	nextCode := hexutil.Bytes{1, 2, 3}
+
+	expected := &execution.StateDiff{
+		Pre: map[types.Address]*execution.AccountDiff{
+			addr: {
+				Balance: &balance,
+				Seqno:   &seqno,
+				Code:    &code,
+				Storage: map[common.Hash]common.Hash{common.HexToHash("0x01"): {}},
+				Tokens:  map[types.TokenId]types.Value{token: types.NewValueFromUint64(1)},
+			},
+		},
+		Post: map[types.Address]*execution.AccountDiff{
+			addr: {
+				Balance: &balance,
+				Seqno:   &nextSeqno,
+				Code:    &nextCode,
+				Storage: map[common.Hash]common.Hash{common.HexToHash("0x01"): common.HexToHash("0x02")},
+				Tokens:  map[types.TokenId]types.Value{token: types.NewValueFromUint64(2)},
+			},
+		},
+	}
+
+	response := new(StateDiffResponse)
+	require.NoError(t, response.PackProtoMessage(expected, nil))
+
+	data, err := proto.Marshal(response)
+	require.NoError(t, err)
+
+	var unpacked StateDiffResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	actual, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	// The empty code is kept to tell the account without code from the unchanged one.
+	require.NotNil(t, actual.Pre[addr].Code)
+	require.Nil(t, actual.Pre[addr].ExtSeqno)
+}
+
+func TestConsensusEvidenceResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	newPrepare := func(hash []byte) *protoIBFT.IbftMessage {
+		return &protoIBFT.IbftMessage{
+			View:      &protoIBFT.View{Height: 5, Round: 1},
+			From:      []byte{1, 2, 3},
+			Signature: []byte{4, 5, 6},
+			Type:      protoIBFT.MessageType_PREPARE,
+			Payload: &protoIBFT.IbftMessage_PrepareData{
+				PrepareData: &protoIBFT.PrepareMessage{ProposalHash: hash},
+			},
+		}
+	}
+	expected := &evidence.Evidence{
+		ShardId: types.BaseShardId,
+		First:   newPrepare([]byte{1}),
+		Second:  newPrepare([]byte{2}),
+	}
+
+	response := new(ConsensusEvidenceResponse)
+	require.NoError(t, response.PackProtoMessage([]*evidence.Evidence{expected}, nil))
+
+	data, err := proto.Marshal(response)
+	require.NoError(t, err)
+
+	var unpacked ConsensusEvidenceResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	actual, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, expected.ShardId, actual[0].ShardId)
+	assert.Equal(t, expected.Id(), actual[0].Id())
+	assert.True(t, proto.Equal(expected.First, actual[0].First))
+	assert.True(t, proto.Equal(expected.Second, actual[0].Second))
+}
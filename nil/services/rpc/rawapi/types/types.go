@@ -156,3 +156,10 @@ type SmartContract struct {
 	Tokens       map[types.TokenId]types.Value
 	AsyncContext map[types.TransactionIndex]types.AsyncContext
 }
+
+// StorageRange is a page of the contract storage ordered by keys.
+type StorageRange struct {
+	Storage map[common.Hash]types.Uint256
+	// NextKey is the first key of the next page, nil if there are no more entries.
+	NextKey *common.Hash
+}
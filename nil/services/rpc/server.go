@@ -50,6 +50,9 @@ func StartRpcServer(
 			nil,
 			cfg.HttpCompression)
 	}
+	if cfg.WSEnabled {
+		httpHandler = newWebsocketUpgradeHandler(httpHandler, srv.WebsocketHandler(cfg.HttpCORSDomain))
+	}
 
 	listener, httpAddr, err := http.StartHTTPEndpoint(httpEndpoint, &http.HttpEndpointConfig{
 		Timeouts: cfg.HTTPTimeouts,
@@ -75,3 +78,14 @@ func StartRpcServer(
 	<-ctx.Done()
 	return nil
 }
+
+// newWebsocketUpgradeHandler routes WebSocket upgrade requests to wsHandler and the rest to httpHandler.
+func newWebsocketUpgradeHandler(httpHandler, wsHandler net_http.Handler) net_http.Handler {
+	return net_http.HandlerFunc(func(w net_http.ResponseWriter, r *net_http.Request) {
+		if transport.IsWebsocket(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+}
@@ -4,6 +4,7 @@ import "fmt"
 
 var (
 	_ Error = new(methodNotFoundError)
+	_ Error = new(subscriptionNotFoundError)
 	_ Error = new(parseError)
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
@@ -21,6 +22,14 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("the method %s does not exist/is not available", e.method)
 }
 
+type subscriptionNotFoundError struct{ namespace, name string }
+
+func (e *subscriptionNotFoundError) ErrorCode() int { return -32601 }
+
+func (e *subscriptionNotFoundError) Error() string {
+	return fmt.Sprintf("no %q subscription in %s namespace", e.name, e.namespace)
+}
+
 // Invalid JSON was received by the server.
 type parseError struct{ message string }
 
@@ -44,6 +44,18 @@ type handler struct {
 
 	// requests with heavy params, logged only on trace level
 	heavyLogBlacklist map[string]struct{}
+
+	// subscriptions are available only on connections supporting notifications
+	allowSubscribe bool
+	subLock        sync.Mutex
+	serverSubs     map[ID]*Subscription
+}
+
+// callProc is the state of a single call. Subscriptions created during the call
+// are activated once the response is written.
+type callProc struct {
+	ctx       context.Context
+	notifiers []*Notifier
 }
 
 func HandleError(err error, stream *jsoniter.Stream) {
@@ -104,6 +116,23 @@ func newHandler(
 		slowLogThreshold:  rpcSlowLogThreshold,
 		slowLogBlacklist:  rpccfg.SlowLogBlackList,
 		heavyLogBlacklist: rpccfg.HeavyLogMethods,
+
+		serverSubs: make(map[ID]*Subscription),
+	}
+}
+
+// close cancels all requests of the connection and its subscriptions.
+func (h *handler) close() {
+	h.cancelRoot()
+	h.cancelServerSubscriptions(errClientQuit)
+}
+
+// activateNotifiers lets the subscriptions created by the call send notifications.
+func (h *handler) activateNotifiers(cp *callProc) {
+	for _, n := range cp.notifiers {
+		if err := n.activate(); err != nil {
+			h.logger.Debug().Err(err).Msg("Failed to send subscription notifications")
+		}
 	}
 }
 
@@ -185,6 +214,7 @@ func (h *handler) handleBatch(msgs []*Message) {
 	// Process calls on a goroutine because they may block indefinitely:
 	// All goroutines will place results right to this array. Because requests order must match reply orders.
 	answers := make([]any, len(msgs))
+	calls := make([]*callProc, len(msgs))
 	// Bounded parallelism pattern explanation https://blog.golang.org/pipelines#TOC_9.
 	boundedConcurrency := make(chan struct{}, h.maxBatchConcurrency)
 	defer close(boundedConcurrency)
@@ -200,7 +230,8 @@ func (h *handler) handleBatch(msgs []*Message) {
 
 			buf := bytes.NewBuffer(nil)
 			stream := jsoniter.NewStream(jsoniter.ConfigDefault, buf, 4096)
-			if res := h.handleCallMsg(h.rootCtx, msgs[i], stream); res != nil {
+			calls[i] = &callProc{ctx: h.rootCtx}
+			if res := h.handleCallMsg(calls[i], msgs[i], stream); res != nil {
 				answers[i] = res
 			}
 			_ = stream.Flush()
@@ -213,21 +244,27 @@ func (h *handler) handleBatch(msgs []*Message) {
 	if len(answers) > 0 {
 		_ = h.conn.WriteJSON(h.rootCtx, answers)
 	}
+	for _, cp := range calls {
+		h.activateNotifiers(cp)
+	}
 }
 
 // handleMsg handles a single message.
 func (h *handler) handleMsg(msg *Message) {
 	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096)
-	answer := h.handleCallMsg(h.rootCtx, msg, stream)
+	cp := &callProc{ctx: h.rootCtx}
+	answer := h.handleCallMsg(cp, msg, stream)
 	if answer != nil {
 		buffer, _ := json.Marshal(answer) //nolint: errchkjson
 		_, _ = stream.Write(buffer)
 	}
 	_ = h.conn.WriteJSON(h.rootCtx, json.RawMessage(stream.Buffer()))
+	h.activateNotifiers(cp)
 }
 
 // handleCallMsg executes a call message and returns the answer.
-func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoniter.Stream) *Message {
+func (h *handler) handleCallMsg(cp *callProc, msg *Message, stream *jsoniter.Stream) *Message {
+	ctx := cp.ctx
 	start := time.Now()
 	switch {
 	case msg.isCall():
@@ -242,7 +279,7 @@ func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoni
 			}
 		}
 
-		resp := h.handleCall(ctx, msg, stream)
+		resp := h.handleCall(cp, msg, stream)
 		requestDuration := time.Since(start)
 
 		if doSlowLog {
@@ -270,7 +307,15 @@ func (h *handler) handleCallMsg(ctx context.Context, msg *Message, stream *jsoni
 }
 
 // handleCall processes method calls.
-func (h *handler) handleCall(ctx context.Context, msg *Message, stream *jsoniter.Stream) *Message {
+func (h *handler) handleCall(cp *callProc, msg *Message, stream *jsoniter.Stream) *Message {
+	switch {
+	case msg.isSubscribe():
+		return h.handleSubscribe(cp, msg)
+	case msg.isUnsubscribe():
+		return h.handleUnsubscribe(msg)
+	}
+
+	ctx := cp.ctx
 	callb := h.reg.callback(msg.Method)
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
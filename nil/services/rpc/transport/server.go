@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -22,7 +23,8 @@ import (
 const (
 	MetadataApi             = "rpc"
 	defaultBatchConcurrency = 2
-	defaultBatchLimit       = 100
+	// DefaultBatchLimit is the default maximum number of requests in a batch, see SetBatchLimit.
+	DefaultBatchLimit = 100
 )
 
 type ContextKey string
@@ -47,6 +49,9 @@ type Server struct {
 	logger              logging.Logger
 	rpcSlowLogThreshold time.Duration
 	mh                  *metricsHandler
+
+	codecsMu sync.Mutex
+	codecs   map[ServerCodec]struct{} // long-living connections, closed on Stop
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -67,10 +72,11 @@ func NewServer(
 		batchConcurrency:    defaultBatchConcurrency,
 		traceRequests:       traceRequests,
 		debugSingleRequest:  debugSingleRequest,
-		batchLimit:          defaultBatchLimit,
+		batchLimit:          DefaultBatchLimit,
 		keepHeaders:         keepHeaders,
 		logger:              logger,
 		rpcSlowLogThreshold: rpcSlowLogThreshold,
+		codecs:              make(map[ServerCodec]struct{}),
 		mh: &metricsHandler{
 			meter:  meter,
 			failed: failedCounter,
@@ -163,6 +169,74 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		}
 		return
 	}
+	s.handleRequests(ctx, h, codec, reqs, batch)
+}
+
+// ServeCodec reads incoming requests from the codec, calls the appropriate callbacks and writes
+// the responses back using the same codec. Unlike ServeSingleRequest, it serves the connection
+// until it is closed, so subscriptions are available. It blocks until the connection is closed
+// or the server is stopped.
+func (s *Server) ServeCodec(ctx context.Context, codec ServerCodec) {
+	defer codec.Close()
+
+	// Don't serve if the server is stopped.
+	if !s.trackCodec(codec) {
+		return
+	}
+	defer s.untrackCodec(codec)
+
+	h := newHandler(
+		ctx,
+		codec,
+		&s.services,
+		s.batchConcurrency,
+		s.traceRequests,
+		s.logger,
+		s.rpcSlowLogThreshold,
+		s.mh)
+	h.allowSubscribe = true
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer h.close()
+
+	for atomic.LoadInt32(&s.run) == 1 {
+		reqs, batch, err := codec.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				_ = codec.WriteJSON(ctx, errorMessage(&invalidMessageError{"parse error"}))
+			}
+			return
+		}
+
+		// Requests are processed concurrently, so that long calls don't block the connection.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleRequests(ctx, h, codec, reqs, batch)
+		}()
+	}
+}
+
+func (s *Server) trackCodec(codec ServerCodec) bool {
+	s.codecsMu.Lock()
+	defer s.codecsMu.Unlock()
+
+	if atomic.LoadInt32(&s.run) == 0 {
+		return false
+	}
+	s.codecs[codec] = struct{}{}
+	return true
+}
+
+func (s *Server) untrackCodec(codec ServerCodec) {
+	s.codecsMu.Lock()
+	defer s.codecsMu.Unlock()
+
+	delete(s.codecs, codec)
+}
+
+func (s *Server) handleRequests(ctx context.Context, h *handler, codec ServerCodec, reqs []*Message, batch bool) {
 	if batch {
 		if s.batchLimit > 0 && len(reqs) > s.batchLimit {
 			_ = codec.WriteJSON(ctx, errorMessage(fmt.Errorf(
@@ -178,8 +252,14 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 // Stop stops reading new requests, waits for stopPendingRequestTimeout to allow pending
 // requests to finish, then closes all codecs that will cancel pending requests.
 func (s *Server) Stop() {
+	s.codecsMu.Lock()
+	defer s.codecsMu.Unlock()
+
 	if atomic.CompareAndSwapInt32(&s.run, 1, 0) {
 		s.logger.Info().Msg("RPC server shutting down")
+		for codec := range s.codecs {
+			codec.Close()
+		}
 	}
 }
 
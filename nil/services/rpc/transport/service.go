@@ -30,19 +30,21 @@ type serviceRegistry struct {
 
 // service represents a registered object.
 type service struct {
-	name      string               // name for service
-	callbacks map[string]*callback // registered handlers
+	name          string               // name for service
+	callbacks     map[string]*callback // registered handlers
+	subscriptions map[string]*callback // available subscriptions/notifications
 }
 
 // callback is a method callback that was registered in the server
 type callback struct {
-	fn         reflect.Value  // the function
-	rcvr       reflect.Value  // receiver object of method, set if fn is method
-	argTypes   []reflect.Type // input argument types
-	hasCtx     bool           // method's first argument is a context (not included in argTypes)
-	errPos     int            // err return idx, of -1 when method cannot return error
-	streamable bool           // support JSON streaming (more efficient for large responses)
-	logger     logging.Logger
+	fn          reflect.Value  // the function
+	rcvr        reflect.Value  // receiver object of method, set if fn is method
+	argTypes    []reflect.Type // input argument types
+	hasCtx      bool           // method's first argument is a context (not included in argTypes)
+	errPos      int            // err return idx, of -1 when method cannot return error
+	streamable  bool           // support JSON streaming (more efficient for large responses)
+	isSubscribe bool           // true if this is a subscription callback
+	logger      logging.Logger
 }
 
 func (r *serviceRegistry) registerName(name string, rcvr any) error {
@@ -63,13 +65,18 @@ func (r *serviceRegistry) registerName(name string, rcvr any) error {
 	svc, ok := r.services[name]
 	if !ok {
 		svc = service{
-			name:      name,
-			callbacks: make(map[string]*callback),
+			name:          name,
+			callbacks:     make(map[string]*callback),
+			subscriptions: make(map[string]*callback),
 		}
 		r.services[name] = svc
 	}
 	for name, cb := range callbacks {
-		svc.callbacks[name] = cb
+		if cb.isSubscribe {
+			svc.subscriptions[name] = cb
+		} else {
+			svc.callbacks[name] = cb
+		}
 	}
 	return nil
 }
@@ -85,6 +92,13 @@ func (r *serviceRegistry) callback(method string) *callback {
 	return r.services[elem[0]].callbacks[elem[1]]
 }
 
+// subscription returns a subscription callback in the given service.
+func (r *serviceRegistry) subscription(service, name string) *callback {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.services[service].subscriptions[name]
+}
+
 // suitableCallbacks iterates over the methods of the given type. It determines if a method
 // satisfies the criteria for a RPC callback and adds it to the collection of callbacks.
 // See server documentation for a summary of these criteria.
@@ -125,6 +139,12 @@ func newCallback(receiver, fn reflect.Value, name string, logger logging.Logger)
 			"Cannot register RPC callback [%s] - maximum 2 return values are allowed, got %d", name, len(outs)))
 		return nil
 	}
+	// Subscription callbacks return the subscription created by the notifier from the context.
+	if c.hasCtx && len(outs) == 2 && outs[0] == subscriptionType && isErrorType(outs[1]) {
+		c.isSubscribe = true
+		c.errPos = 1
+		return c
+	}
 	// If an error is returned, it must be the last returned value.
 	switch {
 	case len(outs) == 1 && isErrorType(outs[0]):
@@ -0,0 +1,265 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+)
+
+var (
+	// ErrNotificationsUnsupported is returned when the connection doesn't support notifications, e.g. plain HTTP.
+	ErrNotificationsUnsupported = errors.New("notifications not supported")
+	// ErrSubscriptionNotFound is returned when the subscription for the given id is not found.
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	errClientQuit = errors.New("client quit")
+)
+
+const (
+	subscribeMethodSuffix    = "_subscribe"
+	unsubscribeMethodSuffix  = "_unsubscribe"
+	notificationMethodSuffix = "_subscription"
+)
+
+var (
+	subscriptionType = reflect.TypeOf((*Subscription)(nil))
+	stringType       = reflect.TypeOf("")
+)
+
+// ID identifies an RPC subscription.
+type ID string
+
+// NewID returns a new random subscription ID.
+func NewID() ID {
+	var id [16]byte
+	_, err := rand.Read(id[:])
+	check.PanicIfErr(err)
+	return ID("0x" + hex.EncodeToString(id[:]))
+}
+
+type notifierKey struct{}
+
+// NotifierFromContext returns the Notifier of the connection the subscription request came from.
+// It is available only inside subscription callbacks and only on connections supporting notifications.
+func NotifierFromContext(ctx context.Context) (*Notifier, bool) {
+	n, ok := ctx.Value(notifierKey{}).(*Notifier)
+	return n, ok
+}
+
+// Notifier is tied to an RPC connection that supports subscriptions.
+// Subscription callbacks use it to create the subscription and to send notifications to the client.
+type Notifier struct {
+	h         *handler
+	namespace string
+
+	mu           sync.Mutex
+	sub          *Subscription
+	buffer       []any
+	callReturned bool
+	activated    bool
+}
+
+// CreateSubscription returns a new subscription that is coupled to the RPC connection.
+// By default, subscriptions are inactive and notifications are buffered until the subscription
+// ID is sent to the client. Only one subscription can be created per subscription request.
+func (n *Notifier) CreateSubscription() *Subscription {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	check.PanicIfNotf(n.sub == nil, "can't create multiple subscriptions with Notifier")
+	check.PanicIfNotf(!n.callReturned, "can't create subscription after subscribe call has returned")
+
+	n.sub = &Subscription{ID: NewID(), namespace: n.namespace, err: make(chan error, 1)}
+	return n.sub
+}
+
+// Notify sends a notification for the subscription with the given id to the client.
+func (n *Notifier) Notify(id ID, data any) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	check.PanicIfNotf(n.sub != nil, "can't Notify before subscription is created")
+	check.PanicIfNotf(n.sub.ID == id, "Notify with wrong ID")
+
+	if n.activated {
+		return n.send(data)
+	}
+	n.buffer = append(n.buffer, data)
+	return nil
+}
+
+// Closed returns a channel which is closed when the RPC connection is closed.
+func (n *Notifier) Closed() <-chan any {
+	return n.h.conn.Closed()
+}
+
+// takeSubscription returns the subscription (if one has been created).
+// No subscription can be created after this call.
+func (n *Notifier) takeSubscription() *Subscription {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.callReturned = true
+	return n.sub
+}
+
+// activate is called after the subscription ID was sent to the client.
+// Notifications buffered before that are sent out.
+func (n *Notifier) activate() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, data := range n.buffer {
+		if err := n.send(data); err != nil {
+			return err
+		}
+	}
+	n.buffer = nil
+	n.activated = true
+	return nil
+}
+
+func (n *Notifier) send(data any) error {
+	params, err := json.Marshal(&subscriptionResult{ID: n.sub.ID, Result: data})
+	if err != nil {
+		return err
+	}
+	return n.h.conn.WriteJSON(n.h.rootCtx, &Message{
+		Version: Version,
+		Method:  n.namespace + notificationMethodSuffix,
+		Params:  params,
+	})
+}
+
+// Subscription is created by a Notifier and tied to it.
+// The service can use Err() to learn that the client has unsubscribed or disconnected.
+type Subscription struct {
+	ID        ID
+	namespace string
+	err       chan error // closed on unsubscribe
+}
+
+// Err returns a channel that is closed when the client sends an unsubscribe request
+// or the connection is closed.
+func (s *Subscription) Err() <-chan error {
+	return s.err
+}
+
+// MarshalJSON marshals a subscription as its ID.
+func (s *Subscription) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ID)
+}
+
+type subscriptionResult struct {
+	ID     ID  `json:"subscription"`
+	Result any `json:"result,omitempty"`
+}
+
+func (msg *Message) isSubscribe() bool {
+	return strings.HasSuffix(msg.Method, subscribeMethodSuffix)
+}
+
+func (msg *Message) isUnsubscribe() bool {
+	return strings.HasSuffix(msg.Method, unsubscribeMethodSuffix)
+}
+
+func (msg *Message) namespace() string {
+	namespace, _, _ := strings.Cut(msg.Method, serviceMethodSeparator)
+	return namespace
+}
+
+// handleSubscribe processes *_subscribe method calls.
+func (h *handler) handleSubscribe(cp *callProc, msg *Message) *Message {
+	if !h.allowSubscribe {
+		return msg.errorResponse(ErrNotificationsUnsupported)
+	}
+
+	// The first parameter is the name of the subscription, the rest are passed to the callback.
+	namespace := msg.namespace()
+	name, err := parseSubscriptionName(msg.Params)
+	if err != nil {
+		return msg.errorResponse(&InvalidParamsError{err.Error()})
+	}
+	callb := h.reg.subscription(namespace, name)
+	if callb == nil {
+		return msg.errorResponse(&subscriptionNotFoundError{namespace: namespace, name: name})
+	}
+
+	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
+	args, err := parsePositionalArguments(msg.Params, argTypes)
+	if err != nil {
+		return msg.errorResponse(&InvalidParamsError{err.Error()})
+	}
+	args = args[1:]
+
+	n := &Notifier{h: h, namespace: namespace}
+	ctx := context.WithValue(cp.ctx, notifierKey{}, n)
+	_, err = callb.call(ctx, msg.Method, args, nil)
+	sub := n.takeSubscription()
+	if err != nil {
+		return msg.errorResponse(err)
+	}
+	if sub == nil {
+		return msg.errorResponse(fmt.Errorf("subscription callback %s did not create a subscription", name))
+	}
+
+	h.subLock.Lock()
+	h.serverSubs[sub.ID] = sub
+	h.subLock.Unlock()
+
+	cp.notifiers = append(cp.notifiers, n)
+	return msg.response(sub.ID)
+}
+
+func parseSubscriptionName(rawArgs json.RawMessage) (string, error) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(rawArgs, &params); err != nil {
+		return "", fmt.Errorf("invalid params: %w", err)
+	}
+	var name string
+	if len(params) == 0 || json.Unmarshal(params[0], &name) != nil {
+		return "", errors.New("expected subscription name as the first argument")
+	}
+	return name, nil
+}
+
+// handleUnsubscribe processes *_unsubscribe method calls.
+func (h *handler) handleUnsubscribe(msg *Message) *Message {
+	args, err := parsePositionalArguments(msg.Params, []reflect.Type{stringType})
+	if err != nil {
+		return msg.errorResponse(&InvalidParamsError{err.Error()})
+	}
+	id := ID(args[0].String())
+
+	h.subLock.Lock()
+	sub, ok := h.serverSubs[id]
+	if ok && sub.namespace == msg.namespace() {
+		delete(h.serverSubs, id)
+	}
+	h.subLock.Unlock()
+
+	if !ok || sub.namespace != msg.namespace() {
+		return msg.errorResponse(ErrSubscriptionNotFound)
+	}
+	close(sub.err)
+	return msg.response(true)
+}
+
+// cancelServerSubscriptions notifies all subscriptions of the connection that it is closed.
+func (h *handler) cancelServerSubscriptions(err error) {
+	h.subLock.Lock()
+	defer h.subLock.Unlock()
+
+	for id, sub := range h.serverSubs {
+		sub.err <- err
+		close(sub.err)
+		delete(h.serverSubs, id)
+	}
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsReadBuffer       = 1024
+	wsWriteBuffer      = 1024
+	wsPingInterval     = 30 * time.Second
+	wsPingWriteTimeout = 5 * time.Second
+	wsMessageSizeLimit = 32 * 1024 * 1024
+)
+
+// WebsocketHandler returns a handler that serves JSON-RPC over WebSocket connections.
+// Origins of browser clients are checked against allowedOrigins ("*" allows any origin);
+// if the list is empty, only same-origin requests are accepted.
+func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  wsReadBuffer,
+		WriteBufferSize: wsWriteBuffer,
+		CheckOrigin:     wsOriginChecker(allowedOrigins),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			s.logger.Debug().Err(err).Msg("WebSocket upgrade failed")
+			return
+		}
+
+		headers := http.Header{}
+		for _, h := range s.keepHeaders {
+			headers.Add(h, r.Header.Get(h))
+		}
+		ctx := context.WithValue(r.Context(), HeadersContextKey, headers)
+
+		s.ServeCodec(ctx, newWebsocketCodec(conn))
+	})
+}
+
+// IsWebsocket checks whether the request asks for a WebSocket upgrade.
+func IsWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+func wsOriginChecker(allowedOrigins []string) func(*http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		// The default checker of the upgrader accepts same-origin requests only.
+		return nil
+	}
+
+	origins := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			return func(*http.Request) bool { return true }
+		}
+		origins[strings.ToLower(origin)] = struct{}{}
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Non-browser clients don't send the header.
+			return true
+		}
+		_, ok := origins[strings.ToLower(origin)]
+		return ok
+	}
+}
+
+// websocketCodec is a jsonCodec over a WebSocket connection that keeps the connection alive with pings.
+type websocketCodec struct {
+	*jsonCodec
+	conn *websocket.Conn
+	wg   sync.WaitGroup
+}
+
+func newWebsocketCodec(conn *websocket.Conn) *websocketCodec {
+	conn.SetReadLimit(wsMessageSizeLimit)
+
+	codec := &websocketCodec{
+		jsonCodec: &jsonCodec{
+			remote:  conn.RemoteAddr().String(),
+			closeCh: make(chan any),
+			encode:  conn.WriteJSON,
+			decode:  conn.ReadJSON,
+			conn:    conn,
+		},
+		conn: conn,
+	}
+	codec.wg.Add(1)
+	go codec.pingLoop()
+	return codec
+}
+
+func (c *websocketCodec) Close() {
+	c.jsonCodec.Close()
+	c.wg.Wait()
+}
+
+func (c *websocketCodec) pingLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Closed():
+			return
+		case <-ticker.C:
+			// WriteControl is safe to use concurrently with the other writes.
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsPingWriteTimeout)); err != nil {
+				// The connection is broken, the read loop will notice it.
+				_ = c.conn.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type counterService struct{}
+
+func (s *counterService) Echo(_ context.Context, v int) (int, error) {
+	return v, nil
+}
+
+func (s *counterService) Counter(ctx context.Context, n int) (*Subscription, error) {
+	notifier, ok := NotifierFromContext(ctx)
+	if !ok {
+		return nil, ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := range n {
+			if err := notifier.Notify(sub.ID, i); err != nil {
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func TestWebsocketSubscription(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	require.NoError(t, server.RegisterName("test", &counterService{}))
+	defer server.Stop()
+
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	call := func(id int, method string, params ...any) {
+		t.Helper()
+		rawParams, err := json.Marshal(params)
+		require.NoError(t, err)
+		idJson, err := json.Marshal(id)
+		require.NoError(t, err)
+		require.NoError(t, conn.WriteJSON(&Message{Version: Version, ID: idJson, Method: method, Params: rawParams}))
+	}
+	read := func() *Message {
+		t.Helper()
+		var msg Message
+		require.NoError(t, conn.ReadJSON(&msg))
+		return &msg
+	}
+
+	// Regular calls work as usual.
+	call(1, "test_echo", 5)
+	msg := read()
+	require.Nil(t, msg.Error)
+	require.JSONEq(t, "5", string(msg.Result))
+
+	call(2, "test_subscribe", "unknown")
+	msg = read()
+	require.NotNil(t, msg.Error)
+	require.Contains(t, msg.Error.Message, "no \"unknown\" subscription in test namespace")
+
+	// The subscription ID is sent before any notification.
+	call(3, "test_subscribe", "counter", 3)
+	msg = read()
+	require.Nil(t, msg.Error)
+	var subId ID
+	require.NoError(t, json.Unmarshal(msg.Result, &subId))
+	require.NotEmpty(t, subId)
+
+	for i := range 3 {
+		msg = read()
+		require.Equal(t, "test_subscription", msg.Method)
+		var res subscriptionResult
+		require.NoError(t, json.Unmarshal(msg.Params, &res))
+		require.Equal(t, subId, res.ID)
+		require.EqualValues(t, i, res.Result)
+	}
+
+	call(4, "test_unsubscribe", subId)
+	msg = read()
+	require.Nil(t, msg.Error)
+	require.JSONEq(t, "true", string(msg.Result))
+
+	call(5, "test_unsubscribe", subId)
+	msg = read()
+	require.NotNil(t, msg.Error)
+	require.Equal(t, ErrSubscriptionNotFound.Error(), msg.Error.Message)
+}
+
+func TestSubscriptionOverHttp(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	require.NoError(t, server.RegisterName("test", &counterService{}))
+	defer server.Stop()
+
+	h := newHandler(t.Context(), nil, &server.services, 1, false, server.logger, 0, server.mh)
+	defer h.close()
+	msg := h.handleCallMsg(&callProc{ctx: t.Context()}, &Message{
+		Version: Version,
+		ID:      json.RawMessage("1"),
+		Method:  "test_subscribe",
+		Params:  json.RawMessage(`["counter", 1]`),
+	}, nil)
+	require.NotNil(t, msg.Error)
+	require.Equal(t, ErrNotificationsUnsupported.Error(), msg.Error.Message)
+}
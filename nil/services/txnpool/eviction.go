@@ -0,0 +1,94 @@
+package txnpool
+
+import (
+	"container/heap"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// EvictionQueue keeps the transactions with the highest seqno of their receivers ordered by evictionLess,
+// so that the candidate for eviction from the full pool is found without scanning the pool.
+type EvictionQueue struct {
+	txns []*metaTxn
+	// last maps the receivers to their transactions in the queue.
+	last map[types.Address]*metaTxn
+}
+
+func NewEvictionQueue() *EvictionQueue {
+	return &EvictionQueue{last: make(map[types.Address]*metaTxn)}
+}
+
+func (q *EvictionQueue) Len() int {
+	return len(q.txns)
+}
+
+func (q *EvictionQueue) Less(i, j int) bool {
+	return evictionLess(q.txns[i], q.txns[j])
+}
+
+func (q *EvictionQueue) Swap(i, j int) {
+	if i != j {
+		q.txns[i], q.txns[j] = q.txns[j], q.txns[i]
+		q.txns[i].evictIndex = i
+		q.txns[j].evictIndex = j
+	}
+}
+
+func (q *EvictionQueue) Push(x any) {
+	txn, ok := x.(*metaTxn)
+	check.PanicIfNot(ok)
+	txn.evictIndex = len(q.txns)
+	q.txns = append(q.txns, txn)
+}
+
+func (q *EvictionQueue) Pop() any {
+	old := q.txns
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil // avoid memory leak
+	q.txns = old[0 : n-1]
+	item.evictIndex = -1
+	return item
+}
+
+// set makes txn the queued transaction of the receiver. If txn is nil, the receiver is removed from the queue.
+func (q *EvictionQueue) set(to types.Address, txn *metaTxn) {
+	prev := q.last[to]
+	if prev == txn {
+		return
+	}
+	if prev != nil {
+		check.PanicIfNotf(prev.evictIndex >= 0 && prev.evictIndex < len(q.txns),
+			"prev.evictIndex is out of range: prev.evictIndex=%d, len(q.txns)=%d", prev.evictIndex, len(q.txns))
+		heap.Remove(q, prev.evictIndex)
+		delete(q.last, to)
+	}
+	if txn != nil {
+		heap.Push(q, txn)
+		q.last[to] = txn
+	}
+}
+
+// fix restores the order after the eviction priorities of the transactions are changed.
+func (q *EvictionQueue) fix() {
+	heap.Init(q)
+}
+
+// candidate returns the first transaction to evict that is not sent to the excluded receiver.
+// The queue holds a single transaction per receiver, so it's either the root of the heap or one of its children.
+func (q *EvictionQueue) candidate(excluded types.Address) *metaTxn {
+	if len(q.txns) == 0 {
+		return nil
+	}
+	if q.txns[0].To != excluded {
+		return q.txns[0]
+	}
+	var res *metaTxn
+	for _, txn := range q.txns[1:min(len(q.txns), 3)] {
+		if res == nil || evictionLess(txn, res) {
+			res = txn
+		}
+	}
+	return res
+}
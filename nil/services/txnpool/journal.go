@@ -0,0 +1,179 @@
+package txnpool
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// journal persists the pool transactions to the database, so that they survive node restarts.
+// Every entry is keyed by the transaction hash and holds the time the transaction entered the pool
+// followed by the SSZ-encoded transaction.
+type journal struct {
+	db      db.DB
+	shardId types.ShardId
+}
+
+type journalEntry struct {
+	txn     *types.Transaction
+	addedAt time.Time
+}
+
+const journalTimestampSize = 8
+
+func newJournal(database db.DB, shardId types.ShardId) *journal {
+	return &journal{db: database, shardId: shardId}
+}
+
+// write applies the changes of the pool in a single transaction.
+// The transactions are written to the journal, the hashes mapped to nil are removed from it.
+func (j *journal) write(ctx context.Context, changes map[common.Hash]*metaTxn) error {
+	return j.update(ctx, func(tx db.RwTx) error {
+		for hash, txn := range changes {
+			if txn == nil {
+				if err := tx.DeleteFromShard(j.shardId, db.TxnPoolJournalTable, hash.Bytes()); err != nil {
+					return err
+				}
+				continue
+			}
+			value, err := encodeJournalEntry(txn)
+			if err != nil {
+				return err
+			}
+			if err := tx.PutToShard(j.shardId, db.TxnPoolJournalTable, hash.Bytes(), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (j *journal) remove(ctx context.Context, hashes ...common.Hash) error {
+	return j.update(ctx, func(tx db.RwTx) error {
+		for _, hash := range hashes {
+			if err := tx.DeleteFromShard(j.shardId, db.TxnPoolJournalTable, hash.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (j *journal) update(ctx context.Context, f func(tx db.RwTx) error) error {
+	tx, err := j.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// load returns all journaled transactions. Entries that cannot be decoded are dropped from the journal.
+func (j *journal) load(ctx context.Context) ([]journalEntry, error) {
+	tx, err := j.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	iter, err := tx.RangeByShard(j.shardId, db.TxnPoolJournalTable, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var entries []journalEntry
+	var broken []common.Hash
+	for iter.HasNext() {
+		key, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		entry, err := decodeJournalEntry(value)
+		if err != nil {
+			broken = append(broken, common.BytesToHash(key))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(broken) > 0 {
+		if err := j.remove(ctx, broken...); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// committedSeqnos returns the external seqnos of the accounts in the last block of the shard.
+// The transactions to the accounts with lower seqnos are committed already.
+func (j *journal) committedSeqnos(
+	ctx context.Context, addresses []types.Address,
+) (map[types.Address]types.Seqno, error) {
+	tx, err := j.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res := make(map[types.Address]types.Seqno)
+	block, _, err := db.ReadLastBlock(tx, j.shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := execution.NewDbContractTrieReader(tx, j.shardId)
+	contracts.SetRootHash(block.SmartContractsRoot)
+	for _, addr := range addresses {
+		if _, ok := res[addr]; ok {
+			continue
+		}
+		contract, err := contracts.Fetch(addr.Hash())
+		if errors.Is(err, db.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read account %s: %w", addr, err)
+		}
+		res[addr] = contract.ExtSeqno
+	}
+	return res, nil
+}
+
+func encodeJournalEntry(txn *metaTxn) ([]byte, error) {
+	data, err := txn.MarshalSSZ()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal txn: %w", err)
+	}
+	value := binary.BigEndian.AppendUint64(
+		make([]byte, 0, journalTimestampSize+len(data)), uint64(txn.addedAt.UnixNano()))
+	return append(value, data...), nil
+}
+
+func decodeJournalEntry(value []byte) (journalEntry, error) {
+	if len(value) < journalTimestampSize {
+		return journalEntry{}, errors.New("journal entry is too short")
+	}
+	txn := &types.Transaction{}
+	if err := txn.UnmarshalSSZ(value[journalTimestampSize:]); err != nil {
+		return journalEntry{}, err
+	}
+	return journalEntry{
+		txn:     txn,
+		addedAt: time.Unix(0, int64(binary.BigEndian.Uint64(value[:journalTimestampSize]))),
+	}, nil
+}
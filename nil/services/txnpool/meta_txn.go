@@ -1,6 +1,8 @@
 package txnpool
 
 import (
+	"time"
+
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -9,7 +11,11 @@ type metaTxn struct {
 	*types.TxnWithHash
 	effectivePriorityFee types.Value
 	bestIndex            int
-	valid                bool
+	// evictIndex is the index in the EvictionQueue or -1 if the transaction isn't there.
+	evictIndex int
+	valid      bool
+	// addedAt is the time the transaction entered the pool.
+	addedAt time.Time
 }
 
 func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
@@ -19,6 +25,7 @@ func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
 		effectivePriorityFee: effectivePriorityFee,
 		valid:                valid,
 		bestIndex:            -1,
+		evictIndex:           -1,
 	}
 }
 
@@ -27,7 +34,9 @@ func (m *metaTxn) Clone() *metaTxn {
 		TxnWithHash:          m.TxnWithHash,
 		effectivePriorityFee: m.effectivePriorityFee,
 		bestIndex:            m.bestIndex,
+		evictIndex:           m.evictIndex,
 		valid:                m.valid,
+		addedAt:              m.addedAt,
 	}
 }
 
@@ -0,0 +1,57 @@
+package txnpool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// Snapshot is the content of the pool saved to be restored later by Revert.
+type Snapshot struct {
+	baseFee  types.Value
+	seqnoMap map[types.Address]types.Seqno
+	txns     []*types.Transaction
+}
+
+// Snapshot saves the content of the pool.
+func (p *TxnPool) Snapshot() *Snapshot {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	res := &Snapshot{
+		baseFee:  p.baseFee,
+		seqnoMap: maps.Clone(p.seqnoMap),
+		txns:     make([]*types.Transaction, 0, p.all.tree.Len()),
+	}
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		res.txns = append(res.txns, txn.Transaction)
+		return true
+	})
+	return res
+}
+
+// Revert replaces the content of the pool with the snapshot.
+// It is used by the development API to roll the pool back along with the state of the shard.
+func (p *TxnPool) Revert(_ context.Context, snapshot *Snapshot) error {
+	p.lock.Lock()
+	defer p.unlockAndFlushJournal()
+
+	for _, txn := range p.byHash {
+		p.journalLocked(txn.Hash(), nil)
+	}
+
+	p.baseFee = snapshot.baseFee
+	p.seqnoMap = maps.Clone(snapshot.seqnoMap)
+	p.byHash = make(map[string]*metaTxn)
+	p.all = NewBySenderAndSeqno(p.logger)
+	p.queue = &TxnQueue{}
+
+	for _, txn := range snapshot.txns {
+		if reason := p.addLocked(newMetaTxn(txn, p.baseFee)); reason != NotSet {
+			return fmt.Errorf("failed to restore transaction %s: %s", txn.Hash(), reason)
+		}
+	}
+	return nil
+}
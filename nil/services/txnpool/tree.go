@@ -42,18 +42,26 @@ func NewBySenderAndSeqno(logger logging.Logger) *ByReceiverAndSeqno {
 }
 
 func (b *ByReceiverAndSeqno) seqno(to types.Address) (seqno types.Seqno, ok bool) {
+	if txn := b.last(to); txn != nil {
+		return txn.Seqno, true
+	}
+	return 0, false
+}
+
+// last returns the receiver's transaction with the highest seqno.
+func (b *ByReceiverAndSeqno) last(to types.Address) *metaTxn {
 	s := b.search
 	s.To = to
 	s.Seqno = math.MaxUint64
 
+	var res *metaTxn
 	b.tree.DescendLessOrEqual(s, func(txn *metaTxn) bool {
 		if txn.To.Equal(to) {
-			seqno = txn.Seqno
-			ok = true
+			res = txn
 		}
 		return false
 	})
-	return seqno, ok
+	return res
 }
 
 func (b *ByReceiverAndSeqno) ascendAll(f func(*metaTxn) bool) {
@@ -72,7 +80,7 @@ func (b *ByReceiverAndSeqno) ascend(to types.Address, f func(*metaTxn) bool) {
 	})
 }
 
-func (b *ByReceiverAndSeqno) count(to types.Address) int { //nolint:unused
+func (b *ByReceiverAndSeqno) count(to types.Address) int {
 	return b.toTxnCount[to]
 }
 
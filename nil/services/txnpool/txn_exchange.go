@@ -30,3 +30,30 @@ func PublishPendingTransaction(
 
 	return networkManager.PubSub().Publish(ctx, topicPendingTransactions(shardId), data)
 }
+
+// validateNetworkTransaction checks the transaction received from the network before it is relayed further.
+// Only the checks that don't depend on the state of the node are fatal, the outdated transactions are just dropped.
+func (p *TxnPool) validateNetworkTransaction(
+	_ context.Context,
+	_ network.PeerID,
+	data []byte,
+) network.ValidationResult {
+	txn := &types.Transaction{}
+	if err := txn.UnmarshalSSZ(data); err != nil {
+		return network.ValidationReject
+	}
+	if !txn.IsExternal() ||
+		txn.To.ShardId() != p.cfg.ShardId ||
+		txn.ChainId != types.DefaultChainId ||
+		txn.MaxFeePerGas.IsZero() {
+		return network.ValidationReject
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if seqno, ok := p.seqnoMap[txn.To]; ok && seqno > txn.Seqno {
+		return network.ValidationIgnore
+	}
+	return network.ValidationAccept
+}
@@ -3,12 +3,16 @@ package txnpool
 import (
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -19,6 +23,13 @@ import (
 // priorityFee of at least 105 to replace the existing transaction.
 const FeeBumpPercentage = 5
 
+// maxEvictionInterval is the maximum period of checking the pool for expired transactions.
+const maxEvictionInterval = time.Minute
+
+// addedTxnsBufferSize is the number of added transactions buffered for a subscriber.
+// The transactions are dropped for the subscriber that doesn't keep up.
+const addedTxnsBufferSize = 256
+
 type Pool interface {
 	Add(ctx context.Context, txns ...*types.Transaction) ([]DiscardReason, error)
 	Discard(ctx context.Context, txns []common.Hash, reason DiscardReason) error
@@ -32,6 +43,10 @@ type Pool interface {
 	Get(hash common.Hash) (*types.Transaction, error)
 	GetPendingLength() (int, error)
 	GetSize() int
+
+	// SubscribeAdded returns a channel that receives the transactions added to the pool.
+	SubscribeAdded() (uint64, <-chan *types.TxnWithHash)
+	UnsubscribeAdded(id uint64)
 }
 
 type TxnPool struct {
@@ -42,16 +57,31 @@ type TxnPool struct {
 	seqnoMap map[types.Address]types.Seqno
 
 	networkManager network.Manager
+	journal        *journal
+	// journalLock orders the writes of the journal changes, see unlockAndFlushJournal.
+	journalLock sync.Mutex
 
 	lock sync.Mutex
+	// journalChanges are the changes of the journal made under the lock: the added transactions
+	// and the hashes of the removed ones mapped to nil.
+	journalChanges map[common.Hash]*metaTxn // +checklocks:lock
 
 	byHash map[string]*metaTxn // hash => txn : only those records not committed to db yet
 	all    *ByReceiverAndSeqno // from => (sorted map of txn seqno => *txn)
 	queue  *TxnQueue
-	logger logging.Logger
+	// evictionQueue orders the eviction candidates of the full pool.
+	evictionQueue *EvictionQueue
+	logger        logging.Logger
+
+	subsMutex sync.Mutex
+	subsId    uint64                             // +checklocks:subsMutex
+	subs      map[uint64]chan *types.TxnWithHash // +checklocks:subsMutex
 }
 
-func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnPool, error) {
+// New creates the pool. If the journal is enabled in the config, database must be set:
+// the pool is restored from it and all the changes are persisted there.
+func New(ctx context.Context, cfg Config, networkManager network.Manager, database db.DB) (*TxnPool, error) {
+	cfg = cfg.withDefaults()
 	logger := logging.NewLogger("txnpool").With().
 		Stringer(logging.FieldShardId, cfg.ShardId).
 		Logger()
@@ -63,18 +93,37 @@ func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnP
 
 		networkManager: networkManager,
 
-		byHash: map[string]*metaTxn{},
-		all:    NewBySenderAndSeqno(logger),
-		queue:  &TxnQueue{},
-		logger: logger,
+		byHash:        map[string]*metaTxn{},
+		all:           NewBySenderAndSeqno(logger),
+		queue:         &TxnQueue{},
+		evictionQueue: NewEvictionQueue(),
+		logger:        logger,
+
+		subs: make(map[uint64]chan *types.TxnWithHash),
 	}
 
+	if cfg.Journal {
+		if database == nil {
+			return nil, errors.New("txnpool journal requires a database")
+		}
+		res.journal = newJournal(database, cfg.ShardId)
+		if err := res.restore(ctx); err != nil {
+			return nil, fmt.Errorf("failed to restore txnpool from journal: %w", err)
+		}
+	}
+
+	go res.evictExpired(ctx)
+
 	if networkManager == nil {
 		// we don't always want to run the network (e.g., in tests)
 		return res, nil
 	}
 
-	sub, err := networkManager.PubSub().Subscribe(topicPendingTransactions(cfg.ShardId))
+	topic := topicPendingTransactions(cfg.ShardId)
+	if err := networkManager.PubSub().RegisterTopicValidator(topic, res.validateNetworkTransaction); err != nil {
+		return nil, err
+	}
+	sub, err := networkManager.PubSub().Subscribe(topic)
 	if err != nil {
 		return nil, err
 	}
@@ -147,10 +196,24 @@ func (p *TxnPool) Add(ctx context.Context, txns ...*types.Transaction) ([]Discar
 }
 
 func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
+	discardReasons, err := p.addAll(txns)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, txn := range txns {
+		if discardReasons[i] == NotSet {
+			p.notifyAdded(txn.TxnWithHash)
+		}
+	}
+	return discardReasons, nil
+}
+
+func (p *TxnPool) addAll(txns []*metaTxn) ([]DiscardReason, error) {
 	discardReasons := make([]DiscardReason, len(txns))
 
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlockAndFlushJournal()
 
 	for i, txn := range txns {
 		if txn.To.ShardId() != p.cfg.ShardId {
@@ -184,6 +247,37 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 	return discardReasons, nil
 }
 
+func (p *TxnPool) SubscribeAdded() (uint64, <-chan *types.TxnWithHash) {
+	p.subsMutex.Lock()
+	defer p.subsMutex.Unlock()
+
+	ch := make(chan *types.TxnWithHash, addedTxnsBufferSize)
+	id := p.subsId
+	p.subs[id] = ch
+	p.subsId++
+	return id, ch
+}
+
+func (p *TxnPool) UnsubscribeAdded(id uint64) {
+	p.subsMutex.Lock()
+	defer p.subsMutex.Unlock()
+
+	close(p.subs[id])
+	delete(p.subs, id)
+}
+
+func (p *TxnPool) notifyAdded(txn *types.TxnWithHash) {
+	p.subsMutex.Lock()
+	defer p.subsMutex.Unlock()
+
+	for _, ch := range p.subs {
+		select {
+		case ch <- txn:
+		default:
+		}
+	}
+}
+
 func (p *TxnPool) validateTxn(txn *metaTxn) (DiscardReason, bool) {
 	if txn.ChainId != types.DefaultChainId {
 		return InvalidChainId, false
@@ -273,11 +367,27 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 		if !shouldReplace(found, txn) {
 			return NotReplaced
 		}
+	} else if uint64(p.all.count(txn.To)) >= p.cfg.AccountSlots {
+		return AccountSlotsExceeded
+	}
+
+	if found == nil && uint64(p.all.tree.Len()) >= p.cfg.Size {
+		// Only the transactions with the highest seqno of their receivers are evicted, so that eviction doesn't
+		// leave seqno gaps. The transactions to the receiver of the incoming one are never evicted.
+		victim := p.evictionQueue.candidate(txn.To)
+		if victim == nil || !evictionLess(victim, txn) {
+			return PoolOverflow
+		}
+		p.discardLocked(victim, EvictedByHigherFee)
+	}
+	if found != nil {
 		p.discardLocked(found, ReplacedByHigherTip)
 	}
 
-	if uint64(p.queue.Len()) >= p.cfg.Size {
-		return PoolOverflow
+	// Transactions restored from the journal keep the time they entered the pool and are journaled already.
+	if txn.addedAt.IsZero() {
+		txn.addedAt = time.Now()
+		p.journalLocked(txn.Hash(), txn)
 	}
 
 	hashStr := string(txn.Hash().Bytes())
@@ -285,6 +395,7 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 
 	replaced := p.all.replaceOrInsert(txn)
 	check.PanicIfNot(replaced == nil)
+	p.evictionQueue.set(txn.To, p.all.last(txn.To))
 
 	if needToAdd := txn.valid; needToAdd {
 		for _, t := range p.queue.txns {
@@ -311,12 +422,161 @@ func (p *TxnPool) discardLocked(txn *metaTxn, reason DiscardReason) {
 	hashStr := string(txn.Hash().Bytes())
 	delete(p.byHash, hashStr)
 	p.all.delete(txn, reason)
+	p.evictionQueue.set(txn.To, p.all.last(txn.To))
 	if txn.IsInQueue() {
 		p.queue.Remove(txn)
 		if t := p.nextSenderTxnLocked(txn.To, txn.Seqno); t != nil {
 			heap.Push(p.queue, t)
 		}
 	}
+
+	p.journalLocked(txn.Hash(), nil)
+}
+
+// journalLocked records the change of the journal: txn is written under the hash or removed if it is nil.
+// The changes are written by unlockAndFlushJournal.
+func (p *TxnPool) journalLocked(hash common.Hash, txn *metaTxn) {
+	if p.journal == nil {
+		return
+	}
+	if p.journalChanges == nil {
+		p.journalChanges = make(map[common.Hash]*metaTxn)
+	}
+	p.journalChanges[hash] = txn
+}
+
+// unlockAndFlushJournal releases the lock and writes the journal changes made under it in a single transaction.
+// The journal lock is taken before the pool lock is released, so the changes are written in the order they are made.
+func (p *TxnPool) unlockAndFlushJournal() {
+	changes := p.journalChanges
+	p.journalChanges = nil
+	if len(changes) == 0 {
+		p.lock.Unlock()
+		return
+	}
+
+	p.journalLock.Lock()
+	defer p.journalLock.Unlock()
+	p.lock.Unlock()
+
+	if err := p.journal.write(context.Background(), changes); err != nil {
+		p.logger.Error().Err(err).
+			Int("count", len(changes)).
+			Msg("Failed to write transactions to journal")
+	}
+}
+
+// evictionLess reports whether a should be evicted from the full pool before b.
+// Transactions that can't pay the base fee go first, then the ones with the lowest priority fee.
+func evictionLess(a, b *metaTxn) bool {
+	if a.valid != b.valid {
+		return !a.valid
+	}
+	return a.effectivePriorityFee.Cmp(b.effectivePriorityFee) < 0
+}
+
+// evictExpired periodically drops the transactions to the receivers that got no new transactions
+// during the pool lifetime.
+func (p *TxnPool) evictExpired(ctx context.Context) {
+	ticker := time.NewTicker(min(p.cfg.Lifetime, maxEvictionInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.lock.Lock()
+			p.evictExpiredLocked(now)
+			p.unlockAndFlushJournal()
+		}
+	}
+}
+
+func (p *TxnPool) evictExpiredLocked(now time.Time) {
+	lastAdded := make(map[types.Address]time.Time)
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		if txn.addedAt.After(lastAdded[txn.To]) {
+			lastAdded[txn.To] = txn.addedAt
+		}
+		return true
+	})
+
+	var toDel []*metaTxn // can't delete items while iterate them
+	for to, added := range lastAdded {
+		if now.Sub(added) < p.cfg.Lifetime {
+			continue
+		}
+		p.all.ascend(to, func(txn *metaTxn) bool {
+			toDel = append(toDel, txn)
+			return true
+		})
+	}
+
+	for _, txn := range toDel {
+		p.discardLocked(txn, Expired)
+	}
+	if len(toDel) > 0 {
+		p.logger.Debug().
+			Int("count", len(toDel)).
+			Msg("Evicted expired transactions")
+	}
+}
+
+// restore adds the journaled transactions to the pool and drops the ones that were not accepted from the journal.
+// The transactions committed while the node was down are dropped first.
+func (p *TxnPool) restore(ctx context.Context) error {
+	entries, err := p.journal.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	addresses := make([]types.Address, 0, len(entries))
+	for _, entry := range entries {
+		addresses = append(addresses, entry.txn.To)
+	}
+	seqnos, err := p.journal.committedSeqnos(ctx, addresses)
+	if err != nil {
+		return fmt.Errorf("failed to read committed seqnos: %w", err)
+	}
+
+	var committed, rejected []common.Hash
+	txns := make([]*metaTxn, 0, len(entries))
+	for _, entry := range entries {
+		if entry.txn.To.ShardId() != p.cfg.ShardId {
+			continue
+		}
+		if entry.txn.Seqno < seqnos[entry.txn.To] {
+			committed = append(committed, entry.txn.Hash())
+			continue
+		}
+		txn := newMetaTxn(entry.txn, p.baseFee)
+		txn.addedAt = entry.addedAt
+		txns = append(txns, txn)
+	}
+
+	reasons, err := p.add(txns...)
+	if err != nil {
+		return err
+	}
+
+	for i, reason := range reasons {
+		if reason != NotSet {
+			rejected = append(rejected, txns[i].Hash())
+		}
+	}
+	if dropped := slices.Concat(committed, rejected); len(dropped) > 0 {
+		if err := p.journal.remove(ctx, dropped...); err != nil {
+			return err
+		}
+	}
+
+	p.logger.Info().
+		Int("restored", len(txns)-len(rejected)).
+		Int("committed", len(committed)).
+		Int("rejected", len(rejected)).
+		Msg("Restored transactions from journal")
+	return nil
 }
 
 func (p *TxnPool) nextSenderTxnLocked(senderID types.Address, seqno types.Seqno) *metaTxn {
@@ -333,7 +593,7 @@ func (p *TxnPool) nextSenderTxnLocked(senderID types.Address, seqno types.Seqno)
 
 func (p *TxnPool) Discard(_ context.Context, hashes []common.Hash, reason DiscardReason) error {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlockAndFlushJournal()
 
 	for _, hash := range hashes {
 		mm := p.getLocked(hash)
@@ -350,7 +610,7 @@ func (p *TxnPool) Discard(_ context.Context, hashes []common.Hash, reason Discar
 
 func (p *TxnPool) OnCommitted(_ context.Context, baseFee types.Value, committed []*types.Transaction) error {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlockAndFlushJournal()
 
 	if err := p.removeCommitted(p.all, committed); err != nil {
 		return fmt.Errorf("failed to remove committed transactions: %w", err)
@@ -368,6 +628,7 @@ func (p *TxnPool) updateTransactionsLocked() {
 		txn.effectivePriorityFee, txn.valid = execution.GetEffectivePriorityFee(p.baseFee, txn.Transaction)
 		return true
 	})
+	p.evictionQueue.fix()
 	p.all.ascendAll(func(txn *metaTxn) bool {
 		if !txn.valid && txn.bestIndex >= 0 {
 			p.queue.Remove(txn)
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/rs/zerolog"
@@ -56,7 +58,7 @@ func (s *SuiteTxnPool) SetupTest() {
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	var err error
-	s.pool, err = New(s.ctx, NewConfig(0), nil)
+	s.pool, err = New(s.ctx, NewConfig(0), nil, nil)
 	s.Require().NoError(err)
 }
 
@@ -164,10 +166,172 @@ func (s *SuiteTxnPool) TestAddOverflow() {
 		newTransaction(defaultAddress, 1, 123), PoolOverflow)
 }
 
+func (s *SuiteTxnPool) TestAddOverflowEvictsLowestFee() {
+	s.pool.cfg.Size = 3
+	address2 := types.ShardAndHexToAddress(0, "22")
+	address3 := types.ShardAndHexToAddress(0, "33")
+
+	txn11 := newTransaction2(defaultAddress, 0, 5, defaultMaxFee, 0)
+	txn12 := newTransaction2(defaultAddress, 1, 10, defaultMaxFee, 1)
+	txn21 := newTransaction2(address2, 0, 20, defaultMaxFee, 2)
+	s.addTransactionsSuccessfully(txn11, txn12, txn21)
+
+	// txn11 has the lowest fee, but evicting it would leave a seqno gap,
+	// so the incoming transaction has to pay more than txn12
+	s.addTransactionWithDiscardReason(newTransaction2(address3, 0, 8, defaultMaxFee, 3), PoolOverflow)
+	s.addTransactionWithDiscardReason(newTransaction2(address3, 0, 10, defaultMaxFee, 3), PoolOverflow)
+
+	s.Equal([]DiscardReason{NotSet}, s.addTransactions(newTransaction2(address3, 0, 30, defaultMaxFee, 3)))
+	s.checkTransactionsOrder(3, 2, 0)
+
+	// Replacement doesn't need room in the pool
+	s.Equal([]DiscardReason{NotSet}, s.addTransactions(newTransaction2(address3, 0, 40, defaultMaxFee, 5)))
+	s.checkTransactionsOrder(5, 2, 0)
+
+	// The cheapest txn11 is of the incoming receiver, so the next cheapest txn21 is evicted
+	s.Equal([]DiscardReason{NotSet}, s.addTransactions(newTransaction2(defaultAddress, 1, 25, defaultMaxFee, 6)))
+	s.checkTransactionsOrder(5, 0, 6)
+}
+
+func (s *SuiteTxnPool) TestAccountSlots() {
+	s.pool.cfg.AccountSlots = 2
+
+	s.addTransactionsSuccessfully(
+		newTransaction(defaultAddress, 0, 123),
+		newTransaction(defaultAddress, 1, 123))
+
+	s.addTransactionWithDiscardReason(
+		newTransaction(defaultAddress, 2, 123), AccountSlotsExceeded)
+
+	// Other receivers are not affected
+	s.addTransactionsSuccessfully(
+		newTransaction(types.ShardAndHexToAddress(0, "22"), 0, 123))
+
+	// Replacement doesn't take a new slot
+	s.Equal([]DiscardReason{NotSet}, s.addTransactions(newTransaction(defaultAddress, 1, 200)))
+}
+
+func (s *SuiteTxnPool) TestEvictExpired() {
+	address2 := types.ShardAndHexToAddress(0, "22")
+
+	txn11 := newTransaction(defaultAddress, 0, 123)
+	txn12 := newTransaction(defaultAddress, 1, 123)
+	s.addTransactionsSuccessfully(txn11, txn12)
+
+	s.pool.lock.Lock()
+	s.pool.byHash[string(txn11.Hash().Bytes())].addedAt = time.Now().Add(-2 * s.pool.cfg.Lifetime)
+	s.pool.lock.Unlock()
+
+	txn2 := newTransaction(address2, 0, 123)
+	s.addTransactionsSuccessfully(txn2)
+
+	// txn11 is too old, but txn12 of the same receiver is not
+	s.pool.lock.Lock()
+	s.pool.evictExpiredLocked(time.Now())
+	s.pool.lock.Unlock()
+	s.Equal(3, s.pool.GetSize())
+
+	s.pool.lock.Lock()
+	s.pool.evictExpiredLocked(time.Now().Add(s.pool.cfg.Lifetime))
+	s.pool.lock.Unlock()
+	s.Equal(0, s.pool.GetSize())
+	s.Empty(s.getTransactions())
+}
+
+func (s *SuiteTxnPool) TestJournal() {
+	database, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer database.Close()
+
+	cfg := NewConfig(0)
+	cfg.Journal = true
+
+	_, err = New(s.ctx, cfg, nil, nil)
+	s.Require().Error(err)
+
+	pool, err := New(s.ctx, cfg, nil, database)
+	s.Require().NoError(err)
+
+	txn1 := newTransaction(defaultAddress, 0, 123)
+	txn2 := newTransaction(defaultAddress, 1, 123)
+	txn3 := newTransaction(types.ShardAndHexToAddress(0, "22"), 0, 123)
+	s.addTransactionsToPoolSuccessfully(pool, txn1, txn2, txn3)
+	s.Require().NoError(pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn1}))
+
+	restored, err := New(s.ctx, cfg, nil, database)
+	s.Require().NoError(err)
+	s.Equal(2, restored.GetSize())
+
+	for _, txn := range []*types.Transaction{txn2, txn3} {
+		poolTxn, err := restored.Get(txn.Hash())
+		s.Require().NoError(err)
+		s.Equal(txn.Hash(), poolTxn.Hash())
+	}
+	s.Equal(
+		pool.byHash[string(txn2.Hash().Bytes())].addedAt.UnixNano(),
+		restored.byHash[string(txn2.Hash().Bytes())].addedAt.UnixNano())
+}
+
+func (s *SuiteTxnPool) TestJournalDropsCommitted() {
+	database, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer database.Close()
+
+	cfg := NewConfig(0)
+	cfg.Journal = true
+
+	pool, err := New(s.ctx, cfg, nil, database)
+	s.Require().NoError(err)
+
+	txn1 := newTransaction(defaultAddress, 0, 123)
+	txn2 := newTransaction(defaultAddress, 1, 123)
+	s.addTransactionsToPoolSuccessfully(pool, txn1, txn2)
+
+	// txn1 is committed while the node is down.
+	tx, err := database.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	contracts := execution.NewDbContractTrie(tx, cfg.ShardId)
+	s.Require().NoError(contracts.Update(
+		defaultAddress.Hash(), &types.SmartContract{Address: defaultAddress, ExtSeqno: 1}))
+	block := &types.Block{BlockData: types.BlockData{SmartContractsRoot: contracts.RootHash()}}
+	hash := block.Hash(cfg.ShardId)
+	s.Require().NoError(db.WriteBlock(tx, cfg.ShardId, hash, block))
+	s.Require().NoError(db.WriteLastBlockHash(tx, cfg.ShardId, hash))
+	s.Require().NoError(tx.Commit())
+
+	restored, err := New(s.ctx, cfg, nil, database)
+	s.Require().NoError(err)
+	s.Equal(1, restored.GetSize())
+	has, err := restored.IdHashKnown(txn2.Hash())
+	s.Require().NoError(err)
+	s.True(has)
+
+	entries, err := restored.journal.load(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(entries, 1)
+	s.Equal(txn2.Hash(), entries[0].txn.Hash())
+}
+
 func (s *SuiteTxnPool) TestStarted() {
 	s.True(s.pool.Started())
 }
 
+func (s *SuiteTxnPool) TestSubscribeAdded() {
+	id, added := s.pool.SubscribeAdded()
+
+	txn := newTransaction(defaultAddress, 0, 123)
+	s.addTransactionsSuccessfully(txn)
+	s.addTransactionWithDiscardReason(txn, DuplicateHash)
+
+	s.Require().Len(added, 1)
+	s.Equal(txn.Hash(), (<-added).Hash())
+
+	s.pool.UnsubscribeAdded(id)
+	_, ok := <-added
+	s.False(ok)
+}
+
 func (s *SuiteTxnPool) TestIdHashKnownGet() {
 	txn := newTransaction(defaultAddress, 0, 123)
 	s.addTransactionsSuccessfully(txn)
@@ -349,9 +513,9 @@ func (s *SuiteTxnPool) TestReplacement() {
 func (s *SuiteTxnPool) TestNetwork() {
 	nms := network.NewTestManagers(s.ctx, s.T(), 9100, 2)
 
-	pool1, err := New(s.ctx, NewConfig(0), nms[0])
+	pool1, err := New(s.ctx, NewConfig(0), nms[0], nil)
 	s.Require().NoError(err)
-	pool2, err := New(s.ctx, NewConfig(0), nms[1])
+	pool2, err := New(s.ctx, NewConfig(0), nms[1], nil)
 	s.Require().NoError(err)
 
 	// Ensure that both nodes have subscribed, so that they will exchange this info on the following connect.
@@ -372,6 +536,44 @@ func (s *SuiteTxnPool) TestNetwork() {
 	}, 20*time.Second, 200*time.Millisecond)
 }
 
+func (s *SuiteTxnPool) TestValidateNetworkTransaction() {
+	validate := func(txn *types.Transaction) network.ValidationResult {
+		s.T().Helper()
+
+		data, err := txn.MarshalSSZ()
+		s.Require().NoError(err)
+		return s.pool.validateNetworkTransaction(s.ctx, "", data)
+	}
+
+	txn := newTransaction(defaultAddress, 0, 123)
+	s.Equal(network.ValidationAccept, validate(txn))
+
+	s.Run("Malformed", func() {
+		s.Equal(network.ValidationReject, s.pool.validateNetworkTransaction(s.ctx, "", []byte{1, 2, 3}))
+	})
+
+	s.Run("OtherShard", func() {
+		s.Equal(network.ValidationReject, validate(newTransaction(types.ShardAndHexToAddress(1, "11"), 0, 123)))
+	})
+
+	s.Run("Internal", func() {
+		internal := newTransaction(defaultAddress, 0, 123)
+		internal.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+		s.Equal(network.ValidationReject, validate(internal))
+	})
+
+	s.Run("ZeroMaxFee", func() {
+		s.Equal(network.ValidationReject, validate(newTransaction2(defaultAddress, 0, 0, 0, 0)))
+	})
+
+	s.Run("Outdated", func() {
+		s.addTransactionsSuccessfully(txn)
+		s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn}))
+		s.Equal(network.ValidationIgnore, validate(txn))
+		s.Equal(network.ValidationAccept, validate(newTransaction(defaultAddress, 1, 123)))
+	})
+}
+
 func (s *SuiteTxnPool) TestUnverifiedDuplicates() {
 	txn1 := newTransaction(defaultAddress, 0, 123)
 	txn2 := newTransaction(defaultAddress, 1, 123)
@@ -382,6 +584,37 @@ func (s *SuiteTxnPool) TestUnverifiedDuplicates() {
 	s.Require().NoError(err)
 }
 
+func (s *SuiteTxnPool) TestSnapshotRevert() {
+	txn1 := newTransaction(defaultAddress, 0, 123)
+	txn2 := newTransaction(defaultAddress, 1, 123)
+	s.addTransactionsSuccessfully(txn1, txn2)
+
+	snapshot := s.pool.Snapshot()
+
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn1, txn2}))
+	s.Equal(0, s.getTransactionCount(s.pool))
+
+	// The committed seqno is rejected until the pool is reverted.
+	reasons, err := s.pool.Add(s.ctx, txn1)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{SeqnoTooLow}, reasons)
+
+	txn3 := newTransaction(defaultAddress, 2, 123)
+	s.addTransactionsSuccessfully(txn3)
+
+	s.Require().NoError(s.pool.Revert(s.ctx, snapshot))
+
+	transactions, err := s.pool.Peek(10)
+	s.Require().NoError(err)
+	s.Require().Len(transactions, 2)
+	s.Equal(types.NewTxnWithHash(txn1), transactions[0])
+	s.Equal(types.NewTxnWithHash(txn2), transactions[1])
+
+	known, err := s.pool.IdHashKnown(txn3.Hash())
+	s.Require().NoError(err)
+	s.False(known)
+}
+
 func (s *SuiteTxnPool) checkTransactionsOrder(vals ...int) {
 	s.T().Helper()
 
@@ -422,7 +655,7 @@ func TestSuiteTxnpool(t *testing.T) {
 func BenchmarkTxnPoolAdd(b *testing.B) {
 	shardId := types.ShardId(0)
 	ctx := b.Context()
-	pool, err := New(ctx, NewConfig(shardId), nil)
+	pool, err := New(ctx, NewConfig(shardId), nil, nil)
 	if err != nil {
 		b.Fatalf("Failed to create transaction pool: %s", err)
 	}
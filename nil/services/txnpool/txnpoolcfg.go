@@ -2,22 +2,52 @@ package txnpool
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
-const defaultPoolSize = 10000
+const (
+	defaultPoolSize     = 10000
+	defaultAccountSlots = 64
+	defaultLifetime     = 3 * time.Hour
+)
 
 type Config struct {
-	ShardId types.ShardId
-	Size    uint64
+	ShardId types.ShardId `yaml:"-"`
+	// Size is the maximum number of transactions in the pool.
+	// When the pool is full, a new transaction evicts the one with the lowest priority fee if it pays more.
+	Size uint64 `yaml:"size,omitempty"`
+	// AccountSlots is the maximum number of transactions to a single address in the pool.
+	AccountSlots uint64 `yaml:"accountSlots,omitempty"`
+	// Lifetime is the maximum time the transactions to an address stay in the pool
+	// if no new transactions to this address arrive.
+	Lifetime time.Duration `yaml:"lifetime,omitempty"`
+	// Journal enables persisting the pool to the database, so that it is restored on restart.
+	Journal bool `yaml:"journal,omitempty"`
 }
 
 func NewConfig(shardId types.ShardId) Config {
 	return Config{
-		ShardId: shardId,
-		Size:    defaultPoolSize,
+		ShardId:      shardId,
+		Size:         defaultPoolSize,
+		AccountSlots: defaultAccountSlots,
+		Lifetime:     defaultLifetime,
+	}
+}
+
+// withDefaults returns the config with the zero limits replaced by the default ones.
+func (c Config) withDefaults() Config {
+	if c.Size == 0 {
+		c.Size = defaultPoolSize
+	}
+	if c.AccountSlots == 0 {
+		c.AccountSlots = defaultAccountSlots
+	}
+	if c.Lifetime == 0 {
+		c.Lifetime = defaultLifetime
 	}
+	return c
 }
 
 type DiscardReason uint8
@@ -42,6 +72,12 @@ const (
 	Unverified DiscardReason = 22
 	// Transaction max fee is too small
 	TooSmallMaxFee DiscardReason = 23
+	// The receiver already has the maximum number of transactions in the pool
+	AccountSlotsExceeded DiscardReason = 24
+	// No new transactions to the receiver arrived during the pool lifetime
+	Expired DiscardReason = 25
+	// Evicted from the full pool by a transaction with a higher priority fee
+	EvictedByHigherFee DiscardReason = 26
 )
 
 func (r DiscardReason) String() string {
@@ -72,6 +108,12 @@ func (r DiscardReason) String() string {
 		return "verification failed"
 	case TooSmallMaxFee:
 		return "max fee too small"
+	case AccountSlotsExceeded:
+		return "account slots exceeded"
+	case Expired:
+		return "expired"
+	case EvictedByHigherFee:
+		return "evicted by higher fee"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
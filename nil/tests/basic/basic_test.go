@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
@@ -669,6 +670,44 @@ func (s *SuiteRpc) TestMultipleRefunds() {
 	s.Require().True(receipt.OutReceipts[0].Success)
 }
 
+func (s *SuiteRpc) TestSimulateTransaction() {
+	addr := types.GenerateRandomAddress(2)
+	value := types.NewValueFromUint64(1000)
+
+	calldata, err := client.CreateInternalTransactionPayload(nil, value, nil, addr, false)
+	s.Require().NoError(err)
+	extTxn, err := client.CreateExternalTransaction(
+		s.Context, s.Client, calldata, types.MainSmartAccountAddress, types.NewFeePackFromGas(1_000_000), false, 0)
+	s.Require().NoError(err)
+	s.Require().NoError(extTxn.Sign(execution.MainPrivateKey))
+
+	balance := s.GetBalance(types.MainSmartAccountAddress)
+
+	res, err := s.Client.SimulateTransaction(s.Context, extTxn, "latest")
+	s.Require().NoError(err)
+	s.Require().True(res.Transaction.Success, res.Transaction.Error)
+	s.Equal(extTxn.Hash(), res.Transaction.Hash)
+	s.Require().Len(res.Transaction.OutTransactions, 1)
+
+	out := res.Transaction.OutTransactions[0]
+	s.Equal(addr, out.To)
+	s.Equal(value, out.Value)
+	s.True(out.Success, out.Error)
+	s.True(res.TotalFee.Cmp(res.Transaction.CoinsUsed) > 0)
+	s.Contains(res.StateDiffs[types.MainSmartAccountAddress.ShardId()], types.MainSmartAccountAddress)
+	s.Contains(res.StateDiffs[addr.ShardId()], addr)
+
+	// Nothing is sent to the network.
+	s.Equal(balance, s.GetBalance(types.MainSmartAccountAddress))
+
+	hash, err := s.Client.SendTransaction(s.Context, extTxn)
+	s.Require().NoError(err)
+	receipt := s.WaitIncludedInMain(hash)
+	s.Require().True(receipt.Success)
+	s.Require().Len(receipt.OutReceipts, 1)
+	s.Equal(out.Success, receipt.OutReceipts[0].Success)
+}
+
 func (s *SuiteRpc) TestRpcBlockContent() {
 	// Deploy transaction
 	hash, _, err := s.Client.DeployContract(
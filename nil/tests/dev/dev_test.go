@@ -0,0 +1,100 @@
+package dev
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/nilservice"
+	"github.com/NilFoundation/nil/nil/services/rpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/tests"
+	"github.com/stretchr/testify/suite"
+)
+
+type SuiteDevApi struct {
+	tests.RpcSuite
+}
+
+func (s *SuiteDevApi) SetupSuite() {
+	s.Start(&nilservice.Config{
+		NShards:      3,
+		HttpUrl:      rpc.GetSockPath(s.T()),
+		EnableDevApi: true,
+		ManualMining: true,
+	})
+}
+
+func (s *SuiteDevApi) TearDownSuite() {
+	s.Cancel()
+}
+
+func (s *SuiteDevApi) lastBlockId(shardId types.ShardId) uint64 {
+	s.T().Helper()
+
+	block, err := s.Client.GetBlock(s.Context, shardId, transport.LatestBlockNumber, false)
+	s.Require().NoError(err)
+	return block.Number.Uint64()
+}
+
+func (s *SuiteDevApi) TestManualMining() {
+	shardId := types.BaseShardId
+	before := s.lastBlockId(shardId)
+
+	last, err := s.Client.Mine(s.Context, shardId, 3)
+	s.Require().NoError(err)
+	s.Equal(hexutil.Uint64(before+3), last)
+	s.Equal(before+3, s.lastBlockId(shardId))
+}
+
+func (s *SuiteDevApi) TestSetBalance() {
+	address := types.GenerateRandomAddress(types.BaseShardId)
+	balance := types.NewValueFromUint64(12345)
+
+	s.Require().NoError(s.Client.SetBalance(s.Context, address, balance))
+	_, err := s.Client.Mine(s.Context, address.ShardId(), 1)
+	s.Require().NoError(err)
+
+	s.Equal(balance, s.GetBalance(address))
+}
+
+func (s *SuiteDevApi) TestSnapshotRevert() {
+	address := types.GenerateRandomAddress(types.BaseShardId)
+	s.Require().NoError(s.Client.SetBalance(s.Context, address, types.NewValueFromUint64(1)))
+	_, err := s.Client.Mine(s.Context, address.ShardId(), 1)
+	s.Require().NoError(err)
+
+	lastBlocks := make([]uint64, s.ShardsNum)
+	for i := range s.ShardsNum {
+		lastBlocks[i] = s.lastBlockId(types.ShardId(i))
+	}
+	id := s.Snapshot()
+
+	s.Require().NoError(s.Client.SetBalance(s.Context, address, types.NewValueFromUint64(2)))
+	for i := range s.ShardsNum {
+		_, err := s.Client.Mine(s.Context, types.ShardId(i), 2)
+		s.Require().NoError(err)
+	}
+	s.Equal(types.NewValueFromUint64(2), s.GetBalance(address))
+
+	s.Revert(id)
+
+	for i := range s.ShardsNum {
+		s.Equal(lastBlocks[i], s.lastBlockId(types.ShardId(i)))
+	}
+	s.Equal(types.NewValueFromUint64(1), s.GetBalance(address))
+
+	// The shards keep producing blocks after the revert.
+	last, err := s.Client.Mine(s.Context, address.ShardId(), 1)
+	s.Require().NoError(err)
+	s.Equal(hexutil.Uint64(lastBlocks[address.ShardId()]+1), last)
+
+	// The snapshot can't be used twice.
+	s.Require().Error(s.Client.Revert(s.Context, id))
+}
+
+func TestDevApi(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteDevApi))
+}
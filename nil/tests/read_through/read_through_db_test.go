@@ -49,6 +49,12 @@ func (s *SuiteReadThroughDb) TearDownTest() {
 func (s *SuiteReadThroughDb) initCache() {
 	s.T().Helper()
 
+	s.startCache(s.cfg)
+}
+
+func (s *SuiteReadThroughDb) startCache(cfg *nilservice.Config) {
+	s.T().Helper()
+
 	s.cache.DbInit = func() db.DB {
 		inDb, err := db.NewBadgerDbInMemory()
 		check.PanicIfErr(err)
@@ -59,8 +65,8 @@ func (s *SuiteReadThroughDb) initCache() {
 	}
 
 	s.num++
-	s.cfg.HttpUrl = rpc.GetSockPathIdx(s.T(), s.num)
-	s.cache.Start(s.cfg)
+	cfg.HttpUrl = rpc.GetSockPathIdx(s.T(), s.num)
+	s.cache.Start(cfg)
 }
 
 func (s *SuiteReadThroughDb) waitBlockOnMasterShard(shardId types.ShardId, blockNumber types.BlockNumber) {
@@ -158,6 +164,48 @@ func (s *SuiteReadThroughDb) TestIsolation() {
 	})
 }
 
+// TestFork checks the setup of `nild fork`: the forked node is not a validator of the source network,
+// so it produces the blocks of all shards without consensus.
+func (s *SuiteReadThroughDb) TestFork() {
+	shardId := types.BaseShardId
+	var addrCallee types.Address
+	var receipt *jsonrpc.RPCReceipt
+
+	s.Run("Deploy", func() {
+		addrCallee, receipt = s.server.DeployContractViaMainSmartAccount(shardId,
+			contracts.CounterDeployPayload(s.T()),
+			types.GasToValue(50_000_000))
+		s.Require().True(receipt.OutReceipts[0].Success)
+	})
+
+	s.waitBlockOnMasterShard(shardId, receipt.BlockNumber)
+	s.startCache(&nilservice.Config{
+		NShards:          s.cfg.NShards,
+		DisableConsensus: true,
+		EnableDevApi:     true,
+	})
+
+	value := int32(3)
+	s.Run("IncrementFork", func() {
+		receipt := s.cache.SendTransactionViaSmartAccount(
+			types.MainSmartAccountAddress,
+			addrCallee,
+			execution.MainPrivateKey,
+			contracts.NewCounterAddCallData(s.T(), value))
+		s.Require().True(receipt.OutReceipts[0].Success)
+	})
+
+	s.Run("GetFromFork", func() {
+		data := s.cache.CallGetter(addrCallee, contracts.NewCounterGetCallData(s.T()), "latest", nil)
+		s.Require().Equal(value, int32(data[31]))
+	})
+
+	s.Run("GetFromServer", func() {
+		data := s.server.CallGetter(addrCallee, contracts.NewCounterGetCallData(s.T()), "latest", nil)
+		s.Require().Zero(int32(data[31]))
+	})
+}
+
 func TestSuiteReadThroughDb(t *testing.T) {
 	t.Parallel()
 
@@ -17,6 +17,7 @@ import (
 	rpc_client "github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/collate"
@@ -155,6 +156,22 @@ func (s *RpcSuite) Cancel() {
 	}
 }
 
+// Snapshot saves the state of the network, so that tests can Revert to it instead of restarting the network.
+// It requires the development API.
+func (s *RpcSuite) Snapshot() hexutil.Uint64 {
+	s.T().Helper()
+
+	id, err := s.Client.Snapshot(s.Context)
+	s.Require().NoError(err)
+	return id
+}
+
+func (s *RpcSuite) Revert(id hexutil.Uint64) {
+	s.T().Helper()
+
+	s.Require().NoError(s.Client.Revert(s.Context, id))
+}
+
 func (s *RpcSuite) WaitForReceipt(hash common.Hash) *jsonrpc.RPCReceipt {
 	s.T().Helper()
 
@@ -24,7 +24,7 @@ func StartFaucetService(
 
 	endpoint := rpc.GetSockPathService(t, "faucet")
 
-	serviceFaucet, err := faucet.NewService(client)
+	serviceFaucet, err := faucet.NewService(ctx, client)
 	require.NoError(t, err)
 
 	wg.Add(1)
@@ -26,6 +26,7 @@ library Nil {
     address public constant IS_RESPONSE_TRANSACTION = address(0xd9);
     address public constant LOG = address(0xda);
     address public constant GOVERNANCE = address(0xdb);
+    address public constant VERIFY_BLS_SIGNATURE = address(0xdc);
 
     // The following constants specify from where and how the gas should be taken during async call.
     // Forwarding values are calculated in the following order: FORWARD_VALUE, FORWARD_PERCENTAGE, FORWARD_REMAINING.
@@ -217,6 +218,27 @@ library Nil {
         return result;
     }
 
+    /**
+     * @dev Validates a BLS signature made by a validator key using a precompiled contract.
+     * @param pubkey BLS public key of the validator.
+     * @param hash Signed hash.
+     * @param signature Signature to be validated.
+     * @return Boolean indicating if the key and the signature are valid.
+     */
+    function validateBlsSignature(
+        bytes memory pubkey,
+        uint256 hash,
+        bytes memory signature
+    ) internal view returns (bool) {
+        bytes memory encodedInput = abi.encode(pubkey, hash, signature);
+        (bool success, bytes memory returnData) = VERIFY_BLS_SIGNATURE.staticcall(encodedInput);
+        require(success, "Precompiled contract call failed");
+        if (returnData.length == 0) {
+            return false;
+        }
+        return abi.decode(returnData, (bool));
+    }
+
     /**
      * @dev Returns the balance of a token with a given id for a given address.
      * @param addr Address to check the balance for.
@@ -323,7 +345,7 @@ library Nil {
     }
 
     struct ValidatorInfo {
-        uint8[33] PublicKey;
+        uint8[128] PublicKey;
         address WithdrawalAddress;
     }
 
@@ -347,6 +369,19 @@ library Nil {
         bytes32 hash;
     }
 
+    struct ProtocolVersionActivation {
+        uint32 version;
+        uint64 height;
+    }
+
+    struct ParamProtocolVersions {
+        ProtocolVersionActivation[] activations;
+    }
+
+    struct ParamValidatorsEpoch {
+        uint64 length;
+    }
+
     /**
      * @dev Returns the current validators.
      * @return Struct containing the list of validators.
@@ -443,6 +478,8 @@ contract NilConfigAbi {
     function curr_validators(Nil.ParamValidators memory) public {}
     function gas_price(Nil.ParamGasPrice memory) public {}
     function l1block(Nil.ParamL1BlockInfo memory) public {}
+    function protocol_versions(Nil.ParamProtocolVersions memory) public {}
+    function validators_epoch(Nil.ParamValidatorsEpoch memory) public {}
 }
 
 function tokenIdEqual(TokenId a, TokenId b) pure returns (bool) {